@@ -10,11 +10,17 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/carewallet/backend/internal/bankverify"
 	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/fees"
 	"github.com/carewallet/backend/internal/handler"
 	"github.com/carewallet/backend/internal/middleware"
+	"github.com/carewallet/backend/internal/paystack"
+	"github.com/carewallet/backend/internal/qr"
 	"github.com/carewallet/backend/internal/repository"
 	"github.com/carewallet/backend/internal/service"
+	"github.com/carewallet/backend/internal/storage"
 	"github.com/carewallet/backend/internal/utils"
 	"github.com/carewallet/backend/pkg/database"
 	"github.com/gin-gonic/gin"
@@ -45,6 +51,20 @@ func runMigrations(databaseURL string) error {
 	return nil
 }
 
+func newBlobStore(cfg *config.Config) (storage.BlobStore, error) {
+	if cfg.StorageBackend == "s3" {
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			UsePathStyle:    cfg.S3UsePathStyle,
+		})
+	}
+	return storage.NewLocalStore(cfg.StorageLocalPath)
+}
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
@@ -79,28 +99,111 @@ func main() {
 	otpRepo := repository.NewOTPRepository(db)
 	tokenBlacklistRepo := repository.NewTokenBlacklistRepository(db)
 	paymentRepo := repository.NewPaymentRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	manualCreditRepo := repository.NewManualCreditRepository(db)
+	settlementRepo := repository.NewSettlementRepository(db)
+	bankAccountRepo := repository.NewBankAccountRepository(db)
+	pharmacyUserRepo := repository.NewPharmacyUserRepository(db)
+	pharmacyWithdrawalRepo := repository.NewPharmacyWithdrawalRepository(db)
+	passwordSetupTokenRepo := repository.NewPasswordSetupTokenRepository(db)
+	uploadRepo := repository.NewUploadRepository(db)
+	organisationRepo := repository.NewOrganisationRepository(db)
+	spendingRuleRepo := repository.NewWalletSpendingRuleRepository(db)
+	pharmacyAPIKeyRepo := repository.NewPharmacyAPIKeyRepository(db)
+	feeRuleRepo := repository.NewFeeRuleRepository(db)
+	feeQuoteRepo := repository.NewFeeQuoteRepository(db)
+	disputeRepo := repository.NewDisputeRepository(db)
+	walletLookupRepo := repository.NewWalletLookupRepository(db)
+	shareLinkRepo := repository.NewWalletShareLinkRepository(db)
+	walletClosureRepo := repository.NewWalletClosureRepository(db)
+	walletCampaignRepo := repository.NewWalletCampaignRepository(db)
+	walletContributionRepo := repository.NewWalletContributionRepository(db)
+
+	// Initialize payment gateway
+	var paystackGateway paystack.Gateway = paystack.NewClient(cfg.PaystackSecretKey)
+	if cfg.PaystackSecretKey == "" {
+		// The fake accepts every payment and webhook, so it must never run
+		// against real wallets.
+		if !cfg.IsDevelopment() {
+			log.Fatal("PAYSTACK_SECRET_KEY must be set outside development")
+		}
+		log.Println("PAYSTACK_SECRET_KEY not set, simulating Paystack")
+		paystackGateway = paystack.NewFakeGateway()
+	}
+
+	// Initialize file storage
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize %s storage: %v", cfg.StorageBackend, err)
+	}
+	urlSigner := storage.NewURLSigner(cfg.FileURLSecret, cfg.PublicAPIURL, time.Duration(cfg.FileURLTTLMinutes)*time.Minute)
+	voucherSigner := qr.NewVoucherSigner(cfg.WalletVoucherSecret, time.Duration(cfg.WalletVoucherTTLMinutes)*time.Minute)
+
+	// Initialize fee engine
+	feeEngine := fees.NewEngine(feeRuleRepo, cfg)
 
 	// Initialize services
 	emailService := service.NewMockEmailService()
+	auditService := service.NewAuditService(auditLogRepo)
 	otpService := service.NewOTPService(otpRepo, emailService, cfg)
 	authService := service.NewAuthService(userRepo, tokenBlacklistRepo, jwtManager, cfg)
-	walletService := service.NewWalletService(walletRepo)
-	transactionService := service.NewTransactionService(transactionRepo, walletRepo, pharmacyRepo, otpService, cfg)
-	paymentService := service.NewPaymentService(paymentRepo, walletRepo, transactionRepo, cfg.PaystackSecretKey)
-	adminService := service.NewAdminService(pharmacyRepo, transactionRepo)
-	pharmacyAuthService := service.NewPharmacyAuthService(pharmacyRepo, jwtManager, cfg)
+	uploadService := service.NewUploadService(uploadRepo, blobStore, urlSigner, cfg)
+	walletCampaignService := service.NewWalletCampaignService(walletCampaignRepo, walletRepo, userRepo, emailService, auditService, cfg)
+	walletService := service.NewWalletService(walletRepo, spendingRuleRepo, shareLinkRepo, pharmacyRepo, organisationRepo, uploadService, walletCampaignService, auditService, cfg)
+	walletLookupService := service.NewWalletLookupService(walletLookupRepo, walletRepo, spendingRuleRepo, userRepo, otpService, auditService, voucherSigner, cfg)
+	feeQuoteService := service.NewFeeQuoteService(feeQuoteRepo, walletRepo, pharmacyRepo, spendingRuleRepo, walletLookupService, feeEngine, cfg)
+	transactionService := service.NewTransactionService(transactionRepo, walletRepo, spendingRuleRepo, pharmacyRepo, feeEngine, feeQuoteService, otpService, auditService, cfg)
+	paymentService := service.NewPaymentService(paymentRepo, walletRepo, shareLinkRepo, feeEngine, paystackGateway)
+	pharmacyStaffService := service.NewPharmacyStaffService(pharmacyUserRepo, pharmacyRepo, passwordSetupTokenRepo, emailService, auditService, cfg)
+	pharmacyAPIKeyService := service.NewPharmacyAPIKeyService(pharmacyAPIKeyRepo, pharmacyStaffService, auditService)
+	adminService := service.NewAdminService(pharmacyRepo, transactionRepo, pharmacyStaffService, auditService)
+	pharmacyAuthService := service.NewPharmacyAuthService(pharmacyRepo, pharmacyUserRepo, jwtManager, cfg)
+	pharmacyOnboardingService := service.NewPharmacyOnboardingService(pharmacyRepo, pharmacyStaffService, uploadService, emailService, auditService, cfg)
+	pharmacyWithdrawalService := service.NewPharmacyWithdrawalService(pharmacyWithdrawalRepo, transactionRepo, spendingRuleRepo, userRepo, pharmacyRepo, walletLookupService, feeEngine, feeQuoteService, otpService, auditService, cfg)
+	manualCreditService := service.NewManualCreditService(manualCreditRepo, walletRepo, auditService, cfg)
+	settlementService := service.NewSettlementService(settlementRepo, transactionRepo, pharmacyRepo, bankAccountRepo, auditService, cfg)
+	payoutService := service.NewPayoutService(settlementRepo, bankAccountRepo, paystackGateway, auditService, cfg)
+	walletContributionService := service.NewWalletContributionService(walletContributionRepo, walletRepo, shareLinkRepo, auditService)
+	walletClosureService := service.NewWalletClosureService(walletClosureRepo, walletRepo, paymentRepo, paystackGateway, auditService)
+	bankAccountService := service.NewBankAccountService(bankAccountRepo, pharmacyRepo, bankverify.NewStubVerifier(), auditService, cfg)
+	pharmacyDirectoryService := service.NewPharmacyDirectoryService(pharmacyRepo, auditService, cfg)
+	feeRuleService := service.NewFeeRuleService(feeRuleRepo, pharmacyRepo, organisationRepo, auditService)
+	walletQRService := service.NewWalletQRService(walletRepo, voucherSigner, cfg)
+	pharmacyReportService := service.NewPharmacyReportService(transactionRepo, pharmacyRepo, cfg)
+	disputeService := service.NewDisputeService(disputeRepo, transactionRepo, walletRepo, userRepo, pharmacyRepo, uploadService, emailService, auditService, cfg)
+	organisationService := service.NewOrganisationService(organisationRepo, pharmacyRepo, userRepo, transactionRepo, settlementRepo, settlementService, auditService)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	walletHandler := handler.NewWalletHandler(walletService)
+	walletClosureHandler := handler.NewWalletClosureHandler(walletClosureService)
+	walletCampaignHandler := handler.NewWalletCampaignHandler(walletCampaignService)
+	walletContributionHandler := handler.NewWalletContributionHandler(walletContributionService)
 	transactionHandler := handler.NewTransactionHandler(transactionService, otpService)
 	otpHandler := handler.NewOTPHandler(otpService)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
 	adminHandler := handler.NewAdminHandler(adminService)
-	pharmacyAuthHandler := handler.NewPharmacyAuthHandler(pharmacyAuthService, walletRepo, userRepo, otpService, transactionService)
+	pharmacyAuthHandler := handler.NewPharmacyAuthHandler(pharmacyAuthService, otpService, transactionService, pharmacyWithdrawalService)
+	pharmacyStaffHandler := handler.NewPharmacyStaffHandler(pharmacyStaffService)
+	pharmacyOnboardingHandler := handler.NewPharmacyOnboardingHandler(pharmacyOnboardingService)
+	uploadHandler := handler.NewUploadHandler(uploadService, cfg)
+	manualCreditHandler := handler.NewManualCreditHandler(manualCreditService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	settlementHandler := handler.NewSettlementHandler(settlementService, cfg)
+	bankAccountHandler := handler.NewBankAccountHandler(bankAccountService)
+	payoutHandler := handler.NewPayoutHandler(payoutService)
+	organisationHandler := handler.NewOrganisationHandler(organisationService, cfg)
+	pharmacyDirectoryHandler := handler.NewPharmacyDirectoryHandler(pharmacyDirectoryService)
+	pharmacyAPIKeyHandler := handler.NewPharmacyAPIKeyHandler(pharmacyAPIKeyService)
+	feeRuleHandler := handler.NewFeeRuleHandler(feeRuleService)
+	feeQuoteHandler := handler.NewFeeQuoteHandler(feeQuoteService)
+	disputeHandler := handler.NewDisputeHandler(disputeService)
+	pharmacyReportHandler := handler.NewPharmacyReportHandler(pharmacyReportService, cfg)
+	walletLookupHandler := handler.NewWalletLookupHandler(walletLookupService)
+	walletQRHandler := handler.NewWalletQRHandler(walletQRService)
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtManager, authService)
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager, authService, pharmacyStaffService, pharmacyAPIKeyService)
 
 	// Create router
 	router := gin.New()
@@ -144,7 +247,9 @@ func main() {
 		{
 			// Public routes
 			wallets.GET("/code/:code", walletHandler.GetByShareableCode)
-			wallets.POST("/:id/deposit", transactionHandler.Deposit) // Anyone can contribute
+			wallets.GET("/code/:code/contributions", walletContributionHandler.ListByCode)
+			wallets.GET("/share/:token", walletHandler.GetByShareLink)
+			wallets.GET("/share/:token/contributions", walletContributionHandler.ListByShareLink)
 
 			// Protected routes
 			protected := wallets.Group("")
@@ -154,11 +259,62 @@ func main() {
 			protected.POST("", walletHandler.Create)
 			protected.PUT("/:id", walletHandler.Update)
 			protected.DELETE("/:id", walletHandler.Delete)
+			protected.PUT("/:id/status", walletHandler.ChangeStatus)
+			protected.GET("/:id/status-history", walletHandler.GetStatusHistory)
+			protected.POST("/:id/close", walletClosureHandler.Close)
+			protected.GET("/:id/closure", walletClosureHandler.GetForWallet)
+			protected.GET("/:id/campaigns", walletCampaignHandler.List)
+			protected.POST("/:id/campaigns", walletCampaignHandler.Create)
+			protected.GET("/:id/campaigns/:campaignId", walletCampaignHandler.Get)
+			protected.PUT("/:id/campaigns/:campaignId", walletCampaignHandler.Update)
+			protected.DELETE("/:id/campaigns/:campaignId", walletCampaignHandler.Cancel)
+			protected.GET("/:id/campaigns/:campaignId/events", walletCampaignHandler.GetEvents)
+			protected.GET("/:id/contributions", walletContributionHandler.ListForOwner)
+			protected.PUT("/:id/contributions/:transactionId/hide-message", walletContributionHandler.HideMessage)
+			protected.PUT("/:id/contributions/:transactionId/show-message", walletContributionHandler.ShowMessage)
 			protected.GET("/:id/transactions", transactionHandler.GetWalletTransactions)
+			protected.GET("/:id/spending-rules", walletHandler.GetSpendingRules)
+			protected.POST("/:id/spending-rules", walletHandler.AddSpendingRule)
+			protected.DELETE("/:id/spending-rules/:ruleId", walletHandler.RemoveSpendingRule)
+			protected.POST("/:id/rotate-code", walletHandler.RotateCode)
+			protected.GET("/:id/share-links", walletHandler.GetShareLinks)
+			protected.POST("/:id/share-links", walletHandler.CreateShareLink)
+			protected.DELETE("/:id/share-links/:linkId", walletHandler.RevokeShareLink)
+			protected.GET("/:id/qr", walletQRHandler.ContributionQR)
+			protected.GET("/:id/voucher", walletQRHandler.Voucher)
+			protected.GET("/:id/voucher/qr", walletQRHandler.VoucherQR)
 		}
 
-		// Withdrawal route (requires OTP)
+		// Organisation members see every branch of their pharmacy chain
+		organisations := api.Group("/organisations")
+		organisations.Use(authMiddleware.RequireAuth())
+		{
+			organisations.GET("", organisationHandler.ListMine)
+			organisations.GET("/:id", organisationHandler.GetForMember)
+			organisations.GET("/:id/branches", organisationHandler.GetBranchesForMember)
+			organisations.GET("/:id/transactions", organisationHandler.GetTransactionsForMember)
+			organisations.GET("/:id/settlements", organisationHandler.ListSettlementsForMember)
+			organisations.GET("/:id/settlements/summary", organisationHandler.GetSettlementSummaryForMember)
+			organisations.GET("/:id/settlements/:settlementId/statement", organisationHandler.GetSettlementStatementForMember)
+		}
+
+		// File uploads; downloads use signed links and need no login
+		api.POST("/uploads", authMiddleware.RequireAuth(), uploadHandler.Upload)
+		api.GET("/uploads/:id", authMiddleware.RequireAuth(), uploadHandler.Get)
+		api.GET("/files/:id", uploadHandler.Download)
+
+		// Withdrawal routes; withdrawing requires an OTP
 		api.POST("/withdrawals", authMiddleware.RequireAuth(), transactionHandler.Withdraw)
+		api.POST("/withdrawals/quote", authMiddleware.RequireAuth(), feeQuoteHandler.Quote)
+
+		// Disputes over pharmacy withdrawals, opened by wallet members
+		disputes := api.Group("/disputes")
+		disputes.Use(authMiddleware.RequireAuth())
+		{
+			disputes.POST("", disputeHandler.Open)
+			disputes.GET("", disputeHandler.ListForUser)
+			disputes.GET("/:id", disputeHandler.GetForUser)
+		}
 
 		// OTP routes
 		otp := api.Group("/otp")
@@ -172,36 +328,155 @@ func main() {
 		{
 			payments.POST("/initialize", paymentHandler.Initialize)
 			payments.GET("/verify/:reference", paymentHandler.Verify)
+			payments.POST("/webhook", payoutHandler.Webhook)
 		}
 
+		// Public pharmacy finder
+		api.GET("/pharmacies/nearby", pharmacyDirectoryHandler.Search)
+
 		// Pharmacy portal routes
 		pharmacy := api.Group("/pharmacy")
 		{
 			pharmacy.POST("/auth/login", pharmacyAuthHandler.Login)
+			pharmacy.POST("/auth/set-password", pharmacyStaffHandler.SetPassword)
+
+			// Self-registration; applicants use the token they were given
+			pharmacy.POST("/register", pharmacyOnboardingHandler.Register)
+			pharmacy.POST("/register/uploads", middleware.Quota(cfg.ApplicantUploadLimit, time.Hour), uploadHandler.UploadForApplicant)
+			pharmacy.GET("/register/:id", pharmacyOnboardingHandler.GetApplication)
+			pharmacy.PUT("/register/:id", pharmacyOnboardingHandler.Resubmit)
 
 			// Protected pharmacy routes
 			pharmacyProtected := pharmacy.Group("")
-			pharmacyProtected.Use(authMiddleware.RequireAuth())
+			pharmacyProtected.Use(authMiddleware.RequireAuth(), authMiddleware.RequirePharmacy())
+			canTransact := authMiddleware.RequirePharmacyRole(domain.PharmacyUserRoleManager, domain.PharmacyUserRoleCashier)
+			managerOnly := authMiddleware.RequirePharmacyRole(domain.PharmacyUserRoleManager)
+
 			pharmacyProtected.GET("/auth/me", pharmacyAuthHandler.GetCurrentPharmacy)
-			pharmacyProtected.GET("/wallets/:code", pharmacyAuthHandler.LookupWallet)
-			pharmacyProtected.POST("/withdrawals/initiate", pharmacyAuthHandler.InitiateWithdrawal)
-			pharmacyProtected.POST("/withdrawals/complete", pharmacyAuthHandler.CompleteWithdrawal)
+			pharmacyProtected.GET("/bank-accounts", managerOnly, bankAccountHandler.ListForPharmacy)
+			pharmacyProtected.POST("/bank-accounts", managerOnly, bankAccountHandler.Submit)
+			pharmacyProtected.GET("/listing", pharmacyDirectoryHandler.GetListing)
+			pharmacyProtected.PUT("/listing", managerOnly, pharmacyDirectoryHandler.UpdateListing)
+			pharmacyProtected.POST("/withdrawals/:id/void", managerOnly, pharmacyAuthHandler.VoidWithdrawal)
+			pharmacyProtected.GET("/transactions", pharmacyReportHandler.ListTransactions)
+			pharmacyProtected.GET("/reports/end-of-day", pharmacyReportHandler.EndOfDay)
+			pharmacyProtected.GET("/disputes", managerOnly, disputeHandler.ListForPharmacy)
+			pharmacyProtected.GET("/disputes/:id", managerOnly, disputeHandler.GetForPharmacy)
+			pharmacyProtected.POST("/disputes/:id/respond", managerOnly, disputeHandler.Respond)
+
+			// Counter routes also accept API keys from dispensing software
+			withKey := authMiddleware.RequirePharmacyOrAPIKey
+			pharmacy.GET("/wallets/:code", withKey(domain.APIKeyScopeWalletLookup), walletLookupHandler.Lookup)
+			pharmacy.POST("/wallets/scan", withKey(domain.APIKeyScopeWalletLookup), walletLookupHandler.ScanVoucher)
+			pharmacy.POST("/wallets/:code/balance-consent", withKey(domain.APIKeyScopeWalletLookup), walletLookupHandler.RequestBalanceConsent)
+			pharmacy.POST("/wallets/:code/balance", withKey(domain.APIKeyScopeWalletLookup), walletLookupHandler.DiscloseBalance)
+			pharmacy.POST("/withdrawals/quote", withKey(domain.APIKeyScopeWithdraw), feeQuoteHandler.QuoteForPharmacy)
+			pharmacy.POST("/withdrawals/initiate", withKey(domain.APIKeyScopeWithdraw), canTransact, pharmacyAuthHandler.InitiateWithdrawal)
+			pharmacy.POST("/withdrawals/complete", withKey(domain.APIKeyScopeWithdraw), canTransact, pharmacyAuthHandler.CompleteWithdrawal)
+			pharmacy.POST("/cash-ins", withKey(domain.APIKeyScopeCashIn), canTransact, pharmacyAuthHandler.CashIn)
+			pharmacy.GET("/cash-ins/summary", withKey(domain.APIKeyScopeCashIn), pharmacyAuthHandler.GetCashInSummary)
+			pharmacy.GET("/settlements", withKey(domain.APIKeyScopeSettlementsRead), settlementHandler.ListForPharmacy)
+			pharmacy.GET("/settlements/:id/statement", withKey(domain.APIKeyScopeSettlementsRead), settlementHandler.GetStatementForPharmacy)
+
+			// API keys are managed from a staff login only
+			pharmacyProtected.GET("/api-keys", managerOnly, pharmacyAPIKeyHandler.List)
+			pharmacyProtected.POST("/api-keys", managerOnly, pharmacyAPIKeyHandler.Create)
+			pharmacyProtected.DELETE("/api-keys/:id", managerOnly, pharmacyAPIKeyHandler.Revoke)
+
+			// Staff management
+			pharmacyProtected.GET("/staff", managerOnly, pharmacyStaffHandler.List)
+			pharmacyProtected.POST("/staff", managerOnly, pharmacyStaffHandler.Create)
+			pharmacyProtected.PUT("/staff/:id", managerOnly, pharmacyStaffHandler.Update)
+			pharmacyProtected.DELETE("/staff/:id", managerOnly, pharmacyStaffHandler.Disable)
+			pharmacyProtected.POST("/uploads", canTransact, uploadHandler.UploadForPharmacy)
+			pharmacyProtected.GET("/uploads/:id", uploadHandler.GetForPharmacy)
 		}
 
 		// Admin routes
 		admin := api.Group("/admin")
-		admin.Use(authMiddleware.RequireAuth())
-		// Note: In production, add admin role check middleware
+		admin.Use(authMiddleware.RequireAuth(), authMiddleware.RequireAdmin())
 		{
 			admin.GET("/dashboard/stats", adminHandler.GetDashboardStats)
 			admin.GET("/pharmacies", adminHandler.GetPharmacies)
 			admin.GET("/pharmacies/:id", adminHandler.GetPharmacy)
 			admin.POST("/pharmacies", adminHandler.CreatePharmacy)
 			admin.PUT("/pharmacies/:id", adminHandler.UpdatePharmacy)
-			admin.PUT("/pharmacies/:id/approve", adminHandler.ApprovePharmacy)
+			admin.PUT("/pharmacies/:id/approve", pharmacyOnboardingHandler.Approve)
+			admin.PUT("/pharmacies/:id/request-changes", pharmacyOnboardingHandler.RequestChanges)
+			admin.PUT("/pharmacies/:id/reject", pharmacyOnboardingHandler.Reject)
 			admin.PUT("/pharmacies/:id/suspend", adminHandler.SuspendPharmacy)
 			admin.PUT("/pharmacies/:id/reactivate", adminHandler.ReactivatePharmacy)
 			admin.DELETE("/pharmacies/:id", adminHandler.DeletePharmacy)
+			admin.GET("/pharmacies/:id/staff", pharmacyStaffHandler.ListForAdmin)
+			admin.POST("/pharmacies/:id/staff", pharmacyStaffHandler.CreateForAdmin)
+			admin.POST("/pharmacies/:id/staff/:userId/setup-link", pharmacyStaffHandler.SendSetupLink)
+			admin.GET("/pharmacies/:id/listing", pharmacyDirectoryHandler.GetListingForAdmin)
+			admin.GET("/pharmacies/:id/wallet-lookups", walletLookupHandler.ListForAdmin)
+			admin.PUT("/pharmacies/:id/listing", pharmacyDirectoryHandler.UpdateListingForAdmin)
+
+			// Pharmacy registration review queue
+			admin.GET("/pharmacy-applications", pharmacyOnboardingHandler.List)
+			admin.GET("/pharmacy-applications/:id", pharmacyOnboardingHandler.Get)
+			admin.GET("/uploads/:id", uploadHandler.GetForAdmin)
+
+			// Manual wallet credits replace the old public deposit endpoint
+			admin.POST("/wallets/:id/credits", manualCreditHandler.Request)
+			admin.PUT("/wallets/:id/freeze", walletHandler.Freeze)
+			admin.PUT("/wallets/:id/unfreeze", walletHandler.Unfreeze)
+
+			// Closed wallets' balances, refunds to contributors and the platform fund
+			admin.GET("/wallet-closures", walletClosureHandler.List)
+			admin.GET("/wallet-closures/:id", walletClosureHandler.Get)
+			admin.POST("/wallet-closures/:id/retry-refunds", walletClosureHandler.RetryRefunds)
+			admin.GET("/platform-fund", walletClosureHandler.PlatformFund)
+			admin.GET("/credits", manualCreditHandler.List)
+			admin.PUT("/credits/:id/approve", manualCreditHandler.Approve)
+			admin.PUT("/credits/:id/reject", manualCreditHandler.Reject)
+
+			admin.GET("/audit-logs", auditHandler.List)
+
+			admin.GET("/settlements", settlementHandler.List)
+			admin.POST("/settlements/run", settlementHandler.Run)
+			admin.GET("/settlements/:id/statement", settlementHandler.GetStatement)
+			admin.PUT("/settlements/:id/mark-paid", settlementHandler.MarkPaid)
+			admin.PUT("/settlements/:id/approve", payoutHandler.Approve)
+			admin.POST("/settlements/:id/retry-payout", payoutHandler.Retry)
+
+			// Bank account changes need verification and approval before a cooling-off period
+			admin.GET("/bank-accounts", bankAccountHandler.List)
+			admin.GET("/pharmacies/:id/bank-accounts", bankAccountHandler.ListByPharmacy)
+			admin.PUT("/bank-accounts/:id/verify", bankAccountHandler.Verify)
+			admin.PUT("/bank-accounts/:id/approve", bankAccountHandler.Approve)
+			admin.PUT("/bank-accounts/:id/reject", bankAccountHandler.Reject)
+
+			// Pharmacy chains
+			admin.GET("/organisations", organisationHandler.List)
+			admin.POST("/organisations", organisationHandler.Create)
+			admin.GET("/organisations/:id", organisationHandler.Get)
+			admin.PUT("/organisations/:id", organisationHandler.Update)
+			admin.GET("/organisations/:id/branches", organisationHandler.GetBranches)
+			admin.POST("/organisations/:id/branches", organisationHandler.AddBranch)
+			admin.DELETE("/organisations/:id/branches/:pharmacyId", organisationHandler.RemoveBranch)
+			admin.GET("/organisations/:id/members", organisationHandler.GetMembers)
+			admin.POST("/organisations/:id/members", organisationHandler.AddMember)
+			admin.DELETE("/organisations/:id/members/:memberId", organisationHandler.RemoveMember)
+			admin.GET("/organisations/:id/transactions", organisationHandler.GetTransactions)
+			admin.GET("/organisations/:id/settlements", organisationHandler.ListSettlements)
+			admin.GET("/organisations/:id/settlements/summary", organisationHandler.GetSettlementSummary)
+
+			// Fee rules
+			admin.GET("/fee-rules", feeRuleHandler.List)
+			admin.POST("/fee-rules", feeRuleHandler.Create)
+			admin.GET("/fee-rules/:id", feeRuleHandler.Get)
+			admin.GET("/fee-rules/:id/versions", feeRuleHandler.GetVersions)
+			admin.PUT("/fee-rules/:id", feeRuleHandler.Update)
+			admin.DELETE("/fee-rules/:id", feeRuleHandler.Retire)
+
+			// Disputes
+			admin.GET("/disputes", disputeHandler.List)
+			admin.GET("/disputes/:id", disputeHandler.Get)
+			admin.PUT("/disputes/:id/uphold", disputeHandler.Uphold)
+			admin.PUT("/disputes/:id/reject", disputeHandler.Reject)
 		}
 	}
 
@@ -214,6 +489,26 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if cfg.SettlementIntervalHours > 0 {
+		go service.RunSettlementScheduler(jobsCtx, settlementService, cfg, time.Duration(cfg.SettlementIntervalHours)*time.Hour)
+	}
+
+	if cfg.PayoutRetryIntervalMinutes > 0 {
+		go service.RunPayoutRetries(jobsCtx, payoutService, time.Duration(cfg.PayoutRetryIntervalMinutes)*time.Minute)
+	}
+
+	if cfg.CampaignCheckIntervalMinutes > 0 {
+		go service.RunCampaignChecks(jobsCtx, walletCampaignService, time.Duration(cfg.CampaignCheckIntervalMinutes)*time.Minute)
+	}
+
+	if cfg.UploadCleanupIntervalMinutes > 0 {
+		go service.RunUploadCleanup(jobsCtx, uploadService, time.Duration(cfg.UploadCleanupIntervalMinutes)*time.Minute)
+	}
+
 	// Start server in goroutine
 	go func() {
 		log.Printf("Server starting on port %s", cfg.Port)
@@ -228,6 +523,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS manual_credits;
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,36 @@
+-- Audit trail for privileged and money-moving actions
+CREATE TABLE audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id UUID,
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID,
+    metadata JSONB DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_type, actor_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+-- Manual wallet credits requested by admins, replacing the public deposit endpoint
+CREATE TABLE manual_credits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    amount DECIMAL(15, 2) NOT NULL,
+    reason TEXT NOT NULL,
+    external_reference VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by UUID NOT NULL REFERENCES users(id),
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    rejection_reason TEXT,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_manual_credits_wallet_id ON manual_credits(wallet_id);
+CREATE INDEX idx_manual_credits_status ON manual_credits(status);
+CREATE UNIQUE INDEX idx_manual_credits_external_reference ON manual_credits(external_reference) WHERE status <> 'rejected';
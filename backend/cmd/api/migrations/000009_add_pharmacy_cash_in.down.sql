@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_pharmacy_id_created_at;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS daily_cash_in_limit;
@@ -0,0 +1,4 @@
+-- Per-pharmacy override of the platform daily cash-in limit
+ALTER TABLE pharmacies ADD COLUMN daily_cash_in_limit DECIMAL(15, 2);
+
+CREATE INDEX idx_transactions_pharmacy_id_created_at ON transactions(pharmacy_id, created_at);
@@ -0,0 +1,29 @@
+-- Settlement batches group a pharmacy's completed withdrawals and cash-ins
+-- for a period into a single amount payable between CareWallet and the pharmacy.
+CREATE TABLE settlements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id),
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    withdrawal_count INTEGER NOT NULL DEFAULT 0,
+    gross_withdrawals DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    withdrawal_fees DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    net_withdrawals DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    cash_in_count INTEGER NOT NULL DEFAULT 0,
+    cash_in_total DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    -- Positive when CareWallet owes the pharmacy, negative when the pharmacy owes CareWallet
+    net_payable DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    status VARCHAR(20) NOT NULL DEFAULT 'unpaid',
+    payout_reference VARCHAR(100),
+    paid_by UUID REFERENCES users(id),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_settlements_pharmacy_id ON settlements(pharmacy_id);
+CREATE INDEX idx_settlements_status ON settlements(status);
+CREATE INDEX idx_settlements_period_end ON settlements(period_end);
+
+ALTER TABLE transactions ADD COLUMN settlement_id UUID REFERENCES settlements(id) ON DELETE SET NULL;
+CREATE INDEX idx_transactions_settlement_id ON transactions(settlement_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pharmacy_bank_accounts;
@@ -0,0 +1,24 @@
+-- Bank accounts pharmacies are paid into. Every change is a new row that must
+-- be verified and approved by an admin, and only takes effect after a cooling-off period.
+CREATE TABLE pharmacy_bank_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id) ON DELETE CASCADE,
+    bank_name VARCHAR(100) NOT NULL,
+    bank_code VARCHAR(20),
+    branch_code VARCHAR(20) NOT NULL,
+    account_number VARCHAR(30) NOT NULL,
+    account_holder VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    verification_status VARCHAR(20) NOT NULL DEFAULT 'unverified',
+    verified_name VARCHAR(255),
+    verified_at TIMESTAMP WITH TIME ZONE,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    rejection_reason TEXT,
+    effective_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pharmacy_bank_accounts_pharmacy_id ON pharmacy_bank_accounts(pharmacy_id);
+CREATE INDEX idx_pharmacy_bank_accounts_status ON pharmacy_bank_accounts(status);
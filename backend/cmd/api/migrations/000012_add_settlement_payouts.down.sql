@@ -0,0 +1,10 @@
+ALTER TABLE pharmacy_bank_accounts DROP COLUMN IF EXISTS recipient_code;
+
+DROP INDEX IF EXISTS idx_settlements_transfer_reference;
+
+ALTER TABLE settlements DROP COLUMN IF EXISTS last_payout_error;
+ALTER TABLE settlements DROP COLUMN IF EXISTS payout_attempts;
+ALTER TABLE settlements DROP COLUMN IF EXISTS transfer_code;
+ALTER TABLE settlements DROP COLUMN IF EXISTS transfer_reference;
+ALTER TABLE settlements DROP COLUMN IF EXISTS approved_at;
+ALTER TABLE settlements DROP COLUMN IF EXISTS approved_by;
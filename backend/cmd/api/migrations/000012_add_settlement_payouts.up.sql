@@ -0,0 +1,13 @@
+-- Approved settlements are paid out automatically with Paystack transfers.
+-- Each attempt gets its own transfer reference so late webhooks for an
+-- earlier attempt can be told apart from the current one.
+ALTER TABLE settlements ADD COLUMN approved_by UUID REFERENCES users(id);
+ALTER TABLE settlements ADD COLUMN approved_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE settlements ADD COLUMN transfer_reference VARCHAR(100);
+ALTER TABLE settlements ADD COLUMN transfer_code VARCHAR(100);
+ALTER TABLE settlements ADD COLUMN payout_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE settlements ADD COLUMN last_payout_error TEXT;
+
+CREATE UNIQUE INDEX idx_settlements_transfer_reference ON settlements(transfer_reference);
+
+ALTER TABLE pharmacy_bank_accounts ADD COLUMN recipient_code VARCHAR(100);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_pharmacy_user_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS pharmacy_user_id;
+DROP TABLE IF EXISTS pharmacy_withdrawals;
+DROP TABLE IF EXISTS pharmacy_users;
@@ -0,0 +1,43 @@
+-- Staff accounts for the pharmacy portal. Each cashier signs in with their own
+-- username so withdrawals and cash-ins can be traced to a person.
+CREATE TABLE pharmacy_users (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id) ON DELETE CASCADE,
+    username VARCHAR(50) NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'cashier',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_pharmacy_users_username ON pharmacy_users(pharmacy_id, LOWER(username));
+
+-- Existing pharmacies keep their shared password as a "manager" login.
+INSERT INTO pharmacy_users (pharmacy_id, username, full_name, password_hash, role)
+SELECT id, 'manager', name, password_hash, 'manager'
+FROM pharmacies
+WHERE password_hash IS NOT NULL;
+
+-- Withdrawals started at the pharmacy counter wait here until the
+-- beneficiary confirms them with an OTP.
+CREATE TABLE pharmacy_withdrawals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id),
+    pharmacy_user_id UUID NOT NULL REFERENCES pharmacy_users(id),
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(15, 2) NOT NULL,
+    otp_email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    transaction_id UUID REFERENCES transactions(id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pharmacy_withdrawals_pharmacy_id ON pharmacy_withdrawals(pharmacy_id);
+
+ALTER TABLE transactions ADD COLUMN pharmacy_user_id UUID REFERENCES pharmacy_users(id);
+CREATE INDEX idx_transactions_pharmacy_user_id ON transactions(pharmacy_user_id);
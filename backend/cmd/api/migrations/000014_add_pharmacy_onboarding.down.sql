@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS password_setup_tokens;
+DROP TABLE IF EXISTS pharmacy_documents;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS review_notes;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS submitted_at;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS application_token_hash;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS contact_name;
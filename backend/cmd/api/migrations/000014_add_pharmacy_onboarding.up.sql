@@ -0,0 +1,33 @@
+-- Pharmacies can apply through the public registration form. Applications
+-- stay pending until an admin approves them, asks for changes or rejects them.
+ALTER TABLE pharmacies ADD COLUMN contact_name VARCHAR(255);
+ALTER TABLE pharmacies ADD COLUMN application_token_hash VARCHAR(64);
+ALTER TABLE pharmacies ADD COLUMN submitted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE pharmacies ADD COLUMN review_notes TEXT;
+ALTER TABLE pharmacies ADD COLUMN reviewed_by UUID REFERENCES users(id);
+ALTER TABLE pharmacies ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE;
+
+-- Supporting documents such as the pharmacy licence, submitted with an application.
+CREATE TABLE pharmacy_documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id) ON DELETE CASCADE,
+    document_type VARCHAR(50) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pharmacy_documents_pharmacy_id ON pharmacy_documents(pharmacy_id);
+
+-- One-time links emailed to pharmacy staff so they choose their own password.
+-- Only a SHA-256 hash of the token is stored.
+CREATE TABLE password_setup_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_user_id UUID NOT NULL REFERENCES pharmacy_users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_setup_tokens_pharmacy_user_id ON password_setup_tokens(pharmacy_user_id);
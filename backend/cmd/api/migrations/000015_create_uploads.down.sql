@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pharmacy_documents_upload_id;
+DELETE FROM pharmacy_documents WHERE url IS NULL;
+ALTER TABLE pharmacy_documents ALTER COLUMN url SET NOT NULL;
+ALTER TABLE pharmacy_documents DROP COLUMN IF EXISTS upload_id;
+ALTER TABLE wallets DROP COLUMN IF EXISTS photo_upload_id;
+DROP TABLE IF EXISTS uploads;
@@ -0,0 +1,22 @@
+-- Files uploaded to blob storage. Contents live in the configured store under
+-- storage_key; this table records who owns each file and what it is for.
+CREATE TABLE uploads (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_type VARCHAR(20) NOT NULL,
+    owner_id UUID,
+    purpose VARCHAR(50) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_uploads_owner ON uploads(owner_type, owner_id);
+
+ALTER TABLE wallets ADD COLUMN photo_upload_id UUID REFERENCES uploads(id) ON DELETE SET NULL;
+
+-- Registration documents can point at an upload instead of an external URL.
+ALTER TABLE pharmacy_documents ADD COLUMN upload_id UUID REFERENCES uploads(id);
+ALTER TABLE pharmacy_documents ALTER COLUMN url DROP NOT NULL;
+CREATE UNIQUE INDEX idx_pharmacy_documents_upload_id ON pharmacy_documents(upload_id);
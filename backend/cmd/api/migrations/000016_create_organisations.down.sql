@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS wallet_spending_rules;
+DROP TABLE IF EXISTS organisation_members;
+DROP INDEX IF EXISTS idx_pharmacies_organisation_id;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS organisation_id;
+DROP TABLE IF EXISTS organisations;
@@ -0,0 +1,41 @@
+-- Organisations group pharmacies that belong to the same chain. Each branch
+-- keeps its own short code, staff and settlements.
+CREATE TABLE organisations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    registration_number VARCHAR(100),
+    email VARCHAR(255),
+    phone VARCHAR(20),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE pharmacies ADD COLUMN organisation_id UUID REFERENCES organisations(id) ON DELETE SET NULL;
+CREATE INDEX idx_pharmacies_organisation_id ON pharmacies(organisation_id);
+
+-- Users who can see every branch's transactions and settlements.
+CREATE TABLE organisation_members (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (organisation_id, user_id)
+);
+
+CREATE INDEX idx_organisation_members_user_id ON organisation_members(user_id);
+
+-- A wallet with no rules can be spent anywhere. Once it has rules it can only
+-- be spent at the listed pharmacies or at any branch of the listed chains.
+CREATE TABLE wallet_spending_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    pharmacy_id UUID REFERENCES pharmacies(id) ON DELETE CASCADE,
+    organisation_id UUID REFERENCES organisations(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK ((pharmacy_id IS NULL) <> (organisation_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_wallet_spending_rules_pharmacy ON wallet_spending_rules(wallet_id, pharmacy_id) WHERE pharmacy_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_wallet_spending_rules_organisation ON wallet_spending_rules(wallet_id, organisation_id) WHERE organisation_id IS NOT NULL;
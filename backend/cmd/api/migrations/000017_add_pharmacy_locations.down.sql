@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS pharmacy_opening_hours;
+DROP INDEX IF EXISTS idx_pharmacies_location;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS services;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS longitude;
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,21 @@
+-- Location and listing details used by the public pharmacy finder. Distances
+-- are computed in SQL, so no geocoding service or PostGIS is needed.
+ALTER TABLE pharmacies ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90);
+ALTER TABLE pharmacies ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180);
+ALTER TABLE pharmacies ADD COLUMN services TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_pharmacies_location ON pharmacies(latitude, longitude) WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+
+-- Weekly opening hours in the business timezone. day_of_week follows
+-- Postgres' EXTRACT(DOW): 0 is Sunday. A day can have several periods, and
+-- closes_at may be 24:00 for pharmacies open until midnight.
+CREATE TABLE pharmacy_opening_hours (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX idx_pharmacy_opening_hours_pharmacy_id ON pharmacy_opening_hours(pharmacy_id, day_of_week);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pharmacy_api_keys;
@@ -0,0 +1,19 @@
+-- API keys let a pharmacy's dispensing software call CareWallet directly.
+-- Keys act for the manager who issued them, limited to their scopes. Only a
+-- SHA-256 hash of the key is stored; the prefix identifies it in listings.
+CREATE TABLE pharmacy_api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES pharmacy_users(id),
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL UNIQUE,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by UUID REFERENCES pharmacy_users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pharmacy_api_keys_pharmacy_id ON pharmacy_api_keys(pharmacy_id);
@@ -0,0 +1,8 @@
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS contributor_pays_fee,
+    DROP COLUMN IF EXISTS fee_rule_id,
+    DROP COLUMN IF EXISTS fee;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_rule_id;
+
+DROP TABLE IF EXISTS fee_rules;
@@ -0,0 +1,43 @@
+-- Fee rules price withdrawals and deposits. A rule applies platform-wide, to
+-- one organisation's branches or to a single pharmacy. Rules are never edited
+-- in place: a change adds the next version under the same code and
+-- supersedes the current one, so every transaction keeps pointing at the
+-- exact terms that priced it. A retired rule is superseded with no successor.
+CREATE TABLE fee_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    name VARCHAR(255) NOT NULL,
+    transaction_type VARCHAR(20) NOT NULL CHECK (transaction_type IN ('withdrawal', 'deposit')),
+    pharmacy_id UUID REFERENCES pharmacies(id) ON DELETE CASCADE,
+    organisation_id UUID REFERENCES organisations(id) ON DELETE CASCADE,
+    percentage DECIMAL(7, 6) NOT NULL DEFAULT 0 CHECK (percentage >= 0 AND percentage < 1),
+    flat_amount DECIMAL(15, 2) NOT NULL DEFAULT 0 CHECK (flat_amount >= 0),
+    min_fee DECIMAL(15, 2) CHECK (min_fee >= 0),
+    max_fee DECIMAL(15, 2) CHECK (max_fee >= 0),
+    is_waiver BOOLEAN NOT NULL DEFAULT FALSE,
+    contributor_pays VARCHAR(20) NOT NULL DEFAULT 'never' CHECK (contributor_pays IN ('never', 'optional', 'always')),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id),
+    superseded_at TIMESTAMP WITH TIME ZONE,
+    superseded_by UUID REFERENCES fee_rules(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (code, version),
+    CHECK (pharmacy_id IS NULL OR organisation_id IS NULL),
+    CHECK (min_fee IS NULL OR max_fee IS NULL OR min_fee <= max_fee),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+-- Only one version of each rule is current at a time.
+CREATE UNIQUE INDEX idx_fee_rules_current_code ON fee_rules(code) WHERE superseded_at IS NULL;
+CREATE INDEX idx_fee_rules_current_type ON fee_rules(transaction_type) WHERE superseded_at IS NULL;
+
+ALTER TABLE transactions ADD COLUMN fee_rule_id UUID REFERENCES fee_rules(id);
+
+-- Deposits are priced when the payment is started so the contributor is
+-- charged exactly what they were shown.
+ALTER TABLE payments
+    ADD COLUMN fee DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN fee_rule_id UUID REFERENCES fee_rules(id),
+    ADD COLUMN contributor_pays_fee BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_quote_id;
+ALTER TABLE pharmacy_withdrawals DROP COLUMN IF EXISTS fee_quote_id;
+DROP TABLE IF EXISTS fee_quotes;
@@ -0,0 +1,18 @@
+-- A fee quote fixes the fee on a withdrawal before it is made. The withdrawal
+-- that redeems the quote is charged exactly the quoted fee, even if the fee
+-- rules change in between. Each quote can be redeemed once.
+CREATE TABLE fee_quotes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id) ON DELETE CASCADE,
+    amount DECIMAL(15, 2) NOT NULL,
+    fee DECIMAL(15, 2) NOT NULL,
+    net_amount DECIMAL(15, 2) NOT NULL,
+    fee_rule_id UUID REFERENCES fee_rules(id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    redeemed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE pharmacy_withdrawals ADD COLUMN fee_quote_id UUID REFERENCES fee_quotes(id);
+ALTER TABLE transactions ADD COLUMN fee_quote_id UUID REFERENCES fee_quotes(id);
@@ -0,0 +1,15 @@
+-- What a pharmacy dispensed against a withdrawal. Line items are optional;
+-- when present their totals add up to the withdrawal amount.
+CREATE TABLE transaction_line_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    nappi_code VARCHAR(20),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price DECIMAL(15, 2) NOT NULL CHECK (unit_price >= 0),
+    line_total DECIMAL(15, 2) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('prescription', 'otc', 'other')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (transaction_id, position)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_reversal_of;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS reversal_reason,
+    DROP COLUMN IF EXISTS reversal_of;
@@ -0,0 +1,8 @@
+-- A reversal transaction undoes a completed withdrawal: it credits the amount
+-- back to the wallet and the original is marked reversed so it is never
+-- settled. Each transaction can be reversed once.
+ALTER TABLE transactions
+    ADD COLUMN reversal_of UUID REFERENCES transactions(id),
+    ADD COLUMN reversal_reason TEXT;
+
+CREATE UNIQUE INDEX idx_transactions_reversal_of ON transactions(reversal_of) WHERE reversal_of IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE settlements
+    DROP COLUMN IF EXISTS reversal_total,
+    DROP COLUMN IF EXISTS reversal_count;
+
+DROP TABLE IF EXISTS disputes;
@@ -0,0 +1,32 @@
+-- A dispute is a wallet member contesting a pharmacy withdrawal. The pharmacy
+-- may respond with evidence before an admin upholds or rejects it. Upholding
+-- reverses the withdrawal; if the pharmacy had already been settled for it,
+-- the reversal is deducted from the pharmacy's next settlement.
+CREATE TABLE disputes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id),
+    opened_by UUID NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'responded', 'upheld', 'rejected')),
+    pharmacy_response TEXT,
+    evidence_upload_ids UUID[] NOT NULL DEFAULT '{}',
+    responded_by UUID REFERENCES pharmacy_users(id),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    resolved_by UUID REFERENCES users(id),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution_note TEXT,
+    reversal_transaction_id UUID REFERENCES transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_disputes_status ON disputes(status);
+CREATE INDEX idx_disputes_pharmacy_id ON disputes(pharmacy_id);
+CREATE INDEX idx_disputes_wallet_id ON disputes(wallet_id);
+
+ALTER TABLE settlements
+    ADD COLUMN reversal_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reversal_total DECIMAL(15, 2) NOT NULL DEFAULT 0.00;
@@ -0,0 +1,13 @@
+-- Every wallet code a pharmacy looks up is logged, found or not, so code
+-- scanning can be spotted and failed lookups rate-limited per pharmacy.
+CREATE TABLE wallet_lookups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pharmacy_id UUID NOT NULL REFERENCES pharmacies(id) ON DELETE CASCADE,
+    pharmacy_user_id UUID REFERENCES pharmacy_users(id) ON DELETE SET NULL,
+    code VARCHAR(20) NOT NULL,
+    wallet_id UUID REFERENCES wallets(id) ON DELETE SET NULL,
+    found BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_wallet_lookups_pharmacy_id ON wallet_lookups(pharmacy_id, created_at);
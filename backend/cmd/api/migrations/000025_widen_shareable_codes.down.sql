@@ -0,0 +1,2 @@
+-- Fails if any wallet has been given a ten-character code.
+ALTER TABLE wallets ALTER COLUMN shareable_code TYPE VARCHAR(8);
@@ -0,0 +1,3 @@
+-- New shareable codes carry a check character and are ten characters long.
+-- Existing eight-character codes stay valid.
+ALTER TABLE wallets ALTER COLUMN shareable_code TYPE VARCHAR(12);
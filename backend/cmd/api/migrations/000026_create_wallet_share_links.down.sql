@@ -0,0 +1,3 @@
+ALTER TABLE payments DROP COLUMN IF EXISTS share_link_id;
+DROP TABLE IF EXISTS wallet_share_links;
+DROP TABLE IF EXISTS wallet_retired_codes;
@@ -0,0 +1,32 @@
+-- Codes a wallet used before its code was rotated. They still lead
+-- contributors to the wallet's public page but are refused at pharmacies.
+CREATE TABLE wallet_retired_codes (
+    code VARCHAR(12) PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    retired_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    retired_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_wallet_retired_codes_wallet_id ON wallet_retired_codes(wallet_id);
+
+-- Extra links to a wallet's contribution page. Unlike the shareable code a
+-- link cannot be used at a pharmacy, and it can expire, be limited to a
+-- number of contributions and be revoked.
+CREATE TABLE wallet_share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    token VARCHAR(64) NOT NULL UNIQUE,
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    max_contributions INTEGER,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    contribution_count INTEGER NOT NULL DEFAULT 0,
+    contribution_total DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_wallet_share_links_wallet_id ON wallet_share_links(wallet_id);
+
+ALTER TABLE payments ADD COLUMN share_link_id UUID REFERENCES wallet_share_links(id) ON DELETE SET NULL;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS wallet_status_changes;
+
+ALTER TABLE wallets
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS frozen_from,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status_reason;
+
+UPDATE wallets SET status = 'inactive' WHERE status IN ('paused', 'frozen');
+UPDATE wallets SET status = 'active' WHERE status = 'closing';
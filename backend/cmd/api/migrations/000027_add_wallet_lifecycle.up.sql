@@ -0,0 +1,23 @@
+-- Wallet statuses are now active, paused, closing, closed and frozen, and
+-- only change through allowed transitions. Deleting a wallet closes it and
+-- hides it instead of removing its history.
+UPDATE wallets SET status = 'paused' WHERE status = 'inactive';
+
+ALTER TABLE wallets
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN status_changed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN frozen_from VARCHAR(20),
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE wallet_status_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_wallet_status_changes_wallet_id ON wallet_status_changes(wallet_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS wallet_closure_refunds;
+DROP TABLE IF EXISTS wallet_closures;
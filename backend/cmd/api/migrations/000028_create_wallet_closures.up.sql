@@ -0,0 +1,38 @@
+-- How a closed wallet's remaining balance was disposed of: moved to another
+-- wallet, refunded to contributors or donated to the platform fund. The
+-- wallet is debited and closed in the same database transaction.
+CREATE TABLE wallet_closures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL UNIQUE REFERENCES wallets(id) ON DELETE CASCADE,
+    requested_by UUID NOT NULL REFERENCES users(id),
+    disposition VARCHAR(20) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    target_wallet_id UUID REFERENCES wallets(id),
+    transaction_id UUID REFERENCES transactions(id),
+    target_transaction_id UUID REFERENCES transactions(id),
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_wallet_closures_status ON wallet_closures(status, created_at);
+
+-- One contributor's share of a refunded balance, paid back through Paystack
+-- against the payment they contributed with.
+CREATE TABLE wallet_closure_refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    closure_id UUID NOT NULL REFERENCES wallet_closures(id) ON DELETE CASCADE,
+    payment_id UUID NOT NULL REFERENCES payments(id),
+    transaction_id UUID NOT NULL REFERENCES transactions(id),
+    contributor_email VARCHAR(255) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    gateway_refund_id VARCHAR(50) NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_wallet_closure_refunds_closure_id ON wallet_closure_refunds(closure_id);
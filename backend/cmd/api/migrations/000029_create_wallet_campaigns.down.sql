@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_wallet_id_created_at;
+DROP TABLE IF EXISTS wallet_campaign_events;
+DROP TABLE IF EXISTS wallet_campaign_milestones;
+DROP TABLE IF EXISTS wallet_campaigns;
@@ -0,0 +1,46 @@
+-- Fundraising campaigns for wallets: a goal to reach between two dates, with
+-- milestones along the way. Progress is worked out from the wallet's
+-- transactions; only the moments milestones and the goal were reached are
+-- stored. A wallet runs at most one campaign at a time.
+CREATE TABLE wallet_campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    title VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    goal DECIMAL(15, 2) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    goal_reached_at TIMESTAMP WITH TIME ZONE,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_wallet_campaigns_one_active ON wallet_campaigns(wallet_id) WHERE status = 'active';
+CREATE INDEX idx_wallet_campaigns_wallet_id ON wallet_campaigns(wallet_id, created_at);
+
+CREATE TABLE wallet_campaign_milestones (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    campaign_id UUID NOT NULL REFERENCES wallet_campaigns(id) ON DELETE CASCADE,
+    label VARCHAR(100) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    reached_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (campaign_id, amount)
+);
+
+CREATE TABLE wallet_campaign_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    campaign_id UUID NOT NULL REFERENCES wallet_campaigns(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    milestone_id UUID REFERENCES wallet_campaign_milestones(id) ON DELETE SET NULL,
+    raised DECIMAL(15, 2) NOT NULL,
+    contributor_count INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_wallet_campaign_events_campaign_id ON wallet_campaign_events(campaign_id, created_at);
+
+-- Campaign progress sums a wallet's transactions over a date range.
+CREATE INDEX idx_transactions_wallet_id_created_at ON transactions(wallet_id, created_at);
@@ -0,0 +1,10 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS message_hidden_by,
+    DROP COLUMN IF EXISTS message_hidden_at,
+    DROP COLUMN IF EXISTS contributor_hides_amount,
+    DROP COLUMN IF EXISTS contributor_anonymous;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS hide_amount,
+    DROP COLUMN IF EXISTS anonymous,
+    DROP COLUMN IF EXISTS display_name;
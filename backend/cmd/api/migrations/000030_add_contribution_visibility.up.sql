@@ -0,0 +1,13 @@
+-- How a contribution appears on the wallet's public contributor wall. The
+-- contributor chooses whether their name and amount are shown; the wallet's
+-- creator can hide the message.
+ALTER TABLE payments
+    ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN hide_amount BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE transactions
+    ADD COLUMN contributor_anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN contributor_hides_amount BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN message_hidden_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN message_hidden_by UUID REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_wallet_lookups_amount_checks;
+
+ALTER TABLE wallet_lookups DROP COLUMN IF EXISTS amount_checked;
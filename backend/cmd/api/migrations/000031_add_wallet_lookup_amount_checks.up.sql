@@ -0,0 +1,5 @@
+-- Lookups that asked whether an amount is covered are counted per pharmacy
+-- and wallet, so the balance cannot be narrowed down by repeated checks.
+ALTER TABLE wallet_lookups ADD COLUMN amount_checked BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_wallet_lookups_amount_checks ON wallet_lookups(pharmacy_id, wallet_id, created_at) WHERE amount_checked;
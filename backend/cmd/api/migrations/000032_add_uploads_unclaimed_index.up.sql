@@ -0,0 +1,2 @@
+-- Applicant uploads that no registration claims are removed after a while.
+CREATE INDEX idx_uploads_unclaimed ON uploads(created_at) WHERE owner_id IS NULL;
@@ -0,0 +1,76 @@
+// Package bankverify confirms that a bank account belongs to the person or
+// business it is being registered for before any money is paid into it.
+package bankverify
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+type AccountDetails struct {
+	BankCode      string
+	BranchCode    string
+	AccountNumber string
+	AccountHolder string
+}
+
+type Result struct {
+	// AccountName is the holder name reported by the bank or provider.
+	AccountName string
+}
+
+// Verifier resolves the registered holder name of a bank account.
+type Verifier interface {
+	Verify(ctx context.Context, details AccountDetails) (*Result, error)
+}
+
+// StubVerifier is a local stand-in for a real provider. It echoes the supplied
+// holder name back, except for account numbers starting with "000" which are
+// reported as belonging to someone else so the mismatch path can be exercised.
+type StubVerifier struct{}
+
+func NewStubVerifier() Verifier {
+	return &StubVerifier{}
+}
+
+func (v *StubVerifier) Verify(ctx context.Context, details AccountDetails) (*Result, error) {
+	if strings.HasPrefix(details.AccountNumber, "000") {
+		return &Result{AccountName: "UNKNOWN ACCOUNT HOLDER"}, nil
+	}
+
+	return &Result{AccountName: strings.ToUpper(details.AccountHolder)}, nil
+}
+
+// NamesMatch compares two account holder names ignoring case, punctuation,
+// word order and common company suffixes.
+func NamesMatch(expected, actual string) bool {
+	a, b := nameTokens(expected), nameTokens(actual)
+	if len(a) == 0 || len(b) == 0 || len(a) != len(b) {
+		return false
+	}
+	for token := range a {
+		if !b[token] {
+			return false
+		}
+	}
+	return true
+}
+
+var ignoredNameTokens = map[string]bool{
+	"PTY": true, "LTD": true, "LIMITED": true, "CC": true, "INC": true, "THE": true,
+}
+
+func nameTokens(name string) map[string]bool {
+	fields := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if !ignoredNameTokens[f] {
+			tokens[f] = true
+		}
+	}
+	return tokens
+}
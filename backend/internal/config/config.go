@@ -18,6 +18,92 @@ type Config struct {
 	OTPExpirationMinutes  int
 	PlatformFeePercentage float64
 	PaystackSecretKey     string
+
+	// ManualCreditRequiresApproval requires a second admin to approve
+	// manual wallet credits before any funds are moved.
+	ManualCreditRequiresApproval bool
+
+	// CashInDailyLimit caps the cash a pharmacy may accept per day unless the
+	// pharmacy has its own limit.
+	CashInDailyLimit float64
+	// Timezone is used for business-day boundaries such as daily limits.
+	Timezone string
+
+	// SettlementIntervalHours is how often settlement batches are generated;
+	// zero disables the scheduler.
+	SettlementIntervalHours int
+
+	// BankAccountCoolingOffHours delays approved bank account changes before
+	// settlements are paid into the new account.
+	BankAccountCoolingOffHours int
+
+	// PayoutMaxAttempts limits automatic retries of failed settlement
+	// transfers; admins can still retry by hand.
+	PayoutMaxAttempts          int
+	PayoutRetryIntervalMinutes int
+
+	// CampaignCheckIntervalMinutes is how often wallet campaigns are checked
+	// for milestones, goals and deadlines; zero disables the checks.
+	CampaignCheckIntervalMinutes int
+
+	// FrontendURL is the base of links emailed to users, such as the link
+	// pharmacy managers use to set their password.
+	FrontendURL             string
+	PasswordSetupTokenHours int
+
+	// StorageBackend selects where uploads are kept: "local" or "s3". The S3
+	// settings also work with compatible servers such as MinIO.
+	StorageBackend    string
+	StorageLocalPath  string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UsePathStyle    bool
+	UploadMaxBytes    int64
+
+	// ApplicantUploadLimit is how many registration documents one client may
+	// upload an hour before applying; zero removes the limit. Applicant
+	// uploads no registration claims are removed UnclaimedUploadTTLHours
+	// after upload, checked every UploadCleanupIntervalMinutes (zero disables
+	// the cleanup).
+	ApplicantUploadLimit         int
+	UnclaimedUploadTTLHours      int
+	UploadCleanupIntervalMinutes int
+
+	// PublicAPIURL is the externally reachable base URL of this API, used in
+	// signed file download links.
+	PublicAPIURL      string
+	FileURLSecret     string
+	FileURLTTLMinutes int
+
+	// FeeQuoteValidityMinutes is how long a withdrawal fee quote can be
+	// redeemed for.
+	FeeQuoteValidityMinutes int
+
+	// WithdrawalVoidWindowMinutes limits how long after completion a pharmacy
+	// manager may void a withdrawal. Voids are only ever allowed on the same
+	// business day and before settlement; 0 sets no further limit.
+	WithdrawalVoidWindowMinutes int
+
+	// DisputeWindowDays is how long after a withdrawal a wallet member may
+	// dispute it.
+	DisputeWindowDays int
+
+	// WalletLookupFailureLimit is how many wallet codes that match no wallet
+	// a pharmacy may look up in an hour before further lookups are refused.
+	WalletLookupFailureLimit int
+	// WalletAmountCheckLimit is how many times an hour a pharmacy may ask
+	// whether an amount is covered by the same wallet, so the balance cannot
+	// be found by trying amounts; zero removes the limit.
+	WalletAmountCheckLimit int
+
+	// WalletVoucherSecret signs the wallet vouchers pharmacies scan instead
+	// of typing a wallet code; a voucher expires WalletVoucherTTLMinutes
+	// after it is shown.
+	WalletVoucherSecret     string
+	WalletVoucherTTLMinutes int
 }
 
 func Load() *Config {
@@ -32,6 +118,53 @@ func Load() *Config {
 		OTPExpirationMinutes:  getEnvAsInt("OTP_EXPIRATION_MINUTES", 10),
 		PlatformFeePercentage: getEnvAsFloat("PLATFORM_FEE_PERCENTAGE", 0.04),
 		PaystackSecretKey:     getEnv("PAYSTACK_SECRET_KEY", ""),
+
+		ManualCreditRequiresApproval: getEnvAsBool("MANUAL_CREDIT_REQUIRES_APPROVAL", true),
+
+		CashInDailyLimit: getEnvAsFloat("CASH_IN_DAILY_LIMIT", 5000),
+		Timezone:         getEnv("TIMEZONE", "Africa/Johannesburg"),
+
+		SettlementIntervalHours: getEnvAsInt("SETTLEMENT_INTERVAL_HOURS", 24),
+
+		BankAccountCoolingOffHours: getEnvAsInt("BANK_ACCOUNT_COOLING_OFF_HOURS", 72),
+
+		PayoutMaxAttempts:          getEnvAsInt("PAYOUT_MAX_ATTEMPTS", 3),
+		PayoutRetryIntervalMinutes: getEnvAsInt("PAYOUT_RETRY_INTERVAL_MINUTES", 30),
+
+		CampaignCheckIntervalMinutes: getEnvAsInt("CAMPAIGN_CHECK_INTERVAL_MINUTES", 15),
+
+		FrontendURL:             strings.TrimRight(getEnv("FRONTEND_URL", "http://localhost:3000"), "/"),
+		PasswordSetupTokenHours: getEnvAsInt("PASSWORD_SETUP_TOKEN_HOURS", 72),
+
+		StorageBackend:    getEnv("STORAGE_BACKEND", "local"),
+		StorageLocalPath:  getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+		S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		S3UsePathStyle:    getEnvAsBool("S3_USE_PATH_STYLE", false),
+		UploadMaxBytes:    int64(getEnvAsInt("UPLOAD_MAX_BYTES", 5<<20)),
+
+		ApplicantUploadLimit:         getEnvAsInt("APPLICANT_UPLOAD_LIMIT", 20),
+		UnclaimedUploadTTLHours:      getEnvAsInt("UNCLAIMED_UPLOAD_TTL_HOURS", 24),
+		UploadCleanupIntervalMinutes: getEnvAsInt("UPLOAD_CLEANUP_INTERVAL_MINUTES", 60),
+
+		PublicAPIURL:      strings.TrimRight(getEnv("PUBLIC_API_URL", "http://localhost:8080"), "/"),
+		FileURLSecret:     getEnv("FILE_URL_SECRET", "your-file-url-secret-change-in-production"),
+		FileURLTTLMinutes: getEnvAsInt("FILE_URL_TTL_MINUTES", 15),
+
+		FeeQuoteValidityMinutes: getEnvAsInt("FEE_QUOTE_VALIDITY_MINUTES", 10),
+
+		WithdrawalVoidWindowMinutes: getEnvAsInt("WITHDRAWAL_VOID_WINDOW_MINUTES", 0),
+
+		DisputeWindowDays: getEnvAsInt("DISPUTE_WINDOW_DAYS", 60),
+
+		WalletLookupFailureLimit: getEnvAsInt("WALLET_LOOKUP_FAILURE_LIMIT", 20),
+		WalletAmountCheckLimit:   getEnvAsInt("WALLET_AMOUNT_CHECK_LIMIT", 5),
+
+		WalletVoucherSecret:     getEnv("WALLET_VOUCHER_SECRET", "your-wallet-voucher-secret-change-in-production"),
+		WalletVoucherTTLMinutes: getEnvAsInt("WALLET_VOUCHER_TTL_MINUTES", 10),
 	}
 }
 
@@ -60,6 +193,15 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	if value, exists := os.LookupEnv(key); exists {
 		return strings.Split(value, ",")
@@ -74,3 +216,18 @@ func (c *Config) IsDevelopment() bool {
 func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
+
+// Location returns the configured business timezone, falling back to UTC.
+func (c *Config) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// StartOfDay returns midnight of the business day containing t.
+func (c *Config) StartOfDay(t time.Time) time.Time {
+	local := t.In(c.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+type AuditActorType string
+
+const (
+	AuditActorAdmin    AuditActorType = "admin"
+	AuditActorUser     AuditActorType = "user"
+	AuditActorPharmacy AuditActorType = "pharmacy"
+	AuditActorSystem   AuditActorType = "system"
+)
+
+type AuditLog struct {
+	ID         string                 `json:"id"`
+	ActorType  AuditActorType         `json:"actor_type"`
+	ActorID    *string                `json:"actor_id,omitempty"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   *string                `json:"entity_id,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
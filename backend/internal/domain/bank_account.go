@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type BankAccountStatus string
+
+const (
+	BankAccountStatusPending   BankAccountStatus = "pending"
+	BankAccountStatusApproved  BankAccountStatus = "approved"
+	BankAccountStatusRejected  BankAccountStatus = "rejected"
+	BankAccountStatusCancelled BankAccountStatus = "cancelled"
+)
+
+type BankVerificationStatus string
+
+const (
+	BankVerificationUnverified BankVerificationStatus = "unverified"
+	BankVerificationVerified   BankVerificationStatus = "verified"
+	BankVerificationMismatch   BankVerificationStatus = "mismatch"
+	BankVerificationFailed     BankVerificationStatus = "failed"
+)
+
+type PharmacyBankAccount struct {
+	ID                 string                 `json:"id"`
+	PharmacyID         string                 `json:"pharmacy_id"`
+	BankName           string                 `json:"bank_name"`
+	BankCode           string                 `json:"bank_code,omitempty"`
+	BranchCode         string                 `json:"branch_code"`
+	AccountNumber      string                 `json:"-"`
+	AccountHolder      string                 `json:"account_holder"`
+	Status             BankAccountStatus      `json:"status"`
+	VerificationStatus BankVerificationStatus `json:"verification_status"`
+	VerifiedName       string                 `json:"verified_name,omitempty"`
+	VerifiedAt         *time.Time             `json:"verified_at,omitempty"`
+	ReviewedBy         *string                `json:"reviewed_by,omitempty"`
+	ReviewedAt         *time.Time             `json:"reviewed_at,omitempty"`
+	RejectionReason    string                 `json:"rejection_reason,omitempty"`
+	EffectiveAt        *time.Time             `json:"effective_at,omitempty"`
+	RecipientCode      string                 `json:"-"`
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+}
+
+// MaskedAccountNumber hides all but the last four digits of the account number.
+func (a *PharmacyBankAccount) MaskedAccountNumber() string {
+	if len(a.AccountNumber) <= 4 {
+		return strings.Repeat("*", len(a.AccountNumber))
+	}
+	return strings.Repeat("*", len(a.AccountNumber)-4) + a.AccountNumber[len(a.AccountNumber)-4:]
+}
+
+// IsEffective reports whether the account is approved and past its cooling-off period.
+func (a *PharmacyBankAccount) IsEffective(now time.Time) bool {
+	return a.Status == BankAccountStatusApproved && a.EffectiveAt != nil && !a.EffectiveAt.After(now)
+}
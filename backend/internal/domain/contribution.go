@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ContributionWallTypes are the transactions shown on a wallet's contributor
+// wall: money given by people, not credits from the platform or from other
+// wallets.
+var ContributionWallTypes = []TransactionType{
+	TransactionTypeDeposit,
+	TransactionTypeCashDeposit,
+}
+
+// Contribution is a contribution as it appears on the wallet's contributor
+// wall. It deliberately carries no contact details, so they cannot leak onto
+// the public page.
+type Contribution struct {
+	TransactionID   string
+	WalletID        string
+	Name            string
+	Anonymous       bool
+	HideAmount      bool
+	Amount          decimal.Decimal
+	Message         string
+	MessageHiddenAt *time.Time
+	CreatedAt       time.Time
+}
+
+// DisplayName is the name shown for the contributor: "Anonymous" when they
+// asked not to be named or gave no name.
+func (c *Contribution) DisplayName() string {
+	if c.Anonymous || c.Name == "" {
+		return "Anonymous"
+	}
+	return c.Name
+}
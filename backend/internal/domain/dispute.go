@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen      DisputeStatus = "open"
+	DisputeStatusResponded DisputeStatus = "responded"
+	DisputeStatusUpheld    DisputeStatus = "upheld"
+	DisputeStatusRejected  DisputeStatus = "rejected"
+)
+
+// Dispute is a wallet member contesting a pharmacy withdrawal. The pharmacy
+// can respond with evidence until an admin upholds or rejects the dispute.
+// Upholding it reverses the withdrawal; ReversalTransactionID is that
+// reversal.
+type Dispute struct {
+	ID                    string        `json:"id"`
+	TransactionID         string        `json:"transaction_id"`
+	WalletID              string        `json:"wallet_id"`
+	PharmacyID            string        `json:"pharmacy_id"`
+	OpenedBy              string        `json:"opened_by"`
+	Reason                string        `json:"reason"`
+	Status                DisputeStatus `json:"status"`
+	PharmacyResponse      string        `json:"pharmacy_response,omitempty"`
+	EvidenceUploadIDs     []string      `json:"evidence_upload_ids"`
+	RespondedBy           *string       `json:"responded_by,omitempty"`
+	RespondedAt           *time.Time    `json:"responded_at,omitempty"`
+	ResolvedBy            *string       `json:"resolved_by,omitempty"`
+	ResolvedAt            *time.Time    `json:"resolved_at,omitempty"`
+	ResolutionNote        string        `json:"resolution_note,omitempty"`
+	ReversalTransactionID *string       `json:"reversal_transaction_id,omitempty"`
+	CreatedAt             time.Time     `json:"created_at"`
+	UpdatedAt             time.Time     `json:"updated_at"`
+}
+
+// IsResolved reports whether an admin has upheld or rejected the dispute.
+func (d *Dispute) IsResolved() bool {
+	return d.Status == DisputeStatusUpheld || d.Status == DisputeStatusRejected
+}
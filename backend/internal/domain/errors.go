@@ -13,21 +13,152 @@ var (
 	ErrWalletAccessDenied = errors.New("you do not have access to this wallet")
 	ErrWalletHasBalance   = errors.New("cannot delete wallet with remaining balance")
 	ErrInvalidWalletCode  = errors.New("invalid wallet code")
+	ErrNoBeneficiaryEmail = errors.New("no beneficiary email found for this wallet")
+	ErrLookupLimitReached = errors.New("too many wallet lookups failed; try again later")
+	ErrAmountCheckLimit   = errors.New("too many amount checks on this wallet; ask the owner to share their balance instead")
+
+	// Wallet lifecycle errors
+	ErrInvalidWalletStatus             = errors.New("wallet status must be active, paused, closing or closed")
+	ErrInvalidWalletTransition         = errors.New("the wallet cannot move to this status from its current status")
+	ErrWalletNotAcceptingContributions = errors.New("this wallet is not accepting contributions")
+	ErrWalletNotSpendable              = errors.New("this wallet cannot be spent from right now")
+	ErrWalletClosed                    = errors.New("this wallet is closed")
+
+	// Wallet closure errors
+	ErrInvalidClosureDisposition = errors.New("disposition must be transfer, refund or donate")
+	ErrInvalidTransferTarget     = errors.New("the remaining balance must be transferred to another wallet that is accepting contributions")
+	ErrRefundNotCovered          = errors.New("part of the balance was not contributed through online payments and cannot be refunded; transfer or donate it instead")
+	ErrWalletBalanceChanged      = errors.New("the wallet balance changed while it was being closed; please try again")
+	ErrWalletClosureNotFound     = errors.New("wallet closure not found")
+	ErrNoOutstandingRefunds      = errors.New("this closure has no refunds left to send")
+
+	// Wallet campaign errors
+	ErrCampaignNotFound       = errors.New("campaign not found")
+	ErrCampaignAlreadyRunning = errors.New("this wallet already has an active campaign")
+	ErrCampaignNotActive      = errors.New("this campaign has ended or been cancelled")
+	ErrInvalidCampaign        = errors.New("a campaign needs a positive goal and an end date in the future after its start date")
+	ErrInvalidMilestone       = errors.New("milestones need a label and distinct amounts above zero and no more than the goal")
+
+	// Contributor wall errors
+	ErrContributionNotFound = errors.New("contribution not found")
+
+	// Wallet share link errors
+	ErrShareLinkNotFound    = errors.New("share link not found")
+	ErrShareLinkUnavailable = errors.New("this share link has expired, been revoked or reached its contribution limit")
+	ErrInvalidShareLink     = errors.New("share link expiry must be in the future and its contribution limit at least 1")
+
+	// Wallet spending rule errors
+	ErrSpendingRuleNotFound = errors.New("spending rule not found")
+	ErrSpendingRuleExists   = errors.New("wallet already has this spending rule")
+	ErrInvalidSpendingRule  = errors.New("a spending rule needs either a pharmacy or an organisation")
+	ErrSpendingNotAllowed   = errors.New("this wallet cannot be spent at this pharmacy")
 
 	// Transaction errors
 	ErrTransactionNotFound   = errors.New("transaction not found")
 	ErrInsufficientBalance   = errors.New("insufficient wallet balance")
 	ErrInvalidAmount         = errors.New("invalid amount")
 	ErrTransactionFailed     = errors.New("transaction failed")
+	ErrTransactionSettled    = errors.New("transaction has already been settled")
+	ErrTransactionReversed   = errors.New("transaction has already been reversed")
 
 	// Pharmacy errors
 	ErrPharmacyNotFound = errors.New("pharmacy not found")
 	ErrPharmacyInactive = errors.New("pharmacy is not active")
+	// Applications go through review instead of being suspended or
+	// reactivated.
+	ErrInvalidPharmacyTransition = errors.New("only active pharmacies can be suspended and only suspended pharmacies reactivated")
+
+	// Pharmacy listing errors
+	ErrInvalidLocation     = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	ErrInvalidOpeningHours = errors.New("opening hours need a day from 0 (Sunday) to 6 and HH:MM times with closing after opening")
+
+	// Organisation errors
+	ErrOrganisationNotFound       = errors.New("organisation not found")
+	ErrOrganisationAccessDenied   = errors.New("you do not have access to this organisation")
+	ErrOrganisationMemberExists   = errors.New("user is already a member of this organisation")
+	ErrOrganisationMemberNotFound = errors.New("organisation member not found")
+
+	// Pharmacy onboarding errors
+	ErrShortCodeTaken           = errors.New("pharmacy code is already in use")
+	ErrApplicationNotFound      = errors.New("pharmacy application not found")
+	ErrApplicationNotReviewable = errors.New("pharmacy application is not awaiting review")
+	ErrApplicationNotEditable   = errors.New("pharmacy application can only be changed when changes are requested")
+	ErrInvalidDocumentType      = errors.New("invalid pharmacy document type")
+	ErrPharmacyEmailRequired    = errors.New("pharmacy needs an email address to receive login details")
+	ErrSetupTokenInvalid        = errors.New("password setup link is invalid or has already been used")
+	ErrSetupTokenExpired        = errors.New("password setup link has expired")
+
+	// Cash-in errors
+	ErrCashInLimitExceeded = errors.New("daily cash-in limit exceeded for this pharmacy")
 
 	// Payment errors
 	ErrPaymentNotFound     = errors.New("payment not found")
 	ErrPaymentAlreadyVerified = errors.New("payment already verified")
 	ErrPaymentFailed       = errors.New("payment failed")
+	ErrPaymentRefundDue    = errors.New("the wallet was closed before this payment arrived, so it is being refunded")
+
+	// Manual credit errors
+	ErrManualCreditNotFound   = errors.New("manual credit not found")
+	ErrManualCreditNotPending = errors.New("manual credit has already been reviewed")
+	ErrDuplicateReference     = errors.New("external reference has already been used")
+	ErrSelfApproval           = errors.New("a manual credit must be approved by a different admin")
+
+	// Settlement errors
+	ErrSettlementNotFound    = errors.New("settlement not found")
+	ErrSettlementAlreadyPaid = errors.New("settlement has already been paid")
+	ErrSettlementNotPayable  = errors.New("settlement cannot be paid out in its current state")
+	ErrNothingToPay          = errors.New("settlement has no amount payable to the pharmacy")
+	ErrPayoutInProgress      = errors.New("settlement payout is in progress")
+
+	// Bank account errors
+	ErrBankAccountNotFound    = errors.New("bank account not found")
+	ErrBankAccountNotPending  = errors.New("bank account change has already been reviewed")
+	ErrBankAccountNotVerified = errors.New("bank account holder name has not been verified")
+	ErrInvalidBankAccount     = errors.New("invalid bank account details")
+	ErrNoActiveBankAccount    = errors.New("pharmacy has no active bank account")
+
+	// Fee rule errors
+	ErrFeeRuleNotFound   = errors.New("fee rule not found")
+	ErrFeeRuleSuperseded = errors.New("fee rule has been superseded or retired")
+	ErrFeeRuleCodeTaken  = errors.New("a fee rule with this code already exists")
+	ErrInvalidFeeRule    = errors.New("invalid fee rule")
+
+	// Fee quote errors
+	ErrFeeQuoteNotFound = errors.New("fee quote not found")
+	ErrFeeQuoteExpired  = errors.New("fee quote has expired or has already been used")
+	ErrFeeQuoteMismatch = errors.New("fee quote does not match this withdrawal")
+
+	// Pharmacy API key errors
+	ErrAPIKeyNotFound     = errors.New("API key not found")
+	ErrAPIKeyInvalid      = errors.New("API key is invalid or has been revoked")
+	ErrInvalidAPIKeyScope = errors.New("invalid API key scope")
+
+	// Pharmacy staff errors
+	ErrPharmacyUserNotFound = errors.New("pharmacy staff member not found")
+	ErrUsernameTaken        = errors.New("username is already in use at this pharmacy")
+	ErrInvalidPharmacyRole  = errors.New("invalid pharmacy staff role")
+	ErrLastManager          = errors.New("a pharmacy must keep at least one active manager")
+
+	// Pharmacy withdrawal errors
+	ErrWithdrawalNotFound   = errors.New("withdrawal not found")
+	ErrWithdrawalNotPending = errors.New("withdrawal has already been completed")
+	ErrWithdrawalExpired    = errors.New("withdrawal has expired")
+	ErrInvalidLineItem      = errors.New("invalid line item")
+	ErrLineItemsMismatch    = errors.New("line items do not add up to the withdrawal amount")
+	ErrVoidWindowClosed     = errors.New("this withdrawal can no longer be voided")
+
+	// Dispute errors
+	ErrDisputeNotFound     = errors.New("dispute not found")
+	ErrDisputeExists       = errors.New("this transaction has already been disputed")
+	ErrDisputeResolved     = errors.New("dispute has already been resolved")
+	ErrNotDisputable       = errors.New("only completed pharmacy withdrawals can be disputed")
+	ErrDisputeWindowClosed = errors.New("this withdrawal can no longer be disputed")
+
+	// Upload errors
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadTooLarge       = errors.New("file is too large")
+	ErrUnsupportedFileType  = errors.New("file type is not allowed for this upload")
+	ErrInvalidUploadPurpose = errors.New("invalid upload purpose")
 
 	// OTP errors
 	ErrOTPNotFound   = errors.New("OTP not found")
@@ -39,4 +170,5 @@ var (
 	ErrTokenInvalid    = errors.New("invalid or expired token")
 	ErrTokenBlacklisted = errors.New("token has been invalidated")
 	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("you do not have permission to perform this action")
 )
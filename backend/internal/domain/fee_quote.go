@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FeeQuote fixes the fee on a withdrawal of Amount from the wallet at the
+// pharmacy until ExpiresAt. The withdrawal that redeems it is charged Fee
+// regardless of later fee rule changes.
+type FeeQuote struct {
+	ID         string          `json:"id"`
+	WalletID   string          `json:"wallet_id"`
+	PharmacyID string          `json:"pharmacy_id"`
+	Amount     decimal.Decimal `json:"amount"`
+	Fee        decimal.Decimal `json:"fee"`
+	NetAmount  decimal.Decimal `json:"net_amount"`
+	FeeRuleID  *string         `json:"fee_rule_id,omitempty"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	RedeemedAt *time.Time      `json:"redeemed_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
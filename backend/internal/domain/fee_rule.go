@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ContributorPays says whether a deposit fee is added to what the contributor
+// is charged instead of being taken from what the wallet receives.
+type ContributorPays string
+
+const (
+	ContributorPaysNever    ContributorPays = "never"
+	ContributorPaysOptional ContributorPays = "optional"
+	ContributorPaysAlways   ContributorPays = "always"
+)
+
+func (p ContributorPays) IsValid() bool {
+	switch p {
+	case ContributorPaysNever, ContributorPaysOptional, ContributorPaysAlways:
+		return true
+	}
+	return false
+}
+
+type FeeRuleScope string
+
+const (
+	FeeRuleScopePlatform     FeeRuleScope = "platform"
+	FeeRuleScopeOrganisation FeeRuleScope = "organisation"
+	FeeRuleScopePharmacy     FeeRuleScope = "pharmacy"
+)
+
+// FeeRule is one version of a fee schedule. The fee is Percentage of the
+// amount plus FlatAmount, held between MinFee and MaxFee when they are set; a
+// waiver charges nothing while it runs. Rules with neither PharmacyID nor
+// OrganisationID apply platform-wide.
+type FeeRule struct {
+	ID              string           `json:"id"`
+	Code            string           `json:"code"`
+	Version         int              `json:"version"`
+	Name            string           `json:"name"`
+	TransactionType TransactionType  `json:"transaction_type"`
+	PharmacyID      *string          `json:"pharmacy_id,omitempty"`
+	OrganisationID  *string          `json:"organisation_id,omitempty"`
+	Percentage      decimal.Decimal  `json:"percentage"`
+	FlatAmount      decimal.Decimal  `json:"flat_amount"`
+	MinFee          *decimal.Decimal `json:"min_fee,omitempty"`
+	MaxFee          *decimal.Decimal `json:"max_fee,omitempty"`
+	IsWaiver        bool             `json:"is_waiver"`
+	ContributorPays ContributorPays  `json:"contributor_pays"`
+	StartsAt        time.Time        `json:"starts_at"`
+	EndsAt          *time.Time       `json:"ends_at,omitempty"`
+	CreatedBy       *string          `json:"created_by,omitempty"`
+	SupersededAt    *time.Time       `json:"superseded_at,omitempty"`
+	SupersededBy    *string          `json:"superseded_by,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+func (r *FeeRule) Scope() FeeRuleScope {
+	switch {
+	case r.PharmacyID != nil:
+		return FeeRuleScopePharmacy
+	case r.OrganisationID != nil:
+		return FeeRuleScopeOrganisation
+	default:
+		return FeeRuleScopePlatform
+	}
+}
+
+// IsCurrent reports whether this is the live version of the rule, i.e. it has
+// been neither superseded nor retired.
+func (r *FeeRule) IsCurrent() bool {
+	return r.SupersededAt == nil
+}
+
+// InEffectAt reports whether the rule's date range covers t.
+func (r *FeeRule) InEffectAt(t time.Time) bool {
+	if t.Before(r.StartsAt) {
+		return false
+	}
+	return r.EndsAt == nil || t.Before(*r.EndsAt)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ManualCreditStatus string
+
+const (
+	ManualCreditStatusPending  ManualCreditStatus = "pending"
+	ManualCreditStatusApproved ManualCreditStatus = "approved"
+	ManualCreditStatusRejected ManualCreditStatus = "rejected"
+)
+
+type ManualCredit struct {
+	ID                string             `json:"id"`
+	WalletID          string             `json:"wallet_id"`
+	Amount            decimal.Decimal    `json:"amount"`
+	Reason            string             `json:"reason"`
+	ExternalReference string             `json:"external_reference"`
+	Status            ManualCreditStatus `json:"status"`
+	RequestedBy       string             `json:"requested_by"`
+	ReviewedBy        *string            `json:"reviewed_by,omitempty"`
+	ReviewedAt        *time.Time         `json:"reviewed_at,omitempty"`
+	RejectionReason   string             `json:"rejection_reason,omitempty"`
+	TransactionID     *string            `json:"transaction_id,omitempty"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+func (m *ManualCredit) IsPending() bool {
+	return m.Status == ManualCreditStatusPending
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type OrganisationStatus string
+
+const (
+	OrganisationStatusActive   OrganisationStatus = "active"
+	OrganisationStatusInactive OrganisationStatus = "inactive"
+)
+
+// Organisation is a pharmacy chain. Its branches are the pharmacies whose
+// OrganisationID points at it.
+type Organisation struct {
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	RegistrationNumber string             `json:"registration_number,omitempty"`
+	Email              string             `json:"email,omitempty"`
+	Phone              string             `json:"phone,omitempty"`
+	Status             OrganisationStatus `json:"status"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// OrganisationMember gives a CareWallet user read access to every branch of
+// an organisation. Email and FullName are read from the user for display.
+type OrganisationMember struct {
+	ID             string    `json:"id"`
+	OrganisationID string    `json:"organisation_id"`
+	UserID         string    `json:"user_id"`
+	Email          string    `json:"email"`
+	FullName       string    `json:"full_name"`
+	AddedBy        *string   `json:"added_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BranchSettlementSummary totals one branch's settlements over a period.
+// Paid and Outstanding split NetPayable by whether the settlement has been
+// paid out yet.
+type BranchSettlementSummary struct {
+	PharmacyID       string          `json:"pharmacy_id"`
+	PharmacyName     string          `json:"pharmacy_name"`
+	ShortCode        string          `json:"short_code"`
+	SettlementCount  int             `json:"settlement_count"`
+	WithdrawalCount  int             `json:"withdrawal_count"`
+	GrossWithdrawals decimal.Decimal `json:"gross_withdrawals"`
+	WithdrawalFees   decimal.Decimal `json:"withdrawal_fees"`
+	NetWithdrawals   decimal.Decimal `json:"net_withdrawals"`
+	CashInCount      int             `json:"cash_in_count"`
+	CashInTotal      decimal.Decimal `json:"cash_in_total"`
+	NetPayable       decimal.Decimal `json:"net_payable"`
+	Paid             decimal.Decimal `json:"paid"`
+	Outstanding      decimal.Decimal `json:"outstanding"`
+}
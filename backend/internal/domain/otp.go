@@ -10,6 +10,7 @@ const (
 	OTPPurposeWithdrawal     OTPPurpose = "withdrawal"
 	OTPPurposeEmailVerify    OTPPurpose = "email_verify"
 	OTPPurposePasswordReset  OTPPurpose = "password_reset"
+	OTPPurposeBalanceDisclosure OTPPurpose = "balance_disclosure"
 )
 
 type OTP struct {
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+)
+
+// PasswordSetupToken lets a pharmacy staff member choose their own password
+// through a one-time link. Only the SHA-256 hash of the token is stored.
+type PasswordSetupToken struct {
+	ID             string     `json:"id"`
+	PharmacyUserID string     `json:"pharmacy_user_id"`
+	TokenHash      string     `json:"-"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	UsedAt         *time.Time `json:"used_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -12,18 +12,52 @@ const (
 	PaymentStatusPending   PaymentStatus = "pending"
 	PaymentStatusCompleted PaymentStatus = "completed"
 	PaymentStatusFailed    PaymentStatus = "failed"
+	// A payment that arrives after its wallet was closed is refunded instead
+	// of being credited. It is refund_due until Paystack accepts the refund.
+	PaymentStatusRefundDue PaymentStatus = "refund_due"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
 )
 
+// Payment is a contribution being collected through Paystack. Amount is the
+// gift the contributor chose and Fee what the platform takes; when
+// ContributorPaysFee is set the contributor is charged both, otherwise the fee
+// comes out of what the wallet receives. ShareLinkID is set when the
+// contributor arrived through a share link. DisplayName, Anonymous and
+// HideAmount are how the contributor chose to appear on the wallet's
+// contributor wall.
 type Payment struct {
-	ID                string          `json:"id"`
-	WalletID          string          `json:"wallet_id"`
-	Reference         string          `json:"reference"`
-	Amount            decimal.Decimal `json:"amount"`
-	Email             string          `json:"email"`
-	Message           string          `json:"message,omitempty"`
-	Status            PaymentStatus   `json:"status"`
-	PaystackReference string          `json:"paystack_reference,omitempty"`
-	VerifiedAt        *time.Time      `json:"verified_at,omitempty"`
-	CreatedAt         time.Time       `json:"created_at"`
-	UpdatedAt         time.Time       `json:"updated_at"`
+	ID                 string          `json:"id"`
+	WalletID           string          `json:"wallet_id"`
+	Reference          string          `json:"reference"`
+	Amount             decimal.Decimal `json:"amount"`
+	Fee                decimal.Decimal `json:"fee"`
+	FeeRuleID          *string         `json:"fee_rule_id,omitempty"`
+	ContributorPaysFee bool            `json:"contributor_pays_fee"`
+	Email              string          `json:"email"`
+	Message            string          `json:"message,omitempty"`
+	DisplayName        string          `json:"display_name,omitempty"`
+	Anonymous          bool            `json:"anonymous"`
+	HideAmount         bool            `json:"hide_amount"`
+	ShareLinkID        *string         `json:"share_link_id,omitempty"`
+	Status             PaymentStatus   `json:"status"`
+	PaystackReference  string          `json:"paystack_reference,omitempty"`
+	VerifiedAt         *time.Time      `json:"verified_at,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// ChargedAmount is what the contributor pays through Paystack.
+func (p *Payment) ChargedAmount() decimal.Decimal {
+	if p.ContributorPaysFee {
+		return p.Amount.Add(p.Fee)
+	}
+	return p.Amount
+}
+
+// CreditedAmount is what the wallet receives once the payment succeeds.
+func (p *Payment) CreditedAmount() decimal.Decimal {
+	if p.ContributorPaysFee {
+		return p.Amount
+	}
+	return p.Amount.Sub(p.Fee)
 }
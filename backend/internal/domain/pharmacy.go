@@ -2,6 +2,8 @@ package domain
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type PharmacyStatus string
@@ -10,18 +12,98 @@ const (
 	PharmacyStatusActive   PharmacyStatus = "active"
 	PharmacyStatusInactive PharmacyStatus = "inactive"
 	PharmacyStatusPending  PharmacyStatus = "pending"
+
+	// Statuses of self-registered pharmacies that have not been approved.
+	PharmacyStatusChangesRequested PharmacyStatus = "changes_requested"
+	PharmacyStatusRejected         PharmacyStatus = "rejected"
 )
 
+// Pharmacy is a pharmacy where wallets can be spent. Self-registered
+// pharmacies carry their application details and the outcome of the latest
+// admin review in ContactName, SubmittedAt and the Review fields. A pharmacy
+// that is a branch of a chain has OrganisationID set. Latitude, Longitude and
+// Services are shown in the public pharmacy finder.
 type Pharmacy struct {
-	ID                 string         `json:"id"`
-	Name               string         `json:"name"`
-	ShortCode          string         `json:"short_code"`
-	RegistrationNumber string         `json:"registration_number"`
-	Address            string         `json:"address,omitempty"`
-	Phone              string         `json:"phone,omitempty"`
-	Email              string         `json:"email,omitempty"`
-	PasswordHash       string         `json:"-"`
-	Status             PharmacyStatus `json:"status"`
-	CreatedAt          time.Time      `json:"created_at"`
-	UpdatedAt          time.Time      `json:"updated_at"`
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name"`
+	ShortCode            string         `json:"short_code"`
+	RegistrationNumber   string         `json:"registration_number"`
+	Address              string         `json:"address,omitempty"`
+	Phone                string         `json:"phone,omitempty"`
+	Email                string         `json:"email,omitempty"`
+	PasswordHash         string         `json:"-"`
+	Status               PharmacyStatus `json:"status"`
+	OrganisationID       *string        `json:"organisation_id,omitempty"`
+	Latitude             *float64       `json:"latitude,omitempty"`
+	Longitude            *float64       `json:"longitude,omitempty"`
+	Services             []string       `json:"services"`
+	ContactName          string         `json:"contact_name,omitempty"`
+	ApplicationTokenHash string         `json:"-"`
+	SubmittedAt          *time.Time     `json:"submitted_at,omitempty"`
+	ReviewNotes          string         `json:"review_notes,omitempty"`
+	ReviewedBy           *string        `json:"reviewed_by,omitempty"`
+	ReviewedAt           *time.Time     `json:"reviewed_at,omitempty"`
+	// DailyCashInLimit overrides the platform-wide cash-in limit when set.
+	DailyCashInLimit *decimal.Decimal `json:"daily_cash_in_limit,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// IsUnderReview reports whether the pharmacy is a registration an admin has
+// not yet approved or rejected.
+func (p *Pharmacy) IsUnderReview() bool {
+	return p.Status == PharmacyStatusPending || p.Status == PharmacyStatusChangesRequested
+}
+
+type PharmacyDocumentType string
+
+const (
+	PharmacyDocumentLicence      PharmacyDocumentType = "pharmacy_licence"
+	PharmacyDocumentRegistration PharmacyDocumentType = "company_registration"
+	PharmacyDocumentAddress      PharmacyDocumentType = "proof_of_address"
+	PharmacyDocumentOther        PharmacyDocumentType = "other"
+)
+
+func (t PharmacyDocumentType) IsValid() bool {
+	switch t {
+	case PharmacyDocumentLicence, PharmacyDocumentRegistration, PharmacyDocumentAddress, PharmacyDocumentOther:
+		return true
+	}
+	return false
+}
+
+// PharmacyDocument is a supporting document submitted with a registration,
+// either hosted elsewhere at URL or uploaded to CareWallet as UploadID.
+type PharmacyDocument struct {
+	ID           string               `json:"id"`
+	PharmacyID   string               `json:"pharmacy_id"`
+	DocumentType PharmacyDocumentType `json:"document_type"`
+	FileName     string               `json:"file_name"`
+	URL          string               `json:"url,omitempty"`
+	UploadID     *string              `json:"upload_id,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+}
+
+// PharmacyOpeningHours is one opening period on a day of the week, in the
+// business timezone. Times are "HH:MM"; ClosesAt may be "24:00".
+type PharmacyOpeningHours struct {
+	PharmacyID string       `json:"-"`
+	DayOfWeek  time.Weekday `json:"day_of_week"`
+	OpensAt    string       `json:"opens_at"`
+	ClosesAt   string       `json:"closes_at"`
+}
+
+// NearbyPharmacy is an active pharmacy found by a location search.
+type NearbyPharmacy struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	ShortCode    string                  `json:"short_code"`
+	Address      string                  `json:"address,omitempty"`
+	Phone        string                  `json:"phone,omitempty"`
+	Latitude     float64                 `json:"latitude"`
+	Longitude    float64                 `json:"longitude"`
+	Services     []string                `json:"services"`
+	DistanceKm   float64                 `json:"distance_km"`
+	OpenNow      bool                    `json:"open_now"`
+	OpeningHours []*PharmacyOpeningHours `json:"opening_hours"`
 }
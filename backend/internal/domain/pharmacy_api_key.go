@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeWalletLookup    APIKeyScope = "wallet:lookup"
+	APIKeyScopeWithdraw        APIKeyScope = "withdraw"
+	APIKeyScopeCashIn          APIKeyScope = "cash_in"
+	APIKeyScopeSettlementsRead APIKeyScope = "settlements:read"
+)
+
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case APIKeyScopeWalletLookup, APIKeyScopeWithdraw, APIKeyScopeCashIn, APIKeyScopeSettlementsRead:
+		return true
+	}
+	return false
+}
+
+// PharmacyAPIKey lets a pharmacy's own software call the pharmacy API. A key
+// acts for the staff member in CreatedBy, with that member's current role,
+// but only on routes covered by its Scopes.
+type PharmacyAPIKey struct {
+	ID         string        `json:"id"`
+	PharmacyID string        `json:"pharmacy_id"`
+	CreatedBy  string        `json:"created_by"`
+	Name       string        `json:"name"`
+	KeyPrefix  string        `json:"key_prefix"`
+	KeyHash    string        `json:"-"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	LastUsedIP string        `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty"`
+	RevokedBy  *string       `json:"revoked_by,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+func (k *PharmacyAPIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *PharmacyAPIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
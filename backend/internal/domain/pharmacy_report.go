@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// CashierSummary totals the transactions one staff member put through at a
+// pharmacy over a period. Withdrawals that were later reversed still count
+// towards the day they were made; the reversal is counted on the day it
+// happened. PharmacyUserID is nil for transactions not tied to a staff login,
+// such as reversals from upheld disputes.
+type CashierSummary struct {
+	PharmacyUserID   *string         `json:"pharmacy_user_id"`
+	Username         string          `json:"username,omitempty"`
+	FullName         string          `json:"full_name,omitempty"`
+	WithdrawalCount  int             `json:"withdrawal_count"`
+	GrossWithdrawals decimal.Decimal `json:"gross_withdrawals"`
+	WithdrawalFees   decimal.Decimal `json:"withdrawal_fees"`
+	NetWithdrawals   decimal.Decimal `json:"net_withdrawals"`
+	ReversalCount    int             `json:"reversal_count"`
+	ReversalTotal    decimal.Decimal `json:"reversal_total"`
+	CashInCount      int             `json:"cash_in_count"`
+	CashInTotal      decimal.Decimal `json:"cash_in_total"`
+}
+
+// Add accumulates another summary's counts and totals into s.
+func (s *CashierSummary) Add(other *CashierSummary) {
+	s.WithdrawalCount += other.WithdrawalCount
+	s.GrossWithdrawals = s.GrossWithdrawals.Add(other.GrossWithdrawals)
+	s.WithdrawalFees = s.WithdrawalFees.Add(other.WithdrawalFees)
+	s.NetWithdrawals = s.NetWithdrawals.Add(other.NetWithdrawals)
+	s.ReversalCount += other.ReversalCount
+	s.ReversalTotal = s.ReversalTotal.Add(other.ReversalTotal)
+	s.CashInCount += other.CashInCount
+	s.CashInTotal = s.CashInTotal.Add(other.CashInTotal)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+)
+
+type PharmacyUserRole string
+
+const (
+	PharmacyUserRoleManager PharmacyUserRole = "manager"
+	PharmacyUserRoleCashier PharmacyUserRole = "cashier"
+	PharmacyUserRoleViewer  PharmacyUserRole = "viewer"
+)
+
+func (r PharmacyUserRole) IsValid() bool {
+	switch r {
+	case PharmacyUserRoleManager, PharmacyUserRoleCashier, PharmacyUserRoleViewer:
+		return true
+	}
+	return false
+}
+
+type PharmacyUserStatus string
+
+const (
+	PharmacyUserStatusActive   PharmacyUserStatus = "active"
+	PharmacyUserStatusDisabled PharmacyUserStatus = "disabled"
+)
+
+// PharmacyUser is a staff member who signs in to the pharmacy portal.
+// Managers manage staff, cashiers handle withdrawals and cash-ins, and
+// viewers can only look things up.
+type PharmacyUser struct {
+	ID           string             `json:"id"`
+	PharmacyID   string             `json:"pharmacy_id"`
+	Username     string             `json:"username"`
+	FullName     string             `json:"full_name"`
+	PasswordHash string             `json:"-"`
+	Role         PharmacyUserRole   `json:"role"`
+	Status       PharmacyUserStatus `json:"status"`
+	LastLoginAt  *time.Time         `json:"last_login_at,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+func (u *PharmacyUser) IsActive() bool {
+	return u.Status == PharmacyUserStatusActive
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PharmacyWithdrawalStatus string
+
+const (
+	PharmacyWithdrawalStatusPending   PharmacyWithdrawalStatus = "pending"
+	PharmacyWithdrawalStatusCompleted PharmacyWithdrawalStatus = "completed"
+)
+
+// PharmacyWithdrawal is a withdrawal started by pharmacy staff that is waiting
+// for the beneficiary to confirm it with an OTP.
+type PharmacyWithdrawal struct {
+	ID             string                   `json:"id"`
+	PharmacyID     string                   `json:"pharmacy_id"`
+	PharmacyUserID string                   `json:"pharmacy_user_id"`
+	WalletID       string                   `json:"wallet_id"`
+	Amount         decimal.Decimal          `json:"amount"`
+	FeeQuoteID     *string                  `json:"fee_quote_id,omitempty"`
+	OTPEmail       string                   `json:"-"`
+	Status         PharmacyWithdrawalStatus `json:"status"`
+	TransactionID  *string                  `json:"transaction_id,omitempty"`
+	ExpiresAt      time.Time                `json:"expires_at"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+func (w *PharmacyWithdrawal) IsExpired() bool {
+	return time.Now().After(w.ExpiresAt)
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type SettlementStatus string
+
+const (
+	SettlementStatusUnpaid     SettlementStatus = "unpaid"
+	SettlementStatusApproved   SettlementStatus = "approved"
+	SettlementStatusProcessing SettlementStatus = "processing"
+	SettlementStatusFailed     SettlementStatus = "failed"
+	SettlementStatusPaid       SettlementStatus = "paid"
+)
+
+// Settlement is a batch of a pharmacy's completed withdrawals and cash-ins.
+// NetPayable is what CareWallet owes the pharmacy: withdrawals net of fees
+// minus the cash the pharmacy collected on the platform's behalf and the net
+// amount of previously settled withdrawals reversed since (ReversalTotal). A
+// negative value means the pharmacy owes CareWallet.
+type Settlement struct {
+	ID                string           `json:"id"`
+	PharmacyID        string           `json:"pharmacy_id"`
+	PeriodStart       time.Time        `json:"period_start"`
+	PeriodEnd         time.Time        `json:"period_end"`
+	WithdrawalCount   int              `json:"withdrawal_count"`
+	GrossWithdrawals  decimal.Decimal  `json:"gross_withdrawals"`
+	WithdrawalFees    decimal.Decimal  `json:"withdrawal_fees"`
+	NetWithdrawals    decimal.Decimal  `json:"net_withdrawals"`
+	CashInCount       int              `json:"cash_in_count"`
+	CashInTotal       decimal.Decimal  `json:"cash_in_total"`
+	ReversalCount     int              `json:"reversal_count"`
+	ReversalTotal     decimal.Decimal  `json:"reversal_total"`
+	NetPayable        decimal.Decimal  `json:"net_payable"`
+	Status            SettlementStatus `json:"status"`
+	PayoutReference   string           `json:"payout_reference,omitempty"`
+	PaidBy            *string          `json:"paid_by,omitempty"`
+	PaidAt            *time.Time       `json:"paid_at,omitempty"`
+	ApprovedBy        *string          `json:"approved_by,omitempty"`
+	ApprovedAt        *time.Time       `json:"approved_at,omitempty"`
+	TransferReference string           `json:"transfer_reference,omitempty"`
+	TransferCode      string           `json:"transfer_code,omitempty"`
+	PayoutAttempts    int              `json:"payout_attempts"`
+	LastPayoutError   string           `json:"last_payout_error,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+func (s *Settlement) IsPaid() bool {
+	return s.Status == SettlementStatusPaid
+}
+
+// CanBeMarkedPaid reports whether an admin may record the settlement as paid
+// by hand. A settlement whose transfer is processing is left to Paystack so
+// the pharmacy is not paid twice.
+func (s *Settlement) CanBeMarkedPaid() bool {
+	return s.Status == SettlementStatusUnpaid || s.IsPayable()
+}
+
+// IsPayable reports whether a payout may be started, either for the first
+// time after approval or as a retry after a failed transfer.
+func (s *Settlement) IsPayable() bool {
+	return s.Status == SettlementStatusApproved || s.Status == SettlementStatusFailed
+}
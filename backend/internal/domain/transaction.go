@@ -10,30 +10,80 @@ type TransactionType string
 type TransactionStatus string
 
 const (
-	TransactionTypeDeposit    TransactionType = "deposit"
-	TransactionTypeWithdrawal TransactionType = "withdrawal"
+	TransactionTypeDeposit      TransactionType = "deposit"
+	TransactionTypeWithdrawal   TransactionType = "withdrawal"
+	TransactionTypeManualCredit TransactionType = "manual_credit"
+	TransactionTypeCashDeposit  TransactionType = "cash_deposit"
+	TransactionTypeReversal     TransactionType = "reversal"
+
+	// Closing a wallet disposes of its balance with one of these.
+	TransactionTypeTransferOut TransactionType = "transfer_out"
+	TransactionTypeTransferIn  TransactionType = "transfer_in"
+	TransactionTypeRefund      TransactionType = "refund"
+	TransactionTypeDonation    TransactionType = "donation"
 )
 
 const (
 	TransactionStatusPending   TransactionStatus = "pending"
 	TransactionStatusCompleted TransactionStatus = "completed"
 	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusReversed  TransactionStatus = "reversed"
 )
 
+// Transaction is a movement of money in or out of a wallet. For
+// contributions, ContributorAnonymous and ContributorHidesAmount say how the
+// contributor chose to appear on the wallet's contributor wall.
 type Transaction struct {
-	ID                 string            `json:"id"`
-	WalletID           string            `json:"wallet_id"`
-	Type               TransactionType   `json:"type"`
-	Amount             decimal.Decimal   `json:"amount"`
-	Fee                decimal.Decimal   `json:"fee"`
-	NetAmount          decimal.Decimal   `json:"net_amount"`
-	Status             TransactionStatus `json:"status"`
-	ContributorEmail   string            `json:"contributor_email,omitempty"`
-	ContributorName    string            `json:"contributor_name,omitempty"`
-	ContributorMessage string            `json:"contributor_message,omitempty"`
-	PharmacyID         *string           `json:"pharmacy_id,omitempty"`
-	PharmacyName       string            `json:"pharmacy_name,omitempty"`
-	PaystackReference  string            `json:"paystack_reference,omitempty"`
-	CreatedAt          time.Time         `json:"created_at"`
-	UpdatedAt          time.Time         `json:"updated_at"`
+	ID                     string            `json:"id"`
+	WalletID               string            `json:"wallet_id"`
+	Type                   TransactionType   `json:"type"`
+	Amount                 decimal.Decimal   `json:"amount"`
+	Fee                    decimal.Decimal   `json:"fee"`
+	FeeRuleID              *string           `json:"fee_rule_id,omitempty"`
+	FeeQuoteID             *string           `json:"fee_quote_id,omitempty"`
+	NetAmount              decimal.Decimal   `json:"net_amount"`
+	Status                 TransactionStatus `json:"status"`
+	ContributorEmail       string            `json:"contributor_email,omitempty"`
+	ContributorName        string            `json:"contributor_name,omitempty"`
+	ContributorMessage     string            `json:"contributor_message,omitempty"`
+	ContributorAnonymous   bool              `json:"contributor_anonymous"`
+	ContributorHidesAmount bool              `json:"contributor_hides_amount"`
+	PharmacyID             *string           `json:"pharmacy_id,omitempty"`
+	PharmacyName           string            `json:"pharmacy_name,omitempty"`
+	PharmacyUserID         *string           `json:"pharmacy_user_id,omitempty"`
+	PaystackReference      string            `json:"paystack_reference,omitempty"`
+	SettlementID           *string           `json:"settlement_id,omitempty"`
+	ReversalOf             *string           `json:"reversal_of,omitempty"`
+	ReversalReason         string            `json:"reversal_reason,omitempty"`
+	LineItems              []LineItem        `json:"line_items,omitempty"`
+	CreatedAt              time.Time         `json:"created_at"`
+	UpdatedAt              time.Time         `json:"updated_at"`
+}
+
+type LineItemCategory string
+
+const (
+	LineItemCategoryPrescription LineItemCategory = "prescription"
+	LineItemCategoryOTC          LineItemCategory = "otc"
+	LineItemCategoryOther        LineItemCategory = "other"
+)
+
+func (c LineItemCategory) IsValid() bool {
+	switch c {
+	case LineItemCategoryPrescription, LineItemCategoryOTC, LineItemCategoryOther:
+		return true
+	}
+	return false
+}
+
+// LineItem is one product a pharmacy dispensed against a withdrawal.
+// NAPPICode is the product's South African NAPPI code, when known.
+type LineItem struct {
+	ID          string           `json:"id"`
+	ProductName string           `json:"product_name"`
+	NAPPICode   string           `json:"nappi_code,omitempty"`
+	Quantity    int              `json:"quantity"`
+	UnitPrice   decimal.Decimal  `json:"unit_price"`
+	Total       decimal.Decimal  `json:"total"`
+	Category    LineItemCategory `json:"category"`
 }
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+)
+
+// UploadOwnerType says who uploaded a file. Applicant uploads are made before
+// a pharmacy registration exists and have no owner ID until the registration
+// claims them.
+type UploadOwnerType string
+
+const (
+	UploadOwnerUser      UploadOwnerType = "user"
+	UploadOwnerPharmacy  UploadOwnerType = "pharmacy"
+	UploadOwnerApplicant UploadOwnerType = "applicant"
+)
+
+type UploadPurpose string
+
+const (
+	UploadPurposeWalletPhoto      UploadPurpose = "wallet_photo"
+	UploadPurposePharmacyDocument UploadPurpose = "pharmacy_document"
+	UploadPurposePrescription     UploadPurpose = "prescription"
+	UploadPurposeIDDocument       UploadPurpose = "id_document"
+	UploadPurposeDisputeEvidence  UploadPurpose = "dispute_evidence"
+)
+
+// AllowedContentTypes lists the sniffed content types accepted for the purpose.
+func (p UploadPurpose) AllowedContentTypes() []string {
+	switch p {
+	case UploadPurposeWalletPhoto:
+		return []string{"image/jpeg", "image/png", "image/webp"}
+	case UploadPurposePharmacyDocument, UploadPurposePrescription, UploadPurposeIDDocument, UploadPurposeDisputeEvidence:
+		return []string{"image/jpeg", "image/png", "image/webp", "application/pdf"}
+	}
+	return nil
+}
+
+// CanUpload reports whether this kind of owner may upload files for the purpose.
+func (t UploadOwnerType) CanUpload(p UploadPurpose) bool {
+	switch t {
+	case UploadOwnerUser:
+		return p == UploadPurposeWalletPhoto || p == UploadPurposePrescription || p == UploadPurposeIDDocument
+	case UploadOwnerPharmacy:
+		return p == UploadPurposePharmacyDocument || p == UploadPurposePrescription || p == UploadPurposeIDDocument ||
+			p == UploadPurposeDisputeEvidence
+	case UploadOwnerApplicant:
+		return p == UploadPurposePharmacyDocument
+	}
+	return false
+}
+
+// Upload is a file kept in blob storage.
+type Upload struct {
+	ID          string          `json:"id"`
+	OwnerType   UploadOwnerType `json:"owner_type"`
+	OwnerID     *string         `json:"owner_id,omitempty"`
+	Purpose     UploadPurpose   `json:"purpose"`
+	StorageKey  string          `json:"-"`
+	FileName    string          `json:"file_name"`
+	ContentType string          `json:"content_type"`
+	SizeBytes   int64           `json:"size_bytes"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// IsOwnedBy reports whether the upload belongs to the given owner. Unclaimed
+// applicant uploads match an empty owner ID.
+func (u *Upload) IsOwnedBy(ownerType UploadOwnerType, ownerID string) bool {
+	if u.OwnerType != ownerType {
+		return false
+	}
+	if u.OwnerID == nil {
+		return ownerID == ""
+	}
+	return *u.OwnerID == ownerID
+}
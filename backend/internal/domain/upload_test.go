@@ -0,0 +1,39 @@
+package domain
+
+import "testing"
+
+func TestUploadOwnerTypeCanUpload(t *testing.T) {
+	purposes := []UploadPurpose{
+		UploadPurposeWalletPhoto,
+		UploadPurposePharmacyDocument,
+		UploadPurposePrescription,
+		UploadPurposeIDDocument,
+		UploadPurposeDisputeEvidence,
+	}
+
+	tests := []struct {
+		owner   UploadOwnerType
+		allowed []UploadPurpose
+	}{
+		{UploadOwnerUser, []UploadPurpose{UploadPurposeWalletPhoto, UploadPurposePrescription, UploadPurposeIDDocument}},
+		{UploadOwnerPharmacy, []UploadPurpose{UploadPurposePharmacyDocument, UploadPurposePrescription, UploadPurposeIDDocument, UploadPurposeDisputeEvidence}},
+		{UploadOwnerApplicant, []UploadPurpose{UploadPurposePharmacyDocument}},
+		{"admin", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.owner), func(t *testing.T) {
+			for _, purpose := range purposes {
+				want := false
+				for _, allowed := range tt.allowed {
+					if allowed == purpose {
+						want = true
+					}
+				}
+				if got := tt.owner.CanUpload(purpose); got != want {
+					t.Errorf("CanUpload(%s) = %v, want %v", purpose, got, want)
+				}
+			}
+		})
+	}
+}
@@ -6,27 +6,96 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// WalletStatus is where a wallet is in its lifecycle:
+//
+//   - active wallets take contributions and can be spent;
+//   - paused wallets have stopped taking contributions for now but can still
+//     be spent;
+//   - closing wallets take no more contributions while the rest of the
+//     balance is used up;
+//   - closed wallets are finished, and nothing moves in or out;
+//   - frozen wallets are held by an admin, and nothing can be spent.
 type WalletStatus string
 
 const (
-	WalletStatusActive   WalletStatus = "active"
-	WalletStatusInactive WalletStatus = "inactive"
-	WalletStatusClosed   WalletStatus = "closed"
+	WalletStatusActive  WalletStatus = "active"
+	WalletStatusPaused  WalletStatus = "paused"
+	WalletStatusClosing WalletStatus = "closing"
+	WalletStatusClosed  WalletStatus = "closed"
+	WalletStatusFrozen  WalletStatus = "frozen"
 )
 
+func (s WalletStatus) IsValid() bool {
+	switch s {
+	case WalletStatusActive, WalletStatusPaused, WalletStatusClosing, WalletStatusClosed, WalletStatusFrozen:
+		return true
+	}
+	return false
+}
+
+// AcceptsContributions reports whether contributors may pay into a wallet
+// with this status.
+func (s WalletStatus) AcceptsContributions() bool {
+	return s == WalletStatusActive
+}
+
+// CanBeSpent reports whether withdrawals may be made from a wallet with this
+// status.
+func (s WalletStatus) CanBeSpent() bool {
+	switch s {
+	case WalletStatusActive, WalletStatusPaused, WalletStatusClosing:
+		return true
+	}
+	return false
+}
+
+// CanBeCredited reports whether admin credits and reversals of earlier
+// withdrawals may be paid into a wallet with this status.
+func (s WalletStatus) CanBeCredited() bool {
+	return s != WalletStatusClosed
+}
+
+// ownerWalletTransitions lists the status changes a wallet's creator may
+// make. Freezing and unfreezing are for admins only.
+var ownerWalletTransitions = map[WalletStatus][]WalletStatus{
+	WalletStatusActive:  {WalletStatusPaused, WalletStatusClosing, WalletStatusClosed},
+	WalletStatusPaused:  {WalletStatusActive, WalletStatusClosing, WalletStatusClosed},
+	WalletStatusClosing: {WalletStatusActive, WalletStatusClosed},
+}
+
+// Wallet holds funds for a beneficiary. The photo is either an external
+// PhotoURL or an uploaded file referenced by PhotoUploadID. FrozenFrom is
+// the status to return to when an admin unfreezes the wallet.
 type Wallet struct {
-	ID            string          `json:"id"`
-	CreatorID     string          `json:"creator_id"`
-	BeneficiaryID *string         `json:"beneficiary_id,omitempty"`
-	WalletName    string          `json:"wallet_name"`
-	Description   string          `json:"description,omitempty"`
-	PhotoURL      string          `json:"photo_url,omitempty"`
-	Balance       decimal.Decimal `json:"balance"`
-	FundingGoal   decimal.Decimal `json:"funding_goal,omitempty"`
-	ShareableCode string          `json:"shareable_code"`
-	Status        WalletStatus    `json:"status"`
-	CreatedAt     time.Time       `json:"created_at"`
-	UpdatedAt     time.Time       `json:"updated_at"`
+	ID              string          `json:"id"`
+	CreatorID       string          `json:"creator_id"`
+	BeneficiaryID   *string         `json:"beneficiary_id,omitempty"`
+	WalletName      string          `json:"wallet_name"`
+	Description     string          `json:"description,omitempty"`
+	PhotoURL        string          `json:"photo_url,omitempty"`
+	PhotoUploadID   *string         `json:"photo_upload_id,omitempty"`
+	Balance         decimal.Decimal `json:"balance"`
+	FundingGoal     decimal.Decimal `json:"funding_goal,omitempty"`
+	ShareableCode   string          `json:"shareable_code"`
+	Status          WalletStatus    `json:"status"`
+	StatusReason    string          `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time      `json:"status_changed_at,omitempty"`
+	FrozenFrom      *WalletStatus   `json:"frozen_from,omitempty"`
+	DeletedAt       *time.Time      `json:"deleted_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// WalletStatusChange records one move between statuses and why it was made.
+type WalletStatusChange struct {
+	ID         string         `json:"id"`
+	WalletID   string         `json:"wallet_id"`
+	FromStatus WalletStatus   `json:"from_status"`
+	ToStatus   WalletStatus   `json:"to_status"`
+	Reason     string         `json:"reason,omitempty"`
+	ActorType  AuditActorType `json:"actor_type"`
+	ActorID    *string        `json:"actor_id,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
 func (w *Wallet) CanBeAccessedBy(userID string) bool {
@@ -42,3 +111,22 @@ func (w *Wallet) CanBeAccessedBy(userID string) bool {
 func (w *Wallet) CanBeDeleted() bool {
 	return w.Balance.IsZero()
 }
+
+func (w *Wallet) IsDeleted() bool {
+	return w.DeletedAt != nil
+}
+
+// OwnerCanChangeStatusTo reports whether the wallet's creator may move it to
+// the given status. Closing a wallet for good also needs a zero balance.
+func (w *Wallet) OwnerCanChangeStatusTo(to WalletStatus) bool {
+	for _, allowed := range ownerWalletTransitions[w.Status] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Wallet) CanBeFrozen() bool {
+	return w.Status.CanBeSpent()
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type CampaignStatus string
+
+const (
+	// CampaignStatusActive campaigns are running, or waiting for StartsAt.
+	CampaignStatusActive    CampaignStatus = "active"
+	CampaignStatusEnded     CampaignStatus = "ended"
+	CampaignStatusCancelled CampaignStatus = "cancelled"
+)
+
+// CampaignContributionTypes are the transactions that count towards a
+// campaign: money paid into the wallet, but not reversals of its spending.
+var CampaignContributionTypes = []TransactionType{
+	TransactionTypeDeposit,
+	TransactionTypeCashDeposit,
+	TransactionTypeManualCredit,
+	TransactionTypeTransferIn,
+}
+
+// WalletCampaign is a fundraising push for a wallet: a goal to reach between
+// StartsAt and EndsAt, with milestones along the way. Progress is worked out
+// from the wallet's contributions in that window rather than stored.
+// GoalReachedAt and EndedAt are set when the campaign's events fire.
+type WalletCampaign struct {
+	ID            string               `json:"id"`
+	WalletID      string               `json:"wallet_id"`
+	CreatedBy     string               `json:"created_by"`
+	Title         string               `json:"title"`
+	Description   string               `json:"description,omitempty"`
+	Goal          decimal.Decimal      `json:"goal"`
+	StartsAt      time.Time            `json:"starts_at"`
+	EndsAt        time.Time            `json:"ends_at"`
+	Status        CampaignStatus       `json:"status"`
+	GoalReachedAt *time.Time           `json:"goal_reached_at,omitempty"`
+	EndedAt       *time.Time           `json:"ended_at,omitempty"`
+	Milestones    []*CampaignMilestone `json:"milestones,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// HasStarted reports whether contributions at now count towards the campaign.
+func (c *WalletCampaign) HasStarted(now time.Time) bool {
+	return !now.Before(c.StartsAt)
+}
+
+// IsPastDeadline reports whether an active campaign's end date has passed and
+// it is due to be ended.
+func (c *WalletCampaign) IsPastDeadline(now time.Time) bool {
+	return c.Status == CampaignStatusActive && !now.Before(c.EndsAt)
+}
+
+// CampaignMilestone is an amount raised along the way to the campaign's goal.
+type CampaignMilestone struct {
+	ID         string          `json:"id"`
+	CampaignID string          `json:"campaign_id"`
+	Label      string          `json:"label"`
+	Amount     decimal.Decimal `json:"amount"`
+	ReachedAt  *time.Time      `json:"reached_at,omitempty"`
+}
+
+// CampaignProgress is what a campaign has raised so far. Contributors who
+// paid online are counted once per email address; every other contribution
+// counts as its own contributor.
+type CampaignProgress struct {
+	Raised           decimal.Decimal `json:"raised"`
+	ContributorCount int             `json:"contributor_count"`
+}
+
+// Percent is how far the campaign is towards its goal, to one decimal place.
+// It goes past 100 when the goal is exceeded.
+func (p *CampaignProgress) Percent(goal decimal.Decimal) decimal.Decimal {
+	if !goal.IsPositive() {
+		return decimal.Zero
+	}
+	return p.Raised.Div(goal).Mul(decimal.NewFromInt(100)).RoundFloor(1)
+}
+
+type CampaignEventType string
+
+const (
+	CampaignEventMilestoneReached CampaignEventType = "milestone_reached"
+	CampaignEventGoalReached      CampaignEventType = "goal_reached"
+	CampaignEventDeadlinePassed   CampaignEventType = "deadline_passed"
+)
+
+// CampaignEvent records something that happened to a campaign, with its
+// progress at the time.
+type CampaignEvent struct {
+	ID               string            `json:"id"`
+	CampaignID       string            `json:"campaign_id"`
+	Type             CampaignEventType `json:"type"`
+	MilestoneID      *string           `json:"milestone_id,omitempty"`
+	Raised           decimal.Decimal   `json:"raised"`
+	ContributorCount int               `json:"contributor_count"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCampaignProgressPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		raised string
+		goal   string
+		want   string
+	}{
+		{"nothing raised", "0", "1000", "0"},
+		{"part way", "250", "1000", "25"},
+		{"rounded down to one place", "333.33", "1000", "33.3"},
+		{"just short of the goal", "999.99", "1000", "99.9"},
+		{"goal reached", "1000", "1000", "100"},
+		{"goal exceeded", "1500", "1000", "150"},
+		{"no goal", "100", "0", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := &CampaignProgress{Raised: decimal.RequireFromString(tt.raised)}
+			got := progress.Percent(decimal.RequireFromString(tt.goal))
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Fatalf("Percent(%s) with %s raised = %s, want %s", tt.goal, tt.raised, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalletCampaignDates(t *testing.T) {
+	startsAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		status           CampaignStatus
+		now              time.Time
+		wantStarted      bool
+		wantPastDeadline bool
+	}{
+		{"before the start", CampaignStatusActive, startsAt.Add(-time.Second), false, false},
+		{"at the start", CampaignStatusActive, startsAt, true, false},
+		{"running", CampaignStatusActive, startsAt.Add(24 * time.Hour), true, false},
+		{"at the deadline", CampaignStatusActive, endsAt, true, true},
+		{"after the deadline", CampaignStatusActive, endsAt.Add(time.Hour), true, true},
+		{"ended after the deadline", CampaignStatusEnded, endsAt.Add(time.Hour), true, false},
+		{"cancelled after the deadline", CampaignStatusCancelled, endsAt.Add(time.Hour), true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			campaign := &WalletCampaign{Status: tt.status, StartsAt: startsAt, EndsAt: endsAt}
+			if got := campaign.HasStarted(tt.now); got != tt.wantStarted {
+				t.Errorf("HasStarted() = %v, want %v", got, tt.wantStarted)
+			}
+			if got := campaign.IsPastDeadline(tt.now); got != tt.wantPastDeadline {
+				t.Errorf("IsPastDeadline() = %v, want %v", got, tt.wantPastDeadline)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ClosureDisposition is what happens to a wallet's remaining balance when its
+// owner closes it.
+type ClosureDisposition string
+
+const (
+	// ClosureDispositionTransfer moves the balance into another wallet.
+	ClosureDispositionTransfer ClosureDisposition = "transfer"
+	// ClosureDispositionRefund pays the balance back to the wallet's
+	// contributors through Paystack, in proportion to what each gave.
+	ClosureDispositionRefund ClosureDisposition = "refund"
+	// ClosureDispositionDonate gives the balance to the CareWallet platform
+	// fund.
+	ClosureDispositionDonate ClosureDisposition = "donate"
+)
+
+func (d ClosureDisposition) IsValid() bool {
+	switch d {
+	case ClosureDispositionTransfer, ClosureDispositionRefund, ClosureDispositionDonate:
+		return true
+	}
+	return false
+}
+
+type WalletClosureStatus string
+
+const (
+	// WalletClosureStatusRefunding closures have debited the wallet but still
+	// have refunds Paystack has not accepted.
+	WalletClosureStatusRefunding WalletClosureStatus = "refunding"
+	WalletClosureStatusCompleted WalletClosureStatus = "completed"
+)
+
+// WalletClosure records how a wallet's remaining balance was disposed of when
+// it was closed. The wallet is debited and closed in one step; for refunds
+// the payments back to contributors follow, one WalletClosureRefund each.
+// TransactionID is the wallet's debit for a transfer or donation, and
+// TargetTransactionID the matching credit to the target wallet.
+type WalletClosure struct {
+	ID                  string                 `json:"id"`
+	WalletID            string                 `json:"wallet_id"`
+	RequestedBy         string                 `json:"requested_by"`
+	Disposition         ClosureDisposition     `json:"disposition"`
+	Amount              decimal.Decimal        `json:"amount"`
+	TargetWalletID      *string                `json:"target_wallet_id,omitempty"`
+	TargetWalletName    string                 `json:"target_wallet_name,omitempty"`
+	TransactionID       *string                `json:"transaction_id,omitempty"`
+	TargetTransactionID *string                `json:"target_transaction_id,omitempty"`
+	Reason              string                 `json:"reason,omitempty"`
+	Status              WalletClosureStatus    `json:"status"`
+	Refunds             []*WalletClosureRefund `json:"refunds,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
+}
+
+type ClosureRefundStatus string
+
+const (
+	ClosureRefundStatusPending  ClosureRefundStatus = "pending"
+	ClosureRefundStatusRefunded ClosureRefundStatus = "refunded"
+	ClosureRefundStatusFailed   ClosureRefundStatus = "failed"
+)
+
+// WalletClosureRefund is one contributor's share of a refunded balance, paid
+// back against the Paystack payment they contributed with.
+type WalletClosureRefund struct {
+	ID               string              `json:"id"`
+	ClosureID        string              `json:"closure_id"`
+	PaymentID        string              `json:"payment_id"`
+	PaymentReference string              `json:"payment_reference"`
+	TransactionID    string              `json:"transaction_id"`
+	ContributorEmail string              `json:"contributor_email"`
+	Amount           decimal.Decimal     `json:"amount"`
+	Status           ClosureRefundStatus `json:"status"`
+	GatewayRefundID  string              `json:"gateway_refund_id,omitempty"`
+	Attempts         int                 `json:"attempts"`
+	LastError        string              `json:"last_error,omitempty"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+// IsOutstanding reports whether the refund still has to be sent to Paystack.
+func (r *WalletClosureRefund) IsOutstanding() bool {
+	return r.Status == ClosureRefundStatusPending || r.Status == ClosureRefundStatusFailed
+}
+
+// ProRataShares splits total between contributions in proportion to their
+// amounts, to the cent. Cents lost to rounding go to the contributions with
+// the largest remainders, so the shares always add up to total. No share is
+// more than its contribution as long as total does not exceed their sum.
+func ProRataShares(total decimal.Decimal, contributions []decimal.Decimal) []decimal.Decimal {
+	shares := make([]decimal.Decimal, len(contributions))
+	sum := decimal.Zero
+	for _, c := range contributions {
+		sum = sum.Add(c)
+	}
+	if !sum.IsPositive() {
+		return shares
+	}
+
+	remainders := make([]decimal.Decimal, len(contributions))
+	allocated := decimal.Zero
+	for i, c := range contributions {
+		exact := total.Mul(c).Div(sum)
+		shares[i] = exact.RoundFloor(2)
+		remainders[i] = exact.Sub(shares[i])
+		allocated = allocated.Add(shares[i])
+	}
+
+	cent := decimal.New(1, -2)
+	for left := total.Sub(allocated); left.IsPositive(); left = left.Sub(cent) {
+		best := -1
+		for i := range contributions {
+			if shares[i].Add(cent).GreaterThan(contributions[i]) {
+				continue
+			}
+			if best == -1 || remainders[i].GreaterThan(remainders[best]) {
+				best = i
+			}
+		}
+		if best == -1 {
+			break
+		}
+		shares[best] = shares[best].Add(cent)
+		remainders[best] = decimal.Zero.Sub(cent)
+	}
+
+	return shares
+}
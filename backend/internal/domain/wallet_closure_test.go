@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestProRataShares(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         string
+		contributions []string
+		want          []string
+	}{
+		{"whole balance", "150", []string{"100", "50"}, []string{"100", "50"}},
+		{"even split", "50", []string{"100", "100"}, []string{"25", "25"}},
+		{"proportional split", "60", []string{"100", "50"}, []string{"40", "20"}},
+		{"leftover cent to the largest remainder", "10", []string{"10", "10", "10"}, []string{"3.34", "3.33", "3.33"}},
+		{"leftover cents spread across remainders", "100", []string{"30", "30", "30", "10"}, []string{"30", "30", "30", "10"}},
+		{"cents split unevenly", "0.05", []string{"1", "2", "3"}, []string{"0.01", "0.02", "0.02"}},
+		{"no share above its contribution", "1.01", []string{"0.01", "1"}, []string{"0.01", "1"}},
+		{"zero total", "0", []string{"10", "20"}, []string{"0", "0"}},
+		{"no contributions", "10", nil, nil},
+		{"nothing contributed", "10", []string{"0", "0"}, []string{"0", "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contributions := make([]decimal.Decimal, len(tt.contributions))
+			for i, c := range tt.contributions {
+				contributions[i] = decimal.RequireFromString(c)
+			}
+
+			got := ProRataShares(decimal.RequireFromString(tt.total), contributions)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ProRataShares() returned %d shares, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !got[i].Equal(decimal.RequireFromString(tt.want[i])) {
+					t.Fatalf("ProRataShares(%s, %v) = %v, want %v", tt.total, tt.contributions, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+)
+
+// WalletLookup records a pharmacy looking up a wallet code. WalletID is nil
+// when the code did not match a wallet. AmountChecked is set when the lookup
+// asked whether an amount is covered.
+type WalletLookup struct {
+	ID             string    `json:"id"`
+	PharmacyID     string    `json:"pharmacy_id"`
+	PharmacyUserID *string   `json:"pharmacy_user_id,omitempty"`
+	Code           string    `json:"code"`
+	WalletID       *string   `json:"wallet_id,omitempty"`
+	Found          bool      `json:"found"`
+	AmountChecked  bool      `json:"amount_checked"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WalletShareLink is an extra link to a wallet's contribution page. It only
+// takes contributions; it cannot be used at a pharmacy. A link stops working
+// once it is revoked, its ExpiresAt passes or MaxContributions contributions
+// have been paid through it.
+type WalletShareLink struct {
+	ID                string          `json:"id"`
+	WalletID          string          `json:"wallet_id"`
+	CreatedBy         string          `json:"created_by"`
+	Token             string          `json:"token"`
+	Label             string          `json:"label,omitempty"`
+	ExpiresAt         *time.Time      `json:"expires_at,omitempty"`
+	MaxContributions  *int            `json:"max_contributions,omitempty"`
+	ViewCount         int             `json:"view_count"`
+	ContributionCount int             `json:"contribution_count"`
+	ContributionTotal decimal.Decimal `json:"contribution_total"`
+	RevokedAt         *time.Time      `json:"revoked_at,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+func (l *WalletShareLink) IsRevoked() bool {
+	return l.RevokedAt != nil
+}
+
+// IsUsable reports whether the link still takes contributions at now.
+func (l *WalletShareLink) IsUsable(now time.Time) bool {
+	if l.IsRevoked() {
+		return false
+	}
+	if l.ExpiresAt != nil && !now.Before(*l.ExpiresAt) {
+		return false
+	}
+	if l.MaxContributions != nil && l.ContributionCount >= *l.MaxContributions {
+		return false
+	}
+	return true
+}
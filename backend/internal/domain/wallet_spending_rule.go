@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// WalletSpendingRule allows a wallet to be spent at one pharmacy or at every
+// branch of an organisation; exactly one of PharmacyID and OrganisationID is
+// set. A wallet without rules can be spent at any pharmacy. TargetName is the
+// pharmacy or organisation name, read for display.
+type WalletSpendingRule struct {
+	ID             string    `json:"id"`
+	WalletID       string    `json:"wallet_id"`
+	PharmacyID     *string   `json:"pharmacy_id,omitempty"`
+	OrganisationID *string   `json:"organisation_id,omitempty"`
+	TargetName     string    `json:"target_name"`
+	CreatedAt      time.Time `json:"created_at"`
+}
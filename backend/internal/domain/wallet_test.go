@@ -0,0 +1,73 @@
+package domain
+
+import "testing"
+
+func TestWalletStatusPermissions(t *testing.T) {
+	tests := []struct {
+		status            WalletStatus
+		valid             bool
+		takesContribution bool
+		canBeSpent        bool
+		canBeCredited     bool
+	}{
+		{WalletStatusActive, true, true, true, true},
+		{WalletStatusPaused, true, false, true, true},
+		{WalletStatusClosing, true, false, true, true},
+		{WalletStatusClosed, true, false, false, false},
+		{WalletStatusFrozen, true, false, false, true},
+		{"archived", false, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsValid(); got != tt.valid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.valid)
+			}
+			if got := tt.status.AcceptsContributions(); got != tt.takesContribution {
+				t.Errorf("AcceptsContributions() = %v, want %v", got, tt.takesContribution)
+			}
+			if got := tt.status.CanBeSpent(); got != tt.canBeSpent {
+				t.Errorf("CanBeSpent() = %v, want %v", got, tt.canBeSpent)
+			}
+			if got := tt.status.CanBeCredited(); got != tt.canBeCredited {
+				t.Errorf("CanBeCredited() = %v, want %v", got, tt.canBeCredited)
+			}
+			if got := (&Wallet{Status: tt.status}).CanBeFrozen(); got != tt.canBeSpent {
+				t.Errorf("CanBeFrozen() = %v, want %v", got, tt.canBeSpent)
+			}
+		})
+	}
+}
+
+func TestWalletOwnerCanChangeStatusTo(t *testing.T) {
+	statuses := []WalletStatus{WalletStatusActive, WalletStatusPaused, WalletStatusClosing, WalletStatusClosed, WalletStatusFrozen}
+
+	tests := []struct {
+		from    WalletStatus
+		allowed []WalletStatus
+	}{
+		{WalletStatusActive, []WalletStatus{WalletStatusPaused, WalletStatusClosing, WalletStatusClosed}},
+		{WalletStatusPaused, []WalletStatus{WalletStatusActive, WalletStatusClosing, WalletStatusClosed}},
+		{WalletStatusClosing, []WalletStatus{WalletStatusActive, WalletStatusClosed}},
+		// Closed wallets stay closed and only admins unfreeze.
+		{WalletStatusClosed, nil},
+		{WalletStatusFrozen, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from), func(t *testing.T) {
+			wallet := &Wallet{Status: tt.from}
+			for _, to := range statuses {
+				want := false
+				for _, allowed := range tt.allowed {
+					if allowed == to {
+						want = true
+					}
+				}
+				if got := wallet.OwnerCanChangeStatusTo(to); got != want {
+					t.Errorf("OwnerCanChangeStatusTo(%s) = %v, want %v", to, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package dto
+
+type BankAccountRequest struct {
+	BankName      string `json:"bank_name" binding:"required"`
+	BankCode      string `json:"bank_code"`
+	BranchCode    string `json:"branch_code" binding:"required,numeric"`
+	AccountNumber string `json:"account_number" binding:"required,numeric,min=6,max=20"`
+	AccountHolder string `json:"account_holder" binding:"required"`
+}
+
+type BankAccountResponse struct {
+	ID                 string  `json:"id"`
+	PharmacyID         string  `json:"pharmacy_id"`
+	BankName           string  `json:"bank_name"`
+	BankCode           string  `json:"bank_code,omitempty"`
+	BranchCode         string  `json:"branch_code"`
+	AccountNumber      string  `json:"account_number"`
+	AccountHolder      string  `json:"account_holder"`
+	Status             string  `json:"status"`
+	VerificationStatus string  `json:"verification_status"`
+	VerifiedName       string  `json:"verified_name,omitempty"`
+	RejectionReason    string  `json:"rejection_reason,omitempty"`
+	EffectiveAt        *string `json:"effective_at,omitempty"`
+	IsActive           bool    `json:"is_active"`
+	CreatedAt          string  `json:"created_at"`
+}
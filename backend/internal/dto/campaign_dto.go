@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+type CampaignMilestoneRequest struct {
+	Label  string  `json:"label" binding:"required,max=100"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// CreateCampaignRequest starts a campaign for a wallet. Leaving out StartsAt
+// starts it straight away.
+type CreateCampaignRequest struct {
+	Title       string                     `json:"title" binding:"required,max=100"`
+	Description string                     `json:"description" binding:"max=2000"`
+	Goal        float64                    `json:"goal" binding:"required,gt=0"`
+	StartsAt    *time.Time                 `json:"starts_at,omitempty"`
+	EndsAt      time.Time                  `json:"ends_at" binding:"required"`
+	Milestones  []CampaignMilestoneRequest `json:"milestones" binding:"max=10,dive"`
+}
+
+// UpdateCampaignRequest changes only the fields that are set. The end date
+// can be moved but must stay in the future.
+type UpdateCampaignRequest struct {
+	Title       *string    `json:"title,omitempty" binding:"omitempty,max=100"`
+	Description *string    `json:"description,omitempty" binding:"omitempty,max=2000"`
+	Goal        *float64   `json:"goal,omitempty" binding:"omitempty,gt=0"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+}
+
+type CampaignMilestoneResponse struct {
+	ID        string  `json:"id"`
+	Label     string  `json:"label"`
+	Amount    float64 `json:"amount"`
+	Reached   bool    `json:"reached"`
+	ReachedAt *string `json:"reached_at,omitempty"`
+}
+
+// CampaignResponse describes a campaign with its progress, worked out from the
+// wallet's contributions between StartsAt and EndsAt. DaysRemaining is zero
+// once the campaign has ended.
+type CampaignResponse struct {
+	ID               string                      `json:"id"`
+	Title            string                      `json:"title"`
+	Description      string                      `json:"description,omitempty"`
+	Goal             float64                     `json:"goal"`
+	Raised           float64                     `json:"raised"`
+	ProgressPercent  float64                     `json:"progress_percent"`
+	ContributorCount int                         `json:"contributor_count"`
+	StartsAt         string                      `json:"starts_at"`
+	EndsAt           string                      `json:"ends_at"`
+	DaysRemaining    int                         `json:"days_remaining"`
+	Status           string                      `json:"status"`
+	GoalReachedAt    *string                     `json:"goal_reached_at,omitempty"`
+	EndedAt          *string                     `json:"ended_at,omitempty"`
+	Milestones       []CampaignMilestoneResponse `json:"milestones"`
+}
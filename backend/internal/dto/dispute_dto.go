@@ -0,0 +1,17 @@
+package dto
+
+type OpenDisputeRequest struct {
+	TransactionID string `json:"transaction_id" binding:"required,uuid"`
+	Reason        string `json:"reason" binding:"required,max=1000"`
+}
+
+// RespondDisputeRequest is a pharmacy's answer to a dispute. Evidence is a
+// list of files the pharmacy uploaded with the dispute_evidence purpose.
+type RespondDisputeRequest struct {
+	Response          string   `json:"response" binding:"required,max=2000"`
+	EvidenceUploadIDs []string `json:"evidence_upload_ids" binding:"omitempty,max=10,dive,uuid"`
+}
+
+type ResolveDisputeRequest struct {
+	Note string `json:"note" binding:"max=1000"`
+}
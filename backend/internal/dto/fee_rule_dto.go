@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// FeeRuleTerms are the parts of a fee rule that can change between versions.
+// Percentage is a fraction of the amount, e.g. 0.04 for 4%. StartsAt
+// defaults to now.
+type FeeRuleTerms struct {
+	Name            string     `json:"name" binding:"required"`
+	Percentage      float64    `json:"percentage" binding:"gte=0,lt=1"`
+	FlatAmount      float64    `json:"flat_amount" binding:"gte=0"`
+	MinFee          *float64   `json:"min_fee" binding:"omitempty,gte=0"`
+	MaxFee          *float64   `json:"max_fee" binding:"omitempty,gte=0"`
+	IsWaiver        bool       `json:"is_waiver"`
+	ContributorPays string     `json:"contributor_pays" binding:"omitempty,oneof=never optional always"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+}
+
+// CreateFeeRuleRequest adds a new rule. Leave both PharmacyID and
+// OrganisationID empty for a platform-wide rule.
+type CreateFeeRuleRequest struct {
+	Code            string `json:"code" binding:"required,max=50"`
+	TransactionType string `json:"transaction_type" binding:"required,oneof=withdrawal deposit"`
+	PharmacyID      string `json:"pharmacy_id"`
+	OrganisationID  string `json:"organisation_id"`
+	FeeRuleTerms
+}
+
+// UpdateFeeRuleRequest replaces a rule's terms with a new version; the rule's
+// code, transaction type and scope stay the same.
+type UpdateFeeRuleRequest struct {
+	FeeRuleTerms
+}
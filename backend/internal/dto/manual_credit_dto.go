@@ -0,0 +1,11 @@
+package dto
+
+type ManualCreditRequest struct {
+	Amount            float64 `json:"amount" binding:"required,gt=0"`
+	Reason            string  `json:"reason" binding:"required"`
+	ExternalReference string  `json:"external_reference" binding:"required"`
+}
+
+type RejectRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
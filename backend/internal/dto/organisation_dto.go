@@ -0,0 +1,36 @@
+package dto
+
+type OrganisationRequest struct {
+	Name               string `json:"name" binding:"required"`
+	RegistrationNumber string `json:"registration_number"`
+	Email              string `json:"email" binding:"omitempty,email"`
+	Phone              string `json:"phone"`
+}
+
+type UpdateOrganisationRequest struct {
+	Name               string `json:"name,omitempty"`
+	RegistrationNumber string `json:"registration_number,omitempty"`
+	Email              string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone              string `json:"phone,omitempty"`
+	Status             string `json:"status,omitempty" binding:"omitempty,oneof=active inactive"`
+}
+
+// AddBranchRequest moves an existing pharmacy into the organisation.
+type AddBranchRequest struct {
+	PharmacyID string `json:"pharmacy_id" binding:"required"`
+}
+
+// AddOrganisationMemberRequest gives an existing CareWallet user access to
+// every branch of the organisation.
+type AddOrganisationMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type BranchResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ShortCode string `json:"short_code"`
+	Address   string `json:"address,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	Status    string `json:"status"`
+}
@@ -2,12 +2,14 @@ package dto
 
 type PharmacyLoginRequest struct {
 	ShortCode string `json:"short_code" binding:"required"`
+	Username  string `json:"username" binding:"required"`
 	Password  string `json:"password" binding:"required"`
 }
 
 type PharmacyAuthResponse struct {
-	Token    string           `json:"token"`
-	Pharmacy PharmacyResponse `json:"pharmacy"`
+	Token    string               `json:"token"`
+	Pharmacy PharmacyResponse     `json:"pharmacy"`
+	User     PharmacyUserResponse `json:"user"`
 }
 
 type PharmacyResponse struct {
@@ -21,16 +23,55 @@ type PharmacyResponse struct {
 	Status             string `json:"status"`
 }
 
+// WalletLookupResponse is what a pharmacy sees for a wallet code. The
+// beneficiary's name is masked and the balance is left out; AmountCovered
+// answers whether the amount asked about could be withdrawn.
 type WalletLookupResponse struct {
-	WalletID        string  `json:"wallet_id"`
-	WalletName      string  `json:"wallet_name"`
-	Balance         float64 `json:"balance"`
-	BeneficiaryName string  `json:"beneficiary_name"`
+	WalletID        string `json:"wallet_id"`
+	WalletName      string `json:"wallet_name"`
+	BeneficiaryName string `json:"beneficiary_name"`
+	// SpendableHere is false when the wallet's spending rules do not include
+	// this pharmacy or its chain.
+	SpendableHere bool  `json:"spendable_here"`
+	AmountCovered *bool `json:"amount_covered,omitempty"`
+}
+
+type BalanceConsentResponse struct {
+	OTPSentTo string `json:"otp_sent_to"`
+}
+
+// BalanceDisclosureRequest carries the OTP the wallet owner shares with the
+// pharmacy to consent to their balance being shown.
+type BalanceDisclosureRequest struct {
+	OTPCode string `json:"otp_code" binding:"required,len=6"`
+}
+
+type WalletBalanceResponse struct {
+	WalletID string  `json:"wallet_id"`
+	Balance  float64 `json:"balance"`
+}
+
+// ScanVoucherRequest carries a wallet voucher read from the QR code on the
+// wallet owner's phone. Amount, when set, asks whether the wallet covers it.
+type ScanVoucherRequest struct {
+	Voucher string   `json:"voucher" binding:"required"`
+	Amount  *float64 `json:"amount,omitempty" binding:"omitempty,gt=0"`
+}
+
+// ScanVoucherResponse describes the wallet like a code lookup, with the wallet
+// code to use for the withdrawal.
+type ScanVoucherResponse struct {
+	WalletLookupResponse
+	WalletCode       string `json:"wallet_code"`
+	VoucherExpiresAt string `json:"voucher_expires_at"`
 }
 
 type WithdrawalInitRequest struct {
 	WalletCode string  `json:"wallet_code" binding:"required"`
 	Amount     float64 `json:"amount" binding:"required,gt=0"`
+	// QuoteID redeems a fee quote for the same wallet and amount, fixing the
+	// fee that will be charged.
+	QuoteID string `json:"quote_id"`
 }
 
 type WithdrawalInitResponse struct {
@@ -44,4 +85,41 @@ type WithdrawalInitResponse struct {
 type WithdrawalCompleteRequest struct {
 	WithdrawalID string `json:"withdrawal_id" binding:"required"`
 	OTPCode      string `json:"otp_code" binding:"required"`
+	// LineItems optionally lists what was dispensed. Their totals must add
+	// up to the withdrawal amount.
+	LineItems []LineItemRequest `json:"line_items" binding:"omitempty,dive"`
+}
+
+type VoidWithdrawalRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type LineItemRequest struct {
+	ProductName string  `json:"product_name" binding:"required,max=255"`
+	NAPPICode   string  `json:"nappi_code" binding:"omitempty,max=20"`
+	Quantity    int     `json:"quantity" binding:"required,gt=0"`
+	UnitPrice   float64 `json:"unit_price" binding:"gte=0"`
+	Category    string  `json:"category" binding:"required,oneof=prescription otc other"`
+}
+
+// CashInRequest records cash handed over at the pharmacy. Anonymous and
+// HideAmount are how the contributor asked to appear on the wallet's
+// contributor wall.
+type CashInRequest struct {
+	WalletCode         string  `json:"wallet_code" binding:"required"`
+	Amount             float64 `json:"amount" binding:"required,gt=0"`
+	ContributorName    string  `json:"contributor_name" binding:"required"`
+	ContributorMessage string  `json:"contributor_message,omitempty" binding:"max=500"`
+	Anonymous          bool    `json:"anonymous"`
+	HideAmount         bool    `json:"hide_amount"`
+}
+
+type CashInSummaryResponse struct {
+	DailyLimit     float64 `json:"daily_limit"`
+	TodayTotal     float64 `json:"today_total"`
+	TodayCount     int     `json:"today_count"`
+	RemainingToday float64 `json:"remaining_today"`
+	// AmountOwed is the cash collected on behalf of CareWallet that the
+	// pharmacy still has to settle with the platform.
+	AmountOwed float64 `json:"amount_owed"`
 }
@@ -0,0 +1,36 @@
+package dto
+
+// PharmacyListingRequest sets what the public pharmacy finder shows. It
+// replaces the pharmacy's services and opening hours; omit Latitude and
+// Longitude to hide the pharmacy from location searches.
+type PharmacyListingRequest struct {
+	Latitude     *float64              `json:"latitude"`
+	Longitude    *float64              `json:"longitude"`
+	Services     []string              `json:"services"`
+	OpeningHours []OpeningHoursRequest `json:"opening_hours" binding:"dive"`
+}
+
+// OpeningHoursRequest is one opening period. DayOfWeek runs from 0 (Sunday)
+// to 6; times are HH:MM in the business timezone and ClosesAt may be 24:00.
+type OpeningHoursRequest struct {
+	DayOfWeek int    `json:"day_of_week" binding:"min=0,max=6"`
+	OpensAt   string `json:"opens_at" binding:"required"`
+	ClosesAt  string `json:"closes_at" binding:"required"`
+}
+
+type PharmacySearchRequest struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Service   string
+	OpenNow   bool
+	Limit     int
+}
+
+type PharmacyListingResponse struct {
+	PharmacyID   string                `json:"pharmacy_id"`
+	Latitude     *float64              `json:"latitude,omitempty"`
+	Longitude    *float64              `json:"longitude,omitempty"`
+	Services     []string              `json:"services"`
+	OpeningHours []OpeningHoursRequest `json:"opening_hours"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+// PharmacyDocumentRequest refers to a document either by an external URL or
+// by the ID of a file uploaded through the registration upload endpoint.
+type PharmacyDocumentRequest struct {
+	DocumentType string `json:"document_type" binding:"required,oneof=pharmacy_licence company_registration proof_of_address other"`
+	FileName     string `json:"file_name" binding:"max=255"`
+	URL          string `json:"url" binding:"required_without=UploadID,omitempty,url"`
+	UploadID     string `json:"upload_id" binding:"required_without=URL,omitempty,uuid"`
+}
+
+// PharmacyRegistrationRequest is submitted by a pharmacy applying to join.
+// ShortCode is the code staff will sign in with once the pharmacy is approved.
+type PharmacyRegistrationRequest struct {
+	Name               string                    `json:"name" binding:"required,max=255"`
+	ShortCode          string                    `json:"short_code" binding:"required,alphanum,min=3,max=20"`
+	RegistrationNumber string                    `json:"registration_number" binding:"required,max=100"`
+	Address            string                    `json:"address" binding:"required"`
+	Phone              string                    `json:"phone" binding:"required,max=20"`
+	Email              string                    `json:"email" binding:"required,email"`
+	ContactName        string                    `json:"contact_name" binding:"required,max=255"`
+	Documents          []PharmacyDocumentRequest `json:"documents" binding:"required,min=1,dive"`
+}
+
+type ApprovePharmacyRequest struct {
+	ManagerUsername string `json:"manager_username" binding:"omitempty,alphanum,min=3,max=50"`
+	Notes           string `json:"notes,omitempty"`
+}
+
+type PharmacyDocumentResponse struct {
+	ID           string  `json:"id"`
+	DocumentType string  `json:"document_type"`
+	FileName     string  `json:"file_name"`
+	URL          string  `json:"url"`
+	UploadID     *string `json:"upload_id,omitempty"`
+}
+
+// PharmacyApplicationResponse describes a registration and its review.
+// ApplicationToken is only returned when the application is first submitted;
+// the applicant needs it to check on or update the application.
+type PharmacyApplicationResponse struct {
+	ID                 string                     `json:"id"`
+	Name               string                     `json:"name"`
+	ShortCode          string                     `json:"short_code"`
+	RegistrationNumber string                     `json:"registration_number"`
+	Address            string                     `json:"address,omitempty"`
+	Phone              string                     `json:"phone,omitempty"`
+	Email              string                     `json:"email,omitempty"`
+	ContactName        string                     `json:"contact_name,omitempty"`
+	Status             string                     `json:"status"`
+	ReviewNotes        string                     `json:"review_notes,omitempty"`
+	SubmittedAt        *string                    `json:"submitted_at,omitempty"`
+	ReviewedAt         *string                    `json:"reviewed_at,omitempty"`
+	Documents          []PharmacyDocumentResponse `json:"documents"`
+	ApplicationToken   string                     `json:"application_token,omitempty"`
+}
+
+type SetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
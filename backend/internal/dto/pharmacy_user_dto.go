@@ -0,0 +1,24 @@
+package dto
+
+type CreatePharmacyUserRequest struct {
+	Username string `json:"username" binding:"required,alphanum,min=3,max=50"`
+	FullName string `json:"full_name" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+	Role     string `json:"role" binding:"required,oneof=manager cashier viewer"`
+}
+
+type UpdatePharmacyUserRequest struct {
+	FullName string `json:"full_name,omitempty"`
+	Password string `json:"password,omitempty" binding:"omitempty,min=8"`
+	Role     string `json:"role,omitempty" binding:"omitempty,oneof=manager cashier viewer"`
+	Status   string `json:"status,omitempty" binding:"omitempty,oneof=active disabled"`
+}
+
+type PharmacyUserResponse struct {
+	ID          string  `json:"id"`
+	Username    string  `json:"username"`
+	FullName    string  `json:"full_name"`
+	Role        string  `json:"role"`
+	Status      string  `json:"status"`
+	LastLoginAt *string `json:"last_login_at,omitempty"`
+}
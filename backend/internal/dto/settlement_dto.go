@@ -0,0 +1,10 @@
+package dto
+
+type RunSettlementRequest struct {
+	// Cutoff is an RFC3339 timestamp; it defaults to the start of the current business day.
+	Cutoff string `json:"cutoff,omitempty"`
+}
+
+type MarkSettlementPaidRequest struct {
+	PayoutReference string `json:"payout_reference" binding:"required"`
+}
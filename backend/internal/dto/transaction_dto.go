@@ -1,35 +1,77 @@
 package dto
 
-type DepositRequest struct {
-	Amount             float64 `json:"amount" binding:"required,gt=0"`
-	ContributorEmail   string  `json:"contributor_email,omitempty"`
-	ContributorName    string  `json:"contributor_name,omitempty"`
-	ContributorMessage string  `json:"contributor_message,omitempty"`
-	PaystackReference  string  `json:"paystack_reference,omitempty"`
-}
-
 type WithdrawalRequest struct {
 	WalletID   string  `json:"wallet_id" binding:"required"`
 	Amount     float64 `json:"amount" binding:"required,gt=0"`
 	PharmacyID string  `json:"pharmacy_id" binding:"required"`
 	OTPCode    string  `json:"otp_code" binding:"required,len=6"`
+	// QuoteID redeems a fee quote for the same wallet, pharmacy and amount.
+	QuoteID string `json:"quote_id"`
+}
+
+// FeeQuoteRequest asks what a withdrawal would cost. Pharmacy staff quote for
+// their own pharmacy; beneficiaries name the pharmacy.
+type FeeQuoteRequest struct {
+	WalletCode string  `json:"wallet_code" binding:"required"`
+	PharmacyID string  `json:"pharmacy_id"`
+	Amount     float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// FeeQuoteViolation is a reason the quoted withdrawal would be refused.
+type FeeQuoteViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// FeeQuoteResponse has a QuoteID and ExpiresAt only when there are no
+// violations; pass the QuoteID with the withdrawal to be charged this fee.
+// AvailableBalance is only given to the wallet's own users, never to a
+// pharmacy.
+type FeeQuoteResponse struct {
+	QuoteID          string              `json:"quote_id,omitempty"`
+	WalletName       string              `json:"wallet_name"`
+	PharmacyName     string              `json:"pharmacy_name"`
+	Amount           float64             `json:"amount"`
+	Fee              float64             `json:"fee"`
+	NetAmount        float64             `json:"net_amount"`
+	AvailableBalance *float64            `json:"available_balance,omitempty"`
+	Violations       []FeeQuoteViolation `json:"violations"`
+	ExpiresAt        string              `json:"expires_at,omitempty"`
 }
 
 type TransactionResponse struct {
-	ID                 string  `json:"id"`
-	WalletID           string  `json:"wallet_id"`
-	Type               string  `json:"type"`
-	Amount             float64 `json:"amount"`
-	Fee                float64 `json:"fee"`
-	NetAmount          float64 `json:"net_amount"`
-	Status             string  `json:"status"`
-	ContributorEmail   string  `json:"contributor_email,omitempty"`
-	ContributorName    string  `json:"contributor_name,omitempty"`
-	ContributorMessage string  `json:"contributor_message,omitempty"`
-	PharmacyID         *string `json:"pharmacy_id,omitempty"`
-	PharmacyName       string  `json:"pharmacy_name,omitempty"`
-	PaystackReference  string  `json:"paystack_reference,omitempty"`
-	CreatedAt          string  `json:"created_at"`
+	ID                     string             `json:"id"`
+	WalletID               string             `json:"wallet_id"`
+	Type                   string             `json:"type"`
+	Amount                 float64            `json:"amount"`
+	Fee                    float64            `json:"fee"`
+	FeeRuleID              *string            `json:"fee_rule_id,omitempty"`
+	FeeQuoteID             *string            `json:"fee_quote_id,omitempty"`
+	NetAmount              float64            `json:"net_amount"`
+	Status                 string             `json:"status"`
+	ContributorEmail       string             `json:"contributor_email,omitempty"`
+	ContributorName        string             `json:"contributor_name,omitempty"`
+	ContributorMessage     string             `json:"contributor_message,omitempty"`
+	ContributorAnonymous   bool               `json:"contributor_anonymous,omitempty"`
+	ContributorHidesAmount bool               `json:"contributor_hides_amount,omitempty"`
+	PharmacyID             *string            `json:"pharmacy_id,omitempty"`
+	PharmacyName           string             `json:"pharmacy_name,omitempty"`
+	PharmacyUserID         *string            `json:"pharmacy_user_id,omitempty"`
+	PaystackReference      string             `json:"paystack_reference,omitempty"`
+	SettlementID           *string            `json:"settlement_id,omitempty"`
+	ReversalOf             *string            `json:"reversal_of,omitempty"`
+	ReversalReason         string             `json:"reversal_reason,omitempty"`
+	LineItems              []LineItemResponse `json:"line_items,omitempty"`
+	CreatedAt              string             `json:"created_at"`
+}
+
+type LineItemResponse struct {
+	ProductName string  `json:"product_name"`
+	NAPPICode   string  `json:"nappi_code,omitempty"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Total       float64 `json:"total"`
+	Category    string  `json:"category"`
 }
 
 type TransactionListResponse struct {
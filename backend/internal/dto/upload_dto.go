@@ -0,0 +1,14 @@
+package dto
+
+// UploadResponse describes an uploaded file. URL is a signed download link
+// that stops working at URLExpiresAt.
+type UploadResponse struct {
+	ID           string `json:"id"`
+	Purpose      string `json:"purpose"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+	URL          string `json:"url"`
+	URLExpiresAt string `json:"url_expires_at"`
+	CreatedAt    string `json:"created_at"`
+}
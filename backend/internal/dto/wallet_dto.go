@@ -1,42 +1,146 @@
 package dto
 
+import "time"
+
 type CreateWalletRequest struct {
 	WalletName    string  `json:"wallet_name" binding:"required"`
 	Description   string  `json:"description,omitempty"`
 	PhotoURL      string  `json:"photo_url,omitempty"`
+	PhotoUploadID *string `json:"photo_upload_id,omitempty"`
 	FundingGoal   float64 `json:"funding_goal,omitempty"`
 	BeneficiaryID *string `json:"beneficiary_id,omitempty"`
 }
 
+// UpdateWalletRequest changes only the fields that are set. PhotoUploadID
+// replaces the photo with an uploaded file; an empty string removes it.
+// Status goes through the same checks as ChangeWalletStatusRequest.
 type UpdateWalletRequest struct {
-	WalletName  *string  `json:"wallet_name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	PhotoURL    *string  `json:"photo_url,omitempty"`
-	FundingGoal *float64 `json:"funding_goal,omitempty"`
-	Status      *string  `json:"status,omitempty"`
+	WalletName    *string  `json:"wallet_name,omitempty"`
+	Description   *string  `json:"description,omitempty"`
+	PhotoURL      *string  `json:"photo_url,omitempty"`
+	PhotoUploadID *string  `json:"photo_upload_id,omitempty"`
+	FundingGoal   *float64 `json:"funding_goal,omitempty"`
+	Status        *string  `json:"status,omitempty"`
+}
+
+// ChangeWalletStatusRequest moves a wallet to active, paused, closing or
+// closed.
+type ChangeWalletStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type FreezeWalletRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// CloseWalletRequest closes a wallet and says what happens to its balance:
+// transfer it to the wallet with TargetWalletCode, refund it to contributors
+// or donate it to the platform fund.
+type CloseWalletRequest struct {
+	Disposition      string `json:"disposition" binding:"required"`
+	TargetWalletCode string `json:"target_wallet_code"`
+	Reason           string `json:"reason" binding:"max=500"`
+}
+
+// PlatformFundResponse is what closed wallets have donated to the platform
+// fund.
+type PlatformFundResponse struct {
+	Total         float64 `json:"total"`
+	DonationCount int     `json:"donation_count"`
 }
 
 type WalletResponse struct {
-	ID            string  `json:"id"`
-	CreatorID     string  `json:"creator_id"`
-	BeneficiaryID *string `json:"beneficiary_id,omitempty"`
-	WalletName    string  `json:"wallet_name"`
-	Description   string  `json:"description,omitempty"`
-	PhotoURL      string  `json:"photo_url,omitempty"`
-	Balance       float64 `json:"balance"`
-	FundingGoal   float64 `json:"funding_goal,omitempty"`
-	ShareableCode string  `json:"shareable_code"`
-	Status        string  `json:"status"`
-	CreatedAt     string  `json:"created_at"`
-	UpdatedAt     string  `json:"updated_at"`
+	ID              string  `json:"id"`
+	CreatorID       string  `json:"creator_id"`
+	BeneficiaryID   *string `json:"beneficiary_id,omitempty"`
+	WalletName      string  `json:"wallet_name"`
+	Description     string  `json:"description,omitempty"`
+	PhotoURL        string  `json:"photo_url,omitempty"`
+	Balance         float64 `json:"balance"`
+	FundingGoal     float64 `json:"funding_goal,omitempty"`
+	ShareableCode   string  `json:"shareable_code"`
+	DisplayCode     string  `json:"display_code"`
+	Status          string  `json:"status"`
+	StatusReason    string  `json:"status_reason,omitempty"`
+	StatusChangedAt *string `json:"status_changed_at,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
 }
 
+// PublicWalletResponse is what contributors see. The codes are left out when
+// the page was reached through a share link or a retired code. RedirectedFrom
+// is set when the page was asked for by a code the wallet has since rotated
+// away from.
 type PublicWalletResponse struct {
-	ID            string  `json:"id"`
-	WalletName    string  `json:"wallet_name"`
-	Description   string  `json:"description,omitempty"`
-	PhotoURL      string  `json:"photo_url,omitempty"`
-	Balance       float64 `json:"balance"`
-	FundingGoal   float64 `json:"funding_goal,omitempty"`
-	ShareableCode string  `json:"shareable_code"`
+	ID             string  `json:"id"`
+	WalletName     string  `json:"wallet_name"`
+	Description    string  `json:"description,omitempty"`
+	PhotoURL       string  `json:"photo_url,omitempty"`
+	Balance        float64 `json:"balance"`
+	FundingGoal    float64 `json:"funding_goal,omitempty"`
+	Status         string  `json:"status"`
+	ShareableCode  string  `json:"shareable_code,omitempty"`
+	DisplayCode    string  `json:"display_code,omitempty"`
+	RedirectedFrom string  `json:"redirected_from,omitempty"`
+	// Campaign is the wallet's running campaign, or else its last one.
+	Campaign *CampaignResponse `json:"campaign,omitempty"`
+}
+
+// ContributionResponse is one entry on a wallet's contributor wall. Amount
+// is left out when the contributor asked for it to be hidden, and Message
+// when the wallet's creator has hidden it. Only the creator's own view sets
+// MessageHidden, and it still includes the hidden message.
+type ContributionResponse struct {
+	ID            string   `json:"id"`
+	DisplayName   string   `json:"display_name"`
+	Amount        *float64 `json:"amount,omitempty"`
+	Message       string   `json:"message,omitempty"`
+	MessageHidden bool     `json:"message_hidden,omitempty"`
+	CreatedAt     string   `json:"created_at"`
+}
+
+// AddSpendingRuleRequest allows the wallet to be spent at one pharmacy or at
+// every branch of an organisation; exactly one of the IDs must be set.
+type AddSpendingRuleRequest struct {
+	PharmacyID     string `json:"pharmacy_id,omitempty"`
+	OrganisationID string `json:"organisation_id,omitempty"`
+}
+
+type SpendingRuleResponse struct {
+	ID             string  `json:"id"`
+	PharmacyID     *string `json:"pharmacy_id,omitempty"`
+	OrganisationID *string `json:"organisation_id,omitempty"`
+	TargetName     string  `json:"target_name"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// CreateShareLinkRequest creates a contribution-only link. Leaving out
+// ExpiresAt or MaxContributions means the link never expires or has no limit.
+type CreateShareLinkRequest struct {
+	Label            string     `json:"label" binding:"max=100"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	MaxContributions *int       `json:"max_contributions,omitempty"`
+}
+
+type ShareLinkResponse struct {
+	ID                string  `json:"id"`
+	Label             string  `json:"label,omitempty"`
+	URL               string  `json:"url"`
+	Token             string  `json:"token"`
+	ExpiresAt         *string `json:"expires_at,omitempty"`
+	MaxContributions  *int    `json:"max_contributions,omitempty"`
+	Usable            bool    `json:"usable"`
+	ViewCount         int     `json:"view_count"`
+	ContributionCount int     `json:"contribution_count"`
+	ContributionTotal float64 `json:"contribution_total"`
+	RevokedAt         *string `json:"revoked_at,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+}
+
+// WalletVoucherResponse is a signed, short-lived voucher for the wallet's
+// code, shown as a QR code for a pharmacy to scan.
+type WalletVoucherResponse struct {
+	Voucher   string `json:"voucher"`
+	ExpiresAt string `json:"expires_at"`
 }
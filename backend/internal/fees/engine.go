@@ -0,0 +1,184 @@
+// Package fees prices withdrawals and deposits from the fee rules admins
+// configure. The most specific rule in effect wins: a pharmacy's own rule over
+// its organisation's, and an organisation's over the platform's. A waiver in
+// effect at any of those levels beats every ordinary rule. With no rule in
+// effect withdrawals fall back to the configured platform percentage and
+// deposits are free.
+package fees
+
+import (
+	"context"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// Request describes the transaction being priced. OrganisationID is the
+// pharmacy's organisation, if any; deposits leave both IDs empty.
+// ContributorPaysFee is the contributor's choice when the rule leaves it to
+// them.
+type Request struct {
+	Type               domain.TransactionType
+	Amount             decimal.Decimal
+	PharmacyID         string
+	OrganisationID     string
+	ContributorPaysFee bool
+	At                 time.Time
+}
+
+// Result is the priced transaction. Gross is what the wallet is debited or
+// the contributor is charged, and Net what the pharmacy is owed or the wallet
+// is credited. Rule is nil when the default fee applied.
+type Result struct {
+	Gross              decimal.Decimal
+	Fee                decimal.Decimal
+	Net                decimal.Decimal
+	ContributorPaysFee bool
+	Rule               *domain.FeeRule
+}
+
+// RuleID returns the ID of the rule version that priced the transaction, for
+// recording against it.
+func (r *Result) RuleID() *string {
+	if r.Rule == nil {
+		return nil
+	}
+	return &r.Rule.ID
+}
+
+type Engine interface {
+	Evaluate(ctx context.Context, req Request) (*Result, error)
+}
+
+type engine struct {
+	feeRuleRepo       repository.FeeRuleRepository
+	defaultPercentage decimal.Decimal
+}
+
+func NewEngine(feeRuleRepo repository.FeeRuleRepository, cfg *config.Config) Engine {
+	return &engine{
+		feeRuleRepo:       feeRuleRepo,
+		defaultPercentage: decimal.NewFromFloat(cfg.PlatformFeePercentage),
+	}
+}
+
+func (e *engine) Evaluate(ctx context.Context, req Request) (*Result, error) {
+	if req.At.IsZero() {
+		req.At = time.Now()
+	}
+
+	rules, err := e.feeRuleRepo.GetApplicable(ctx, req.Type, req.PharmacyID, req.OrganisationID, req.At)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := Select(rules, req.At)
+	if rule == nil {
+		return e.defaultResult(req), nil
+	}
+
+	return Apply(rule, req), nil
+}
+
+func (e *engine) defaultResult(req Request) *Result {
+	fee := decimal.Zero
+	if req.Type == domain.TransactionTypeWithdrawal {
+		fee = req.Amount.Mul(e.defaultPercentage).Round(2)
+	}
+
+	return &Result{
+		Gross: req.Amount,
+		Fee:   fee,
+		Net:   req.Amount.Sub(fee),
+	}
+}
+
+// Select picks the rule that prices a transaction at t from the candidates,
+// or returns nil if none is in effect. Ties at the same level go to the rule
+// that started most recently.
+func Select(rules []*domain.FeeRule, t time.Time) *domain.FeeRule {
+	var best *domain.FeeRule
+	for _, rule := range rules {
+		if !rule.IsCurrent() || !rule.InEffectAt(t) {
+			continue
+		}
+		if best == nil || outranks(rule, best) {
+			best = rule
+		}
+	}
+	return best
+}
+
+func outranks(a, b *domain.FeeRule) bool {
+	if a.IsWaiver != b.IsWaiver {
+		return a.IsWaiver
+	}
+	if scopeRank(a) != scopeRank(b) {
+		return scopeRank(a) > scopeRank(b)
+	}
+	if !a.StartsAt.Equal(b.StartsAt) {
+		return a.StartsAt.After(b.StartsAt)
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
+
+func scopeRank(rule *domain.FeeRule) int {
+	switch rule.Scope() {
+	case domain.FeeRuleScopePharmacy:
+		return 2
+	case domain.FeeRuleScopeOrganisation:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Apply prices the request with the rule. A fee the pharmacy or wallet bears
+// never exceeds the amount itself.
+func Apply(rule *domain.FeeRule, req Request) *Result {
+	result := &Result{Rule: rule}
+
+	if rule.TransactionType == domain.TransactionTypeDeposit {
+		switch rule.ContributorPays {
+		case domain.ContributorPaysAlways:
+			result.ContributorPaysFee = true
+		case domain.ContributorPaysOptional:
+			result.ContributorPaysFee = req.ContributorPaysFee
+		}
+	}
+
+	result.Fee = Calculate(rule, req.Amount)
+
+	if result.ContributorPaysFee {
+		result.Gross = req.Amount.Add(result.Fee)
+		result.Net = req.Amount
+		return result
+	}
+
+	if result.Fee.GreaterThan(req.Amount) {
+		result.Fee = req.Amount
+	}
+	result.Gross = req.Amount
+	result.Net = req.Amount.Sub(result.Fee)
+	return result
+}
+
+// Calculate returns the rule's fee on the amount, rounded to cents.
+func Calculate(rule *domain.FeeRule, amount decimal.Decimal) decimal.Decimal {
+	if rule.IsWaiver {
+		return decimal.Zero
+	}
+
+	fee := amount.Mul(rule.Percentage).Round(2).Add(rule.FlatAmount)
+	if rule.MinFee != nil && fee.LessThan(*rule.MinFee) {
+		fee = *rule.MinFee
+	}
+	if rule.MaxFee != nil && fee.GreaterThan(*rule.MaxFee) {
+		fee = *rule.MaxFee
+	}
+
+	return fee
+}
@@ -0,0 +1,142 @@
+package fees
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestSelect(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-48 * time.Hour)
+	later := now.Add(48 * time.Hour)
+
+	platform := &domain.FeeRule{ID: "platform", StartsAt: earlier}
+	organisation := &domain.FeeRule{ID: "organisation", OrganisationID: strPtr("o1"), StartsAt: earlier}
+	pharmacy := &domain.FeeRule{ID: "pharmacy", PharmacyID: strPtr("p1"), StartsAt: earlier}
+	newerPharmacy := &domain.FeeRule{ID: "newer-pharmacy", PharmacyID: strPtr("p1"), StartsAt: now.Add(-time.Hour)}
+	platformWaiver := &domain.FeeRule{ID: "platform-waiver", IsWaiver: true, StartsAt: earlier}
+	superseded := &domain.FeeRule{ID: "superseded", PharmacyID: strPtr("p1"), StartsAt: earlier, SupersededAt: &earlier}
+	notStarted := &domain.FeeRule{ID: "not-started", PharmacyID: strPtr("p1"), StartsAt: later}
+	ended := &domain.FeeRule{ID: "ended", PharmacyID: strPtr("p1"), StartsAt: earlier, EndsAt: &now}
+	sameStartOlder := &domain.FeeRule{ID: "same-start-older", StartsAt: earlier, CreatedAt: earlier}
+	sameStartNewer := &domain.FeeRule{ID: "same-start-newer", StartsAt: earlier, CreatedAt: now}
+
+	tests := []struct {
+		name  string
+		rules []*domain.FeeRule
+		want  string
+	}{
+		{"no rules", nil, ""},
+		{"platform only", []*domain.FeeRule{platform}, "platform"},
+		{"organisation over platform", []*domain.FeeRule{platform, organisation}, "organisation"},
+		{"pharmacy over organisation", []*domain.FeeRule{organisation, pharmacy, platform}, "pharmacy"},
+		{"waiver over pharmacy", []*domain.FeeRule{pharmacy, platformWaiver}, "platform-waiver"},
+		{"later start wins at the same level", []*domain.FeeRule{pharmacy, newerPharmacy}, "newer-pharmacy"},
+		{"later creation breaks a tie", []*domain.FeeRule{sameStartNewer, sameStartOlder}, "same-start-newer"},
+		{"superseded rule ignored", []*domain.FeeRule{platform, superseded}, "platform"},
+		{"rule not yet started ignored", []*domain.FeeRule{platform, notStarted}, "platform"},
+		{"rule ending now ignored", []*domain.FeeRule{platform, ended}, "platform"},
+		{"nothing in effect", []*domain.FeeRule{superseded, notStarted, ended}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if rule := Select(tt.rules, now); rule != nil {
+				got = rule.ID
+			}
+			if got != tt.want {
+				t.Fatalf("Select() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   *domain.FeeRule
+		amount string
+		want   string
+	}{
+		{"percentage", &domain.FeeRule{Percentage: dec("0.025")}, "100", "2.5"},
+		{"percentage rounded half up to the cent", &domain.FeeRule{Percentage: dec("0.025")}, "10.20", "0.26"},
+		{"percentage rounded down to the cent", &domain.FeeRule{Percentage: dec("0.015")}, "10.10", "0.15"},
+		{"flat amount added after rounding", &domain.FeeRule{Percentage: dec("0.01"), FlatAmount: dec("1.50")}, "33.33", "1.83"},
+		{"minimum fee", &domain.FeeRule{Percentage: dec("0.01"), MinFee: decPtr("2")}, "50", "2"},
+		{"maximum fee", &domain.FeeRule{Percentage: dec("0.05"), MaxFee: decPtr("20")}, "1000", "20"},
+		{"between minimum and maximum", &domain.FeeRule{Percentage: dec("0.05"), MinFee: decPtr("2"), MaxFee: decPtr("20")}, "100", "5"},
+		{"waiver", &domain.FeeRule{IsWaiver: true, Percentage: dec("0.05"), MinFee: decPtr("2")}, "100", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(tt.rule, dec(tt.amount))
+			if !got.Equal(dec(tt.want)) {
+				t.Fatalf("Calculate(%s) = %s, want %s", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	withdrawal := &domain.FeeRule{TransactionType: domain.TransactionTypeWithdrawal, FlatAmount: dec("5")}
+	deposit := func(pays domain.ContributorPays) *domain.FeeRule {
+		return &domain.FeeRule{TransactionType: domain.TransactionTypeDeposit, Percentage: dec("0.03"), ContributorPays: pays}
+	}
+
+	tests := []struct {
+		name                   string
+		rule                   *domain.FeeRule
+		amount                 string
+		contributorPaysFee     bool
+		wantGross              string
+		wantFee                string
+		wantNet                string
+		wantContributorPaysFee bool
+	}{
+		{"withdrawal fee taken from the amount", withdrawal, "100", false, "100", "5", "95", false},
+		{"withdrawal fee capped at the amount", withdrawal, "3", false, "3", "3", "0", false},
+		{"withdrawal ignores the contributor's choice", withdrawal, "100", true, "100", "5", "95", false},
+		{"deposit fee never paid by the contributor", deposit(domain.ContributorPaysNever), "100", true, "100", "3", "97", false},
+		{"deposit fee left to the contributor who declines", deposit(domain.ContributorPaysOptional), "100", false, "100", "3", "97", false},
+		{"deposit fee left to the contributor who pays", deposit(domain.ContributorPaysOptional), "100", true, "103", "3", "100", true},
+		{"deposit fee always paid by the contributor", deposit(domain.ContributorPaysAlways), "100", false, "103", "3", "100", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Apply(tt.rule, Request{
+				Type:               tt.rule.TransactionType,
+				Amount:             dec(tt.amount),
+				ContributorPaysFee: tt.contributorPaysFee,
+			})
+			if !got.Gross.Equal(dec(tt.wantGross)) || !got.Fee.Equal(dec(tt.wantFee)) || !got.Net.Equal(dec(tt.wantNet)) {
+				t.Fatalf("Apply() = gross %s, fee %s, net %s, want gross %s, fee %s, net %s",
+					got.Gross, got.Fee, got.Net, tt.wantGross, tt.wantFee, tt.wantNet)
+			}
+			if got.ContributorPaysFee != tt.wantContributorPaysFee {
+				t.Fatalf("Apply() ContributorPaysFee = %v, want %v", got.ContributorPaysFee, tt.wantContributorPaysFee)
+			}
+			if got.Rule != tt.rule {
+				t.Fatalf("Apply() Rule = %v, want the applied rule", got.Rule)
+			}
+		})
+	}
+}
@@ -61,32 +61,50 @@ func (h *AdminHandler) GetPharmacy(c *gin.Context) {
 }
 
 type CreatePharmacyRequest struct {
-	Name               string `json:"name" binding:"required"`
-	ShortCode          string `json:"short_code" binding:"required"`
-	RegistrationNumber string `json:"registration_number" binding:"required"`
-	Address            string `json:"address"`
-	Phone              string `json:"phone"`
-	Email              string `json:"email"`
-	Password           string `json:"password" binding:"required,min=8"`
+	Name               string   `json:"name" binding:"required"`
+	ShortCode          string   `json:"short_code" binding:"required"`
+	RegistrationNumber string   `json:"registration_number" binding:"required"`
+	Address            string   `json:"address"`
+	Phone              string   `json:"phone"`
+	Email              string   `json:"email" binding:"required,email"`
+	ManagerUsername    string   `json:"manager_username" binding:"omitempty,alphanum,min=3,max=50"`
+	ManagerName        string   `json:"manager_name"`
+	DailyCashInLimit   *float64 `json:"daily_cash_in_limit" binding:"omitempty,gte=0"`
 }
 
 func (h *AdminHandler) CreatePharmacy(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
 	var req CreatePharmacyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
 
-	pharmacy, err := h.adminService.CreatePharmacy(c.Request.Context(), service.CreatePharmacyRequest{
+	pharmacy, err := h.adminService.CreatePharmacy(c.Request.Context(), adminID.(string), service.CreatePharmacyRequest{
 		Name:               req.Name,
 		ShortCode:          req.ShortCode,
 		RegistrationNumber: req.RegistrationNumber,
 		Address:            req.Address,
 		Phone:              req.Phone,
 		Email:              req.Email,
-		Password:           req.Password,
+		ManagerUsername:    req.ManagerUsername,
+		ManagerName:        req.ManagerName,
+		DailyCashInLimit:   req.DailyCashInLimit,
 	})
 	if err != nil {
+		if errors.Is(err, domain.ErrShortCodeTaken) {
+			Conflict(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrPharmacyEmailRequired) {
+			BadRequest(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to create pharmacy")
 		return
 	}
@@ -109,23 +127,11 @@ func (h *AdminHandler) UpdatePharmacy(c *gin.Context) {
 			NotFound(c, err.Error())
 			return
 		}
-		InternalError(c, "Failed to update pharmacy")
-		return
-	}
-
-	Success(c, pharmacy)
-}
-
-func (h *AdminHandler) ApprovePharmacy(c *gin.Context) {
-	id := c.Param("id")
-
-	pharmacy, err := h.adminService.ApprovePharmacy(c.Request.Context(), id)
-	if err != nil {
-		if errors.Is(err, domain.ErrPharmacyNotFound) {
-			NotFound(c, err.Error())
+		if errors.Is(err, domain.ErrShortCodeTaken) {
+			Conflict(c, err.Error())
 			return
 		}
-		InternalError(c, "Failed to approve pharmacy")
+		InternalError(c, "Failed to update pharmacy")
 		return
 	}
 
@@ -141,6 +147,10 @@ func (h *AdminHandler) SuspendPharmacy(c *gin.Context) {
 			NotFound(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidPharmacyTransition) {
+			Conflict(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to suspend pharmacy")
 		return
 	}
@@ -157,6 +167,10 @@ func (h *AdminHandler) ReactivatePharmacy(c *gin.Context) {
 			NotFound(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidPharmacyTransition) {
+			Conflict(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to reactivate pharmacy")
 		return
 	}
@@ -173,6 +187,10 @@ func (h *AdminHandler) DeletePharmacy(c *gin.Context) {
 			NotFound(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidPharmacyTransition) {
+			Conflict(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to delete pharmacy")
 		return
 	}
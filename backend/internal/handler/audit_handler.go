@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+
+	entries, total, err := h.auditService.List(c.Request.Context(), c.Query("entity_type"), c.Query("entity_id"), page, pageSize)
+	if err != nil {
+		InternalError(c, "Failed to get audit logs")
+		return
+	}
+
+	Success(c, gin.H{
+		"items":     entries,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type BankAccountHandler struct {
+	bankAccountService service.BankAccountService
+}
+
+func NewBankAccountHandler(bankAccountService service.BankAccountService) *BankAccountHandler {
+	return &BankAccountHandler{bankAccountService: bankAccountService}
+}
+
+func (h *BankAccountHandler) Submit(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.BankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	account, err := h.bankAccountService.Submit(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrPharmacyNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidBankAccount) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to submit bank account")
+		return
+	}
+
+	Created(c, account)
+}
+
+func (h *BankAccountHandler) ListForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.listForPharmacy(c, pharmacyID.(string))
+}
+
+func (h *BankAccountHandler) ListByPharmacy(c *gin.Context) {
+	h.listForPharmacy(c, c.Param("id"))
+}
+
+func (h *BankAccountHandler) listForPharmacy(c *gin.Context, pharmacyID string) {
+	accounts, err := h.bankAccountService.ListForPharmacy(c.Request.Context(), pharmacyID)
+	if err != nil {
+		InternalError(c, "Failed to get bank accounts")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": accounts,
+		"total": len(accounts),
+	})
+}
+
+func (h *BankAccountHandler) List(c *gin.Context) {
+	accounts, err := h.bankAccountService.List(c.Request.Context(), c.DefaultQuery("status", string(domain.BankAccountStatusPending)))
+	if err != nil {
+		InternalError(c, "Failed to get bank accounts")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": accounts,
+		"total": len(accounts),
+	})
+}
+
+func (h *BankAccountHandler) Verify(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	account, err := h.bankAccountService.Verify(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		h.handleReviewError(c, err, "Failed to verify bank account")
+		return
+	}
+
+	Success(c, account)
+}
+
+func (h *BankAccountHandler) Approve(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	account, err := h.bankAccountService.Approve(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		h.handleReviewError(c, err, "Failed to approve bank account")
+		return
+	}
+
+	Success(c, account)
+}
+
+func (h *BankAccountHandler) Reject(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	account, err := h.bankAccountService.Reject(c.Request.Context(), adminID.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		h.handleReviewError(c, err, "Failed to reject bank account")
+		return
+	}
+
+	Success(c, account)
+}
+
+func (h *BankAccountHandler) handleReviewError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrBankAccountNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrBankAccountNotPending):
+		Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrBankAccountNotVerified):
+		Error(c, 422, "BANK_ACCOUNT_NOT_VERIFIED", err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type DisputeHandler struct {
+	disputeService service.DisputeService
+}
+
+func NewDisputeHandler(disputeService service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{disputeService: disputeService}
+}
+
+func (h *DisputeHandler) Open(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	dispute, err := h.disputeService.Open(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to open dispute")
+		return
+	}
+
+	Created(c, dispute)
+}
+
+func (h *DisputeHandler) ListForUser(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.list(c, repository.DisputeFilter{UserID: userID.(string)})
+}
+
+func (h *DisputeHandler) GetForUser(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.get(c, userID.(string), "")
+}
+
+func (h *DisputeHandler) ListForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.list(c, repository.DisputeFilter{PharmacyID: pharmacyID.(string)})
+}
+
+func (h *DisputeHandler) GetForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.get(c, "", pharmacyID.(string))
+}
+
+func (h *DisputeHandler) Respond(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.RespondDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	dispute, err := h.disputeService.Respond(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to respond to dispute")
+		return
+	}
+
+	Success(c, dispute)
+}
+
+func (h *DisputeHandler) List(c *gin.Context) {
+	h.list(c, repository.DisputeFilter{PharmacyID: c.Query("pharmacy_id")})
+}
+
+func (h *DisputeHandler) Get(c *gin.Context) {
+	h.get(c, "", "")
+}
+
+func (h *DisputeHandler) Uphold(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	dispute, err := h.disputeService.Uphold(c.Request.Context(), adminID.(string), c.Param("id"), req.Note)
+	if err != nil {
+		h.handleError(c, err, "Failed to uphold dispute")
+		return
+	}
+
+	Success(c, dispute)
+}
+
+func (h *DisputeHandler) Reject(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	dispute, err := h.disputeService.Reject(c.Request.Context(), adminID.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		h.handleError(c, err, "Failed to reject dispute")
+		return
+	}
+
+	Success(c, dispute)
+}
+
+func (h *DisputeHandler) list(c *gin.Context, filter repository.DisputeFilter) {
+	filter.Status = domain.DisputeStatus(c.Query("status"))
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	disputes, total, err := h.disputeService.List(c.Request.Context(), filter)
+	if err != nil {
+		InternalError(c, "Failed to get disputes")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": disputes,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.PageSize,
+	})
+}
+
+func (h *DisputeHandler) get(c *gin.Context, userID, pharmacyID string) {
+	dispute, err := h.disputeService.Get(c.Request.Context(), c.Param("id"), userID, pharmacyID)
+	if err != nil {
+		h.handleError(c, err, "Failed to get dispute")
+		return
+	}
+
+	Success(c, dispute)
+}
+
+func (h *DisputeHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrDisputeNotFound), errors.Is(err, domain.ErrTransactionNotFound),
+		errors.Is(err, domain.ErrWalletNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrUploadNotFound):
+		BadRequest(c, "Evidence must be files your pharmacy uploaded as dispute evidence")
+	case errors.Is(err, domain.ErrNotDisputable), errors.Is(err, domain.ErrDisputeWindowClosed):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrDisputeExists), errors.Is(err, domain.ErrDisputeResolved),
+		errors.Is(err, domain.ErrTransactionReversed), errors.Is(err, domain.ErrWalletClosed):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FeeQuoteHandler struct {
+	feeQuoteService service.FeeQuoteService
+}
+
+func NewFeeQuoteHandler(feeQuoteService service.FeeQuoteService) *FeeQuoteHandler {
+	return &FeeQuoteHandler{feeQuoteService: feeQuoteService}
+}
+
+// Quote prices a withdrawal from one of the caller's wallets at the named
+// pharmacy.
+func (h *FeeQuoteHandler) Quote(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.FeeQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	if req.PharmacyID == "" {
+		BadRequest(c, "pharmacy_id is required")
+		return
+	}
+
+	quote, err := h.feeQuoteService.Quote(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	Success(c, quote)
+}
+
+// QuoteForPharmacy prices a withdrawal at the caller's pharmacy.
+func (h *FeeQuoteHandler) QuoteForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.FeeQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	quote, err := h.feeQuoteService.QuoteForPharmacy(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	Success(c, quote)
+}
+
+func (h *FeeQuoteHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound):
+		NotFound(c, "Wallet not found")
+	case errors.Is(err, domain.ErrPharmacyNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrPharmacyInactive), errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrInvalidWalletCode):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrWalletNotSpendable):
+		Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrLookupLimitReached):
+		Error(c, 429, "LOOKUP_LIMIT_REACHED", err.Error())
+	case errors.Is(err, domain.ErrAmountCheckLimit):
+		Error(c, 429, "AMOUNT_CHECK_LIMIT_REACHED", err.Error())
+	default:
+		InternalError(c, "Failed to quote withdrawal")
+	}
+}
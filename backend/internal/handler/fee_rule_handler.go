@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FeeRuleHandler struct {
+	feeRuleService service.FeeRuleService
+}
+
+func NewFeeRuleHandler(feeRuleService service.FeeRuleService) *FeeRuleHandler {
+	return &FeeRuleHandler{feeRuleService: feeRuleService}
+}
+
+func (h *FeeRuleHandler) Create(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.CreateFeeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	rule, err := h.feeRuleService.Create(c.Request.Context(), adminID.(string), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create fee rule")
+		return
+	}
+
+	Created(c, rule)
+}
+
+// List returns the current rules; pass include_superseded=true to include
+// earlier versions and retired rules.
+func (h *FeeRuleHandler) List(c *gin.Context) {
+	filter := repository.FeeRuleFilter{
+		TransactionType:   domain.TransactionType(c.Query("transaction_type")),
+		PharmacyID:        c.Query("pharmacy_id"),
+		OrganisationID:    c.Query("organisation_id"),
+		IncludeSuperseded: c.Query("include_superseded") == "true",
+	}
+
+	rules, err := h.feeRuleService.List(c.Request.Context(), filter)
+	if err != nil {
+		InternalError(c, "Failed to get fee rules")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": rules,
+		"total": len(rules),
+	})
+}
+
+func (h *FeeRuleHandler) Get(c *gin.Context) {
+	rule, err := h.feeRuleService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get fee rule")
+		return
+	}
+
+	Success(c, rule)
+}
+
+func (h *FeeRuleHandler) GetVersions(c *gin.Context) {
+	versions, err := h.feeRuleService.GetVersions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get fee rule versions")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": versions,
+		"total": len(versions),
+	})
+}
+
+func (h *FeeRuleHandler) Update(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.UpdateFeeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	rule, err := h.feeRuleService.Update(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update fee rule")
+		return
+	}
+
+	Success(c, rule)
+}
+
+func (h *FeeRuleHandler) Retire(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.feeRuleService.Retire(c.Request.Context(), adminID.(string), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to retire fee rule")
+		return
+	}
+
+	Success(c, gin.H{"message": "Fee rule retired"})
+}
+
+func (h *FeeRuleHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrFeeRuleNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidFeeRule), errors.Is(err, domain.ErrPharmacyNotFound),
+		errors.Is(err, domain.ErrOrganisationNotFound):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrFeeRuleSuperseded), errors.Is(err, domain.ErrFeeRuleCodeTaken):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
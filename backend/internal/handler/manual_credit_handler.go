@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ManualCreditHandler struct {
+	manualCreditService service.ManualCreditService
+}
+
+func NewManualCreditHandler(manualCreditService service.ManualCreditService) *ManualCreditHandler {
+	return &ManualCreditHandler{manualCreditService: manualCreditService}
+}
+
+func (h *ManualCreditHandler) Request(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.ManualCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	credit, err := h.manualCreditService.Request(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidAmount) {
+			BadRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrDuplicateReference) || errors.Is(err, domain.ErrWalletClosed) {
+			Conflict(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to create manual credit")
+		return
+	}
+
+	Created(c, credit)
+}
+
+func (h *ManualCreditHandler) List(c *gin.Context) {
+	credits, err := h.manualCreditService.List(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		InternalError(c, "Failed to get manual credits")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": credits,
+		"total": len(credits),
+	})
+}
+
+func (h *ManualCreditHandler) Approve(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	credit, err := h.manualCreditService.Approve(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		h.handleReviewError(c, err, "Failed to approve manual credit")
+		return
+	}
+
+	Success(c, credit)
+}
+
+func (h *ManualCreditHandler) Reject(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	credit, err := h.manualCreditService.Reject(c.Request.Context(), adminID.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		h.handleReviewError(c, err, "Failed to reject manual credit")
+		return
+	}
+
+	Success(c, credit)
+}
+
+func (h *ManualCreditHandler) handleReviewError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrManualCreditNotFound), errors.Is(err, domain.ErrWalletNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrManualCreditNotPending), errors.Is(err, domain.ErrWalletClosed):
+		Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrSelfApproval):
+		Forbidden(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
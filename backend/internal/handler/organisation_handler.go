@@ -0,0 +1,370 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OrganisationHandler serves both the admin organisation routes and the
+// routes organisation members use to view their chain. Member routes pass the
+// caller's user ID to the service; admin routes pass an empty one.
+type OrganisationHandler struct {
+	organisationService service.OrganisationService
+	config              *config.Config
+}
+
+func NewOrganisationHandler(organisationService service.OrganisationService, cfg *config.Config) *OrganisationHandler {
+	return &OrganisationHandler{
+		organisationService: organisationService,
+		config:              cfg,
+	}
+}
+
+func (h *OrganisationHandler) Create(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.OrganisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	organisation, err := h.organisationService.Create(c.Request.Context(), adminID.(string), req)
+	if err != nil {
+		InternalError(c, "Failed to create organisation")
+		return
+	}
+
+	Created(c, organisation)
+}
+
+func (h *OrganisationHandler) List(c *gin.Context) {
+	organisations, err := h.organisationService.List(c.Request.Context())
+	if err != nil {
+		InternalError(c, "Failed to get organisations")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": organisations,
+		"total": len(organisations),
+	})
+}
+
+func (h *OrganisationHandler) ListMine(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	organisations, err := h.organisationService.ListForUser(c.Request.Context(), userID.(string))
+	if err != nil {
+		InternalError(c, "Failed to get organisations")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": organisations,
+		"total": len(organisations),
+	})
+}
+
+func (h *OrganisationHandler) Get(c *gin.Context) {
+	h.get(c, "")
+}
+
+func (h *OrganisationHandler) GetForMember(c *gin.Context) {
+	h.forMember(c, h.get)
+}
+
+func (h *OrganisationHandler) get(c *gin.Context, userID string) {
+	organisation, err := h.organisationService.Get(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get organisation")
+		return
+	}
+
+	Success(c, organisation)
+}
+
+func (h *OrganisationHandler) Update(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.UpdateOrganisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	organisation, err := h.organisationService.Update(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update organisation")
+		return
+	}
+
+	Success(c, organisation)
+}
+
+func (h *OrganisationHandler) AddBranch(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.AddBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	branch, err := h.organisationService.AddBranch(c.Request.Context(), adminID.(string), c.Param("id"), req.PharmacyID)
+	if err != nil {
+		h.handleError(c, err, "Failed to add branch")
+		return
+	}
+
+	Created(c, branch)
+}
+
+func (h *OrganisationHandler) RemoveBranch(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.organisationService.RemoveBranch(c.Request.Context(), adminID.(string), c.Param("id"), c.Param("pharmacyId")); err != nil {
+		h.handleError(c, err, "Failed to remove branch")
+		return
+	}
+
+	Success(c, gin.H{"message": "Branch removed from organisation"})
+}
+
+func (h *OrganisationHandler) GetBranches(c *gin.Context) {
+	h.getBranches(c, "")
+}
+
+func (h *OrganisationHandler) GetBranchesForMember(c *gin.Context) {
+	h.forMember(c, h.getBranches)
+}
+
+func (h *OrganisationHandler) getBranches(c *gin.Context, userID string) {
+	branches, err := h.organisationService.GetBranches(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get branches")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": branches,
+		"total": len(branches),
+	})
+}
+
+func (h *OrganisationHandler) AddMember(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.AddOrganisationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	member, err := h.organisationService.AddMember(c.Request.Context(), adminID.(string), c.Param("id"), req.Email)
+	if err != nil {
+		h.handleError(c, err, "Failed to add member")
+		return
+	}
+
+	Created(c, member)
+}
+
+func (h *OrganisationHandler) GetMembers(c *gin.Context) {
+	members, err := h.organisationService.GetMembers(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get members")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": members,
+		"total": len(members),
+	})
+}
+
+func (h *OrganisationHandler) RemoveMember(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.organisationService.RemoveMember(c.Request.Context(), adminID.(string), c.Param("id"), c.Param("memberId")); err != nil {
+		h.handleError(c, err, "Failed to remove member")
+		return
+	}
+
+	Success(c, gin.H{"message": "Member removed from organisation"})
+}
+
+func (h *OrganisationHandler) GetTransactions(c *gin.Context) {
+	h.getTransactions(c, "")
+}
+
+func (h *OrganisationHandler) GetTransactionsForMember(c *gin.Context) {
+	h.forMember(c, h.getTransactions)
+}
+
+func (h *OrganisationHandler) getTransactions(c *gin.Context, userID string) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	result, err := h.organisationService.GetTransactions(c.Request.Context(), userID, c.Param("id"), c.Query("pharmacy_id"), page, pageSize)
+	if err != nil {
+		h.handleError(c, err, "Failed to get transactions")
+		return
+	}
+
+	Success(c, result)
+}
+
+func (h *OrganisationHandler) ListSettlements(c *gin.Context) {
+	h.listSettlements(c, "")
+}
+
+func (h *OrganisationHandler) ListSettlementsForMember(c *gin.Context) {
+	h.forMember(c, h.listSettlements)
+}
+
+func (h *OrganisationHandler) listSettlements(c *gin.Context, userID string) {
+	filter := repository.SettlementFilter{
+		PharmacyID: c.Query("pharmacy_id"),
+		Status:     domain.SettlementStatus(c.Query("status")),
+	}
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	settlements, total, err := h.organisationService.ListSettlements(c.Request.Context(), userID, c.Param("id"), filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to get settlements")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": settlements,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.PageSize,
+	})
+}
+
+func (h *OrganisationHandler) GetSettlementStatementForMember(c *gin.Context) {
+	h.forMember(c, func(c *gin.Context, userID string) {
+		statement, err := h.organisationService.GetSettlementStatement(c.Request.Context(), userID, c.Param("id"), c.Param("settlementId"))
+		if err != nil {
+			h.handleError(c, err, "Failed to get settlement statement")
+			return
+		}
+
+		if c.Query("format") == "csv" {
+			writeStatementCSV(c, statement)
+			return
+		}
+
+		Success(c, statement)
+	})
+}
+
+func (h *OrganisationHandler) GetSettlementSummary(c *gin.Context) {
+	h.getSettlementSummary(c, "")
+}
+
+func (h *OrganisationHandler) GetSettlementSummaryForMember(c *gin.Context) {
+	h.forMember(c, h.getSettlementSummary)
+}
+
+// getSettlementSummary covers the from and to dates (YYYY-MM-DD, business
+// timezone) inclusive; it defaults to the last 30 days.
+func (h *OrganisationHandler) getSettlementSummary(c *gin.Context, userID string) {
+	to := h.config.StartOfDay(time.Now()).AddDate(0, 0, 1)
+	if c.Query("to") != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", c.Query("to"), h.config.Location())
+		if err != nil {
+			BadRequest(c, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if c.Query("from") != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", c.Query("from"), h.config.Location())
+		if err != nil {
+			BadRequest(c, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		BadRequest(c, "from must be before to")
+		return
+	}
+
+	summary, err := h.organisationService.GetSettlementSummary(c.Request.Context(), userID, c.Param("id"), from, to)
+	if err != nil {
+		h.handleError(c, err, "Failed to get settlement summary")
+		return
+	}
+
+	Success(c, summary)
+}
+
+func (h *OrganisationHandler) forMember(c *gin.Context, next func(c *gin.Context, userID string)) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	next(c, userID.(string))
+}
+
+func (h *OrganisationHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrOrganisationNotFound), errors.Is(err, domain.ErrOrganisationMemberNotFound),
+		errors.Is(err, domain.ErrPharmacyNotFound), errors.Is(err, domain.ErrSettlementNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrUserNotFound):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrOrganisationAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrOrganisationMemberExists):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
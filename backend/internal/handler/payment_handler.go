@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"net/http"
 
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/internal/service"
@@ -20,7 +21,18 @@ type InitializePaymentRequest struct {
 	WalletID string  `json:"wallet_id" binding:"required"`
 	Amount   float64 `json:"amount" binding:"required,gt=0"`
 	Email    string  `json:"email" binding:"required,email"`
-	Message  string  `json:"message"`
+	Message  string  `json:"message" binding:"max=500"`
+	// DisplayName, Anonymous and HideAmount are how the contributor appears
+	// on the wallet's contributor wall.
+	DisplayName string `json:"display_name" binding:"max=100"`
+	Anonymous   bool   `json:"anonymous"`
+	HideAmount  bool   `json:"hide_amount"`
+	// CoverFee adds the deposit fee to the contributor's charge so the
+	// wallet receives the full amount, where the fee rule allows it.
+	CoverFee bool `json:"cover_fee"`
+	// ShareLink is the token of the share link the contributor arrived
+	// through, if any.
+	ShareLink string `json:"share_link"`
 }
 
 func (h *PaymentHandler) Initialize(c *gin.Context) {
@@ -36,9 +48,16 @@ func (h *PaymentHandler) Initialize(c *gin.Context) {
 		req.Email,
 		req.Amount,
 		req.Message,
+		service.ContributorDisplay{
+			DisplayName: req.DisplayName,
+			Anonymous:   req.Anonymous,
+			HideAmount:  req.HideAmount,
+		},
+		req.CoverFee,
+		req.ShareLink,
 	)
 	if err != nil {
-		if errors.Is(err, domain.ErrWalletNotFound) {
+		if errors.Is(err, domain.ErrWalletNotFound) || errors.Is(err, domain.ErrShareLinkNotFound) {
 			NotFound(c, err.Error())
 			return
 		}
@@ -46,6 +65,14 @@ func (h *PaymentHandler) Initialize(c *gin.Context) {
 			Forbidden(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrWalletNotAcceptingContributions) {
+			Conflict(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrShareLinkUnavailable) {
+			Error(c, http.StatusGone, "SHARE_LINK_UNAVAILABLE", err.Error())
+			return
+		}
 		InternalError(c, "Failed to initialize payment")
 		return
 	}
@@ -74,6 +101,10 @@ func (h *PaymentHandler) Verify(c *gin.Context) {
 			Error(c, 400, "PAYMENT_FAILED", err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrPaymentRefundDue) {
+			Conflict(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to verify payment")
 		return
 	}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PayoutHandler struct {
+	payoutService service.PayoutService
+}
+
+func NewPayoutHandler(payoutService service.PayoutService) *PayoutHandler {
+	return &PayoutHandler{payoutService: payoutService}
+}
+
+func (h *PayoutHandler) Approve(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	settlement, err := h.payoutService.Approve(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to approve settlement")
+		return
+	}
+
+	Success(c, settlement)
+}
+
+func (h *PayoutHandler) Retry(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	settlement, err := h.payoutService.Retry(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retry payout")
+		return
+	}
+
+	Success(c, settlement)
+}
+
+// Webhook receives Paystack event notifications. It must respond quickly
+// with 200, otherwise Paystack keeps retrying the delivery.
+func (h *PayoutHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		BadRequest(c, "Failed to read request body")
+		return
+	}
+
+	if err := h.payoutService.HandleWebhook(c.Request.Context(), body, c.GetHeader("x-paystack-signature")); err != nil {
+		if errors.Is(err, domain.ErrUnauthorized) {
+			Unauthorized(c, "Invalid signature")
+			return
+		}
+		InternalError(c, "Failed to process webhook")
+		return
+	}
+
+	Success(c, gin.H{"received": true})
+}
+
+func (h *PayoutHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrSettlementNotFound), errors.Is(err, domain.ErrNoActiveBankAccount):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrSettlementNotPayable), errors.Is(err, domain.ErrPayoutInProgress):
+		Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrNothingToPay):
+		Error(c, 422, "NOTHING_TO_PAY", err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
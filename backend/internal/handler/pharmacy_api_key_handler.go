@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PharmacyAPIKeyHandler struct {
+	apiKeyService service.PharmacyAPIKeyService
+}
+
+func NewPharmacyAPIKeyHandler(apiKeyService service.PharmacyAPIKeyService) *PharmacyAPIKeyHandler {
+	return &PharmacyAPIKeyHandler{apiKeyService: apiKeyService}
+}
+
+func (h *PharmacyAPIKeyHandler) Create(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	key, err := h.apiKeyService.Create(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAPIKeyScope) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to create API key")
+		return
+	}
+
+	Created(c, key)
+}
+
+func (h *PharmacyAPIKeyHandler) List(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	keys, err := h.apiKeyService.List(c.Request.Context(), pharmacyID.(string))
+	if err != nil {
+		InternalError(c, "Failed to get API keys")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": keys,
+		"total": len(keys),
+	})
+}
+
+func (h *PharmacyAPIKeyHandler) Revoke(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), c.Param("id")); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to revoke API key")
+		return
+	}
+
+	Success(c, gin.H{"message": "API key revoked"})
+}
@@ -5,32 +5,28 @@ import (
 
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/internal/dto"
-	"github.com/carewallet/backend/internal/repository"
 	"github.com/carewallet/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 type PharmacyAuthHandler struct {
 	pharmacyAuthService service.PharmacyAuthService
-	walletRepo          repository.WalletRepository
-	userRepo            repository.UserRepository
 	otpService          service.OTPService
 	transactionService  service.TransactionService
+	withdrawalService   service.PharmacyWithdrawalService
 }
 
 func NewPharmacyAuthHandler(
 	pharmacyAuthService service.PharmacyAuthService,
-	walletRepo repository.WalletRepository,
-	userRepo repository.UserRepository,
 	otpService service.OTPService,
 	transactionService service.TransactionService,
+	withdrawalService service.PharmacyWithdrawalService,
 ) *PharmacyAuthHandler {
 	return &PharmacyAuthHandler{
 		pharmacyAuthService: pharmacyAuthService,
-		walletRepo:          walletRepo,
-		userRepo:            userRepo,
 		otpService:          otpService,
 		transactionService:  transactionService,
+		withdrawalService:   withdrawalService,
 	}
 }
 
@@ -41,10 +37,10 @@ func (h *PharmacyAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.pharmacyAuthService.Login(c.Request.Context(), req.ShortCode, req.Password)
+	response, err := h.pharmacyAuthService.Login(c.Request.Context(), req.ShortCode, req.Username, req.Password)
 	if err != nil {
 		if errors.Is(err, domain.ErrInvalidCredentials) {
-			Unauthorized(c, "Invalid pharmacy code or password")
+			Unauthorized(c, "Invalid pharmacy code, username or password")
 			return
 		}
 		if errors.Is(err, domain.ErrPharmacyInactive) {
@@ -59,7 +55,7 @@ func (h *PharmacyAuthHandler) Login(c *gin.Context) {
 }
 
 func (h *PharmacyAuthHandler) GetCurrentPharmacy(c *gin.Context) {
-	pharmacyID, exists := c.Get("userID") // We reuse the same JWT middleware
+	pharmacyID, exists := c.Get("pharmacyID")
 	if !exists {
 		Unauthorized(c, "Not authenticated")
 		return
@@ -78,166 +74,162 @@ func (h *PharmacyAuthHandler) GetCurrentPharmacy(c *gin.Context) {
 	Success(c, pharmacy)
 }
 
-func (h *PharmacyAuthHandler) LookupWallet(c *gin.Context) {
-	code := c.Param("code")
-	if code == "" {
-		BadRequest(c, "wallet code is required")
+func (h *PharmacyAuthHandler) InitiateWithdrawal(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
 		return
 	}
 
-	wallet, err := h.walletRepo.GetByShareableCode(c.Request.Context(), code)
-	if err != nil {
-		if errors.Is(err, domain.ErrWalletNotFound) {
-			NotFound(c, "Wallet not found")
-			return
-		}
-		InternalError(c, "Failed to lookup wallet")
+	var req dto.WithdrawalInitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
 		return
 	}
 
-	beneficiaryName := "Unknown"
-	if wallet.BeneficiaryID != nil {
-		beneficiary, err := h.userRepo.GetByID(c.Request.Context(), *wallet.BeneficiaryID)
-		if err == nil {
-			beneficiaryName = beneficiary.FullName
-		}
-	} else {
-		creator, err := h.userRepo.GetByID(c.Request.Context(), wallet.CreatorID)
-		if err == nil {
-			beneficiaryName = creator.FullName
-		}
-	}
-
-	response := dto.WalletLookupResponse{
-		WalletID:        wallet.ID,
-		WalletName:      wallet.WalletName,
-		Balance:         wallet.Balance.InexactFloat64(),
-		BeneficiaryName: beneficiaryName,
+	response, err := h.withdrawalService.Initiate(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), req)
+	if err != nil {
+		h.handleWithdrawalError(c, err, "Failed to start withdrawal")
+		return
 	}
 
 	Success(c, response)
 }
 
-func (h *PharmacyAuthHandler) InitiateWithdrawal(c *gin.Context) {
-	pharmacyID, exists := c.Get("userID")
+func (h *PharmacyAuthHandler) CompleteWithdrawal(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
 	if !exists {
 		Unauthorized(c, "Not authenticated")
 		return
 	}
 
-	var req dto.WithdrawalInitRequest
+	var req dto.WithdrawalCompleteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
 
-	// Get wallet by code
-	wallet, err := h.walletRepo.GetByShareableCode(c.Request.Context(), req.WalletCode)
+	transaction, err := h.withdrawalService.Complete(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), req)
 	if err != nil {
-		if errors.Is(err, domain.ErrWalletNotFound) {
-			NotFound(c, "Wallet not found")
-			return
-		}
-		InternalError(c, "Failed to lookup wallet")
+		h.handleWithdrawalError(c, err, "Failed to complete withdrawal")
 		return
 	}
 
-	// Check balance
-	amountDecimal := wallet.Balance.InexactFloat64()
-	if amountDecimal < req.Amount {
-		BadRequest(c, "Insufficient wallet balance")
-		return
-	}
+	Created(c, transaction)
+}
 
-	// Get beneficiary email for OTP
-	var beneficiaryEmail string
-	beneficiaryName := "Unknown"
-	if wallet.BeneficiaryID != nil {
-		beneficiary, err := h.userRepo.GetByID(c.Request.Context(), *wallet.BeneficiaryID)
-		if err == nil {
-			beneficiaryEmail = beneficiary.Email
-			beneficiaryName = beneficiary.FullName
-		}
-	} else {
-		creator, err := h.userRepo.GetByID(c.Request.Context(), wallet.CreatorID)
-		if err == nil {
-			beneficiaryEmail = creator.Email
-			beneficiaryName = creator.FullName
-		}
+// VoidWithdrawal reverses a completed withdrawal made at the caller's
+// pharmacy and returns the reversal transaction.
+func (h *PharmacyAuthHandler) VoidWithdrawal(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
 	}
 
-	if beneficiaryEmail == "" {
-		BadRequest(c, "No beneficiary email found for this wallet")
+	var req dto.VoidWithdrawalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
 		return
 	}
 
-	// Send OTP
-	_, err = h.otpService.Send(c.Request.Context(), dto.SendOTPRequest{
-		Email:   beneficiaryEmail,
-		Purpose: "withdrawal",
-	})
+	reversal, err := h.withdrawalService.Void(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), c.Param("id"), req.Reason)
 	if err != nil {
-		InternalError(c, "Failed to send OTP")
+		h.handleWithdrawalError(c, err, "Failed to void withdrawal")
 		return
 	}
 
-	// Create a pending withdrawal ID (using pharmacy ID + wallet ID + timestamp as a simple approach)
-	withdrawalID := pharmacyID.(string) + "_" + wallet.ID
+	Created(c, reversal)
+}
 
-	response := dto.WithdrawalInitResponse{
-		WithdrawalID:    withdrawalID,
-		WalletName:      wallet.WalletName,
-		BeneficiaryName: beneficiaryName,
-		Amount:          req.Amount,
-		OTPSentTo:       maskEmail(beneficiaryEmail),
+func (h *PharmacyAuthHandler) handleWithdrawalError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound):
+		NotFound(c, "Wallet not found")
+	case errors.Is(err, domain.ErrWithdrawalNotFound), errors.Is(err, domain.ErrFeeQuoteNotFound),
+		errors.Is(err, domain.ErrTransactionNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrInsufficientBalance), errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrNoBeneficiaryEmail), errors.Is(err, domain.ErrWithdrawalExpired),
+		errors.Is(err, domain.ErrFeeQuoteExpired), errors.Is(err, domain.ErrFeeQuoteMismatch),
+		errors.Is(err, domain.ErrInvalidLineItem), errors.Is(err, domain.ErrLineItemsMismatch),
+		errors.Is(err, domain.ErrInvalidWalletCode):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidOTP):
+		BadRequest(c, "Invalid or expired OTP")
+	case errors.Is(err, domain.ErrWithdrawalNotPending), errors.Is(err, domain.ErrVoidWindowClosed),
+		errors.Is(err, domain.ErrTransactionSettled), errors.Is(err, domain.ErrTransactionReversed),
+		errors.Is(err, domain.ErrWalletNotSpendable), errors.Is(err, domain.ErrWalletClosed):
+		Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrPharmacyInactive):
+		Forbidden(c, "Pharmacy account is suspended")
+	case errors.Is(err, domain.ErrSpendingNotAllowed):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrLookupLimitReached):
+		Error(c, 429, "LOOKUP_LIMIT_REACHED", err.Error())
+	default:
+		InternalError(c, message)
 	}
-
-	Success(c, response)
 }
 
-func (h *PharmacyAuthHandler) CompleteWithdrawal(c *gin.Context) {
-	pharmacyID, exists := c.Get("userID")
+func (h *PharmacyAuthHandler) CashIn(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
 	if !exists {
 		Unauthorized(c, "Not authenticated")
 		return
 	}
 
-	var req dto.WithdrawalCompleteRequest
+	var req dto.CashInRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
 
-	// Parse withdrawal ID to get wallet ID
-	// Format: pharmacyID_walletID
-	if len(req.WithdrawalID) < 37 { // UUID length check
-		BadRequest(c, "Invalid withdrawal ID")
+	transaction, err := h.transactionService.CashIn(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			NotFound(c, "Wallet not found")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidAmount) || errors.Is(err, domain.ErrInvalidWalletCode) {
+			BadRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrWalletNotAcceptingContributions) {
+			Conflict(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrCashInLimitExceeded) {
+			Error(c, 422, "CASH_IN_LIMIT_EXCEEDED", err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrPharmacyInactive) {
+			Forbidden(c, "Pharmacy account is suspended")
+			return
+		}
+		InternalError(c, "Failed to record cash top-up")
 		return
 	}
 
-	// For simplicity, we'll just use the request context
-	// In production, you'd want to store pending withdrawals in a table
-	_ = pharmacyID
-
-	// Verify OTP and complete withdrawal would go here
-	// This is a simplified implementation
-
-	Success(c, gin.H{"message": "Withdrawal completed successfully"})
+	Created(c, transaction)
 }
 
-func maskEmail(email string) string {
-	if len(email) < 5 {
-		return "***"
+func (h *PharmacyAuthHandler) GetCashInSummary(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
 	}
-	atIndex := -1
-	for i, c := range email {
-		if c == '@' {
-			atIndex = i
-			break
+
+	summary, err := h.transactionService.GetCashInSummary(c.Request.Context(), pharmacyID.(string))
+	if err != nil {
+		if errors.Is(err, domain.ErrPharmacyNotFound) {
+			NotFound(c, err.Error())
+			return
 		}
+		InternalError(c, "Failed to get cash-in summary")
+		return
 	}
-	if atIndex < 2 {
-		return "***" + email[atIndex:]
-	}
-	return email[:2] + "***" + email[atIndex:]
+
+	Success(c, summary)
 }
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PharmacyDirectoryHandler struct {
+	directoryService service.PharmacyDirectoryService
+}
+
+func NewPharmacyDirectoryHandler(directoryService service.PharmacyDirectoryService) *PharmacyDirectoryHandler {
+	return &PharmacyDirectoryHandler{directoryService: directoryService}
+}
+
+// Search finds active pharmacies near lat/lng. Optional parameters are
+// radius_km, service, open_now and limit.
+func (h *PharmacyDirectoryHandler) Search(c *gin.Context) {
+	var req dto.PharmacySearchRequest
+	var err error
+
+	if req.Latitude, err = strconv.ParseFloat(c.Query("lat"), 64); err != nil {
+		BadRequest(c, "lat is required and must be a number")
+		return
+	}
+	if req.Longitude, err = strconv.ParseFloat(c.Query("lng"), 64); err != nil {
+		BadRequest(c, "lng is required and must be a number")
+		return
+	}
+	if c.Query("radius_km") != "" {
+		if req.RadiusKm, err = strconv.ParseFloat(c.Query("radius_km"), 64); err != nil {
+			BadRequest(c, "radius_km must be a number")
+			return
+		}
+	}
+	req.Service = c.Query("service")
+	req.OpenNow, _ = strconv.ParseBool(c.Query("open_now"))
+	req.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	pharmacies, err := h.directoryService.Search(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLocation) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to search pharmacies")
+		return
+	}
+
+	if pharmacies == nil {
+		pharmacies = []*domain.NearbyPharmacy{}
+	}
+
+	Success(c, gin.H{
+		"items": pharmacies,
+		"total": len(pharmacies),
+	})
+}
+
+func (h *PharmacyDirectoryHandler) GetListing(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.getListing(c, pharmacyID.(string))
+}
+
+func (h *PharmacyDirectoryHandler) GetListingForAdmin(c *gin.Context) {
+	h.getListing(c, c.Param("id"))
+}
+
+func (h *PharmacyDirectoryHandler) getListing(c *gin.Context, pharmacyID string) {
+	listing, err := h.directoryService.GetListing(c.Request.Context(), pharmacyID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPharmacyNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to get pharmacy listing")
+		return
+	}
+
+	Success(c, listing)
+}
+
+func (h *PharmacyDirectoryHandler) UpdateListing(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.updateListing(c, domain.AuditActorPharmacy, c.GetString("pharmacyUserID"), pharmacyID.(string))
+}
+
+func (h *PharmacyDirectoryHandler) UpdateListingForAdmin(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.updateListing(c, domain.AuditActorAdmin, adminID.(string), c.Param("id"))
+}
+
+func (h *PharmacyDirectoryHandler) updateListing(c *gin.Context, actorType domain.AuditActorType, actorID, pharmacyID string) {
+	var req dto.PharmacyListingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	listing, err := h.directoryService.UpdateListing(c.Request.Context(), actorType, actorID, pharmacyID, req)
+	if err != nil {
+		if errors.Is(err, domain.ErrPharmacyNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLocation) || errors.Is(err, domain.ErrInvalidOpeningHours) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to update pharmacy listing")
+		return
+	}
+
+	Success(c, listing)
+}
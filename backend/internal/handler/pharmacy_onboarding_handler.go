@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// applicationTokenHeader carries the token an applicant received when they
+// registered, for checking on or updating their application.
+const applicationTokenHeader = "X-Application-Token"
+
+type PharmacyOnboardingHandler struct {
+	onboardingService service.PharmacyOnboardingService
+}
+
+func NewPharmacyOnboardingHandler(onboardingService service.PharmacyOnboardingService) *PharmacyOnboardingHandler {
+	return &PharmacyOnboardingHandler{onboardingService: onboardingService}
+}
+
+func (h *PharmacyOnboardingHandler) Register(c *gin.Context) {
+	var req dto.PharmacyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	application, err := h.onboardingService.Register(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to submit application")
+		return
+	}
+
+	Created(c, application)
+}
+
+func (h *PharmacyOnboardingHandler) GetApplication(c *gin.Context) {
+	application, err := h.onboardingService.GetApplication(c.Request.Context(), c.Param("id"), c.GetHeader(applicationTokenHeader))
+	if err != nil {
+		h.handleError(c, err, "Failed to get application")
+		return
+	}
+
+	Success(c, application)
+}
+
+func (h *PharmacyOnboardingHandler) Resubmit(c *gin.Context) {
+	var req dto.PharmacyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	application, err := h.onboardingService.Resubmit(c.Request.Context(), c.Param("id"), c.GetHeader(applicationTokenHeader), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update application")
+		return
+	}
+
+	Success(c, application)
+}
+
+func (h *PharmacyOnboardingHandler) List(c *gin.Context) {
+	applications, err := h.onboardingService.List(c.Request.Context(), c.DefaultQuery("status", string(domain.PharmacyStatusPending)))
+	if err != nil {
+		InternalError(c, "Failed to get applications")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": applications,
+		"total": len(applications),
+	})
+}
+
+func (h *PharmacyOnboardingHandler) Get(c *gin.Context) {
+	application, err := h.onboardingService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get application")
+		return
+	}
+
+	Success(c, application)
+}
+
+func (h *PharmacyOnboardingHandler) Approve(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.ApprovePharmacyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	application, err := h.onboardingService.Approve(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to approve pharmacy")
+		return
+	}
+
+	Success(c, application)
+}
+
+func (h *PharmacyOnboardingHandler) RequestChanges(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	application, err := h.onboardingService.RequestChanges(c.Request.Context(), adminID.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		h.handleError(c, err, "Failed to request changes")
+		return
+	}
+
+	Success(c, application)
+}
+
+func (h *PharmacyOnboardingHandler) Reject(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	application, err := h.onboardingService.Reject(c.Request.Context(), adminID.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		h.handleError(c, err, "Failed to reject pharmacy")
+		return
+	}
+
+	Success(c, application)
+}
+
+func (h *PharmacyOnboardingHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrPharmacyNotFound), errors.Is(err, domain.ErrApplicationNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrShortCodeTaken), errors.Is(err, domain.ErrUsernameTaken),
+		errors.Is(err, domain.ErrApplicationNotReviewable), errors.Is(err, domain.ErrApplicationNotEditable):
+		Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidDocumentType), errors.Is(err, domain.ErrPharmacyEmailRequired):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrUploadNotFound):
+		BadRequest(c, "Document upload not found")
+	default:
+		InternalError(c, message)
+	}
+}
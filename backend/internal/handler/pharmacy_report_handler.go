@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/pdf"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PharmacyReportHandler struct {
+	reportService service.PharmacyReportService
+	config        *config.Config
+}
+
+func NewPharmacyReportHandler(reportService service.PharmacyReportService, cfg *config.Config) *PharmacyReportHandler {
+	return &PharmacyReportHandler{
+		reportService: reportService,
+		config:        cfg,
+	}
+}
+
+// ListTransactions lists the pharmacy's transactions, newest first. The from
+// and to dates (YYYY-MM-DD, business timezone) are inclusive.
+func (h *PharmacyReportHandler) ListTransactions(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	filter := repository.PharmacyTransactionFilter{
+		PharmacyID:     pharmacyID.(string),
+		Type:           domain.TransactionType(c.Query("type")),
+		Status:         domain.TransactionStatus(c.Query("status")),
+		PharmacyUserID: c.Query("staff_id"),
+	}
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if c.Query("from") != "" {
+		from, err := time.ParseInLocation("2006-01-02", c.Query("from"), h.config.Location())
+		if err != nil {
+			BadRequest(c, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		filter.From = &from
+	}
+	if c.Query("to") != "" {
+		to, err := time.ParseInLocation("2006-01-02", c.Query("to"), h.config.Location())
+		if err != nil {
+			BadRequest(c, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		BadRequest(c, "from must not be after to")
+		return
+	}
+
+	transactions, err := h.reportService.ListTransactions(c.Request.Context(), filter)
+	if err != nil {
+		InternalError(c, "Failed to get transactions")
+		return
+	}
+
+	Success(c, transactions)
+}
+
+// EndOfDay returns the report for the date query parameter (YYYY-MM-DD,
+// business timezone), defaulting to today. format=csv or format=pdf returns
+// a download instead of JSON.
+func (h *PharmacyReportHandler) EndOfDay(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	day := time.Now()
+	if c.Query("date") != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", c.Query("date"), h.config.Location())
+		if err != nil {
+			BadRequest(c, "date must be in YYYY-MM-DD format")
+			return
+		}
+		day = parsed
+	}
+
+	report, err := h.reportService.EndOfDay(c.Request.Context(), pharmacyID.(string), day)
+	if err != nil {
+		if errors.Is(err, domain.ErrPharmacyNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to get end-of-day report")
+		return
+	}
+
+	switch c.Query("format") {
+	case "csv":
+		writeEndOfDayCSV(c, report)
+	case "pdf":
+		writeEndOfDayPDF(c, report)
+	default:
+		Success(c, report)
+	}
+}
+
+func cashierLabel(s *domain.CashierSummary) string {
+	if s.PharmacyUserID == nil {
+		return "Unassigned"
+	}
+	if s.FullName == "" {
+		return s.Username
+	}
+	return fmt.Sprintf("%s (%s)", s.FullName, s.Username)
+}
+
+func endOfDayFilename(report *service.EndOfDayReport, ext string) string {
+	return fmt.Sprintf("end-of-day-%s-%s.%s", report.PharmacyShortCode, report.Date, ext)
+}
+
+func writeEndOfDayCSV(c *gin.Context, report *service.EndOfDayReport) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", endOfDayFilename(report, "csv")))
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"Pharmacy", report.PharmacyName})
+	w.Write([]string{"Date", report.Date})
+	w.Write(nil)
+	w.Write([]string{"Cashier", "Withdrawals", "Gross", "Fees", "Net", "Reversals", "Reversed", "Cash-ins", "Cash collected"})
+	row := func(label string, s *domain.CashierSummary) []string {
+		return []string{
+			label,
+			strconv.Itoa(s.WithdrawalCount),
+			s.GrossWithdrawals.StringFixed(2),
+			s.WithdrawalFees.StringFixed(2),
+			s.NetWithdrawals.StringFixed(2),
+			strconv.Itoa(s.ReversalCount),
+			s.ReversalTotal.StringFixed(2),
+			strconv.Itoa(s.CashInCount),
+			s.CashInTotal.StringFixed(2),
+		}
+	}
+	for _, cashier := range report.Cashiers {
+		w.Write(row(cashierLabel(cashier), cashier))
+	}
+	w.Write(row("Total", &report.Totals))
+	w.Flush()
+}
+
+func writeEndOfDayPDF(c *gin.Context, report *service.EndOfDayReport) {
+	doc := pdf.New()
+	doc.Linef("End-of-day report: %s (%s)", report.PharmacyName, report.PharmacyShortCode)
+	doc.Linef("Business day %s", report.Date)
+	doc.Blank()
+
+	const format = "%-20.20s %5s %11s %9s %11s %4s %10s %4s %11s"
+	doc.Linef(format, "Cashier", "W/D", "Gross", "Fees", "Net", "Rev", "Reversed", "Cash", "Collected")
+	row := func(label string, s *domain.CashierSummary) {
+		doc.Linef(format,
+			label,
+			strconv.Itoa(s.WithdrawalCount),
+			s.GrossWithdrawals.StringFixed(2),
+			s.WithdrawalFees.StringFixed(2),
+			s.NetWithdrawals.StringFixed(2),
+			strconv.Itoa(s.ReversalCount),
+			s.ReversalTotal.StringFixed(2),
+			strconv.Itoa(s.CashInCount),
+			s.CashInTotal.StringFixed(2),
+		)
+	}
+	for _, cashier := range report.Cashiers {
+		row(cashierLabel(cashier), cashier)
+	}
+	doc.Blank()
+	row("Total", &report.Totals)
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", endOfDayFilename(report, "pdf")))
+	doc.WriteTo(c.Writer)
+}
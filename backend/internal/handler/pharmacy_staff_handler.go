@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PharmacyStaffHandler struct {
+	staffService service.PharmacyStaffService
+}
+
+func NewPharmacyStaffHandler(staffService service.PharmacyStaffService) *PharmacyStaffHandler {
+	return &PharmacyStaffHandler{staffService: staffService}
+}
+
+func (h *PharmacyStaffHandler) List(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.list(c, pharmacyID.(string))
+}
+
+func (h *PharmacyStaffHandler) ListForAdmin(c *gin.Context) {
+	h.list(c, c.Param("id"))
+}
+
+func (h *PharmacyStaffHandler) list(c *gin.Context, pharmacyID string) {
+	staff, err := h.staffService.List(c.Request.Context(), pharmacyID)
+	if err != nil {
+		InternalError(c, "Failed to get staff")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": staff,
+		"total": len(staff),
+	})
+}
+
+func (h *PharmacyStaffHandler) Create(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.create(c, domain.AuditActorPharmacy, c.GetString("pharmacyUserID"), pharmacyID.(string))
+}
+
+// CreateForAdmin lets an admin add a login, e.g. a new manager for a pharmacy
+// that has lost access to its existing manager accounts.
+func (h *PharmacyStaffHandler) CreateForAdmin(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.create(c, domain.AuditActorAdmin, adminID.(string), c.Param("id"))
+}
+
+func (h *PharmacyStaffHandler) create(c *gin.Context, actorType domain.AuditActorType, actorID, pharmacyID string) {
+	var req dto.CreatePharmacyUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.staffService.Create(c.Request.Context(), actorType, actorID, pharmacyID, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create staff member")
+		return
+	}
+
+	Created(c, user)
+}
+
+func (h *PharmacyStaffHandler) Update(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.UpdatePharmacyUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.staffService.Update(c.Request.Context(), c.GetString("pharmacyUserID"), pharmacyID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update staff member")
+		return
+	}
+
+	Success(c, user)
+}
+
+func (h *PharmacyStaffHandler) Disable(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.staffService.Disable(c.Request.Context(), c.GetString("pharmacyUserID"), pharmacyID.(string), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to disable staff member")
+		return
+	}
+
+	Success(c, gin.H{"message": "Staff member disabled"})
+}
+
+// SendSetupLink emails a fresh set-password link, e.g. when the link sent on
+// approval expired before the manager used it.
+func (h *PharmacyStaffHandler) SendSetupLink(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.staffService.SendSetupLink(c.Request.Context(), domain.AuditActorAdmin, adminID.(string), c.Param("id"), c.Param("userId")); err != nil {
+		h.handleError(c, err, "Failed to send set-password link")
+		return
+	}
+
+	Success(c, gin.H{"message": "Set-password link sent"})
+}
+
+func (h *PharmacyStaffHandler) SetPassword(c *gin.Context) {
+	var req dto.SetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.staffService.SetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSetupTokenInvalid), errors.Is(err, domain.ErrSetupTokenExpired):
+			Error(c, 422, "INVALID_SETUP_LINK", err.Error())
+		default:
+			InternalError(c, "Failed to set password")
+		}
+		return
+	}
+
+	Success(c, gin.H{"message": "Password set, you can now sign in"})
+}
+
+func (h *PharmacyStaffHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrPharmacyUserNotFound), errors.Is(err, domain.ErrPharmacyNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrUsernameTaken), errors.Is(err, domain.ErrLastManager):
+		Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidPharmacyRole), errors.Is(err, domain.ErrPharmacyEmailRequired):
+		BadRequest(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SettlementHandler struct {
+	settlementService service.SettlementService
+	config            *config.Config
+}
+
+func NewSettlementHandler(settlementService service.SettlementService, cfg *config.Config) *SettlementHandler {
+	return &SettlementHandler{
+		settlementService: settlementService,
+		config:            cfg,
+	}
+}
+
+func (h *SettlementHandler) List(c *gin.Context) {
+	filter := repository.SettlementFilter{
+		PharmacyID: c.Query("pharmacy_id"),
+		Status:     domain.SettlementStatus(c.Query("status")),
+	}
+	h.list(c, filter)
+}
+
+func (h *SettlementHandler) ListForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	filter := repository.SettlementFilter{
+		PharmacyID: pharmacyID.(string),
+		Status:     domain.SettlementStatus(c.Query("status")),
+	}
+	h.list(c, filter)
+}
+
+func (h *SettlementHandler) list(c *gin.Context, filter repository.SettlementFilter) {
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	settlements, total, err := h.settlementService.List(c.Request.Context(), filter)
+	if err != nil {
+		InternalError(c, "Failed to get settlements")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": settlements,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.PageSize,
+	})
+}
+
+func (h *SettlementHandler) GetStatement(c *gin.Context) {
+	h.statement(c, "")
+}
+
+func (h *SettlementHandler) GetStatementForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.statement(c, pharmacyID.(string))
+}
+
+func (h *SettlementHandler) statement(c *gin.Context, pharmacyID string) {
+	statement, err := h.settlementService.GetStatement(c.Request.Context(), c.Param("id"), pharmacyID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSettlementNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to get settlement statement")
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		writeStatementCSV(c, statement)
+		return
+	}
+
+	Success(c, statement)
+}
+
+func writeStatementCSV(c *gin.Context, statement *service.SettlementStatement) {
+	s := statement.Settlement
+	filename := fmt.Sprintf("settlement-%s-%s.csv", statement.PharmacyShortCode, s.PeriodEnd.Format("20060102"))
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"Pharmacy", statement.PharmacyName})
+	w.Write([]string{"Settlement", s.ID})
+	w.Write([]string{"Period", s.PeriodStart.Format(time.RFC3339), s.PeriodEnd.Format(time.RFC3339)})
+	w.Write([]string{"Status", string(s.Status)})
+	w.Write(nil)
+	w.Write([]string{"Transaction", "Date", "Type", "Contributor", "Amount", "Fee", "Net", "Payable"})
+	for _, line := range statement.Lines {
+		w.Write([]string{
+			line.TransactionID,
+			line.CreatedAt,
+			line.Type,
+			line.ContributorName,
+			strconv.FormatFloat(line.Amount, 'f', 2, 64),
+			strconv.FormatFloat(line.Fee, 'f', 2, 64),
+			strconv.FormatFloat(line.NetAmount, 'f', 2, 64),
+			strconv.FormatFloat(line.Payable, 'f', 2, 64),
+		})
+	}
+	w.Write(nil)
+	w.Write([]string{"Net withdrawals", s.NetWithdrawals.StringFixed(2)})
+	w.Write([]string{"Cash collected", s.CashInTotal.StringFixed(2)})
+	w.Write([]string{"Reversals", s.ReversalTotal.StringFixed(2)})
+	w.Write([]string{"Net payable", s.NetPayable.StringFixed(2)})
+	w.Flush()
+}
+
+func (h *SettlementHandler) Run(c *gin.Context) {
+	var req dto.RunSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	cutoff := h.config.StartOfDay(time.Now())
+	if req.Cutoff != "" {
+		parsed, err := time.Parse(time.RFC3339, req.Cutoff)
+		if err != nil {
+			BadRequest(c, "cutoff must be an RFC3339 timestamp")
+			return
+		}
+		if parsed.After(time.Now()) {
+			BadRequest(c, "cutoff cannot be in the future")
+			return
+		}
+		cutoff = parsed
+	}
+
+	settlements, err := h.settlementService.GenerateBatches(c.Request.Context(), cutoff)
+	if err != nil {
+		InternalError(c, "Failed to generate settlements")
+		return
+	}
+
+	Created(c, gin.H{
+		"items": settlements,
+		"total": len(settlements),
+	})
+}
+
+func (h *SettlementHandler) MarkPaid(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.MarkSettlementPaidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	settlement, err := h.settlementService.MarkPaid(c.Request.Context(), adminID.(string), c.Param("id"), req.PayoutReference)
+	if err != nil {
+		if errors.Is(err, domain.ErrSettlementNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrSettlementAlreadyPaid) || errors.Is(err, domain.ErrPayoutInProgress) {
+			Conflict(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to mark settlement as paid")
+		return
+	}
+
+	Success(c, settlement)
+}
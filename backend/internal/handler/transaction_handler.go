@@ -22,32 +22,6 @@ func NewTransactionHandler(transactionService service.TransactionService, otpSer
 	}
 }
 
-func (h *TransactionHandler) Deposit(c *gin.Context) {
-	walletID := c.Param("id")
-
-	var req dto.DepositRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		BadRequest(c, err.Error())
-		return
-	}
-
-	transaction, err := h.transactionService.Deposit(c.Request.Context(), walletID, req)
-	if err != nil {
-		if errors.Is(err, domain.ErrWalletNotFound) {
-			NotFound(c, err.Error())
-			return
-		}
-		if errors.Is(err, domain.ErrInvalidAmount) {
-			BadRequest(c, err.Error())
-			return
-		}
-		InternalError(c, "Failed to process deposit")
-		return
-	}
-
-	Created(c, transaction)
-}
-
 func (h *TransactionHandler) Withdraw(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -93,10 +67,26 @@ func (h *TransactionHandler) Withdraw(c *gin.Context) {
 			BadRequest(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrWalletNotSpendable) {
+			Conflict(c, err.Error())
+			return
+		}
 		if errors.Is(err, domain.ErrPharmacyNotFound) || errors.Is(err, domain.ErrPharmacyInactive) {
 			BadRequest(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrSpendingNotAllowed) {
+			Forbidden(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrFeeQuoteNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrFeeQuoteExpired) || errors.Is(err, domain.ErrFeeQuoteMismatch) {
+			BadRequest(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to process withdrawal")
 		return
 	}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/carewallet/backend/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead allows for form boundaries and other fields on top of the
+// file itself when capping the request body.
+const multipartOverhead = 64 << 10
+
+type UploadHandler struct {
+	uploadService service.UploadService
+	config        *config.Config
+}
+
+func NewUploadHandler(uploadService service.UploadService, cfg *config.Config) *UploadHandler {
+	return &UploadHandler{uploadService: uploadService, config: cfg}
+}
+
+func (h *UploadHandler) Upload(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.upload(c, domain.UploadOwnerUser, userID.(string), "")
+}
+
+func (h *UploadHandler) UploadForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.upload(c, domain.UploadOwnerPharmacy, pharmacyID.(string), "")
+}
+
+// UploadForApplicant accepts registration documents before the pharmacy has
+// an account. The registration claims the upload when it is submitted.
+func (h *UploadHandler) UploadForApplicant(c *gin.Context) {
+	h.upload(c, domain.UploadOwnerApplicant, "", string(domain.UploadPurposePharmacyDocument))
+}
+
+// upload stores the multipart "file" field. An empty purpose is taken from
+// the "purpose" form field once the size-capped body has been parsed.
+func (h *UploadHandler) upload(c *gin.Context, ownerType domain.UploadOwnerType, ownerID, purpose string) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.config.UploadMaxBytes+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			Error(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", domain.ErrUploadTooLarge.Error())
+			return
+		}
+		BadRequest(c, "A file is required")
+		return
+	}
+	if purpose == "" {
+		purpose = c.PostForm("purpose")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		BadRequest(c, "Could not read the uploaded file")
+		return
+	}
+	defer file.Close()
+
+	upload, err := h.uploadService.Upload(c.Request.Context(), ownerType, ownerID, purpose, fileHeader.Filename, file)
+	if err != nil {
+		h.handleError(c, err, "Failed to upload file")
+		return
+	}
+
+	Created(c, upload)
+}
+
+func (h *UploadHandler) Get(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.get(c, domain.UploadOwnerUser, userID.(string))
+}
+
+func (h *UploadHandler) GetForPharmacy(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	h.get(c, domain.UploadOwnerPharmacy, pharmacyID.(string))
+}
+
+func (h *UploadHandler) get(c *gin.Context, ownerType domain.UploadOwnerType, ownerID string) {
+	upload, err := h.uploadService.Get(c.Request.Context(), ownerType, ownerID, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get file")
+		return
+	}
+
+	Success(c, upload)
+}
+
+func (h *UploadHandler) GetForAdmin(c *gin.Context) {
+	upload, err := h.uploadService.GetForAdmin(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get file")
+		return
+	}
+
+	Success(c, upload)
+}
+
+// Download serves a file to anyone holding a valid signed link.
+func (h *UploadHandler) Download(c *gin.Context) {
+	upload, body, err := h.uploadService.Open(c.Request.Context(), c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidSignature) {
+			Forbidden(c, err.Error())
+			return
+		}
+		h.handleError(c, err, "Failed to download file")
+		return
+	}
+	defer body.Close()
+
+	disposition := "attachment"
+	if upload.Purpose == domain.UploadPurposeWalletPhoto {
+		disposition = "inline"
+	}
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age=300")
+	c.DataFromReader(http.StatusOK, upload.SizeBytes, upload.ContentType, body, map[string]string{
+		"Content-Disposition": fmt.Sprintf("%s; filename=%q", disposition, upload.FileName),
+	})
+}
+
+func (h *UploadHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrUploadNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrUploadTooLarge):
+		Error(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", err.Error())
+	case errors.Is(err, domain.ErrUnsupportedFileType):
+		Error(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_FILE_TYPE", err.Error())
+	case errors.Is(err, domain.ErrInvalidUploadPurpose):
+		BadRequest(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
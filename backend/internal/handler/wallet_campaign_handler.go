@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WalletCampaignHandler struct {
+	campaignService service.WalletCampaignService
+}
+
+func NewWalletCampaignHandler(campaignService service.WalletCampaignService) *WalletCampaignHandler {
+	return &WalletCampaignHandler{campaignService: campaignService}
+}
+
+func (h *WalletCampaignHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	campaign, err := h.campaignService.Create(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create campaign")
+		return
+	}
+
+	Created(c, campaign)
+}
+
+func (h *WalletCampaignHandler) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	campaigns, err := h.campaignService.List(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get campaigns")
+		return
+	}
+
+	Success(c, campaigns)
+}
+
+func (h *WalletCampaignHandler) Get(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	campaign, err := h.campaignService.Get(c.Request.Context(), userID.(string), c.Param("id"), c.Param("campaignId"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get campaign")
+		return
+	}
+
+	Success(c, campaign)
+}
+
+func (h *WalletCampaignHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	campaign, err := h.campaignService.Update(c.Request.Context(), userID.(string), c.Param("id"), c.Param("campaignId"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update campaign")
+		return
+	}
+
+	Success(c, campaign)
+}
+
+// Cancel stops the campaign early. It stays on the wallet's history but is
+// no longer shown on the public page.
+func (h *WalletCampaignHandler) Cancel(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	campaign, err := h.campaignService.Cancel(c.Request.Context(), userID.(string), c.Param("id"), c.Param("campaignId"))
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel campaign")
+		return
+	}
+
+	Success(c, campaign)
+}
+
+func (h *WalletCampaignHandler) GetEvents(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	events, err := h.campaignService.GetEvents(c.Request.Context(), userID.(string), c.Param("id"), c.Param("campaignId"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get campaign events")
+		return
+	}
+
+	Success(c, events)
+}
+
+func (h *WalletCampaignHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound), errors.Is(err, domain.ErrCampaignNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidCampaign), errors.Is(err, domain.ErrInvalidMilestone):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrCampaignAlreadyRunning), errors.Is(err, domain.ErrCampaignNotActive),
+		errors.Is(err, domain.ErrWalletNotAcceptingContributions):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
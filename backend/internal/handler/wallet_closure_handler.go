@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WalletClosureHandler struct {
+	closureService service.WalletClosureService
+}
+
+func NewWalletClosureHandler(closureService service.WalletClosureService) *WalletClosureHandler {
+	return &WalletClosureHandler{closureService: closureService}
+}
+
+// Close closes the wallet and disposes of its remaining balance.
+func (h *WalletClosureHandler) Close(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.CloseWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	closure, err := h.closureService.Close(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to close wallet")
+		return
+	}
+
+	Created(c, closure)
+}
+
+func (h *WalletClosureHandler) GetForWallet(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	closure, err := h.closureService.GetByWalletID(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get wallet closure")
+		return
+	}
+
+	Success(c, closure)
+}
+
+func (h *WalletClosureHandler) List(c *gin.Context) {
+	filter := repository.WalletClosureFilter{
+		Disposition: domain.ClosureDisposition(c.Query("disposition")),
+		Status:      domain.WalletClosureStatus(c.Query("status")),
+	}
+	filter.FailedRefunds, _ = strconv.ParseBool(c.Query("failed_refunds"))
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	closures, total, err := h.closureService.List(c.Request.Context(), filter)
+	if err != nil {
+		InternalError(c, "Failed to get wallet closures")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": closures,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.PageSize,
+	})
+}
+
+func (h *WalletClosureHandler) Get(c *gin.Context) {
+	closure, err := h.closureService.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get wallet closure")
+		return
+	}
+
+	Success(c, closure)
+}
+
+// RetryRefunds sends the closure's failed refunds to Paystack again.
+func (h *WalletClosureHandler) RetryRefunds(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	closure, err := h.closureService.RetryRefunds(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retry refunds")
+		return
+	}
+
+	Success(c, closure)
+}
+
+func (h *WalletClosureHandler) PlatformFund(c *gin.Context) {
+	fund, err := h.closureService.GetPlatformFund(c.Request.Context())
+	if err != nil {
+		InternalError(c, "Failed to get platform fund")
+		return
+	}
+
+	Success(c, fund)
+}
+
+func (h *WalletClosureHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound), errors.Is(err, domain.ErrWalletClosureNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidClosureDisposition), errors.Is(err, domain.ErrInvalidWalletCode),
+		errors.Is(err, domain.ErrInvalidTransferTarget), errors.Is(err, domain.ErrRefundNotCovered):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidWalletTransition), errors.Is(err, domain.ErrWalletBalanceChanged),
+		errors.Is(err, domain.ErrNoOutstandingRefunds):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
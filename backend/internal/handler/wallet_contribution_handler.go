@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WalletContributionHandler struct {
+	contributionService service.WalletContributionService
+}
+
+func NewWalletContributionHandler(contributionService service.WalletContributionService) *WalletContributionHandler {
+	return &WalletContributionHandler{contributionService: contributionService}
+}
+
+// ListByCode returns the contributor wall for the public page reached by a
+// shareable code.
+func (h *WalletContributionHandler) ListByCode(c *gin.Context) {
+	filter := contributionFilter(c)
+	contributions, total, err := h.contributionService.ListByCode(c.Request.Context(), c.Param("code"), filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to get contributions")
+		return
+	}
+
+	h.respond(c, contributions, total, filter)
+}
+
+// ListByShareLink returns the contributor wall for the public page reached by
+// a share link.
+func (h *WalletContributionHandler) ListByShareLink(c *gin.Context) {
+	filter := contributionFilter(c)
+	contributions, total, err := h.contributionService.ListByShareLink(c.Request.Context(), c.Param("token"), filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to get contributions")
+		return
+	}
+
+	h.respond(c, contributions, total, filter)
+}
+
+// ListForOwner returns the contributor wall for the wallet's creator to
+// moderate, including hidden messages.
+func (h *WalletContributionHandler) ListForOwner(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	filter := contributionFilter(c)
+	contributions, total, err := h.contributionService.ListForOwner(c.Request.Context(), userID.(string), c.Param("id"), filter)
+	if err != nil {
+		h.handleError(c, err, "Failed to get contributions")
+		return
+	}
+
+	h.respond(c, contributions, total, filter)
+}
+
+func (h *WalletContributionHandler) HideMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	contribution, err := h.contributionService.HideMessage(c.Request.Context(), userID.(string), c.Param("id"), c.Param("transactionId"))
+	if err != nil {
+		h.handleError(c, err, "Failed to hide message")
+		return
+	}
+
+	Success(c, contribution)
+}
+
+func (h *WalletContributionHandler) ShowMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	contribution, err := h.contributionService.ShowMessage(c.Request.Context(), userID.(string), c.Param("id"), c.Param("transactionId"))
+	if err != nil {
+		h.handleError(c, err, "Failed to show message")
+		return
+	}
+
+	Success(c, contribution)
+}
+
+func contributionFilter(c *gin.Context) repository.ContributionFilter {
+	var filter repository.ContributionFilter
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	return filter
+}
+
+func (h *WalletContributionHandler) respond(c *gin.Context, contributions interface{}, total int, filter repository.ContributionFilter) {
+	Success(c, gin.H{
+		"items": contributions,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.PageSize,
+	})
+}
+
+func (h *WalletContributionHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound), errors.Is(err, domain.ErrShareLinkNotFound),
+		errors.Is(err, domain.ErrContributionNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidWalletCode):
+		BadRequest(c, "Wallet code is not valid; please check it for typos")
+	case errors.Is(err, domain.ErrShareLinkUnavailable):
+		Error(c, http.StatusGone, "SHARE_LINK_UNAVAILABLE", err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"net/http"
 
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/internal/dto"
@@ -32,6 +33,10 @@ func (h *WalletHandler) Create(c *gin.Context) {
 
 	wallet, err := h.walletService.Create(c.Request.Context(), userID.(string), req)
 	if err != nil {
+		if errors.Is(err, domain.ErrUploadNotFound) {
+			BadRequest(c, "Photo upload not found")
+			return
+		}
 		InternalError(c, "Failed to create wallet")
 		return
 	}
@@ -72,6 +77,10 @@ func (h *WalletHandler) GetByShareableCode(c *gin.Context) {
 			NotFound(c, "Wallet not found")
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidWalletCode) {
+			BadRequest(c, "Wallet code is not valid; please check it for typos")
+			return
+		}
 		InternalError(c, "Failed to get wallet")
 		return
 	}
@@ -79,6 +88,17 @@ func (h *WalletHandler) GetByShareableCode(c *gin.Context) {
 	Success(c, wallet)
 }
 
+// GetByShareLink returns the public page for a contribution-only share link.
+func (h *WalletHandler) GetByShareLink(c *gin.Context) {
+	wallet, err := h.walletService.GetByShareLink(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.handleShareError(c, err, "Failed to get wallet")
+		return
+	}
+
+	Success(c, wallet)
+}
+
 func (h *WalletHandler) GetUserWallets(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -119,6 +139,18 @@ func (h *WalletHandler) Update(c *gin.Context) {
 			Forbidden(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrUploadNotFound) {
+			BadRequest(c, "Photo upload not found")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidWalletStatus) || errors.Is(err, domain.ErrWalletHasBalance) {
+			BadRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidWalletTransition) {
+			Conflict(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to update wallet")
 		return
 	}
@@ -148,9 +180,276 @@ func (h *WalletHandler) Delete(c *gin.Context) {
 			BadRequest(c, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidWalletTransition) {
+			Conflict(c, err.Error())
+			return
+		}
 		InternalError(c, "Failed to delete wallet")
 		return
 	}
 
 	Success(c, gin.H{"message": "Wallet deleted successfully"})
 }
+
+func (h *WalletHandler) GetSpendingRules(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	rules, err := h.walletService.GetSpendingRules(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleSpendingRuleError(c, err, "Failed to get spending rules")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": rules,
+		"total": len(rules),
+	})
+}
+
+func (h *WalletHandler) AddSpendingRule(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.AddSpendingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	rule, err := h.walletService.AddSpendingRule(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleSpendingRuleError(c, err, "Failed to add spending rule")
+		return
+	}
+
+	Created(c, rule)
+}
+
+func (h *WalletHandler) RemoveSpendingRule(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.walletService.RemoveSpendingRule(c.Request.Context(), userID.(string), c.Param("id"), c.Param("ruleId")); err != nil {
+		h.handleSpendingRuleError(c, err, "Failed to remove spending rule")
+		return
+	}
+
+	Success(c, gin.H{"message": "Spending rule removed"})
+}
+
+func (h *WalletHandler) handleSpendingRuleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound), errors.Is(err, domain.ErrSpendingRuleNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrPharmacyNotFound), errors.Is(err, domain.ErrOrganisationNotFound),
+		errors.Is(err, domain.ErrInvalidSpendingRule):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrSpendingRuleExists):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
+
+func (h *WalletHandler) RotateCode(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	wallet, err := h.walletService.RotateCode(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleShareError(c, err, "Failed to rotate wallet code")
+		return
+	}
+
+	Success(c, wallet)
+}
+
+func (h *WalletHandler) GetShareLinks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	links, err := h.walletService.GetShareLinks(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleShareError(c, err, "Failed to get share links")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": links,
+		"total": len(links),
+	})
+}
+
+func (h *WalletHandler) CreateShareLink(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	link, err := h.walletService.CreateShareLink(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleShareError(c, err, "Failed to create share link")
+		return
+	}
+
+	Created(c, link)
+}
+
+func (h *WalletHandler) RevokeShareLink(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	if err := h.walletService.RevokeShareLink(c.Request.Context(), userID.(string), c.Param("id"), c.Param("linkId")); err != nil {
+		h.handleShareError(c, err, "Failed to revoke share link")
+		return
+	}
+
+	Success(c, gin.H{"message": "Share link revoked"})
+}
+
+func (h *WalletHandler) handleShareError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound), errors.Is(err, domain.ErrShareLinkNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidShareLink):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrShareLinkUnavailable):
+		Error(c, http.StatusGone, "SHARE_LINK_UNAVAILABLE", err.Error())
+	case errors.Is(err, domain.ErrWalletNotAcceptingContributions):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
+
+func (h *WalletHandler) ChangeStatus(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.ChangeWalletStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	wallet, err := h.walletService.ChangeStatus(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		h.handleStatusError(c, err, "Failed to change wallet status")
+		return
+	}
+
+	Success(c, wallet)
+}
+
+func (h *WalletHandler) GetStatusHistory(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	changes, err := h.walletService.GetStatusHistory(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleStatusError(c, err, "Failed to get wallet status history")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": changes,
+		"total": len(changes),
+	})
+}
+
+// Freeze stops all money movement on a wallet while an admin investigates it.
+func (h *WalletHandler) Freeze(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.FreezeWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	wallet, err := h.walletService.Freeze(c.Request.Context(), adminID.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		h.handleStatusError(c, err, "Failed to freeze wallet")
+		return
+	}
+
+	Success(c, wallet)
+}
+
+// Unfreeze returns a frozen wallet to the status it had before it was frozen.
+func (h *WalletHandler) Unfreeze(c *gin.Context) {
+	adminID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.FreezeWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	wallet, err := h.walletService.Unfreeze(c.Request.Context(), adminID.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		h.handleStatusError(c, err, "Failed to unfreeze wallet")
+		return
+	}
+
+	Success(c, wallet)
+}
+
+func (h *WalletHandler) handleStatusError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidWalletStatus), errors.Is(err, domain.ErrWalletHasBalance):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidWalletTransition):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
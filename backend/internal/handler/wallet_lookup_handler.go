@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/qr"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type WalletLookupHandler struct {
+	lookupService service.WalletLookupService
+}
+
+func NewWalletLookupHandler(lookupService service.WalletLookupService) *WalletLookupHandler {
+	return &WalletLookupHandler{lookupService: lookupService}
+}
+
+// Lookup describes the wallet with the code in the path. An optional amount
+// query parameter asks whether the wallet covers that amount.
+func (h *WalletLookupHandler) Lookup(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var amount *decimal.Decimal
+	if raw := c.Query("amount"); raw != "" {
+		parsed, err := decimal.NewFromString(raw)
+		if err != nil || !parsed.IsPositive() {
+			BadRequest(c, "amount must be a positive number")
+			return
+		}
+		amount = &parsed
+	}
+
+	response, err := h.lookupService.Lookup(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), c.Param("code"), amount)
+	if err != nil {
+		h.handleError(c, err, "Failed to lookup wallet")
+		return
+	}
+
+	Success(c, response)
+}
+
+// ScanVoucher looks up the wallet in a voucher scanned from the QR code on
+// the wallet owner's phone.
+func (h *WalletLookupHandler) ScanVoucher(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.ScanVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.lookupService.ScanVoucher(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to scan wallet voucher")
+		return
+	}
+
+	Success(c, response)
+}
+
+func (h *WalletLookupHandler) RequestBalanceConsent(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	response, err := h.lookupService.RequestBalanceConsent(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), c.Param("code"))
+	if err != nil {
+		h.handleError(c, err, "Failed to request balance consent")
+		return
+	}
+
+	Success(c, response)
+}
+
+func (h *WalletLookupHandler) DiscloseBalance(c *gin.Context) {
+	pharmacyID, exists := c.Get("pharmacyID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	var req dto.BalanceDisclosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	response, err := h.lookupService.DiscloseBalance(c.Request.Context(), pharmacyID.(string), c.GetString("pharmacyUserID"), c.Param("code"), req.OTPCode)
+	if err != nil {
+		h.handleError(c, err, "Failed to get wallet balance")
+		return
+	}
+
+	Success(c, response)
+}
+
+func (h *WalletLookupHandler) ListForAdmin(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	lookups, total, err := h.lookupService.ListLookups(c.Request.Context(), c.Param("id"), page, limit)
+	if err != nil {
+		InternalError(c, "Failed to get wallet lookups")
+		return
+	}
+
+	Success(c, gin.H{
+		"items": lookups,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func (h *WalletLookupHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound):
+		NotFound(c, "Wallet not found")
+	case errors.Is(err, domain.ErrLookupLimitReached):
+		Error(c, 429, "LOOKUP_LIMIT_REACHED", err.Error())
+	case errors.Is(err, domain.ErrAmountCheckLimit):
+		Error(c, 429, "AMOUNT_CHECK_LIMIT_REACHED", err.Error())
+	case errors.Is(err, domain.ErrNoBeneficiaryEmail), errors.Is(err, domain.ErrInvalidWalletCode):
+		BadRequest(c, err.Error())
+	case errors.Is(err, domain.ErrInvalidOTP):
+		BadRequest(c, "Invalid or expired OTP")
+	case errors.Is(err, qr.ErrInvalidVoucher):
+		BadRequest(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/qr"
+	"github.com/carewallet/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WalletQRHandler struct {
+	qrService service.WalletQRService
+}
+
+func NewWalletQRHandler(qrService service.WalletQRService) *WalletQRHandler {
+	return &WalletQRHandler{qrService: qrService}
+}
+
+// ContributionQR renders the wallet's contribution link as a QR code. The
+// format query parameter is png (the default) or svg; size is in pixels.
+func (h *WalletQRHandler) ContributionQR(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	link, err := h.qrService.ContributionLink(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to get contribution link")
+		return
+	}
+
+	h.render(c, link)
+}
+
+func (h *WalletQRHandler) Voucher(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	voucher, err := h.qrService.IssueVoucher(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to issue wallet voucher")
+		return
+	}
+
+	Success(c, voucher)
+}
+
+// VoucherQR renders a freshly signed voucher as a QR code for a pharmacy to
+// scan. Its expiry is in the X-Voucher-Expires-At header.
+func (h *WalletQRHandler) VoucherQR(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		Unauthorized(c, "Not authenticated")
+		return
+	}
+
+	voucher, err := h.qrService.IssueVoucher(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to issue wallet voucher")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Voucher-Expires-At", voucher.ExpiresAt)
+	h.render(c, voucher.Voucher)
+}
+
+func (h *WalletQRHandler) render(c *gin.Context, content string) {
+	format := qr.Format(c.DefaultQuery("format", string(qr.FormatPNG)))
+	size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(qr.DefaultSize)))
+	if err != nil {
+		BadRequest(c, "size must be a number of pixels")
+		return
+	}
+
+	image, err := qr.Encode(content, format, size)
+	if err != nil {
+		if errors.Is(err, qr.ErrUnsupportedFormat) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, "Failed to render QR code")
+		return
+	}
+
+	c.Data(http.StatusOK, format.ContentType(), image)
+}
+
+func (h *WalletQRHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrWalletNotFound):
+		NotFound(c, err.Error())
+	case errors.Is(err, domain.ErrWalletAccessDenied):
+		Forbidden(c, err.Error())
+	case errors.Is(err, domain.ErrWalletNotSpendable):
+		Conflict(c, err.Error())
+	default:
+		InternalError(c, message)
+	}
+}
@@ -1,126 +1,304 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/internal/service"
 	"github.com/carewallet/backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
+// APIKeyHeader carries a pharmacy API key on machine-to-machine requests.
+const APIKeyHeader = "X-API-Key"
+
 type AuthMiddleware struct {
-	jwtManager  *utils.JWTManager
-	authService service.AuthService
+	jwtManager    *utils.JWTManager
+	authService   service.AuthService
+	staffService  service.PharmacyStaffService
+	apiKeyService service.PharmacyAPIKeyService
 }
 
-func NewAuthMiddleware(jwtManager *utils.JWTManager, authService service.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(
+	jwtManager *utils.JWTManager,
+	authService service.AuthService,
+	staffService service.PharmacyStaffService,
+	apiKeyService service.PharmacyAPIKeyService,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtManager:  jwtManager,
-		authService: authService,
+		jwtManager:    jwtManager,
+		authService:   authService,
+		staffService:  staffService,
+		apiKeyService: apiKeyService,
 	}
 }
 
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.authenticate(c) {
+			c.Next()
+		}
+	}
+}
+
+// authenticate validates the bearer token and stores its claims, aborting the
+// request and returning false if it is missing or invalid.
+func (m *AuthMiddleware) authenticate(c *gin.Context) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Authorization header required",
+			},
+		})
+		return false
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Invalid authorization header format",
+			},
+		})
+		return false
+	}
+
+	tokenString := parts[1]
+	claims, err := m.jwtManager.Validate(tokenString)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Invalid or expired token",
+			},
+		})
+		return false
+	}
+
+	// Check if token is blacklisted
+	blacklisted, err := m.authService.IsTokenBlacklisted(c.Request.Context(), claims.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INTERNAL_ERROR",
+				"message": "Failed to validate token",
+			},
+		})
+		return false
+	}
+
+	if blacklisted {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Token has been invalidated",
+			},
+		})
+		return false
+	}
+
+	c.Set("userID", claims.UserID)
+	c.Set("userEmail", claims.Email)
+	c.Set("userRole", claims.Role)
+	c.Set("claims", claims)
+	return true
+}
+
+func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "Authorization header required",
-				},
-			})
+			c.Next()
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "Invalid authorization header format",
-				},
-			})
+			c.Next()
 			return
 		}
 
 		tokenString := parts[1]
 		claims, err := m.jwtManager.Validate(tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "Invalid or expired token",
-				},
-			})
+			c.Next()
 			return
 		}
 
-		// Check if token is blacklisted
-		blacklisted, err := m.authService.IsTokenBlacklisted(c.Request.Context(), claims.ID)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "INTERNAL_ERROR",
-					"message": "Failed to validate token",
-				},
-			})
+		blacklisted, _ := m.authService.IsTokenBlacklisted(c.Request.Context(), claims.ID)
+		if blacklisted {
+			c.Next()
 			return
 		}
 
-		if blacklisted {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		c.Set("userID", claims.UserID)
+		c.Set("userEmail", claims.Email)
+		c.Set("claims", claims)
+		c.Next()
+	}
+}
+
+// RequireAdmin must run after RequireAuth. The role is read from the database
+// rather than the token so that revoking admin access takes effect immediately.
+func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+
+		user, err := m.authService.GetCurrentUser(c.Request.Context(), userID)
+		if err != nil || user.Role != string(domain.UserRoleAdmin) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 				"success": false,
 				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "Token has been invalidated",
+					"code":    "FORBIDDEN",
+					"message": "Admin access required",
 				},
 			})
 			return
 		}
 
-		c.Set("userID", claims.UserID)
-		c.Set("userEmail", claims.Email)
-		c.Set("claims", claims)
 		c.Next()
 	}
 }
 
-func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+// RequirePharmacy must run after RequireAuth and rejects tokens that were not
+// issued by the pharmacy portal login. The staff member is looked up on every
+// request so that disabling a login or changing a role takes effect immediately.
+func (m *AuthMiddleware) RequirePharmacy() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		if m.loadPharmacyStaff(c) {
 			c.Next()
-			return
 		}
+	}
+}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.Next()
+func (m *AuthMiddleware) loadPharmacyStaff(c *gin.Context) bool {
+	if c.GetString("userRole") != string(domain.UserRolePharmacy) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "FORBIDDEN",
+				"message": "Pharmacy access required",
+			},
+		})
+		return false
+	}
+
+	pharmacyID := c.GetString("userID")
+	claims, _ := c.Get("claims")
+	jwtClaims, _ := claims.(*utils.JWTClaims)
+	if jwtClaims == nil || jwtClaims.PharmacyUserID == "" {
+		// Tokens issued before staff logins existed carry no staff member.
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Please sign in again with your staff username",
+			},
+		})
+		return false
+	}
+
+	staff, err := m.staffService.GetActive(c.Request.Context(), pharmacyID, jwtClaims.PharmacyUserID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "UNAUTHORIZED",
+				"message": "Staff account is no longer active",
+			},
+		})
+		return false
+	}
+
+	c.Set("pharmacyID", pharmacyID)
+	c.Set("pharmacyUserID", staff.ID)
+	c.Set("pharmacyRole", string(staff.Role))
+	return true
+}
+
+// RequirePharmacyOrAPIKey accepts either a pharmacy staff login, as
+// RequireAuth followed by RequirePharmacy would, or a pharmacy API key in the
+// X-API-Key header that has the given scope. Requests made with a key act for
+// the staff member who issued it, so role checks still apply.
+func (m *AuthMiddleware) RequirePharmacyOrAPIKey(scope domain.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			if m.authenticate(c) && m.loadPharmacyStaff(c) {
+				c.Next()
+			}
 			return
 		}
 
-		tokenString := parts[1]
-		claims, err := m.jwtManager.Validate(tokenString)
+		key, staff, err := m.apiKeyService.Authenticate(c.Request.Context(), rawKey, c.ClientIP())
 		if err != nil {
-			c.Next()
+			if errors.Is(err, domain.ErrAPIKeyInvalid) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"error": gin.H{
+						"code":    "UNAUTHORIZED",
+						"message": err.Error(),
+					},
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INTERNAL_ERROR",
+					"message": "Failed to validate API key",
+				},
+			})
 			return
 		}
 
-		blacklisted, _ := m.authService.IsTokenBlacklisted(c.Request.Context(), claims.ID)
-		if blacklisted {
-			c.Next()
+		if !key.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "FORBIDDEN",
+					"message": "API key does not have the " + string(scope) + " scope",
+				},
+			})
 			return
 		}
 
-		c.Set("userID", claims.UserID)
-		c.Set("userEmail", claims.Email)
-		c.Set("claims", claims)
+		c.Set("pharmacyID", key.PharmacyID)
+		c.Set("pharmacyUserID", staff.ID)
+		c.Set("pharmacyRole", string(staff.Role))
+		c.Set("pharmacyAPIKeyID", key.ID)
 		c.Next()
 	}
 }
+
+// RequirePharmacyRole must run after RequirePharmacy and only lets staff with
+// one of the given roles through.
+func (m *AuthMiddleware) RequirePharmacyRole(roles ...domain.PharmacyUserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := domain.PharmacyUserRole(c.GetString("pharmacyRole"))
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "FORBIDDEN",
+				"message": "Your staff role does not allow this action",
+			},
+		})
+	}
+}
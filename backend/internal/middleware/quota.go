@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaLimiter allows each client IP a fixed number of requests per window,
+// for endpoints where the global per-second rate limit is far too generous.
+type QuotaLimiter struct {
+	clients map[string]*quotaWindow
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+}
+
+type quotaWindow struct {
+	count   int
+	startAt time.Time
+}
+
+func NewQuotaLimiter(limit int, window time.Duration) *QuotaLimiter {
+	ql := &QuotaLimiter{
+		clients: make(map[string]*quotaWindow),
+		limit:   limit,
+		window:  window,
+	}
+
+	go ql.cleanupClients()
+
+	return ql
+}
+
+func (ql *QuotaLimiter) cleanupClients() {
+	for {
+		time.Sleep(ql.window)
+		ql.mu.Lock()
+		for ip, w := range ql.clients {
+			if time.Since(w.startAt) >= ql.window {
+				delete(ql.clients, ip)
+			}
+		}
+		ql.mu.Unlock()
+	}
+}
+
+func (ql *QuotaLimiter) Allow(ip string) bool {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+
+	now := time.Now()
+	w, exists := ql.clients[ip]
+	if !exists || now.Sub(w.startAt) >= ql.window {
+		w = &quotaWindow{startAt: now}
+		ql.clients[ip] = w
+	}
+
+	if w.count >= ql.limit {
+		return false
+	}
+
+	w.count++
+	return true
+}
+
+// Quota refuses a client's requests once it has made limit of them within
+// the window. A limit of zero or less disables the quota.
+func Quota(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limiter := NewQuotaLimiter(limit, window)
+
+	return func(c *gin.Context) {
+		if !limiter.Allow(c.ClientIP()) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "QUOTA_EXCEEDED",
+					"message": "Too many requests. Please try again later.",
+				},
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
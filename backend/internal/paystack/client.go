@@ -27,6 +27,18 @@ func NewClient(secretKey string) *Client {
 	}
 }
 
+// APIError is a response from Paystack with a non-2xx status. Any other
+// error from the client means Paystack's answer never arrived, so the request
+// may or may not have taken effect.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("paystack API error: %s", e.Body)
+}
+
 type InitializeRequest struct {
 	Email     string            `json:"email"`
 	Amount    int64             `json:"amount"` // Amount in smallest currency unit (kobo for NGN, cents for ZAR)
@@ -99,7 +111,7 @@ func (c *Client) doRequest(method, endpoint string, body interface{}) ([]byte, e
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("paystack API error: %s", string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return respBody, nil
@@ -0,0 +1,153 @@
+package paystack
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeGateway simulates Paystack for local development. Payments and refunds
+// always succeed. Transfers complete with the configured outcome, except
+// transfers to account numbers starting with "999" which always fail.
+type FakeGateway struct {
+	mu              sync.Mutex
+	transferOutcome string
+	recipients      map[string]string // recipient code -> account number
+	transfers       map[string]*TransferData
+	refunds         map[string][]RefundData // refunded transaction -> refunds
+	nextID          int
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{
+		transferOutcome: TransferStatusSuccess,
+		recipients:      make(map[string]string),
+		transfers:       make(map[string]*TransferData),
+		refunds:         make(map[string][]RefundData),
+	}
+}
+
+// SetTransferOutcome sets the status new transfers end in: success, failed
+// or pending. Pending transfers stay pending until SetTransferStatus is called.
+func (g *FakeGateway) SetTransferOutcome(status string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.transferOutcome = status
+}
+
+// SetTransferStatus changes the status of an existing transfer, as a webhook would.
+func (g *FakeGateway) SetTransferStatus(reference, status string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if transfer, ok := g.transfers[reference]; ok {
+		transfer.Status = status
+	}
+}
+
+func (g *FakeGateway) InitializeTransaction(req *InitializeRequest) (*InitializeResponse, error) {
+	response := &InitializeResponse{Status: true, Message: "Authorization URL created"}
+	response.Data.AuthorizationURL = "https://checkout.paystack.test/" + req.Reference
+	response.Data.AccessCode = "fake_" + req.Reference
+	response.Data.Reference = req.Reference
+	return response, nil
+}
+
+func (g *FakeGateway) VerifyTransaction(reference string) (*VerifyResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nextID++
+
+	response := &VerifyResponse{Status: true, Message: "Verification successful"}
+	response.Data.ID = int64(g.nextID)
+	response.Data.Status = "success"
+	response.Data.Reference = reference
+	response.Data.Currency = "ZAR"
+	return response, nil
+}
+
+func (g *FakeGateway) CreateTransferRecipient(req *TransferRecipientRequest) (*TransferRecipientResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nextID++
+
+	code := fmt.Sprintf("RCP_fake%d", g.nextID)
+	g.recipients[code] = req.AccountNumber
+
+	response := &TransferRecipientResponse{Status: true, Message: "Transfer recipient created successfully"}
+	response.Data.RecipientCode = code
+	response.Data.Name = req.Name
+	return response, nil
+}
+
+func (g *FakeGateway) InitiateTransfer(req *TransferRequest) (*TransferResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	accountNumber, ok := g.recipients[req.Recipient]
+	if !ok {
+		return nil, fmt.Errorf("paystack error: recipient %s not found", req.Recipient)
+	}
+	if _, exists := g.transfers[req.Reference]; exists {
+		return nil, fmt.Errorf("paystack error: duplicate transfer reference %s", req.Reference)
+	}
+
+	g.nextID++
+	status := g.transferOutcome
+	reason := ""
+	if strings.HasPrefix(accountNumber, "999") {
+		status = TransferStatusFailed
+		reason = "Account could not be credited"
+	}
+
+	transfer := &TransferData{
+		TransferCode: fmt.Sprintf("TRF_fake%d", g.nextID),
+		Reference:    req.Reference,
+		Status:       status,
+		Amount:       req.Amount,
+		Reason:       reason,
+	}
+	g.transfers[req.Reference] = transfer
+
+	return &TransferResponse{Status: true, Message: "Transfer has been queued", Data: *transfer}, nil
+}
+
+func (g *FakeGateway) VerifyTransfer(reference string) (*TransferResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	transfer, ok := g.transfers[reference]
+	if !ok {
+		return &TransferResponse{Status: false, Message: "Transfer not found"}, nil
+	}
+
+	return &TransferResponse{Status: true, Message: "Transfer retrieved", Data: *transfer}, nil
+}
+
+func (g *FakeGateway) CreateRefund(req *RefundRequest) (*RefundResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nextID++
+	refund := RefundData{
+		ID:     int64(g.nextID),
+		Amount: req.Amount,
+		Status: RefundStatusPending,
+	}
+	g.refunds[req.Transaction] = append(g.refunds[req.Transaction], refund)
+	return &RefundResponse{
+		Status:  true,
+		Message: "Refund has been queued for processing",
+		Data:    refund,
+	}, nil
+}
+
+func (g *FakeGateway) ListRefunds(transactionID string) (*RefundListResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return &RefundListResponse{Status: true, Message: "Refunds retrieved", Data: g.refunds[transactionID]}, nil
+}
+
+// VerifyWebhookSignature accepts every webhook since there is no secret key
+// to sign with.
+func (g *FakeGateway) VerifyWebhookSignature(body []byte, signature string) bool {
+	return true
+}
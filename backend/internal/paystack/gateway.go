@@ -0,0 +1,14 @@
+package paystack
+
+// Gateway is the subset of the Paystack API CareWallet uses. Client talks to
+// Paystack; FakeGateway stands in for it when no secret key is configured.
+type Gateway interface {
+	InitializeTransaction(req *InitializeRequest) (*InitializeResponse, error)
+	VerifyTransaction(reference string) (*VerifyResponse, error)
+	CreateTransferRecipient(req *TransferRecipientRequest) (*TransferRecipientResponse, error)
+	InitiateTransfer(req *TransferRequest) (*TransferResponse, error)
+	VerifyTransfer(reference string) (*TransferResponse, error)
+	CreateRefund(req *RefundRequest) (*RefundResponse, error)
+	ListRefunds(transactionID string) (*RefundListResponse, error)
+	VerifyWebhookSignature(body []byte, signature string) bool
+}
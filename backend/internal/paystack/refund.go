@@ -0,0 +1,81 @@
+package paystack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Refund statuses reported by Paystack. A refund Paystack accepts starts out
+// pending and is processed onto the contributor's card or account later.
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusProcessed = "processed"
+	RefundStatusFailed    = "failed"
+)
+
+type RefundRequest struct {
+	Transaction  string `json:"transaction"` // Reference of the original payment
+	Amount       int64  `json:"amount"`      // Amount in cents; a partial refund when less than the payment
+	Currency     string `json:"currency"`
+	MerchantNote string `json:"merchant_note,omitempty"`
+}
+
+type RefundData struct {
+	ID int64 `json:"id"`
+	// Transaction is the Paystack ID of the refunded payment.
+	Transaction int64  `json:"transaction"`
+	Amount      int64  `json:"amount"`
+	Status      string `json:"status"`
+}
+
+type RefundResponse struct {
+	Status  bool       `json:"status"`
+	Message string     `json:"message"`
+	Data    RefundData `json:"data"`
+}
+
+func (c *Client) CreateRefund(req *RefundRequest) (*RefundResponse, error) {
+	respBody, err := c.doRequest("POST", "/refund", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response RefundResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, err
+	}
+
+	if !response.Status {
+		return nil, fmt.Errorf("paystack error: %s", response.Message)
+	}
+
+	return &response, nil
+}
+
+type RefundListResponse struct {
+	Status  bool         `json:"status"`
+	Message string       `json:"message"`
+	Data    []RefundData `json:"data"`
+}
+
+// ListRefunds returns the refunds made against a payment, given the payment's
+// Paystack ID. It lets a refund whose response was lost be found before it is
+// sent again.
+func (c *Client) ListRefunds(transactionID string) (*RefundListResponse, error) {
+	respBody, err := c.doRequest("GET", "/refund?transaction="+url.QueryEscape(transactionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response RefundListResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, err
+	}
+
+	if !response.Status {
+		return nil, fmt.Errorf("paystack error: %s", response.Message)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,74 @@
+// Package qr renders QR codes for wallets and signs the wallet vouchers
+// pharmacies scan at the counter.
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 1024
+)
+
+var ErrUnsupportedFormat = errors.New("QR code format must be png or svg")
+
+func (f Format) ContentType() string {
+	if f == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Encode renders content as a QR code in the given format. size is the width
+// and height in pixels and is clamped to MinSize..MaxSize.
+func Encode(content string, format Format, size int) ([]byte, error) {
+	if size < MinSize {
+		size = MinSize
+	}
+	if size > MaxSize {
+		size = MaxSize
+	}
+
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatPNG:
+		return code.PNG(size)
+	case FormatSVG:
+		return svg(code.Bitmap(), size), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// svg draws each dark module as a unit square; the bitmap already includes
+// the quiet zone around the code.
+func svg(bitmap [][]bool, size int) []byte {
+	n := len(bitmap)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, n, n)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, n, n)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.Bytes()
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+)
+
+type auditLogRepository struct {
+	db *database.PostgresDB
+}
+
+func NewAuditLogRepository(db *database.PostgresDB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_logs (actor_type, actor_id, action, entity_type, entity_id, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		entry.ActorType,
+		entry.ActorID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		metadata,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *auditLogRepository) List(ctx context.Context, entityType, entityID string, page, pageSize int) ([]*domain.AuditLog, int, error) {
+	filter := `WHERE ($1 = '' OR entity_type = $1) AND ($2 = '' OR entity_id::text = $2)`
+
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM audit_logs `+filter, entityType, entityID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	query := `
+		SELECT id, actor_type, actor_id, action, entity_type, entity_id, metadata, created_at
+		FROM audit_logs ` + filter + `
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Pool.Query(ctx, query, entityType, entityID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditLog
+	for rows.Next() {
+		entry := &domain.AuditLog{}
+		var metadata []byte
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorType,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&metadata,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &entry.Metadata); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type bankAccountRepository struct {
+	db *database.PostgresDB
+}
+
+func NewBankAccountRepository(db *database.PostgresDB) BankAccountRepository {
+	return &bankAccountRepository{db: db}
+}
+
+const bankAccountColumns = `id, pharmacy_id, bank_name, COALESCE(bank_code, ''), branch_code, account_number, account_holder, status, verification_status, COALESCE(verified_name, ''), verified_at, reviewed_by, reviewed_at, COALESCE(rejection_reason, ''), effective_at, COALESCE(recipient_code, ''), created_at, updated_at`
+
+func scanBankAccount(row pgx.Row) (*domain.PharmacyBankAccount, error) {
+	a := &domain.PharmacyBankAccount{}
+	err := row.Scan(
+		&a.ID,
+		&a.PharmacyID,
+		&a.BankName,
+		&a.BankCode,
+		&a.BranchCode,
+		&a.AccountNumber,
+		&a.AccountHolder,
+		&a.Status,
+		&a.VerificationStatus,
+		&a.VerifiedName,
+		&a.VerifiedAt,
+		&a.ReviewedBy,
+		&a.ReviewedAt,
+		&a.RejectionReason,
+		&a.EffectiveAt,
+		&a.RecipientCode,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *bankAccountRepository) queryList(ctx context.Context, query string, args ...any) ([]*domain.PharmacyBankAccount, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*domain.PharmacyBankAccount
+	for rows.Next() {
+		account, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+func (r *bankAccountRepository) Create(ctx context.Context, account *domain.PharmacyBankAccount) error {
+	query := `
+		INSERT INTO pharmacy_bank_accounts (pharmacy_id, bank_name, bank_code, branch_code, account_number, account_holder, status, verification_status, verified_name, verified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		account.PharmacyID,
+		account.BankName,
+		account.BankCode,
+		account.BranchCode,
+		account.AccountNumber,
+		account.AccountHolder,
+		account.Status,
+		account.VerificationStatus,
+		account.VerifiedName,
+		account.VerifiedAt,
+	).Scan(&account.ID, &account.CreatedAt, &account.UpdatedAt)
+}
+
+func (r *bankAccountRepository) GetByID(ctx context.Context, id string) (*domain.PharmacyBankAccount, error) {
+	query := `SELECT ` + bankAccountColumns + ` FROM pharmacy_bank_accounts WHERE id = $1`
+
+	account, err := scanBankAccount(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrBankAccountNotFound
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (r *bankAccountRepository) GetByPharmacyID(ctx context.Context, pharmacyID string) ([]*domain.PharmacyBankAccount, error) {
+	query := `
+		SELECT ` + bankAccountColumns + `
+		FROM pharmacy_bank_accounts
+		WHERE pharmacy_id = $1
+		ORDER BY created_at DESC`
+
+	return r.queryList(ctx, query, pharmacyID)
+}
+
+func (r *bankAccountRepository) GetByStatus(ctx context.Context, status domain.BankAccountStatus) ([]*domain.PharmacyBankAccount, error) {
+	query := `
+		SELECT ` + bankAccountColumns + `
+		FROM pharmacy_bank_accounts
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at`
+
+	return r.queryList(ctx, query, string(status))
+}
+
+func (r *bankAccountRepository) GetEffective(ctx context.Context, pharmacyID string) (*domain.PharmacyBankAccount, error) {
+	query := `
+		SELECT ` + bankAccountColumns + `
+		FROM pharmacy_bank_accounts
+		WHERE pharmacy_id = $1 AND status = 'approved' AND effective_at <= NOW()
+		ORDER BY effective_at DESC
+		LIMIT 1`
+
+	account, err := scanBankAccount(r.db.Pool.QueryRow(ctx, query, pharmacyID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNoActiveBankAccount
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (r *bankAccountRepository) CancelPending(ctx context.Context, pharmacyID string) error {
+	query := `
+		UPDATE pharmacy_bank_accounts
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE pharmacy_id = $1 AND status = 'pending'`
+
+	_, err := r.db.Pool.Exec(ctx, query, pharmacyID)
+	return err
+}
+
+func (r *bankAccountRepository) UpdateVerification(ctx context.Context, account *domain.PharmacyBankAccount) error {
+	query := `
+		UPDATE pharmacy_bank_accounts
+		SET verification_status = $1, verified_name = $2, verified_at = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		account.VerificationStatus,
+		account.VerifiedName,
+		account.VerifiedAt,
+		account.ID,
+	).Scan(&account.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrBankAccountNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *bankAccountRepository) Review(ctx context.Context, account *domain.PharmacyBankAccount) error {
+	query := `
+		UPDATE pharmacy_bank_accounts
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, rejection_reason = $4, effective_at = $5, updated_at = NOW()
+		WHERE id = $6 AND status = 'pending'
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		account.Status,
+		account.ReviewedBy,
+		account.ReviewedAt,
+		account.RejectionReason,
+		account.EffectiveAt,
+		account.ID,
+	).Scan(&account.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrBankAccountNotPending
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *bankAccountRepository) SetRecipientCode(ctx context.Context, account *domain.PharmacyBankAccount) error {
+	query := `
+		UPDATE pharmacy_bank_accounts
+		SET recipient_code = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query, account.RecipientCode, account.ID).Scan(&account.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrBankAccountNotFound
+		}
+		return err
+	}
+
+	return nil
+}
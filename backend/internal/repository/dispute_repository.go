@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type disputeRepository struct {
+	db *database.PostgresDB
+}
+
+func NewDisputeRepository(db *database.PostgresDB) DisputeRepository {
+	return &disputeRepository{db: db}
+}
+
+const disputeColumns = `id, transaction_id, wallet_id, pharmacy_id, opened_by, reason, status, COALESCE(pharmacy_response, ''), evidence_upload_ids::text[], responded_by, responded_at, resolved_by, resolved_at, COALESCE(resolution_note, ''), reversal_transaction_id, created_at, updated_at`
+
+func scanDispute(row pgx.Row) (*domain.Dispute, error) {
+	d := &domain.Dispute{}
+	err := row.Scan(
+		&d.ID,
+		&d.TransactionID,
+		&d.WalletID,
+		&d.PharmacyID,
+		&d.OpenedBy,
+		&d.Reason,
+		&d.Status,
+		&d.PharmacyResponse,
+		&d.EvidenceUploadIDs,
+		&d.RespondedBy,
+		&d.RespondedAt,
+		&d.ResolvedBy,
+		&d.ResolvedAt,
+		&d.ResolutionNote,
+		&d.ReversalTransactionID,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if d.EvidenceUploadIDs == nil {
+		d.EvidenceUploadIDs = []string{}
+	}
+	return d, nil
+}
+
+func (r *disputeRepository) Create(ctx context.Context, dispute *domain.Dispute) error {
+	query := `
+		INSERT INTO disputes (transaction_id, wallet_id, pharmacy_id, opened_by, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		dispute.TransactionID,
+		dispute.WalletID,
+		dispute.PharmacyID,
+		dispute.OpenedBy,
+		dispute.Reason,
+		dispute.Status,
+	).Scan(&dispute.ID, &dispute.CreatedAt, &dispute.UpdatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDisputeExists
+		}
+		return err
+	}
+
+	dispute.EvidenceUploadIDs = []string{}
+	return nil
+}
+
+func (r *disputeRepository) GetByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	query := `SELECT ` + disputeColumns + ` FROM disputes WHERE id = $1`
+
+	dispute, err := scanDispute(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (r *disputeRepository) List(ctx context.Context, filter DisputeFilter) ([]*domain.Dispute, int, error) {
+	where := `
+		WHERE ($1 = '' OR status = $1)
+		AND ($2 = '' OR pharmacy_id::text = $2)
+		AND ($3 = '' OR wallet_id IN (
+			SELECT id FROM wallets WHERE creator_id::text = $3 OR beneficiary_id::text = $3
+		))`
+
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM disputes `+where, string(filter.Status), filter.PharmacyID, filter.UserID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := `
+		SELECT ` + disputeColumns + `
+		FROM disputes ` + where + `
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Pool.Query(ctx, query, string(filter.Status), filter.PharmacyID, filter.UserID, filter.PageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var disputes []*domain.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	return disputes, total, nil
+}
+
+func (r *disputeRepository) Respond(ctx context.Context, dispute *domain.Dispute) error {
+	query := `
+		UPDATE disputes
+		SET status = $1, pharmacy_response = $2, evidence_upload_ids = $3::uuid[],
+			responded_by = $4, responded_at = $5, updated_at = NOW()
+		WHERE id = $6 AND status IN ($7, $1)
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		domain.DisputeStatusResponded,
+		dispute.PharmacyResponse,
+		dispute.EvidenceUploadIDs,
+		dispute.RespondedBy,
+		dispute.RespondedAt,
+		dispute.ID,
+		domain.DisputeStatusOpen,
+	).Scan(&dispute.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrDisputeResolved
+		}
+		return err
+	}
+
+	dispute.Status = domain.DisputeStatusResponded
+	return nil
+}
+
+func (r *disputeRepository) Uphold(ctx context.Context, dispute *domain.Dispute, original, reversal *domain.Transaction) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Only an unresolved dispute can be upheld; this guards against reversing
+	// the withdrawal twice when two admins act concurrently.
+	result, err := tx.Exec(ctx, `
+		UPDATE disputes
+		SET status = $1, resolved_by = $2, resolved_at = $3, resolution_note = $4, updated_at = NOW()
+		WHERE id = $5 AND status IN ($6, $7)`,
+		domain.DisputeStatusUpheld,
+		dispute.ResolvedBy,
+		dispute.ResolvedAt,
+		dispute.ResolutionNote,
+		dispute.ID,
+		domain.DisputeStatusOpen,
+		domain.DisputeStatusResponded,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDisputeResolved
+	}
+
+	if err := reverseTransaction(ctx, tx, original, reversal, true); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE disputes
+		SET reversal_transaction_id = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING updated_at`,
+		reversal.ID,
+		dispute.ID,
+	).Scan(&dispute.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	original.Status = domain.TransactionStatusReversed
+	dispute.Status = domain.DisputeStatusUpheld
+	dispute.ReversalTransactionID = &reversal.ID
+	return nil
+}
+
+func (r *disputeRepository) Reject(ctx context.Context, dispute *domain.Dispute) error {
+	query := `
+		UPDATE disputes
+		SET status = $1, resolved_by = $2, resolved_at = $3, resolution_note = $4, updated_at = NOW()
+		WHERE id = $5 AND status IN ($6, $7)
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		domain.DisputeStatusRejected,
+		dispute.ResolvedBy,
+		dispute.ResolvedAt,
+		dispute.ResolutionNote,
+		dispute.ID,
+		domain.DisputeStatusOpen,
+		domain.DisputeStatusResponded,
+	).Scan(&dispute.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrDisputeResolved
+		}
+		return err
+	}
+
+	dispute.Status = domain.DisputeStatusRejected
+	return nil
+}
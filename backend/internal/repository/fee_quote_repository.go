@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type feeQuoteRepository struct {
+	db *database.PostgresDB
+}
+
+func NewFeeQuoteRepository(db *database.PostgresDB) FeeQuoteRepository {
+	return &feeQuoteRepository{db: db}
+}
+
+const feeQuoteColumns = `id, wallet_id, pharmacy_id, amount, fee, net_amount, fee_rule_id, expires_at, redeemed_at, created_at`
+
+func scanFeeQuote(row pgx.Row) (*domain.FeeQuote, error) {
+	quote := &domain.FeeQuote{}
+	err := row.Scan(
+		&quote.ID,
+		&quote.WalletID,
+		&quote.PharmacyID,
+		&quote.Amount,
+		&quote.Fee,
+		&quote.NetAmount,
+		&quote.FeeRuleID,
+		&quote.ExpiresAt,
+		&quote.RedeemedAt,
+		&quote.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+func (r *feeQuoteRepository) Create(ctx context.Context, quote *domain.FeeQuote) error {
+	query := `
+		INSERT INTO fee_quotes (wallet_id, pharmacy_id, amount, fee, net_amount, fee_rule_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		quote.WalletID,
+		quote.PharmacyID,
+		quote.Amount,
+		quote.Fee,
+		quote.NetAmount,
+		quote.FeeRuleID,
+		quote.ExpiresAt,
+	).Scan(&quote.ID, &quote.CreatedAt)
+}
+
+func (r *feeQuoteRepository) GetByID(ctx context.Context, id string) (*domain.FeeQuote, error) {
+	query := `SELECT ` + feeQuoteColumns + ` FROM fee_quotes WHERE id = $1`
+
+	quote, err := scanFeeQuote(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrFeeQuoteNotFound
+		}
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// redeemFeeQuote marks the quote used by a withdrawal that claimed it at
+// claimedAt, as part of the database transaction recording the withdrawal.
+// It fails with ErrFeeQuoteExpired if the quote had expired by then or was
+// already redeemed.
+func redeemFeeQuote(ctx context.Context, q queryRower, quoteID string, claimedAt time.Time) error {
+	query := `
+		UPDATE fee_quotes
+		SET redeemed_at = NOW()
+		WHERE id = $1 AND redeemed_at IS NULL AND expires_at > $2
+		RETURNING id`
+
+	var id string
+	err := q.QueryRow(ctx, query, quoteID, claimedAt).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrFeeQuoteExpired
+		}
+		return err
+	}
+
+	return nil
+}
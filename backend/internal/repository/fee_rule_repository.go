@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type feeRuleRepository struct {
+	db *database.PostgresDB
+}
+
+func NewFeeRuleRepository(db *database.PostgresDB) FeeRuleRepository {
+	return &feeRuleRepository{db: db}
+}
+
+const feeRuleColumns = `id, code, version, name, transaction_type, pharmacy_id, organisation_id, percentage, flat_amount, min_fee, max_fee, is_waiver, contributor_pays, starts_at, ends_at, created_by, superseded_at, superseded_by, created_at`
+
+func scanFeeRule(row pgx.Row) (*domain.FeeRule, error) {
+	rule := &domain.FeeRule{}
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Code,
+		&rule.Version,
+		&rule.Name,
+		&rule.TransactionType,
+		&rule.PharmacyID,
+		&rule.OrganisationID,
+		&rule.Percentage,
+		&rule.FlatAmount,
+		&rule.MinFee,
+		&rule.MaxFee,
+		&rule.IsWaiver,
+		&rule.ContributorPays,
+		&rule.StartsAt,
+		&rule.EndsAt,
+		&rule.CreatedBy,
+		&rule.SupersededAt,
+		&rule.SupersededBy,
+		&rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+const insertFeeRuleQuery = `
+	INSERT INTO fee_rules (code, version, name, transaction_type, pharmacy_id, organisation_id, percentage, flat_amount, min_fee, max_fee, is_waiver, contributor_pays, starts_at, ends_at, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	RETURNING id, created_at`
+
+func insertFeeRule(ctx context.Context, q queryRower, rule *domain.FeeRule) error {
+	err := q.QueryRow(ctx, insertFeeRuleQuery,
+		rule.Code,
+		rule.Version,
+		rule.Name,
+		rule.TransactionType,
+		rule.PharmacyID,
+		rule.OrganisationID,
+		rule.Percentage,
+		rule.FlatAmount,
+		rule.MinFee,
+		rule.MaxFee,
+		rule.IsWaiver,
+		rule.ContributorPays,
+		rule.StartsAt,
+		rule.EndsAt,
+		rule.CreatedBy,
+	).Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrFeeRuleCodeTaken
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *feeRuleRepository) Create(ctx context.Context, rule *domain.FeeRule) error {
+	rule.Version = 1
+	return insertFeeRule(ctx, r.db.Pool, rule)
+}
+
+func (r *feeRuleRepository) GetByID(ctx context.Context, id string) (*domain.FeeRule, error) {
+	query := `SELECT ` + feeRuleColumns + ` FROM fee_rules WHERE id = $1`
+
+	rule, err := scanFeeRule(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrFeeRuleNotFound
+		}
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (r *feeRuleRepository) List(ctx context.Context, filter FeeRuleFilter) ([]*domain.FeeRule, error) {
+	query := `
+		SELECT ` + feeRuleColumns + `
+		FROM fee_rules
+		WHERE ($1 = '' OR transaction_type = $1)
+		AND ($2 = '' OR pharmacy_id::text = $2)
+		AND ($3 = '' OR organisation_id::text = $3)
+		AND ($4 OR superseded_at IS NULL)
+		ORDER BY code, version DESC`
+
+	return r.query(ctx, query, string(filter.TransactionType), filter.PharmacyID, filter.OrganisationID, filter.IncludeSuperseded)
+}
+
+func (r *feeRuleRepository) GetVersions(ctx context.Context, code string) ([]*domain.FeeRule, error) {
+	query := `SELECT ` + feeRuleColumns + ` FROM fee_rules WHERE code = $1 ORDER BY version DESC`
+	return r.query(ctx, query, code)
+}
+
+func (r *feeRuleRepository) GetApplicable(ctx context.Context, txType domain.TransactionType, pharmacyID, organisationID string, at time.Time) ([]*domain.FeeRule, error) {
+	query := `
+		SELECT ` + feeRuleColumns + `
+		FROM fee_rules
+		WHERE superseded_at IS NULL
+		AND transaction_type = $1
+		AND starts_at <= $4
+		AND (ends_at IS NULL OR ends_at > $4)
+		AND (
+			(pharmacy_id IS NULL AND organisation_id IS NULL)
+			OR ($2 <> '' AND pharmacy_id::text = $2)
+			OR ($3 <> '' AND organisation_id::text = $3)
+		)`
+
+	return r.query(ctx, query, string(txType), pharmacyID, organisationID, at)
+}
+
+func (r *feeRuleRepository) query(ctx context.Context, query string, args ...any) ([]*domain.FeeRule, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.FeeRule
+	for rows.Next() {
+		rule, err := scanFeeRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *feeRuleRepository) Supersede(ctx context.Context, current, next *domain.FeeRule) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		UPDATE fee_rules
+		SET superseded_at = NOW()
+		WHERE id = $1 AND superseded_at IS NULL
+		RETURNING version, superseded_at`,
+		current.ID,
+	).Scan(&current.Version, &current.SupersededAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrFeeRuleSuperseded
+		}
+		return err
+	}
+
+	next.Code = current.Code
+	next.Version = current.Version + 1
+	if err := insertFeeRule(ctx, tx, next); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE fee_rules SET superseded_by = $1 WHERE id = $2`, next.ID, current.ID); err != nil {
+		return err
+	}
+	current.SupersededBy = &next.ID
+
+	return tx.Commit(ctx)
+}
+
+func (r *feeRuleRepository) Retire(ctx context.Context, rule *domain.FeeRule) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE fee_rules
+		SET superseded_at = NOW()
+		WHERE id = $1 AND superseded_at IS NULL
+		RETURNING superseded_at`,
+		rule.ID,
+	).Scan(&rule.SupersededAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrFeeRuleSuperseded
+		}
+		return err
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/carewallet/backend/internal/domain"
+	"github.com/shopspring/decimal"
 )
 
 type UserRepository interface {
@@ -18,12 +19,28 @@ type UserRepository interface {
 type WalletRepository interface {
 	Create(ctx context.Context, wallet *domain.Wallet) error
 	GetByID(ctx context.Context, id string) (*domain.Wallet, error)
+	// GetByShareableCode finds only usable (active, paused or closing)
+	// wallets; closed and deleted wallets are reported as not found.
 	GetByShareableCode(ctx context.Context, code string) (*domain.Wallet, error)
 	GetByUserID(ctx context.Context, userID string) ([]*domain.Wallet, error)
 	Update(ctx context.Context, wallet *domain.Wallet) error
-	Delete(ctx context.Context, id string) error
+	// ChangeStatus moves the wallet to change.ToStatus and records the change
+	// in its status history. wallet.FrozenFrom is saved with it.
+	ChangeStatus(ctx context.Context, wallet *domain.Wallet, change *domain.WalletStatusChange) error
+	// SoftDelete changes the wallet's status like ChangeStatus and hides it
+	// from its members' wallet lists; its transactions are kept.
+	SoftDelete(ctx context.Context, wallet *domain.Wallet, change *domain.WalletStatusChange) error
+	GetStatusHistory(ctx context.Context, walletID string) ([]*domain.WalletStatusChange, error)
 	UpdateBalance(ctx context.Context, id string, amount string) error
+	// ShareableCodeExists reports whether the code is or was ever a wallet's
+	// shareable code, so retired codes are never handed out again.
 	ShareableCodeExists(ctx context.Context, code string) (bool, error)
+	// GetByRetiredCode finds the active wallet that used the code before its
+	// code was rotated.
+	GetByRetiredCode(ctx context.Context, code string) (*domain.Wallet, error)
+	// RotateShareableCode gives the wallet a new shareable code and retires
+	// the old one.
+	RotateShareableCode(ctx context.Context, wallet *domain.Wallet, newCode, userID string) error
 }
 
 type TransactionRepository interface {
@@ -31,6 +48,56 @@ type TransactionRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.Transaction, error)
 	GetByWalletID(ctx context.Context, walletID string, page, pageSize int) ([]*domain.Transaction, int, error)
 	Update(ctx context.Context, transaction *domain.Transaction) error
+	// CreateWithdrawal records a withdrawal, debits the wallet and redeems its
+	// fee quote in a single database transaction, failing with
+	// ErrWalletNotSpendable or ErrInsufficientBalance if the wallet changed
+	// since it was read and ErrFeeQuoteExpired if the quote was used.
+	CreateWithdrawal(ctx context.Context, transaction *domain.Transaction) error
+	// Reverse marks the original transaction reversed, records the reversal
+	// and credits the reversal's amount to the wallet in a single database
+	// transaction. It fails with ErrTransactionSettled once the original has
+	// been settled and ErrTransactionReversed if it already was.
+	Reverse(ctx context.Context, original, reversal *domain.Transaction) error
+	// CreateCashIn records a pharmacy cash deposit and credits the wallet,
+	// failing with ErrCashInLimitExceeded if the pharmacy's cash-ins since the
+	// given time would exceed dailyLimit.
+	CreateCashIn(ctx context.Context, transaction *domain.Transaction, since time.Time, dailyLimit decimal.Decimal) error
+	SumCashIns(ctx context.Context, pharmacyID string, since time.Time) (decimal.Decimal, int, error)
+	SumUnsettledCashIns(ctx context.Context, pharmacyID string) (decimal.Decimal, error)
+	GetBySettlementID(ctx context.Context, settlementID string) ([]*domain.Transaction, error)
+	// GetByOrganisationID lists transactions made at any of the organisation's
+	// branches, or at just one branch when pharmacyID is set.
+	GetByOrganisationID(ctx context.Context, organisationID, pharmacyID string, page, pageSize int) ([]*domain.Transaction, int, error)
+	GetByPharmacyID(ctx context.Context, filter PharmacyTransactionFilter) ([]*domain.Transaction, int, error)
+	// SummariseByCashier totals the pharmacy's transactions created in
+	// [from, to) per staff member.
+	SummariseByCashier(ctx context.Context, pharmacyID string, from, to time.Time) ([]*domain.CashierSummary, error)
+}
+
+// PharmacyTransactionFilter selects a pharmacy's transactions. Empty fields
+// and nil times are not filtered on; To is exclusive.
+type PharmacyTransactionFilter struct {
+	PharmacyID     string
+	Type           domain.TransactionType
+	Status         domain.TransactionStatus
+	PharmacyUserID string
+	From           *time.Time
+	To             *time.Time
+	Page           int
+	PageSize       int
+}
+
+type NearbyPharmacyFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	// Service limits results to pharmacies offering it.
+	Service string
+	// OpenNow limits results to pharmacies open at the current time in
+	// Timezone.
+	OpenNow  bool
+	Timezone string
+	Limit    int
 }
 
 type PharmacyRepository interface {
@@ -38,7 +105,23 @@ type PharmacyRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.Pharmacy, error)
 	GetByShortCode(ctx context.Context, code string) (*domain.Pharmacy, error)
 	GetAll(ctx context.Context) ([]*domain.Pharmacy, error)
+	GetByOrganisationID(ctx context.Context, organisationID string) ([]*domain.Pharmacy, error)
 	Update(ctx context.Context, pharmacy *domain.Pharmacy) error
+	// SetOrganisation moves the pharmacy into pharmacy.OrganisationID, or out
+	// of its chain when that is nil.
+	SetOrganisation(ctx context.Context, pharmacy *domain.Pharmacy) error
+	// UpdateListing saves the pharmacy's location and services and replaces
+	// its opening hours.
+	UpdateListing(ctx context.Context, pharmacy *domain.Pharmacy, hours []*domain.PharmacyOpeningHours) error
+	GetOpeningHours(ctx context.Context, pharmacyIDs ...string) ([]*domain.PharmacyOpeningHours, error)
+	// FindNearby returns active pharmacies within filter.RadiusKm of the
+	// point, nearest first.
+	FindNearby(ctx context.Context, filter NearbyPharmacyFilter) ([]*domain.NearbyPharmacy, error)
+	// CreateApplication and UpdateApplication save a self-registration
+	// together with its supporting documents, replacing any earlier documents.
+	CreateApplication(ctx context.Context, pharmacy *domain.Pharmacy, documents []*domain.PharmacyDocument) error
+	UpdateApplication(ctx context.Context, pharmacy *domain.Pharmacy, documents []*domain.PharmacyDocument) error
+	GetDocuments(ctx context.Context, pharmacyID string) ([]*domain.PharmacyDocument, error)
 }
 
 type OTPRepository interface {
@@ -53,3 +136,297 @@ type TokenBlacklistRepository interface {
 	Exists(ctx context.Context, jti string) (bool, error)
 	DeleteExpired(ctx context.Context) error
 }
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *domain.AuditLog) error
+	List(ctx context.Context, entityType, entityID string, page, pageSize int) ([]*domain.AuditLog, int, error)
+}
+
+type ManualCreditRepository interface {
+	Create(ctx context.Context, credit *domain.ManualCredit) error
+	GetByID(ctx context.Context, id string) (*domain.ManualCredit, error)
+	GetByStatus(ctx context.Context, status domain.ManualCreditStatus) ([]*domain.ManualCredit, error)
+	// Approve marks a pending credit as approved, records the transaction and
+	// credits the wallet in a single database transaction.
+	Approve(ctx context.Context, credit *domain.ManualCredit, transaction *domain.Transaction) error
+	Reject(ctx context.Context, credit *domain.ManualCredit) error
+}
+
+type SettlementFilter struct {
+	PharmacyID     string
+	OrganisationID string
+	Status         domain.SettlementStatus
+	Page           int
+	PageSize       int
+}
+
+type SettlementRepository interface {
+	// CreateBatch assigns the pharmacy's unsettled completed withdrawals,
+	// cash-ins and reversals of settled withdrawals created before cutoff to a
+	// new settlement. It returns nil when there is nothing to settle.
+	CreateBatch(ctx context.Context, pharmacyID string, cutoff time.Time) (*domain.Settlement, error)
+	GetPharmacyIDsWithUnsettled(ctx context.Context, cutoff time.Time) ([]string, error)
+	GetByID(ctx context.Context, id string) (*domain.Settlement, error)
+	List(ctx context.Context, filter SettlementFilter) ([]*domain.Settlement, int, error)
+	// SummariseByOrganisation totals the settlements of each of the
+	// organisation's branches whose period ends after from and up to to.
+	// Branches without settlements are included with zero totals.
+	SummariseByOrganisation(ctx context.Context, organisationID string, from, to time.Time) ([]*domain.BranchSettlementSummary, error)
+	// MarkPaid records a settlement paid by hand. Only unpaid, approved or
+	// failed settlements qualify; it fails with ErrSettlementAlreadyPaid or,
+	// while a transfer is processing, ErrPayoutInProgress.
+	MarkPaid(ctx context.Context, settlement *domain.Settlement) error
+	GetByTransferReference(ctx context.Context, reference string) (*domain.Settlement, error)
+	// GetRetryable returns approved settlements whose payout has not started and
+	// failed payouts with fewer than maxAttempts attempts.
+	GetRetryable(ctx context.Context, maxAttempts int) ([]*domain.Settlement, error)
+	// GetUnconfirmed returns processing settlements last changed before
+	// before: transfers Paystack never confirmed receiving, and accepted
+	// transfers whose outcome never arrived by webhook.
+	GetUnconfirmed(ctx context.Context, before time.Time) ([]*domain.Settlement, error)
+	Approve(ctx context.Context, settlement *domain.Settlement) error
+	// StartPayout moves an approved or failed settlement to processing and
+	// assigns a fresh transfer reference for the new attempt.
+	StartPayout(ctx context.Context, settlement *domain.Settlement) error
+	SetTransferCode(ctx context.Context, settlement *domain.Settlement) error
+	CompletePayout(ctx context.Context, settlement *domain.Settlement) error
+	FailPayout(ctx context.Context, settlement *domain.Settlement) error
+}
+
+type BankAccountRepository interface {
+	Create(ctx context.Context, account *domain.PharmacyBankAccount) error
+	GetByID(ctx context.Context, id string) (*domain.PharmacyBankAccount, error)
+	GetByPharmacyID(ctx context.Context, pharmacyID string) ([]*domain.PharmacyBankAccount, error)
+	GetByStatus(ctx context.Context, status domain.BankAccountStatus) ([]*domain.PharmacyBankAccount, error)
+	// GetEffective returns the most recently approved account whose cooling-off period has passed.
+	GetEffective(ctx context.Context, pharmacyID string) (*domain.PharmacyBankAccount, error)
+	CancelPending(ctx context.Context, pharmacyID string) error
+	UpdateVerification(ctx context.Context, account *domain.PharmacyBankAccount) error
+	// Review moves a pending account to approved or rejected.
+	Review(ctx context.Context, account *domain.PharmacyBankAccount) error
+	SetRecipientCode(ctx context.Context, account *domain.PharmacyBankAccount) error
+}
+
+type PharmacyUserRepository interface {
+	Create(ctx context.Context, user *domain.PharmacyUser) error
+	GetByID(ctx context.Context, id string) (*domain.PharmacyUser, error)
+	GetByUsername(ctx context.Context, pharmacyID, username string) (*domain.PharmacyUser, error)
+	GetByPharmacyID(ctx context.Context, pharmacyID string) ([]*domain.PharmacyUser, error)
+	Update(ctx context.Context, user *domain.PharmacyUser) error
+	UpdateLastLogin(ctx context.Context, id string) error
+	CountActiveManagers(ctx context.Context, pharmacyID string) (int, error)
+}
+
+type PharmacyWithdrawalRepository interface {
+	Create(ctx context.Context, withdrawal *domain.PharmacyWithdrawal) error
+	GetByID(ctx context.Context, id string) (*domain.PharmacyWithdrawal, error)
+	// Complete records the withdrawal transaction with its line items, debits
+	// the wallet, redeems its fee quote and marks the withdrawal completed in
+	// a single database transaction.
+	Complete(ctx context.Context, withdrawal *domain.PharmacyWithdrawal, transaction *domain.Transaction) error
+}
+
+type PasswordSetupTokenRepository interface {
+	// Create stores a new token and invalidates the user's earlier unused ones.
+	Create(ctx context.Context, token *domain.PasswordSetupToken) error
+	// Consume sets the staff member's password and marks the token used,
+	// failing with ErrSetupTokenInvalid or ErrSetupTokenExpired.
+	Consume(ctx context.Context, tokenHash, passwordHash string) (*domain.PasswordSetupToken, error)
+}
+
+type UploadRepository interface {
+	Create(ctx context.Context, upload *domain.Upload) error
+	GetByID(ctx context.Context, id string) (*domain.Upload, error)
+	// SetOwner hands an upload to a new owner, e.g. when a pharmacy
+	// registration claims its applicant's uploads.
+	SetOwner(ctx context.Context, id string, ownerType domain.UploadOwnerType, ownerID string) error
+	// GetUnclaimed returns applicant uploads made before the cutoff that no
+	// registration has claimed or attached.
+	GetUnclaimed(ctx context.Context, before time.Time) ([]*domain.Upload, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type OrganisationRepository interface {
+	Create(ctx context.Context, organisation *domain.Organisation) error
+	GetByID(ctx context.Context, id string) (*domain.Organisation, error)
+	GetAll(ctx context.Context) ([]*domain.Organisation, error)
+	// GetByMemberUserID returns the organisations the user is a member of.
+	GetByMemberUserID(ctx context.Context, userID string) ([]*domain.Organisation, error)
+	Update(ctx context.Context, organisation *domain.Organisation) error
+	AddMember(ctx context.Context, member *domain.OrganisationMember) error
+	GetMembers(ctx context.Context, organisationID string) ([]*domain.OrganisationMember, error)
+	IsMember(ctx context.Context, organisationID, userID string) (bool, error)
+	RemoveMember(ctx context.Context, organisationID, memberID string) error
+}
+
+type WalletSpendingRuleRepository interface {
+	Create(ctx context.Context, rule *domain.WalletSpendingRule) error
+	GetByWalletID(ctx context.Context, walletID string) ([]*domain.WalletSpendingRule, error)
+	Delete(ctx context.Context, walletID, ruleID string) error
+	// IsAllowed reports whether the wallet may be spent at the pharmacy: it has
+	// no rules, or a rule names the pharmacy or the pharmacy's organisation.
+	IsAllowed(ctx context.Context, walletID, pharmacyID string) (bool, error)
+}
+
+type PharmacyAPIKeyRepository interface {
+	Create(ctx context.Context, key *domain.PharmacyAPIKey) error
+	GetByID(ctx context.Context, id string) (*domain.PharmacyAPIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*domain.PharmacyAPIKey, error)
+	GetByPharmacyID(ctx context.Context, pharmacyID string) ([]*domain.PharmacyAPIKey, error)
+	// Revoke fails with ErrAPIKeyNotFound if the key is already revoked.
+	Revoke(ctx context.Context, key *domain.PharmacyAPIKey) error
+	// TouchLastUsed records a use of the key. Uses within a minute of the
+	// last one from the same address are not written again.
+	TouchLastUsed(ctx context.Context, id, ip string) error
+}
+
+type FeeRuleFilter struct {
+	TransactionType   domain.TransactionType
+	PharmacyID        string
+	OrganisationID    string
+	IncludeSuperseded bool
+}
+
+type FeeRuleRepository interface {
+	// Create stores the first version of a new rule, failing with
+	// ErrFeeRuleCodeTaken if the code has been used before.
+	Create(ctx context.Context, rule *domain.FeeRule) error
+	GetByID(ctx context.Context, id string) (*domain.FeeRule, error)
+	List(ctx context.Context, filter FeeRuleFilter) ([]*domain.FeeRule, error)
+	// GetVersions returns every version of the rule with the code, newest first.
+	GetVersions(ctx context.Context, code string) ([]*domain.FeeRule, error)
+	// GetApplicable returns the current rules for the transaction type in
+	// effect at the given time that apply platform-wide, to the pharmacy or to
+	// its organisation. Either ID may be empty.
+	GetApplicable(ctx context.Context, txType domain.TransactionType, pharmacyID, organisationID string, at time.Time) ([]*domain.FeeRule, error)
+	// Supersede replaces current with next as the rule's live version in a
+	// single database transaction, failing with ErrFeeRuleSuperseded if
+	// current is no longer live.
+	Supersede(ctx context.Context, current, next *domain.FeeRule) error
+	// Retire ends the rule without a successor.
+	Retire(ctx context.Context, rule *domain.FeeRule) error
+}
+
+type FeeQuoteRepository interface {
+	Create(ctx context.Context, quote *domain.FeeQuote) error
+	// GetByID returns the quote; it is redeemed by the withdrawal that uses
+	// it, in the same database transaction.
+	GetByID(ctx context.Context, id string) (*domain.FeeQuote, error)
+}
+
+type DisputeFilter struct {
+	Status     domain.DisputeStatus
+	PharmacyID string
+	// UserID limits results to disputes on wallets the user is a member of.
+	UserID   string
+	Page     int
+	PageSize int
+}
+
+type DisputeRepository interface {
+	// Create fails with ErrDisputeExists if the transaction has already been
+	// disputed.
+	Create(ctx context.Context, dispute *domain.Dispute) error
+	GetByID(ctx context.Context, id string) (*domain.Dispute, error)
+	List(ctx context.Context, filter DisputeFilter) ([]*domain.Dispute, int, error)
+	// Respond records the pharmacy's response and evidence, replacing any
+	// earlier response. It fails with ErrDisputeResolved once an admin has
+	// ruled on the dispute.
+	Respond(ctx context.Context, dispute *domain.Dispute) error
+	// Uphold resolves the dispute and reverses the disputed withdrawal in a
+	// single database transaction, even if the withdrawal has been settled.
+	Uphold(ctx context.Context, dispute *domain.Dispute, original, reversal *domain.Transaction) error
+	Reject(ctx context.Context, dispute *domain.Dispute) error
+}
+
+type WalletLookupRepository interface {
+	Create(ctx context.Context, lookup *domain.WalletLookup) error
+	// CountFailed counts the pharmacy's lookups since the given time that
+	// matched no wallet.
+	CountFailed(ctx context.Context, pharmacyID string, since time.Time) (int, error)
+	// CountAmountChecks counts the pharmacy's lookups of the wallet since the
+	// given time that asked whether an amount is covered.
+	CountAmountChecks(ctx context.Context, pharmacyID, walletID string, since time.Time) (int, error)
+	ListByPharmacy(ctx context.Context, pharmacyID string, page, pageSize int) ([]*domain.WalletLookup, int, error)
+}
+
+type WalletShareLinkRepository interface {
+	Create(ctx context.Context, link *domain.WalletShareLink) error
+	GetByID(ctx context.Context, id string) (*domain.WalletShareLink, error)
+	GetByToken(ctx context.Context, token string) (*domain.WalletShareLink, error)
+	GetByWalletID(ctx context.Context, walletID string) ([]*domain.WalletShareLink, error)
+	Revoke(ctx context.Context, link *domain.WalletShareLink) error
+	RecordView(ctx context.Context, id string) error
+	// RecordContribution counts a paid contribution of the given amount
+	// against the link.
+	RecordContribution(ctx context.Context, id string, amount decimal.Decimal) error
+}
+
+type WalletClosureFilter struct {
+	Disposition domain.ClosureDisposition
+	Status      domain.WalletClosureStatus
+	// FailedRefunds keeps only closures with a refund Paystack has refused
+	// or that could not be confirmed, whose funds wait on an admin.
+	FailedRefunds bool
+	Page          int
+	PageSize      int
+}
+
+type WalletClosureRepository interface {
+	// Close takes closure.Amount out of the wallet as the closure's
+	// disposition says, credits any target wallet, stores the closure and its
+	// refunds and closes the wallet, all in one database transaction. It fails
+	// with ErrWalletBalanceChanged if the balance is no longer closure.Amount
+	// and with ErrInvalidTransferTarget if the target stopped taking
+	// contributions.
+	Close(ctx context.Context, wallet *domain.Wallet, closure *domain.WalletClosure, change *domain.WalletStatusChange) error
+	GetByID(ctx context.Context, id string) (*domain.WalletClosure, error)
+	GetByWalletID(ctx context.Context, walletID string) (*domain.WalletClosure, error)
+	List(ctx context.Context, filter WalletClosureFilter) ([]*domain.WalletClosure, int, error)
+	// SumDonations returns what closed wallets have given the platform fund
+	// and how many donations made it up.
+	SumDonations(ctx context.Context) (decimal.Decimal, int, error)
+	// CompleteRefund records that Paystack accepted the refund, completing
+	// its transaction and, once no refunds are outstanding, the closure.
+	CompleteRefund(ctx context.Context, refund *domain.WalletClosureRefund) error
+	FailRefund(ctx context.Context, refund *domain.WalletClosureRefund) error
+}
+
+type WalletCampaignRepository interface {
+	// Create stores the campaign and its milestones, failing with
+	// ErrCampaignAlreadyRunning if the wallet has an active campaign.
+	Create(ctx context.Context, campaign *domain.WalletCampaign) error
+	GetByID(ctx context.Context, id string) (*domain.WalletCampaign, error)
+	// GetCurrentByWalletID returns the wallet's active campaign, or else its
+	// most recently ended one.
+	GetCurrentByWalletID(ctx context.Context, walletID string) (*domain.WalletCampaign, error)
+	GetByWalletID(ctx context.Context, walletID string) ([]*domain.WalletCampaign, error)
+	// GetActive returns the active campaigns that have started by now.
+	GetActive(ctx context.Context, now time.Time) ([]*domain.WalletCampaign, error)
+	Update(ctx context.Context, campaign *domain.WalletCampaign) error
+	Cancel(ctx context.Context, campaign *domain.WalletCampaign) error
+	// GetProgress sums the contributions to the campaign's wallet from its
+	// start until the earlier of until and its end.
+	GetProgress(ctx context.Context, campaign *domain.WalletCampaign, until time.Time) (*domain.CampaignProgress, error)
+	// ReachMilestone, ReachGoal and End record their event unless it was
+	// already recorded, and report whether they recorded it.
+	ReachMilestone(ctx context.Context, milestone *domain.CampaignMilestone, event *domain.CampaignEvent) (bool, error)
+	ReachGoal(ctx context.Context, campaign *domain.WalletCampaign, event *domain.CampaignEvent) (bool, error)
+	End(ctx context.Context, campaign *domain.WalletCampaign, event *domain.CampaignEvent) (bool, error)
+	GetEvents(ctx context.Context, campaignID string) ([]*domain.CampaignEvent, error)
+}
+
+type ContributionFilter struct {
+	WalletID string
+	Page     int
+	PageSize int
+}
+
+type WalletContributionRepository interface {
+	// List returns the wallet's completed contributions, newest first.
+	List(ctx context.Context, filter ContributionFilter) ([]*domain.Contribution, int, error)
+	GetByID(ctx context.Context, transactionID string) (*domain.Contribution, error)
+	// SetMessageHidden hides the contribution's message from the contributor
+	// wall, or shows it again when hidden is false.
+	SetMessageHidden(ctx context.Context, contribution *domain.Contribution, hidden bool, userID string) error
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type manualCreditRepository struct {
+	db *database.PostgresDB
+}
+
+func NewManualCreditRepository(db *database.PostgresDB) ManualCreditRepository {
+	return &manualCreditRepository{db: db}
+}
+
+const manualCreditColumns = `id, wallet_id, amount, reason, external_reference, status, requested_by, reviewed_by, reviewed_at, COALESCE(rejection_reason, ''), transaction_id, created_at, updated_at`
+
+func scanManualCredit(row pgx.Row) (*domain.ManualCredit, error) {
+	credit := &domain.ManualCredit{}
+	err := row.Scan(
+		&credit.ID,
+		&credit.WalletID,
+		&credit.Amount,
+		&credit.Reason,
+		&credit.ExternalReference,
+		&credit.Status,
+		&credit.RequestedBy,
+		&credit.ReviewedBy,
+		&credit.ReviewedAt,
+		&credit.RejectionReason,
+		&credit.TransactionID,
+		&credit.CreatedAt,
+		&credit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return credit, nil
+}
+
+func (r *manualCreditRepository) Create(ctx context.Context, credit *domain.ManualCredit) error {
+	query := `
+		INSERT INTO manual_credits (wallet_id, amount, reason, external_reference, status, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		credit.WalletID,
+		credit.Amount,
+		credit.Reason,
+		credit.ExternalReference,
+		credit.Status,
+		credit.RequestedBy,
+	).Scan(&credit.ID, &credit.CreatedAt, &credit.UpdatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrDuplicateReference
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *manualCreditRepository) GetByID(ctx context.Context, id string) (*domain.ManualCredit, error) {
+	query := `SELECT ` + manualCreditColumns + ` FROM manual_credits WHERE id = $1`
+
+	credit, err := scanManualCredit(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrManualCreditNotFound
+		}
+		return nil, err
+	}
+
+	return credit, nil
+}
+
+func (r *manualCreditRepository) GetByStatus(ctx context.Context, status domain.ManualCreditStatus) ([]*domain.ManualCredit, error) {
+	query := `
+		SELECT ` + manualCreditColumns + `
+		FROM manual_credits
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.Pool.Query(ctx, query, string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credits []*domain.ManualCredit
+	for rows.Next() {
+		credit, err := scanManualCredit(rows)
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, credit)
+	}
+
+	return credits, nil
+}
+
+func (r *manualCreditRepository) Approve(ctx context.Context, credit *domain.ManualCredit, transaction *domain.Transaction) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Only a pending credit can be approved; this guards against double crediting
+	// when two admins approve concurrently.
+	result, err := tx.Exec(ctx, `
+		UPDATE manual_credits
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, updated_at = NOW()
+		WHERE id = $4 AND status = $5`,
+		domain.ManualCreditStatusApproved,
+		credit.ReviewedBy,
+		credit.ReviewedAt,
+		credit.ID,
+		domain.ManualCreditStatusPending,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrManualCreditNotPending
+	}
+
+	walletStatus, err := lockWalletStatus(ctx, tx, transaction.WalletID)
+	if err != nil {
+		return err
+	}
+	if !walletStatus.CanBeCredited() {
+		return domain.ErrWalletClosed
+	}
+
+	if err := insertTransaction(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	result, err = tx.Exec(ctx, `
+		UPDATE wallets
+		SET balance = balance + $1::decimal, updated_at = NOW()
+		WHERE id = $2`,
+		transaction.NetAmount.String(),
+		transaction.WalletID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrWalletNotFound
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE manual_credits
+		SET transaction_id = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING updated_at`,
+		transaction.ID,
+		credit.ID,
+	).Scan(&credit.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	credit.Status = domain.ManualCreditStatusApproved
+	credit.TransactionID = &transaction.ID
+	return nil
+}
+
+func (r *manualCreditRepository) Reject(ctx context.Context, credit *domain.ManualCredit) error {
+	query := `
+		UPDATE manual_credits
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, rejection_reason = $4, updated_at = NOW()
+		WHERE id = $5 AND status = $6
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		domain.ManualCreditStatusRejected,
+		credit.ReviewedBy,
+		credit.ReviewedAt,
+		credit.RejectionReason,
+		credit.ID,
+		domain.ManualCreditStatusPending,
+	).Scan(&credit.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrManualCreditNotPending
+		}
+		return err
+	}
+
+	credit.Status = domain.ManualCreditStatusRejected
+	return nil
+}
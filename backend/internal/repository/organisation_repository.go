@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type organisationRepository struct {
+	db *database.PostgresDB
+}
+
+func NewOrganisationRepository(db *database.PostgresDB) OrganisationRepository {
+	return &organisationRepository{db: db}
+}
+
+const organisationColumns = `id, name, COALESCE(registration_number, ''), COALESCE(email, ''), COALESCE(phone, ''), status, created_at, updated_at`
+
+func scanOrganisation(row pgx.Row) (*domain.Organisation, error) {
+	organisation := &domain.Organisation{}
+	err := row.Scan(
+		&organisation.ID,
+		&organisation.Name,
+		&organisation.RegistrationNumber,
+		&organisation.Email,
+		&organisation.Phone,
+		&organisation.Status,
+		&organisation.CreatedAt,
+		&organisation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return organisation, nil
+}
+
+func (r *organisationRepository) Create(ctx context.Context, organisation *domain.Organisation) error {
+	query := `
+		INSERT INTO organisations (name, registration_number, email, phone, status)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), $5)
+		RETURNING id, created_at, updated_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		organisation.Name,
+		organisation.RegistrationNumber,
+		organisation.Email,
+		organisation.Phone,
+		organisation.Status,
+	).Scan(&organisation.ID, &organisation.CreatedAt, &organisation.UpdatedAt)
+}
+
+func (r *organisationRepository) GetByID(ctx context.Context, id string) (*domain.Organisation, error) {
+	query := `SELECT ` + organisationColumns + ` FROM organisations WHERE id = $1`
+
+	organisation, err := scanOrganisation(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOrganisationNotFound
+		}
+		return nil, err
+	}
+
+	return organisation, nil
+}
+
+func (r *organisationRepository) GetAll(ctx context.Context) ([]*domain.Organisation, error) {
+	return r.list(ctx, `SELECT `+organisationColumns+` FROM organisations ORDER BY name`)
+}
+
+func (r *organisationRepository) GetByMemberUserID(ctx context.Context, userID string) ([]*domain.Organisation, error) {
+	query := `
+		SELECT ` + organisationColumns + `
+		FROM organisations
+		WHERE id IN (SELECT organisation_id FROM organisation_members WHERE user_id = $1)
+		ORDER BY name`
+
+	return r.list(ctx, query, userID)
+}
+
+func (r *organisationRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.Organisation, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organisations []*domain.Organisation
+	for rows.Next() {
+		organisation, err := scanOrganisation(rows)
+		if err != nil {
+			return nil, err
+		}
+		organisations = append(organisations, organisation)
+	}
+
+	return organisations, nil
+}
+
+func (r *organisationRepository) Update(ctx context.Context, organisation *domain.Organisation) error {
+	query := `
+		UPDATE organisations
+		SET name = $1, registration_number = NULLIF($2, ''), email = NULLIF($3, ''), phone = NULLIF($4, ''), status = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		organisation.Name,
+		organisation.RegistrationNumber,
+		organisation.Email,
+		organisation.Phone,
+		organisation.Status,
+		organisation.ID,
+	).Scan(&organisation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrOrganisationNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *organisationRepository) AddMember(ctx context.Context, member *domain.OrganisationMember) error {
+	query := `
+		INSERT INTO organisation_members (organisation_id, user_id, added_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		member.OrganisationID,
+		member.UserID,
+		member.AddedBy,
+	).Scan(&member.ID, &member.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrOrganisationMemberExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *organisationRepository) GetMembers(ctx context.Context, organisationID string) ([]*domain.OrganisationMember, error) {
+	query := `
+		SELECT m.id, m.organisation_id, m.user_id, u.email, u.full_name, m.added_by, m.created_at
+		FROM organisation_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organisation_id = $1
+		ORDER BY m.created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*domain.OrganisationMember
+	for rows.Next() {
+		member := &domain.OrganisationMember{}
+		if err := rows.Scan(
+			&member.ID,
+			&member.OrganisationID,
+			&member.UserID,
+			&member.Email,
+			&member.FullName,
+			&member.AddedBy,
+			&member.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+func (r *organisationRepository) IsMember(ctx context.Context, organisationID, userID string) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM organisation_members WHERE organisation_id = $1 AND user_id = $2)`,
+		organisationID, userID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *organisationRepository) RemoveMember(ctx context.Context, organisationID, memberID string) error {
+	result, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM organisation_members WHERE id = $1 AND organisation_id = $2`,
+		memberID, organisationID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrOrganisationMemberNotFound
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type passwordSetupTokenRepository struct {
+	db *database.PostgresDB
+}
+
+func NewPasswordSetupTokenRepository(db *database.PostgresDB) PasswordSetupTokenRepository {
+	return &passwordSetupTokenRepository{db: db}
+}
+
+func (r *passwordSetupTokenRepository) Create(ctx context.Context, token *domain.PasswordSetupToken) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE password_setup_tokens
+		SET used_at = NOW()
+		WHERE pharmacy_user_id = $1 AND used_at IS NULL`, token.PharmacyUserID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO password_setup_tokens (pharmacy_user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`
+
+	if err := tx.QueryRow(ctx, query,
+		token.PharmacyUserID,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *passwordSetupTokenRepository) Consume(ctx context.Context, tokenHash, passwordHash string) (*domain.PasswordSetupToken, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	token := &domain.PasswordSetupToken{}
+	err = tx.QueryRow(ctx, `
+		SELECT id, pharmacy_user_id, token_hash, expires_at, used_at, created_at
+		FROM password_setup_tokens
+		WHERE token_hash = $1
+		FOR UPDATE`, tokenHash).Scan(
+		&token.ID,
+		&token.PharmacyUserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSetupTokenInvalid
+		}
+		return nil, err
+	}
+
+	if token.UsedAt != nil {
+		return nil, domain.ErrSetupTokenInvalid
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, domain.ErrSetupTokenExpired
+	}
+
+	// Disabled staff cannot bring their login back with an old link.
+	tag, err := tx.Exec(ctx, `
+		UPDATE pharmacy_users
+		SET password_hash = $1, updated_at = NOW()
+		WHERE id = $2 AND status = 'active'`, passwordHash, token.PharmacyUserID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrSetupTokenInvalid
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(ctx, `UPDATE password_setup_tokens SET used_at = $1 WHERE id = $2`, now, token.ID); err != nil {
+		return nil, err
+	}
+	token.UsedAt = &now
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
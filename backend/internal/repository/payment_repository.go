@@ -12,7 +12,16 @@ import (
 type PaymentRepository interface {
 	Create(ctx context.Context, payment *domain.Payment) error
 	GetByReference(ctx context.Context, reference string) (*domain.Payment, error)
+	// GetCompletedByWalletID returns the wallet's successful contributions,
+	// oldest first.
+	GetCompletedByWalletID(ctx context.Context, walletID string) ([]*domain.Payment, error)
 	Update(ctx context.Context, payment *domain.Payment) error
+	// Complete marks a pending payment completed, records its deposit and
+	// credits the wallet in one database transaction, with the wallet's
+	// status locked. It fails with ErrWalletClosed if the wallet can no
+	// longer be credited and ErrPaymentAlreadyVerified if the payment is no
+	// longer pending.
+	Complete(ctx context.Context, payment *domain.Payment, deposit *domain.Transaction) error
 }
 
 type paymentRepository struct {
@@ -25,43 +34,64 @@ func NewPaymentRepository(db *database.PostgresDB) PaymentRepository {
 
 func (r *paymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
 	query := `
-		INSERT INTO payments (wallet_id, reference, amount, email, message, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO payments (wallet_id, reference, amount, fee, fee_rule_id, contributor_pays_fee, email, message, display_name, anonymous, hide_amount, share_link_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id, created_at, updated_at`
 
 	err := r.db.Pool.QueryRow(ctx, query,
 		payment.WalletID,
 		payment.Reference,
 		payment.Amount,
+		payment.Fee,
+		payment.FeeRuleID,
+		payment.ContributorPaysFee,
 		payment.Email,
 		payment.Message,
+		payment.DisplayName,
+		payment.Anonymous,
+		payment.HideAmount,
+		payment.ShareLinkID,
 		payment.Status,
 	).Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt)
 
 	return err
 }
 
-func (r *paymentRepository) GetByReference(ctx context.Context, reference string) (*domain.Payment, error) {
-	query := `
-		SELECT id, wallet_id, reference, amount, email, message, status, paystack_reference, verified_at, created_at, updated_at
-		FROM payments
-		WHERE reference = $1`
+const paymentColumns = `id, wallet_id, reference, amount, fee, fee_rule_id, contributor_pays_fee, email, message, display_name, anonymous, hide_amount, share_link_id, status, paystack_reference, verified_at, created_at, updated_at`
 
+func scanPayment(row pgx.Row) (*domain.Payment, error) {
 	payment := &domain.Payment{}
-	err := r.db.Pool.QueryRow(ctx, query, reference).Scan(
+	err := row.Scan(
 		&payment.ID,
 		&payment.WalletID,
 		&payment.Reference,
 		&payment.Amount,
+		&payment.Fee,
+		&payment.FeeRuleID,
+		&payment.ContributorPaysFee,
 		&payment.Email,
 		&payment.Message,
+		&payment.DisplayName,
+		&payment.Anonymous,
+		&payment.HideAmount,
+		&payment.ShareLinkID,
 		&payment.Status,
 		&payment.PaystackReference,
 		&payment.VerifiedAt,
 		&payment.CreatedAt,
 		&payment.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (r *paymentRepository) GetByReference(ctx context.Context, reference string) (*domain.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE reference = $1`
 
+	payment, err := scanPayment(r.db.Pool.QueryRow(ctx, query, reference))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrPaymentNotFound
@@ -72,6 +102,31 @@ func (r *paymentRepository) GetByReference(ctx context.Context, reference string
 	return payment, nil
 }
 
+func (r *paymentRepository) GetCompletedByWalletID(ctx context.Context, walletID string) ([]*domain.Payment, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE wallet_id = $1 AND status = $2
+		ORDER BY verified_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, walletID, domain.PaymentStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []*domain.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
 func (r *paymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
 	query := `
 		UPDATE payments
@@ -95,3 +150,57 @@ func (r *paymentRepository) Update(ctx context.Context, payment *domain.Payment)
 
 	return nil
 }
+
+func (r *paymentRepository) Complete(ctx context.Context, payment *domain.Payment, deposit *domain.Transaction) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	walletStatus, err := lockWalletStatus(ctx, tx, payment.WalletID)
+	if err != nil {
+		return err
+	}
+	if !walletStatus.CanBeCredited() {
+		return domain.ErrWalletClosed
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE payments
+		SET status = $1, paystack_reference = $2, verified_at = $3, updated_at = NOW()
+		WHERE id = $4 AND status = $5
+		RETURNING updated_at`,
+		domain.PaymentStatusCompleted,
+		payment.PaystackReference,
+		payment.VerifiedAt,
+		payment.ID,
+		domain.PaymentStatusPending,
+	).Scan(&payment.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPaymentAlreadyVerified
+		}
+		return err
+	}
+
+	if err := insertTransaction(ctx, tx, deposit); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE wallets
+		SET balance = balance + $1, updated_at = NOW()
+		WHERE id = $2`,
+		deposit.NetAmount, payment.WalletID,
+	); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	payment.Status = domain.PaymentStatusCompleted
+	return nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type pharmacyAPIKeyRepository struct {
+	db *database.PostgresDB
+}
+
+func NewPharmacyAPIKeyRepository(db *database.PostgresDB) PharmacyAPIKeyRepository {
+	return &pharmacyAPIKeyRepository{db: db}
+}
+
+const pharmacyAPIKeyColumns = `id, pharmacy_id, created_by, name, key_prefix, key_hash, scopes, last_used_at, COALESCE(last_used_ip, ''), revoked_at, revoked_by, created_at`
+
+func scanPharmacyAPIKey(row pgx.Row) (*domain.PharmacyAPIKey, error) {
+	key := &domain.PharmacyAPIKey{}
+	var scopes []string
+
+	err := row.Scan(
+		&key.ID,
+		&key.PharmacyID,
+		&key.CreatedBy,
+		&key.Name,
+		&key.KeyPrefix,
+		&key.KeyHash,
+		&scopes,
+		&key.LastUsedAt,
+		&key.LastUsedIP,
+		&key.RevokedAt,
+		&key.RevokedBy,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]domain.APIKeyScope, len(scopes))
+	for i, scope := range scopes {
+		key.Scopes[i] = domain.APIKeyScope(scope)
+	}
+
+	return key, nil
+}
+
+func (r *pharmacyAPIKeyRepository) Create(ctx context.Context, key *domain.PharmacyAPIKey) error {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	query := `
+		INSERT INTO pharmacy_api_keys (pharmacy_id, created_by, name, key_prefix, key_hash, scopes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		key.PharmacyID,
+		key.CreatedBy,
+		key.Name,
+		key.KeyPrefix,
+		key.KeyHash,
+		scopes,
+	).Scan(&key.ID, &key.CreatedAt)
+}
+
+func (r *pharmacyAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.PharmacyAPIKey, error) {
+	return r.getOne(ctx, `SELECT `+pharmacyAPIKeyColumns+` FROM pharmacy_api_keys WHERE id = $1`, id)
+}
+
+func (r *pharmacyAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.PharmacyAPIKey, error) {
+	return r.getOne(ctx, `SELECT `+pharmacyAPIKeyColumns+` FROM pharmacy_api_keys WHERE key_hash = $1`, keyHash)
+}
+
+func (r *pharmacyAPIKeyRepository) getOne(ctx context.Context, query string, arg string) (*domain.PharmacyAPIKey, error) {
+	key, err := scanPharmacyAPIKey(r.db.Pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *pharmacyAPIKeyRepository) GetByPharmacyID(ctx context.Context, pharmacyID string) ([]*domain.PharmacyAPIKey, error) {
+	query := `
+		SELECT ` + pharmacyAPIKeyColumns + `
+		FROM pharmacy_api_keys
+		WHERE pharmacy_id = $1
+		ORDER BY revoked_at IS NOT NULL, created_at DESC`
+
+	rows, err := r.db.Pool.Query(ctx, query, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.PharmacyAPIKey
+	for rows.Next() {
+		key, err := scanPharmacyAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *pharmacyAPIKeyRepository) Revoke(ctx context.Context, key *domain.PharmacyAPIKey) error {
+	query := `
+		UPDATE pharmacy_api_keys
+		SET revoked_at = NOW(), revoked_by = $1
+		WHERE id = $2 AND revoked_at IS NULL
+		RETURNING revoked_at`
+
+	err := r.db.Pool.QueryRow(ctx, query, key.RevokedBy, key.ID).Scan(&key.RevokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *pharmacyAPIKeyRepository) TouchLastUsed(ctx context.Context, id, ip string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE pharmacy_api_keys
+		SET last_used_at = NOW(), last_used_ip = NULLIF($2, '')
+		WHERE id = $1
+		AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute' OR last_used_ip IS DISTINCT FROM NULLIF($2, ''))`,
+		id, ip,
+	)
+	return err
+}
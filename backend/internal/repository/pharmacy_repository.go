@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/pkg/database"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
 type pharmacyRepository struct {
@@ -17,13 +19,58 @@ func NewPharmacyRepository(db *database.PostgresDB) PharmacyRepository {
 	return &pharmacyRepository{db: db}
 }
 
+const pharmacyColumns = `id, name, short_code, registration_number, COALESCE(address, ''), COALESCE(phone, ''), COALESCE(email, ''), COALESCE(password_hash, ''), status, organisation_id, latitude, longitude, services, COALESCE(contact_name, ''), COALESCE(application_token_hash, ''), submitted_at, COALESCE(review_notes, ''), reviewed_by, reviewed_at, daily_cash_in_limit, created_at, updated_at`
+
+func scanPharmacy(row pgx.Row) (*domain.Pharmacy, error) {
+	pharmacy := &domain.Pharmacy{}
+	var dailyCashInLimit decimal.NullDecimal
+
+	err := row.Scan(
+		&pharmacy.ID,
+		&pharmacy.Name,
+		&pharmacy.ShortCode,
+		&pharmacy.RegistrationNumber,
+		&pharmacy.Address,
+		&pharmacy.Phone,
+		&pharmacy.Email,
+		&pharmacy.PasswordHash,
+		&pharmacy.Status,
+		&pharmacy.OrganisationID,
+		&pharmacy.Latitude,
+		&pharmacy.Longitude,
+		&pharmacy.Services,
+		&pharmacy.ContactName,
+		&pharmacy.ApplicationTokenHash,
+		&pharmacy.SubmittedAt,
+		&pharmacy.ReviewNotes,
+		&pharmacy.ReviewedBy,
+		&pharmacy.ReviewedAt,
+		&dailyCashInLimit,
+		&pharmacy.CreatedAt,
+		&pharmacy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if dailyCashInLimit.Valid {
+		pharmacy.DailyCashInLimit = &dailyCashInLimit.Decimal
+	}
+
+	return pharmacy, nil
+}
+
 func (r *pharmacyRepository) Create(ctx context.Context, pharmacy *domain.Pharmacy) error {
+	return r.create(ctx, r.db.Pool, pharmacy)
+}
+
+func (r *pharmacyRepository) create(ctx context.Context, q queryRower, pharmacy *domain.Pharmacy) error {
 	query := `
-		INSERT INTO pharmacies (name, short_code, registration_number, address, phone, email, password_hash, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO pharmacies (name, short_code, registration_number, address, phone, email, password_hash, status, contact_name, application_token_hash, submitted_at, daily_cash_in_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, NULLIF($9, ''), NULLIF($10, ''), $11, $12)
 		RETURNING id, created_at, updated_at`
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := q.QueryRow(ctx, query,
 		pharmacy.Name,
 		pharmacy.ShortCode,
 		pharmacy.RegistrationNumber,
@@ -32,32 +79,124 @@ func (r *pharmacyRepository) Create(ctx context.Context, pharmacy *domain.Pharma
 		pharmacy.Email,
 		pharmacy.PasswordHash,
 		pharmacy.Status,
+		pharmacy.ContactName,
+		pharmacy.ApplicationTokenHash,
+		pharmacy.SubmittedAt,
+		pharmacy.DailyCashInLimit,
 	).Scan(&pharmacy.ID, &pharmacy.CreatedAt, &pharmacy.UpdatedAt)
 
-	return err
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrShortCodeTaken
+		}
+		return err
+	}
+
+	return nil
 }
 
-func (r *pharmacyRepository) GetByID(ctx context.Context, id string) (*domain.Pharmacy, error) {
+func (r *pharmacyRepository) CreateApplication(ctx context.Context, pharmacy *domain.Pharmacy, documents []*domain.PharmacyDocument) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.create(ctx, tx, pharmacy); err != nil {
+		return err
+	}
+
+	if err := replaceDocuments(ctx, tx, pharmacy.ID, documents); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *pharmacyRepository) UpdateApplication(ctx context.Context, pharmacy *domain.Pharmacy, documents []*domain.PharmacyDocument) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.update(ctx, tx, pharmacy); err != nil {
+		return err
+	}
+
+	if err := replaceDocuments(ctx, tx, pharmacy.ID, documents); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func replaceDocuments(ctx context.Context, tx pgx.Tx, pharmacyID string, documents []*domain.PharmacyDocument) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM pharmacy_documents WHERE pharmacy_id = $1`, pharmacyID); err != nil {
+		return err
+	}
+
 	query := `
-		SELECT id, name, short_code, registration_number, COALESCE(address, ''), COALESCE(phone, ''), COALESCE(email, ''), COALESCE(password_hash, ''), status, created_at, updated_at
-		FROM pharmacies
-		WHERE id = $1`
+		INSERT INTO pharmacy_documents (pharmacy_id, document_type, file_name, url, upload_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING id, created_at`
 
-	pharmacy := &domain.Pharmacy{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&pharmacy.ID,
-		&pharmacy.Name,
-		&pharmacy.ShortCode,
-		&pharmacy.RegistrationNumber,
-		&pharmacy.Address,
-		&pharmacy.Phone,
-		&pharmacy.Email,
-		&pharmacy.PasswordHash,
-		&pharmacy.Status,
-		&pharmacy.CreatedAt,
-		&pharmacy.UpdatedAt,
-	)
+	for _, document := range documents {
+		document.PharmacyID = pharmacyID
+		if err := tx.QueryRow(ctx, query,
+			document.PharmacyID,
+			document.DocumentType,
+			document.FileName,
+			document.URL,
+			document.UploadID,
+		).Scan(&document.ID, &document.CreatedAt); err != nil {
+			if isUniqueViolation(err) {
+				return domain.ErrUploadNotFound
+			}
+			return err
+		}
+	}
 
+	return nil
+}
+
+func (r *pharmacyRepository) GetDocuments(ctx context.Context, pharmacyID string) ([]*domain.PharmacyDocument, error) {
+	query := `
+		SELECT id, pharmacy_id, document_type, file_name, COALESCE(url, ''), upload_id, created_at
+		FROM pharmacy_documents
+		WHERE pharmacy_id = $1
+		ORDER BY created_at, document_type`
+
+	rows, err := r.db.Pool.Query(ctx, query, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var documents []*domain.PharmacyDocument
+	for rows.Next() {
+		document := &domain.PharmacyDocument{}
+		if err := rows.Scan(
+			&document.ID,
+			&document.PharmacyID,
+			&document.DocumentType,
+			&document.FileName,
+			&document.URL,
+			&document.UploadID,
+			&document.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+func (r *pharmacyRepository) GetByID(ctx context.Context, id string) (*domain.Pharmacy, error) {
+	query := `SELECT ` + pharmacyColumns + ` FROM pharmacies WHERE id = $1`
+
+	pharmacy, err := scanPharmacy(r.db.Pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrPharmacyNotFound
@@ -69,26 +208,9 @@ func (r *pharmacyRepository) GetByID(ctx context.Context, id string) (*domain.Ph
 }
 
 func (r *pharmacyRepository) GetByShortCode(ctx context.Context, code string) (*domain.Pharmacy, error) {
-	query := `
-		SELECT id, name, short_code, registration_number, COALESCE(address, ''), COALESCE(phone, ''), COALESCE(email, ''), COALESCE(password_hash, ''), status, created_at, updated_at
-		FROM pharmacies
-		WHERE short_code = $1`
-
-	pharmacy := &domain.Pharmacy{}
-	err := r.db.Pool.QueryRow(ctx, query, code).Scan(
-		&pharmacy.ID,
-		&pharmacy.Name,
-		&pharmacy.ShortCode,
-		&pharmacy.RegistrationNumber,
-		&pharmacy.Address,
-		&pharmacy.Phone,
-		&pharmacy.Email,
-		&pharmacy.PasswordHash,
-		&pharmacy.Status,
-		&pharmacy.CreatedAt,
-		&pharmacy.UpdatedAt,
-	)
+	query := `SELECT ` + pharmacyColumns + ` FROM pharmacies WHERE short_code = $1`
 
+	pharmacy, err := scanPharmacy(r.db.Pool.QueryRow(ctx, query, code))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrPharmacyNotFound
@@ -100,10 +222,7 @@ func (r *pharmacyRepository) GetByShortCode(ctx context.Context, code string) (*
 }
 
 func (r *pharmacyRepository) GetAll(ctx context.Context) ([]*domain.Pharmacy, error) {
-	query := `
-		SELECT id, name, short_code, registration_number, COALESCE(address, ''), COALESCE(phone, ''), COALESCE(email, ''), COALESCE(password_hash, ''), status, created_at, updated_at
-		FROM pharmacies
-		ORDER BY name`
+	query := `SELECT ` + pharmacyColumns + ` FROM pharmacies ORDER BY name`
 
 	rows, err := r.db.Pool.Query(ctx, query)
 	if err != nil {
@@ -113,20 +232,7 @@ func (r *pharmacyRepository) GetAll(ctx context.Context) ([]*domain.Pharmacy, er
 
 	var pharmacies []*domain.Pharmacy
 	for rows.Next() {
-		pharmacy := &domain.Pharmacy{}
-		err := rows.Scan(
-			&pharmacy.ID,
-			&pharmacy.Name,
-			&pharmacy.ShortCode,
-			&pharmacy.RegistrationNumber,
-			&pharmacy.Address,
-			&pharmacy.Phone,
-			&pharmacy.Email,
-			&pharmacy.PasswordHash,
-			&pharmacy.Status,
-			&pharmacy.CreatedAt,
-			&pharmacy.UpdatedAt,
-		)
+		pharmacy, err := scanPharmacy(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -136,14 +242,194 @@ func (r *pharmacyRepository) GetAll(ctx context.Context) ([]*domain.Pharmacy, er
 	return pharmacies, nil
 }
 
+func (r *pharmacyRepository) GetByOrganisationID(ctx context.Context, organisationID string) ([]*domain.Pharmacy, error) {
+	query := `SELECT ` + pharmacyColumns + ` FROM pharmacies WHERE organisation_id = $1 ORDER BY name`
+
+	rows, err := r.db.Pool.Query(ctx, query, organisationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pharmacies []*domain.Pharmacy
+	for rows.Next() {
+		pharmacy, err := scanPharmacy(rows)
+		if err != nil {
+			return nil, err
+		}
+		pharmacies = append(pharmacies, pharmacy)
+	}
+
+	return pharmacies, nil
+}
+
+func (r *pharmacyRepository) SetOrganisation(ctx context.Context, pharmacy *domain.Pharmacy) error {
+	query := `
+		UPDATE pharmacies
+		SET organisation_id = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query, pharmacy.OrganisationID, pharmacy.ID).Scan(&pharmacy.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPharmacyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *pharmacyRepository) UpdateListing(ctx context.Context, pharmacy *domain.Pharmacy, hours []*domain.PharmacyOpeningHours) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE pharmacies
+		SET latitude = $1, longitude = $2, services = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at`
+
+	err = tx.QueryRow(ctx, query, pharmacy.Latitude, pharmacy.Longitude, pharmacy.Services, pharmacy.ID).Scan(&pharmacy.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPharmacyNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM pharmacy_opening_hours WHERE pharmacy_id = $1`, pharmacy.ID); err != nil {
+		return err
+	}
+
+	for _, h := range hours {
+		h.PharmacyID = pharmacy.ID
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO pharmacy_opening_hours (pharmacy_id, day_of_week, opens_at, closes_at) VALUES ($1, $2, $3, $4)`,
+			h.PharmacyID, int(h.DayOfWeek), h.OpensAt, h.ClosesAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *pharmacyRepository) GetOpeningHours(ctx context.Context, pharmacyIDs ...string) ([]*domain.PharmacyOpeningHours, error) {
+	query := `
+		SELECT pharmacy_id, day_of_week, to_char(opens_at, 'HH24:MI'), to_char(closes_at, 'HH24:MI')
+		FROM pharmacy_opening_hours
+		WHERE pharmacy_id = ANY($1)
+		ORDER BY pharmacy_id, day_of_week, opens_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, pharmacyIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hours []*domain.PharmacyOpeningHours
+	for rows.Next() {
+		h := &domain.PharmacyOpeningHours{}
+		var day int
+		if err := rows.Scan(&h.PharmacyID, &day, &h.OpensAt, &h.ClosesAt); err != nil {
+			return nil, err
+		}
+		h.DayOfWeek = time.Weekday(day)
+		hours = append(hours, h)
+	}
+
+	return hours, nil
+}
+
+// FindNearby uses the haversine formula, with a latitude band to skip rows
+// that cannot be within the radius. A pharmacy is open now when the current
+// time in filter.Timezone falls inside one of today's opening periods.
+func (r *pharmacyRepository) FindNearby(ctx context.Context, filter NearbyPharmacyFilter) ([]*domain.NearbyPharmacy, error) {
+	query := `
+		SELECT id, name, short_code, address, phone, latitude, longitude, services, distance_km, open_now
+		FROM (
+			SELECT
+				p.id, p.name, p.short_code, COALESCE(p.address, '') AS address, COALESCE(p.phone, '') AS phone,
+				p.latitude, p.longitude, p.services,
+				6371 * 2 * ASIN(SQRT(
+					POWER(SIN(RADIANS(p.latitude - $1::float8) / 2), 2) +
+					COS(RADIANS($1::float8)) * COS(RADIANS(p.latitude)) * POWER(SIN(RADIANS(p.longitude - $2::float8) / 2), 2)
+				)) AS distance_km,
+				EXISTS (
+					SELECT 1
+					FROM pharmacy_opening_hours h
+					WHERE h.pharmacy_id = p.id
+					AND h.day_of_week = EXTRACT(DOW FROM NOW() AT TIME ZONE $4)
+					AND (NOW() AT TIME ZONE $4)::time >= h.opens_at
+					AND (NOW() AT TIME ZONE $4)::time < h.closes_at
+				) AS open_now
+			FROM pharmacies p
+			WHERE p.status = 'active'
+			AND p.latitude BETWEEN $1::float8 - $3::float8 / 111.0 AND $1::float8 + $3::float8 / 111.0
+			AND p.longitude IS NOT NULL
+			AND ($5 = '' OR $5 = ANY(p.services))
+		) nearby
+		WHERE distance_km <= $3::float8
+		AND (NOT $6 OR open_now)
+		ORDER BY distance_km
+		LIMIT $7`
+
+	rows, err := r.db.Pool.Query(ctx, query,
+		filter.Latitude,
+		filter.Longitude,
+		filter.RadiusKm,
+		filter.Timezone,
+		filter.Service,
+		filter.OpenNow,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pharmacies []*domain.NearbyPharmacy
+	for rows.Next() {
+		p := &domain.NearbyPharmacy{}
+		if err := rows.Scan(
+			&p.ID,
+			&p.Name,
+			&p.ShortCode,
+			&p.Address,
+			&p.Phone,
+			&p.Latitude,
+			&p.Longitude,
+			&p.Services,
+			&p.DistanceKm,
+			&p.OpenNow,
+		); err != nil {
+			return nil, err
+		}
+		pharmacies = append(pharmacies, p)
+	}
+
+	return pharmacies, nil
+}
+
 func (r *pharmacyRepository) Update(ctx context.Context, pharmacy *domain.Pharmacy) error {
+	return r.update(ctx, r.db.Pool, pharmacy)
+}
+
+func (r *pharmacyRepository) update(ctx context.Context, q queryRower, pharmacy *domain.Pharmacy) error {
 	query := `
 		UPDATE pharmacies
-		SET name = $1, short_code = $2, registration_number = $3, address = $4, phone = $5, email = $6, password_hash = $7, status = $8, updated_at = NOW()
-		WHERE id = $9
+		SET name = $1, short_code = $2, registration_number = $3, address = $4, phone = $5, email = $6, password_hash = NULLIF($7, ''), status = $8,
+			contact_name = NULLIF($9, ''), application_token_hash = NULLIF($10, ''), submitted_at = $11, review_notes = NULLIF($12, ''), reviewed_by = $13, reviewed_at = $14,
+			daily_cash_in_limit = $15, updated_at = NOW()
+		WHERE id = $16
 		RETURNING updated_at`
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err := q.QueryRow(ctx, query,
 		pharmacy.Name,
 		pharmacy.ShortCode,
 		pharmacy.RegistrationNumber,
@@ -152,6 +438,13 @@ func (r *pharmacyRepository) Update(ctx context.Context, pharmacy *domain.Pharma
 		pharmacy.Email,
 		pharmacy.PasswordHash,
 		pharmacy.Status,
+		pharmacy.ContactName,
+		pharmacy.ApplicationTokenHash,
+		pharmacy.SubmittedAt,
+		pharmacy.ReviewNotes,
+		pharmacy.ReviewedBy,
+		pharmacy.ReviewedAt,
+		pharmacy.DailyCashInLimit,
 		pharmacy.ID,
 	).Scan(&pharmacy.UpdatedAt)
 
@@ -159,6 +452,9 @@ func (r *pharmacyRepository) Update(ctx context.Context, pharmacy *domain.Pharma
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.ErrPharmacyNotFound
 		}
+		if isUniqueViolation(err) {
+			return domain.ErrShortCodeTaken
+		}
 		return err
 	}
 
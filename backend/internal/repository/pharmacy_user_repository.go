@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type pharmacyUserRepository struct {
+	db *database.PostgresDB
+}
+
+func NewPharmacyUserRepository(db *database.PostgresDB) PharmacyUserRepository {
+	return &pharmacyUserRepository{db: db}
+}
+
+const pharmacyUserColumns = `id, pharmacy_id, username, full_name, password_hash, role, status, last_login_at, created_at, updated_at`
+
+func scanPharmacyUser(row pgx.Row) (*domain.PharmacyUser, error) {
+	u := &domain.PharmacyUser{}
+	err := row.Scan(
+		&u.ID,
+		&u.PharmacyID,
+		&u.Username,
+		&u.FullName,
+		&u.PasswordHash,
+		&u.Role,
+		&u.Status,
+		&u.LastLoginAt,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (r *pharmacyUserRepository) Create(ctx context.Context, user *domain.PharmacyUser) error {
+	query := `
+		INSERT INTO pharmacy_users (pharmacy_id, username, full_name, password_hash, role, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		user.PharmacyID,
+		user.Username,
+		user.FullName,
+		user.PasswordHash,
+		user.Role,
+		user.Status,
+	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameTaken
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *pharmacyUserRepository) GetByID(ctx context.Context, id string) (*domain.PharmacyUser, error) {
+	query := `SELECT ` + pharmacyUserColumns + ` FROM pharmacy_users WHERE id = $1`
+
+	user, err := scanPharmacyUser(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPharmacyUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *pharmacyUserRepository) GetByUsername(ctx context.Context, pharmacyID, username string) (*domain.PharmacyUser, error) {
+	query := `SELECT ` + pharmacyUserColumns + ` FROM pharmacy_users WHERE pharmacy_id = $1 AND LOWER(username) = LOWER($2)`
+
+	user, err := scanPharmacyUser(r.db.Pool.QueryRow(ctx, query, pharmacyID, username))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPharmacyUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *pharmacyUserRepository) GetByPharmacyID(ctx context.Context, pharmacyID string) ([]*domain.PharmacyUser, error) {
+	query := `
+		SELECT ` + pharmacyUserColumns + `
+		FROM pharmacy_users
+		WHERE pharmacy_id = $1
+		ORDER BY role, username`
+
+	rows, err := r.db.Pool.Query(ctx, query, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*domain.PharmacyUser
+	for rows.Next() {
+		user, err := scanPharmacyUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (r *pharmacyUserRepository) Update(ctx context.Context, user *domain.PharmacyUser) error {
+	query := `
+		UPDATE pharmacy_users
+		SET full_name = $1, password_hash = $2, role = $3, status = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		user.FullName,
+		user.PasswordHash,
+		user.Role,
+		user.Status,
+		user.ID,
+	).Scan(&user.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPharmacyUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *pharmacyUserRepository) UpdateLastLogin(ctx context.Context, id string) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE pharmacy_users SET last_login_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+func (r *pharmacyUserRepository) CountActiveManagers(ctx context.Context, pharmacyID string) (int, error) {
+	query := `SELECT COUNT(*) FROM pharmacy_users WHERE pharmacy_id = $1 AND role = 'manager' AND status = 'active'`
+
+	var count int
+	err := r.db.Pool.QueryRow(ctx, query, pharmacyID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type pharmacyWithdrawalRepository struct {
+	db *database.PostgresDB
+}
+
+func NewPharmacyWithdrawalRepository(db *database.PostgresDB) PharmacyWithdrawalRepository {
+	return &pharmacyWithdrawalRepository{db: db}
+}
+
+const pharmacyWithdrawalColumns = `id, pharmacy_id, pharmacy_user_id, wallet_id, amount, fee_quote_id, otp_email, status, transaction_id, expires_at, created_at, updated_at`
+
+func scanPharmacyWithdrawal(row pgx.Row) (*domain.PharmacyWithdrawal, error) {
+	w := &domain.PharmacyWithdrawal{}
+	err := row.Scan(
+		&w.ID,
+		&w.PharmacyID,
+		&w.PharmacyUserID,
+		&w.WalletID,
+		&w.Amount,
+		&w.FeeQuoteID,
+		&w.OTPEmail,
+		&w.Status,
+		&w.TransactionID,
+		&w.ExpiresAt,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *pharmacyWithdrawalRepository) Create(ctx context.Context, withdrawal *domain.PharmacyWithdrawal) error {
+	query := `
+		INSERT INTO pharmacy_withdrawals (pharmacy_id, pharmacy_user_id, wallet_id, amount, fee_quote_id, otp_email, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		withdrawal.PharmacyID,
+		withdrawal.PharmacyUserID,
+		withdrawal.WalletID,
+		withdrawal.Amount,
+		withdrawal.FeeQuoteID,
+		withdrawal.OTPEmail,
+		withdrawal.Status,
+		withdrawal.ExpiresAt,
+	).Scan(&withdrawal.ID, &withdrawal.CreatedAt, &withdrawal.UpdatedAt)
+}
+
+func (r *pharmacyWithdrawalRepository) GetByID(ctx context.Context, id string) (*domain.PharmacyWithdrawal, error) {
+	query := `SELECT ` + pharmacyWithdrawalColumns + ` FROM pharmacy_withdrawals WHERE id = $1`
+
+	withdrawal, err := scanPharmacyWithdrawal(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWithdrawalNotFound
+		}
+		return nil, err
+	}
+
+	return withdrawal, nil
+}
+
+func (r *pharmacyWithdrawalRepository) Complete(ctx context.Context, withdrawal *domain.PharmacyWithdrawal, transaction *domain.Transaction) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status domain.PharmacyWithdrawalStatus
+	err = tx.QueryRow(ctx, `SELECT status FROM pharmacy_withdrawals WHERE id = $1 FOR UPDATE`, withdrawal.ID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrWithdrawalNotFound
+		}
+		return err
+	}
+	if status != domain.PharmacyWithdrawalStatusPending {
+		return domain.ErrWithdrawalNotPending
+	}
+
+	// A quote valid when the withdrawal was started holds until the OTP
+	// expires.
+	if err := insertWithdrawal(ctx, tx, transaction, withdrawal.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := insertLineItems(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE pharmacy_withdrawals
+		SET status = $1, transaction_id = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING updated_at`,
+		domain.PharmacyWithdrawalStatusCompleted,
+		transaction.ID,
+		withdrawal.ID,
+	).Scan(&withdrawal.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	withdrawal.Status = domain.PharmacyWithdrawalStatusCompleted
+	withdrawal.TransactionID = &transaction.ID
+	return nil
+}
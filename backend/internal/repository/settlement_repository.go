@@ -0,0 +1,535 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type settlementRepository struct {
+	db *database.PostgresDB
+}
+
+func NewSettlementRepository(db *database.PostgresDB) SettlementRepository {
+	return &settlementRepository{db: db}
+}
+
+const settlementColumns = `id, pharmacy_id, period_start, period_end, withdrawal_count, gross_withdrawals, withdrawal_fees, net_withdrawals, cash_in_count, cash_in_total, reversal_count, reversal_total, net_payable, status, COALESCE(payout_reference, ''), paid_by, paid_at, approved_by, approved_at, COALESCE(transfer_reference, ''), COALESCE(transfer_code, ''), payout_attempts, COALESCE(last_payout_error, ''), created_at, updated_at`
+
+func scanSettlement(row pgx.Row) (*domain.Settlement, error) {
+	s := &domain.Settlement{}
+	err := row.Scan(
+		&s.ID,
+		&s.PharmacyID,
+		&s.PeriodStart,
+		&s.PeriodEnd,
+		&s.WithdrawalCount,
+		&s.GrossWithdrawals,
+		&s.WithdrawalFees,
+		&s.NetWithdrawals,
+		&s.CashInCount,
+		&s.CashInTotal,
+		&s.ReversalCount,
+		&s.ReversalTotal,
+		&s.NetPayable,
+		&s.Status,
+		&s.PayoutReference,
+		&s.PaidBy,
+		&s.PaidAt,
+		&s.ApprovedBy,
+		&s.ApprovedAt,
+		&s.TransferReference,
+		&s.TransferCode,
+		&s.PayoutAttempts,
+		&s.LastPayoutError,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// settleableTypes matches the transactions a settlement batch accounts for:
+// withdrawals, cash-ins and reversals of withdrawals that were already
+// settled. A reversal of an unsettled withdrawal needs no settling because the
+// original never will be.
+const settleableTypes = `
+	(type IN ('withdrawal', 'cash_deposit')
+		OR (type = 'reversal' AND reversal_of IN (SELECT id FROM transactions WHERE settlement_id IS NOT NULL)))`
+
+// settleableCondition selects the transactions that belong in a pharmacy's
+// next settlement batch. $1 is the pharmacy ID and $2 the cutoff time.
+const settleableCondition = `
+	pharmacy_id = $1
+	AND settlement_id IS NULL
+	AND status = 'completed'
+	AND ` + settleableTypes + `
+	AND created_at < $2`
+
+func (r *settlementRepository) CreateBatch(ctx context.Context, pharmacyID string, cutoff time.Time) (*domain.Settlement, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialise with cash-ins and other batch runs for the same pharmacy.
+	var locked string
+	err = tx.QueryRow(ctx, `SELECT id FROM pharmacies WHERE id = $1 FOR UPDATE`, pharmacyID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPharmacyNotFound
+		}
+		return nil, err
+	}
+
+	// The period starts where the previous batch ended, or at the oldest
+	// transaction being settled for a pharmacy's first batch.
+	var periodStart *time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(
+			(SELECT MAX(period_end) FROM settlements WHERE pharmacy_id = $1),
+			(SELECT MIN(created_at) FROM transactions WHERE `+settleableCondition+`)
+		)`,
+		pharmacyID, cutoff,
+	).Scan(&periodStart)
+	if err != nil {
+		return nil, err
+	}
+	if periodStart == nil {
+		return nil, nil
+	}
+
+	var settlementID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO settlements (pharmacy_id, period_start, period_end, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		pharmacyID, *periodStart, cutoff, domain.SettlementStatusUnpaid,
+	).Scan(&settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE transactions
+		SET settlement_id = $3, updated_at = NOW()
+		WHERE `+settleableCondition,
+		pharmacyID, cutoff, settlementID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE settlements s
+		SET withdrawal_count = t.withdrawal_count,
+			gross_withdrawals = t.gross_withdrawals,
+			withdrawal_fees = t.withdrawal_fees,
+			net_withdrawals = t.net_withdrawals,
+			cash_in_count = t.cash_in_count,
+			cash_in_total = t.cash_in_total,
+			reversal_count = t.reversal_count,
+			reversal_total = t.reversal_total,
+			net_payable = t.net_withdrawals - t.cash_in_total - t.reversal_total,
+			updated_at = NOW()
+		FROM (
+			SELECT
+				COUNT(*) FILTER (WHERE type = 'withdrawal') AS withdrawal_count,
+				COALESCE(SUM(amount) FILTER (WHERE type = 'withdrawal'), 0) AS gross_withdrawals,
+				COALESCE(SUM(fee) FILTER (WHERE type = 'withdrawal'), 0) AS withdrawal_fees,
+				COALESCE(SUM(net_amount) FILTER (WHERE type = 'withdrawal'), 0) AS net_withdrawals,
+				COUNT(*) FILTER (WHERE type = 'cash_deposit') AS cash_in_count,
+				COALESCE(SUM(amount) FILTER (WHERE type = 'cash_deposit'), 0) AS cash_in_total,
+				COUNT(*) FILTER (WHERE type = 'reversal') AS reversal_count,
+				COALESCE(SUM(net_amount) FILTER (WHERE type = 'reversal'), 0) AS reversal_total
+			FROM transactions
+			WHERE settlement_id = $1
+		) t
+		WHERE s.id = $1`,
+		settlementID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement, err := scanSettlement(tx.QueryRow(ctx, `SELECT `+settlementColumns+` FROM settlements WHERE id = $1`, settlementID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+func (r *settlementRepository) GetPharmacyIDsWithUnsettled(ctx context.Context, cutoff time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT pharmacy_id
+		FROM transactions
+		WHERE pharmacy_id IS NOT NULL
+			AND settlement_id IS NULL
+			AND status = 'completed'
+			AND ` + settleableTypes + `
+			AND created_at < $1`
+
+	rows, err := r.db.Pool.Query(ctx, query, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r *settlementRepository) GetByID(ctx context.Context, id string) (*domain.Settlement, error) {
+	query := `SELECT ` + settlementColumns + ` FROM settlements WHERE id = $1`
+
+	settlement, err := scanSettlement(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSettlementNotFound
+		}
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+func (r *settlementRepository) List(ctx context.Context, filter SettlementFilter) ([]*domain.Settlement, int, error) {
+	where := `
+		WHERE ($1 = '' OR pharmacy_id::text = $1)
+		AND ($2 = '' OR status = $2)
+		AND ($3 = '' OR pharmacy_id IN (SELECT id FROM pharmacies WHERE organisation_id::text = $3))`
+
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM settlements `+where, filter.PharmacyID, string(filter.Status), filter.OrganisationID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := `
+		SELECT ` + settlementColumns + `
+		FROM settlements ` + where + `
+		ORDER BY period_end DESC, created_at DESC
+		LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Pool.Query(ctx, query, filter.PharmacyID, string(filter.Status), filter.OrganisationID, filter.PageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var settlements []*domain.Settlement
+	for rows.Next() {
+		settlement, err := scanSettlement(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, total, nil
+}
+
+func (r *settlementRepository) SummariseByOrganisation(ctx context.Context, organisationID string, from, to time.Time) ([]*domain.BranchSettlementSummary, error) {
+	query := `
+		SELECT
+			p.id, p.name, p.short_code,
+			COUNT(s.id),
+			COALESCE(SUM(s.withdrawal_count), 0),
+			COALESCE(SUM(s.gross_withdrawals), 0),
+			COALESCE(SUM(s.withdrawal_fees), 0),
+			COALESCE(SUM(s.net_withdrawals), 0),
+			COALESCE(SUM(s.cash_in_count), 0),
+			COALESCE(SUM(s.cash_in_total), 0),
+			COALESCE(SUM(s.net_payable), 0),
+			COALESCE(SUM(s.net_payable) FILTER (WHERE s.status = 'paid'), 0)
+		FROM pharmacies p
+		LEFT JOIN settlements s ON s.pharmacy_id = p.id AND s.period_end > $2 AND s.period_end <= $3
+		WHERE p.organisation_id = $1
+		GROUP BY p.id, p.name, p.short_code
+		ORDER BY p.name`
+
+	rows, err := r.db.Pool.Query(ctx, query, organisationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*domain.BranchSettlementSummary
+	for rows.Next() {
+		summary := &domain.BranchSettlementSummary{}
+		if err := rows.Scan(
+			&summary.PharmacyID,
+			&summary.PharmacyName,
+			&summary.ShortCode,
+			&summary.SettlementCount,
+			&summary.WithdrawalCount,
+			&summary.GrossWithdrawals,
+			&summary.WithdrawalFees,
+			&summary.NetWithdrawals,
+			&summary.CashInCount,
+			&summary.CashInTotal,
+			&summary.NetPayable,
+			&summary.Paid,
+		); err != nil {
+			return nil, err
+		}
+		summary.Outstanding = summary.NetPayable.Sub(summary.Paid)
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+func (r *settlementRepository) MarkPaid(ctx context.Context, settlement *domain.Settlement) error {
+	query := `
+		UPDATE settlements
+		SET status = $1, payout_reference = $2, paid_by = $3, paid_at = $4, updated_at = NOW()
+		WHERE id = $5 AND status IN ($6, $7, $8)
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		domain.SettlementStatusPaid,
+		settlement.PayoutReference,
+		settlement.PaidBy,
+		settlement.PaidAt,
+		settlement.ID,
+		domain.SettlementStatusUnpaid,
+		domain.SettlementStatusApproved,
+		domain.SettlementStatusFailed,
+	).Scan(&settlement.UpdatedAt)
+
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		// The settlement was paid or a payout started since it was read.
+		var status domain.SettlementStatus
+		if err := r.db.Pool.QueryRow(ctx, `SELECT status FROM settlements WHERE id = $1`, settlement.ID).Scan(&status); err != nil {
+			return err
+		}
+		if status == domain.SettlementStatusPaid {
+			return domain.ErrSettlementAlreadyPaid
+		}
+		return domain.ErrPayoutInProgress
+	}
+
+	settlement.Status = domain.SettlementStatusPaid
+	return nil
+}
+
+func (r *settlementRepository) GetByTransferReference(ctx context.Context, reference string) (*domain.Settlement, error) {
+	query := `SELECT ` + settlementColumns + ` FROM settlements WHERE transfer_reference = $1`
+
+	settlement, err := scanSettlement(r.db.Pool.QueryRow(ctx, query, reference))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSettlementNotFound
+		}
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+func (r *settlementRepository) GetRetryable(ctx context.Context, maxAttempts int) ([]*domain.Settlement, error) {
+	query := `
+		SELECT ` + settlementColumns + `
+		FROM settlements
+		WHERE status = 'approved' OR (status = 'failed' AND payout_attempts < $1)
+		ORDER BY period_end`
+
+	rows, err := r.db.Pool.Query(ctx, query, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settlements []*domain.Settlement
+	for rows.Next() {
+		settlement, err := scanSettlement(rows)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, nil
+}
+
+func (r *settlementRepository) GetUnconfirmed(ctx context.Context, before time.Time) ([]*domain.Settlement, error) {
+	query := `
+		SELECT ` + settlementColumns + `
+		FROM settlements
+		WHERE status = 'processing' AND updated_at < $1
+		ORDER BY period_end`
+
+	rows, err := r.db.Pool.Query(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settlements []*domain.Settlement
+	for rows.Next() {
+		settlement, err := scanSettlement(rows)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, nil
+}
+
+func (r *settlementRepository) Approve(ctx context.Context, settlement *domain.Settlement) error {
+	query := `
+		UPDATE settlements
+		SET status = $1, approved_by = $2, approved_at = $3, updated_at = NOW()
+		WHERE id = $4 AND status = $5
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		domain.SettlementStatusApproved,
+		settlement.ApprovedBy,
+		settlement.ApprovedAt,
+		settlement.ID,
+		domain.SettlementStatusUnpaid,
+	).Scan(&settlement.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrSettlementNotPayable
+		}
+		return err
+	}
+
+	settlement.Status = domain.SettlementStatusApproved
+	return nil
+}
+
+func (r *settlementRepository) StartPayout(ctx context.Context, settlement *domain.Settlement) error {
+	query := `
+		UPDATE settlements
+		SET status = 'processing',
+			payout_attempts = payout_attempts + 1,
+			transfer_reference = 'stl_' || replace(id::text, '-', '') || '_' || (payout_attempts + 1),
+			transfer_code = NULL,
+			last_payout_error = NULL,
+			updated_at = NOW()
+		WHERE id = $1 AND status IN ('approved', 'failed')
+		RETURNING transfer_reference, payout_attempts, updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query, settlement.ID).Scan(
+		&settlement.TransferReference,
+		&settlement.PayoutAttempts,
+		&settlement.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrSettlementNotPayable
+		}
+		return err
+	}
+
+	settlement.Status = domain.SettlementStatusProcessing
+	settlement.TransferCode = ""
+	settlement.LastPayoutError = ""
+	return nil
+}
+
+func (r *settlementRepository) SetTransferCode(ctx context.Context, settlement *domain.Settlement) error {
+	query := `
+		UPDATE settlements
+		SET transfer_code = $1, updated_at = NOW()
+		WHERE id = $2 AND transfer_reference = $3
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query, settlement.TransferCode, settlement.ID, settlement.TransferReference).Scan(&settlement.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrSettlementNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *settlementRepository) CompletePayout(ctx context.Context, settlement *domain.Settlement) error {
+	query := `
+		UPDATE settlements
+		SET status = 'paid', transfer_code = $1, payout_reference = $1, paid_at = $2, updated_at = NOW()
+		WHERE id = $3 AND transfer_reference = $4 AND status = 'processing'
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		settlement.TransferCode,
+		settlement.PaidAt,
+		settlement.ID,
+		settlement.TransferReference,
+	).Scan(&settlement.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrSettlementNotPayable
+		}
+		return err
+	}
+
+	settlement.Status = domain.SettlementStatusPaid
+	settlement.PayoutReference = settlement.TransferCode
+	return nil
+}
+
+func (r *settlementRepository) FailPayout(ctx context.Context, settlement *domain.Settlement) error {
+	// A reversal can arrive after the transfer was reported successful, so
+	// paid settlements for the same transfer are reopened too.
+	query := `
+		UPDATE settlements
+		SET status = 'failed', last_payout_error = $1, payout_reference = NULL, paid_at = NULL, updated_at = NOW()
+		WHERE id = $2 AND transfer_reference = $3 AND status IN ('processing', 'paid')
+		RETURNING updated_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		settlement.LastPayoutError,
+		settlement.ID,
+		settlement.TransferReference,
+	).Scan(&settlement.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrSettlementNotPayable
+		}
+		return err
+	}
+
+	settlement.Status = domain.SettlementStatusFailed
+	settlement.PayoutReference = ""
+	settlement.PaidAt = nil
+	return nil
+}
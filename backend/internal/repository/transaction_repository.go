@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/pkg/database"
@@ -18,57 +19,165 @@ func NewTransactionRepository(db *database.PostgresDB) TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
-func (r *transactionRepository) Create(ctx context.Context, tx *domain.Transaction) error {
-	query := `
-		INSERT INTO transactions (wallet_id, type, amount, fee, net_amount, status, contributor_email, contributor_name, contributor_message, pharmacy_id, pharmacy_name, paystack_reference)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id, created_at, updated_at`
-
-	err := r.db.Pool.QueryRow(ctx, query,
-		tx.WalletID,
-		tx.Type,
-		tx.Amount,
-		tx.Fee,
-		tx.NetAmount,
-		tx.Status,
-		tx.ContributorEmail,
-		tx.ContributorName,
-		tx.ContributorMessage,
-		tx.PharmacyID,
-		tx.PharmacyName,
-		tx.PaystackReference,
-	).Scan(&tx.ID, &tx.CreatedAt, &tx.UpdatedAt)
-
-	return err
-}
-
-func (r *transactionRepository) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
-	query := `
-		SELECT id, wallet_id, type, amount, fee, net_amount, status, contributor_email, contributor_name, contributor_message, pharmacy_id, pharmacy_name, paystack_reference, created_at, updated_at
-		FROM transactions
-		WHERE id = $1`
+const transactionColumns = `id, wallet_id, type, amount, fee, fee_rule_id, fee_quote_id, net_amount, status, contributor_email, contributor_name, contributor_message, contributor_anonymous, contributor_hides_amount, pharmacy_id, pharmacy_name, pharmacy_user_id, paystack_reference, settlement_id, reversal_of, COALESCE(reversal_reason, ''), created_at, updated_at`
 
+func scanTransaction(row pgx.Row) (*domain.Transaction, error) {
 	tx := &domain.Transaction{}
 	var amount, fee, netAmount decimal.Decimal
 
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := row.Scan(
 		&tx.ID,
 		&tx.WalletID,
 		&tx.Type,
 		&amount,
 		&fee,
+		&tx.FeeRuleID,
+		&tx.FeeQuoteID,
 		&netAmount,
 		&tx.Status,
 		&tx.ContributorEmail,
 		&tx.ContributorName,
 		&tx.ContributorMessage,
+		&tx.ContributorAnonymous,
+		&tx.ContributorHidesAmount,
 		&tx.PharmacyID,
 		&tx.PharmacyName,
+		&tx.PharmacyUserID,
 		&tx.PaystackReference,
+		&tx.SettlementID,
+		&tx.ReversalOf,
+		&tx.ReversalReason,
 		&tx.CreatedAt,
 		&tx.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.Amount = amount
+	tx.Fee = fee
+	tx.NetAmount = netAmount
+
+	return tx, nil
+}
+
+const insertTransactionQuery = `
+	INSERT INTO transactions (wallet_id, type, amount, fee, fee_rule_id, fee_quote_id, net_amount, status, contributor_email, contributor_name, contributor_message, contributor_anonymous, contributor_hides_amount, pharmacy_id, pharmacy_name, pharmacy_user_id, paystack_reference, reversal_of, reversal_reason)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NULLIF($19, ''))
+	RETURNING id, created_at, updated_at`
+
+// queryRower is satisfied by both the connection pool and pgx.Tx, so inserts
+// can take part in a wider database transaction when needed.
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
 
+func insertTransaction(ctx context.Context, q queryRower, tx *domain.Transaction) error {
+	return q.QueryRow(ctx, insertTransactionQuery,
+		tx.WalletID,
+		tx.Type,
+		tx.Amount,
+		tx.Fee,
+		tx.FeeRuleID,
+		tx.FeeQuoteID,
+		tx.NetAmount,
+		tx.Status,
+		tx.ContributorEmail,
+		tx.ContributorName,
+		tx.ContributorMessage,
+		tx.ContributorAnonymous,
+		tx.ContributorHidesAmount,
+		tx.PharmacyID,
+		tx.PharmacyName,
+		tx.PharmacyUserID,
+		tx.PaystackReference,
+		tx.ReversalOf,
+		tx.ReversalReason,
+	).Scan(&tx.ID, &tx.CreatedAt, &tx.UpdatedAt)
+}
+
+// insertLineItems stores the transaction's line items in order. It runs on
+// the same database transaction as the insert of the withdrawal itself.
+func insertLineItems(ctx context.Context, q queryRower, tx *domain.Transaction) error {
+	for i := range tx.LineItems {
+		item := &tx.LineItems[i]
+		err := q.QueryRow(ctx, `
+			INSERT INTO transaction_line_items (transaction_id, position, product_name, nappi_code, quantity, unit_price, line_total, category)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+			RETURNING id`,
+			tx.ID,
+			i+1,
+			item.ProductName,
+			item.NAPPICode,
+			item.Quantity,
+			item.UnitPrice,
+			item.Total,
+			item.Category,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachLineItems loads the line items of the given transactions.
+func (r *transactionRepository) attachLineItems(ctx context.Context, transactions ...*domain.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domain.Transaction, len(transactions))
+	ids := make([]string, len(transactions))
+	for i, tx := range transactions {
+		byID[tx.ID] = tx
+		ids[i] = tx.ID
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT transaction_id, id, product_name, COALESCE(nappi_code, ''), quantity, unit_price, line_total, category
+		FROM transaction_line_items
+		WHERE transaction_id = ANY($1::uuid[])
+		ORDER BY transaction_id, position`,
+		ids,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transactionID string
+		var item domain.LineItem
+		err := rows.Scan(
+			&transactionID,
+			&item.ID,
+			&item.ProductName,
+			&item.NAPPICode,
+			&item.Quantity,
+			&item.UnitPrice,
+			&item.Total,
+			&item.Category,
+		)
+		if err != nil {
+			return err
+		}
+		if tx, ok := byID[transactionID]; ok {
+			tx.LineItems = append(tx.LineItems, item)
+		}
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) Create(ctx context.Context, tx *domain.Transaction) error {
+	return insertTransaction(ctx, r.db.Pool, tx)
+}
+
+func (r *transactionRepository) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1`
+
+	tx, err := scanTransaction(r.db.Pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrTransactionNotFound
@@ -76,9 +185,9 @@ func (r *transactionRepository) GetByID(ctx context.Context, id string) (*domain
 		return nil, err
 	}
 
-	tx.Amount = amount
-	tx.Fee = fee
-	tx.NetAmount = netAmount
+	if err := r.attachLineItems(ctx, tx); err != nil {
+		return nil, err
+	}
 
 	return tx, nil
 }
@@ -94,7 +203,7 @@ func (r *transactionRepository) GetByWalletID(ctx context.Context, walletID stri
 
 	offset := (page - 1) * pageSize
 	query := `
-		SELECT id, wallet_id, type, amount, fee, net_amount, status, contributor_email, contributor_name, contributor_message, pharmacy_id, pharmacy_name, paystack_reference, created_at, updated_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE wallet_id = $1
 		ORDER BY created_at DESC
@@ -108,40 +217,152 @@ func (r *transactionRepository) GetByWalletID(ctx context.Context, walletID stri
 
 	var transactions []*domain.Transaction
 	for rows.Next() {
-		tx := &domain.Transaction{}
-		var amount, fee, netAmount decimal.Decimal
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		transactions = append(transactions, tx)
+	}
 
-		err := rows.Scan(
-			&tx.ID,
-			&tx.WalletID,
-			&tx.Type,
-			&amount,
-			&fee,
-			&netAmount,
-			&tx.Status,
-			&tx.ContributorEmail,
-			&tx.ContributorName,
-			&tx.ContributorMessage,
-			&tx.PharmacyID,
-			&tx.PharmacyName,
-			&tx.PaystackReference,
-			&tx.CreatedAt,
-			&tx.UpdatedAt,
-		)
+	if err := r.attachLineItems(ctx, transactions...); err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
+}
+
+func (r *transactionRepository) GetByOrganisationID(ctx context.Context, organisationID, pharmacyID string, page, pageSize int) ([]*domain.Transaction, int, error) {
+	where := `
+		WHERE pharmacy_id IN (SELECT id FROM pharmacies WHERE organisation_id = $1)
+		AND ($2 = '' OR pharmacy_id::text = $2)`
+
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM transactions `+where, organisationID, pharmacyID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions ` + where + `
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Pool.Query(ctx, query, organisationID, pharmacyID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
 		if err != nil {
 			return nil, 0, err
 		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, total, nil
+}
+
+func (r *transactionRepository) GetByPharmacyID(ctx context.Context, filter PharmacyTransactionFilter) ([]*domain.Transaction, int, error) {
+	where := `
+		WHERE pharmacy_id = $1
+		AND ($2 = '' OR type = $2)
+		AND ($3 = '' OR status = $3)
+		AND ($4 = '' OR pharmacy_user_id::text = $4)
+		AND ($5::timestamptz IS NULL OR created_at >= $5)
+		AND ($6::timestamptz IS NULL OR created_at < $6)`
+	args := []interface{}{filter.PharmacyID, string(filter.Type), string(filter.Status), filter.PharmacyUserID, filter.From, filter.To}
+
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM transactions `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		tx.Amount = amount
-		tx.Fee = fee
-		tx.NetAmount = netAmount
+	offset := (filter.Page - 1) * filter.PageSize
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions ` + where + `
+		ORDER BY created_at DESC
+		LIMIT $7 OFFSET $8`
 
+	rows, err := r.db.Pool.Query(ctx, query, append(args, filter.PageSize, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, 0, err
+		}
 		transactions = append(transactions, tx)
 	}
 
 	return transactions, total, nil
 }
 
+func (r *transactionRepository) SummariseByCashier(ctx context.Context, pharmacyID string, from, to time.Time) ([]*domain.CashierSummary, error) {
+	query := `
+		SELECT
+			t.pharmacy_user_id,
+			COALESCE(pu.username, ''),
+			COALESCE(pu.full_name, ''),
+			COUNT(*) FILTER (WHERE t.type = 'withdrawal'),
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'withdrawal'), 0),
+			COALESCE(SUM(t.fee) FILTER (WHERE t.type = 'withdrawal'), 0),
+			COALESCE(SUM(t.net_amount) FILTER (WHERE t.type = 'withdrawal'), 0),
+			COUNT(*) FILTER (WHERE t.type = 'reversal'),
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'reversal'), 0),
+			COUNT(*) FILTER (WHERE t.type = 'cash_deposit'),
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'cash_deposit'), 0)
+		FROM transactions t
+		LEFT JOIN pharmacy_users pu ON pu.id = t.pharmacy_user_id
+		WHERE t.pharmacy_id = $1
+			AND t.created_at >= $2 AND t.created_at < $3
+			AND (
+				(t.type = 'withdrawal' AND t.status IN ('completed', 'reversed'))
+				OR (t.type IN ('cash_deposit', 'reversal') AND t.status = 'completed')
+			)
+		GROUP BY t.pharmacy_user_id, pu.username, pu.full_name
+		ORDER BY pu.username NULLS LAST`
+
+	rows, err := r.db.Pool.Query(ctx, query, pharmacyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*domain.CashierSummary
+	for rows.Next() {
+		summary := &domain.CashierSummary{}
+		if err := rows.Scan(
+			&summary.PharmacyUserID,
+			&summary.Username,
+			&summary.FullName,
+			&summary.WithdrawalCount,
+			&summary.GrossWithdrawals,
+			&summary.WithdrawalFees,
+			&summary.NetWithdrawals,
+			&summary.ReversalCount,
+			&summary.ReversalTotal,
+			&summary.CashInCount,
+			&summary.CashInTotal,
+		); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
 func (r *transactionRepository) Update(ctx context.Context, tx *domain.Transaction) error {
 	query := `
 		UPDATE transactions
@@ -159,3 +380,271 @@ func (r *transactionRepository) Update(ctx context.Context, tx *domain.Transacti
 
 	return nil
 }
+
+func (r *transactionRepository) CreateCashIn(ctx context.Context, transaction *domain.Transaction, since time.Time, dailyLimit decimal.Decimal) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the pharmacy row so concurrent cash-ins cannot overrun the daily limit.
+	var locked string
+	err = tx.QueryRow(ctx, `SELECT id FROM pharmacies WHERE id = $1 FOR UPDATE`, transaction.PharmacyID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPharmacyNotFound
+		}
+		return err
+	}
+
+	var total decimal.Decimal
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM transactions
+		WHERE pharmacy_id = $1 AND type = $2 AND status = $3 AND created_at >= $4`,
+		transaction.PharmacyID,
+		domain.TransactionTypeCashDeposit,
+		domain.TransactionStatusCompleted,
+		since,
+	).Scan(&total)
+	if err != nil {
+		return err
+	}
+
+	if total.Add(transaction.Amount).GreaterThan(dailyLimit) {
+		return domain.ErrCashInLimitExceeded
+	}
+
+	walletStatus, err := lockWalletStatus(ctx, tx, transaction.WalletID)
+	if err != nil {
+		return err
+	}
+	if !walletStatus.AcceptsContributions() {
+		return domain.ErrWalletNotAcceptingContributions
+	}
+
+	if err := insertTransaction(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE wallets
+		SET balance = balance + $1::decimal, updated_at = NOW()
+		WHERE id = $2`,
+		transaction.NetAmount.String(),
+		transaction.WalletID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrWalletNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *transactionRepository) SumCashIns(ctx context.Context, pharmacyID string, since time.Time) (decimal.Decimal, int, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0), COUNT(*)
+		FROM transactions
+		WHERE pharmacy_id = $1 AND type = $2 AND status = $3 AND created_at >= $4`
+
+	var total decimal.Decimal
+	var count int
+	err := r.db.Pool.QueryRow(ctx, query,
+		pharmacyID,
+		domain.TransactionTypeCashDeposit,
+		domain.TransactionStatusCompleted,
+		since,
+	).Scan(&total, &count)
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+
+	return total, count, nil
+}
+
+func (r *transactionRepository) SumUnsettledCashIns(ctx context.Context, pharmacyID string) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM transactions
+		WHERE pharmacy_id = $1 AND type = $2 AND status = $3 AND settlement_id IS NULL`
+
+	var total decimal.Decimal
+	err := r.db.Pool.QueryRow(ctx, query,
+		pharmacyID,
+		domain.TransactionTypeCashDeposit,
+		domain.TransactionStatusCompleted,
+	).Scan(&total)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return total, nil
+}
+
+func (r *transactionRepository) GetBySettlementID(ctx context.Context, settlementID string) ([]*domain.Transaction, error) {
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE settlement_id = $1
+		ORDER BY created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, settlementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionRepository) CreateWithdrawal(ctx context.Context, transaction *domain.Transaction) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertWithdrawal(ctx, tx, transaction, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// insertWithdrawal debits the withdrawal from the wallet and records it
+// within tx, redeeming its fee quote if it has one; quoteClaimedAt is when
+// the withdrawal was started with the quote. The wallet's status is locked
+// first, so a withdrawal cannot race a freeze or closure, and the debit only
+// goes through while the balance still covers it.
+func insertWithdrawal(ctx context.Context, tx pgx.Tx, transaction *domain.Transaction, quoteClaimedAt time.Time) error {
+	if transaction.FeeQuoteID != nil {
+		if err := redeemFeeQuote(ctx, tx, *transaction.FeeQuoteID, quoteClaimedAt); err != nil {
+			return err
+		}
+	}
+
+	walletStatus, err := lockWalletStatus(ctx, tx, transaction.WalletID)
+	if err != nil {
+		return err
+	}
+	if !walletStatus.CanBeSpent() {
+		return domain.ErrWalletNotSpendable
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE wallets
+		SET balance = balance - $1::decimal, updated_at = NOW()
+		WHERE id = $2 AND balance >= $1::decimal`,
+		transaction.Amount.String(),
+		transaction.WalletID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrInsufficientBalance
+	}
+
+	return insertTransaction(ctx, tx, transaction)
+}
+
+func (r *transactionRepository) Reverse(ctx context.Context, original, reversal *domain.Transaction) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := reverseTransaction(ctx, tx, original, reversal, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	original.Status = domain.TransactionStatusReversed
+	return nil
+}
+
+// reverseTransaction marks original reversed, records reversal and credits
+// its amount back to the wallet within tx. A settled original can only be
+// reversed when allowSettled is set; its reversal is then picked up by the
+// pharmacy's next settlement batch as a deduction.
+func reverseTransaction(ctx context.Context, tx pgx.Tx, original, reversal *domain.Transaction, allowSettled bool) error {
+	// Serialise with settlement batch runs for the same pharmacy.
+	if original.PharmacyID != nil {
+		var locked string
+		err := tx.QueryRow(ctx, `SELECT id FROM pharmacies WHERE id = $1 FOR UPDATE`, *original.PharmacyID).Scan(&locked)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrPharmacyNotFound
+			}
+			return err
+		}
+	}
+
+	var status domain.TransactionStatus
+	var settlementID *string
+	err := tx.QueryRow(ctx, `SELECT status, settlement_id FROM transactions WHERE id = $1 FOR UPDATE`, original.ID).Scan(&status, &settlementID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrTransactionNotFound
+		}
+		return err
+	}
+	if status == domain.TransactionStatusReversed {
+		return domain.ErrTransactionReversed
+	}
+	if status != domain.TransactionStatusCompleted {
+		return domain.ErrTransactionNotFound
+	}
+	if settlementID != nil && !allowSettled {
+		return domain.ErrTransactionSettled
+	}
+
+	walletStatus, err := lockWalletStatus(ctx, tx, reversal.WalletID)
+	if err != nil {
+		return err
+	}
+	if !walletStatus.CanBeCredited() {
+		return domain.ErrWalletClosed
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE transactions
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2`,
+		domain.TransactionStatusReversed,
+		original.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertTransaction(ctx, tx, reversal); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE wallets
+		SET balance = balance + $1::decimal, updated_at = NOW()
+		WHERE id = $2`,
+		reversal.Amount.String(),
+		reversal.WalletID,
+	)
+	return err
+}
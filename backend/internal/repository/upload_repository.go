@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type uploadRepository struct {
+	db *database.PostgresDB
+}
+
+func NewUploadRepository(db *database.PostgresDB) UploadRepository {
+	return &uploadRepository{db: db}
+}
+
+func (r *uploadRepository) Create(ctx context.Context, upload *domain.Upload) error {
+	query := `
+		INSERT INTO uploads (owner_type, owner_id, purpose, storage_key, file_name, content_type, size_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		upload.OwnerType,
+		upload.OwnerID,
+		upload.Purpose,
+		upload.StorageKey,
+		upload.FileName,
+		upload.ContentType,
+		upload.SizeBytes,
+	).Scan(&upload.ID, &upload.CreatedAt)
+}
+
+func (r *uploadRepository) GetByID(ctx context.Context, id string) (*domain.Upload, error) {
+	query := `
+		SELECT id, owner_type, owner_id, purpose, storage_key, file_name, content_type, size_bytes, created_at
+		FROM uploads
+		WHERE id = $1`
+
+	upload := &domain.Upload{}
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+		&upload.ID,
+		&upload.OwnerType,
+		&upload.OwnerID,
+		&upload.Purpose,
+		&upload.StorageKey,
+		&upload.FileName,
+		&upload.ContentType,
+		&upload.SizeBytes,
+		&upload.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (r *uploadRepository) SetOwner(ctx context.Context, id string, ownerType domain.UploadOwnerType, ownerID string) error {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE uploads SET owner_type = $1, owner_id = $2 WHERE id = $3`, ownerType, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUploadNotFound
+	}
+	return nil
+}
+
+func (r *uploadRepository) GetUnclaimed(ctx context.Context, before time.Time) ([]*domain.Upload, error) {
+	query := `
+		SELECT u.id, u.owner_type, u.owner_id, u.purpose, u.storage_key, u.file_name, u.content_type, u.size_bytes, u.created_at
+		FROM uploads u
+		WHERE u.owner_type = $1 AND u.owner_id IS NULL AND u.created_at < $2
+		  AND NOT EXISTS (SELECT 1 FROM pharmacy_documents d WHERE d.upload_id = u.id)
+		ORDER BY u.created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, domain.UploadOwnerApplicant, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uploads []*domain.Upload
+	for rows.Next() {
+		upload := &domain.Upload{}
+		if err := rows.Scan(
+			&upload.ID,
+			&upload.OwnerType,
+			&upload.OwnerID,
+			&upload.Purpose,
+			&upload.StorageKey,
+			&upload.FileName,
+			&upload.ContentType,
+			&upload.SizeBytes,
+			&upload.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, upload)
+	}
+
+	return uploads, rows.Err()
+}
+
+func (r *uploadRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM uploads WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUploadNotFound
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type walletCampaignRepository struct {
+	db *database.PostgresDB
+}
+
+func NewWalletCampaignRepository(db *database.PostgresDB) WalletCampaignRepository {
+	return &walletCampaignRepository{db: db}
+}
+
+const walletCampaignColumns = `id, wallet_id, created_by, title, description, goal, starts_at, ends_at, status, goal_reached_at, ended_at, created_at, updated_at`
+
+func scanWalletCampaign(row pgx.Row) (*domain.WalletCampaign, error) {
+	campaign := &domain.WalletCampaign{}
+	err := row.Scan(
+		&campaign.ID,
+		&campaign.WalletID,
+		&campaign.CreatedBy,
+		&campaign.Title,
+		&campaign.Description,
+		&campaign.Goal,
+		&campaign.StartsAt,
+		&campaign.EndsAt,
+		&campaign.Status,
+		&campaign.GoalReachedAt,
+		&campaign.EndedAt,
+		&campaign.CreatedAt,
+		&campaign.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+func (r *walletCampaignRepository) Create(ctx context.Context, campaign *domain.WalletCampaign) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO wallet_campaigns (wallet_id, created_by, title, description, goal, starts_at, ends_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`,
+		campaign.WalletID,
+		campaign.CreatedBy,
+		campaign.Title,
+		campaign.Description,
+		campaign.Goal,
+		campaign.StartsAt,
+		campaign.EndsAt,
+		campaign.Status,
+	).Scan(&campaign.ID, &campaign.CreatedAt, &campaign.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrCampaignAlreadyRunning
+		}
+		return err
+	}
+
+	for _, milestone := range campaign.Milestones {
+		milestone.CampaignID = campaign.ID
+		err := tx.QueryRow(ctx, `
+			INSERT INTO wallet_campaign_milestones (campaign_id, label, amount)
+			VALUES ($1, $2, $3)
+			RETURNING id`,
+			milestone.CampaignID,
+			milestone.Label,
+			milestone.Amount,
+		).Scan(&milestone.ID)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return domain.ErrInvalidMilestone
+			}
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *walletCampaignRepository) GetByID(ctx context.Context, id string) (*domain.WalletCampaign, error) {
+	return r.getOne(ctx, `SELECT `+walletCampaignColumns+` FROM wallet_campaigns WHERE id = $1`, id)
+}
+
+func (r *walletCampaignRepository) GetCurrentByWalletID(ctx context.Context, walletID string) (*domain.WalletCampaign, error) {
+	query := `
+		SELECT ` + walletCampaignColumns + `
+		FROM wallet_campaigns
+		WHERE wallet_id = $1 AND status <> $2
+		ORDER BY status = $3 DESC, created_at DESC
+		LIMIT 1`
+
+	campaign, err := scanWalletCampaign(r.db.Pool.QueryRow(ctx, query, walletID, domain.CampaignStatusCancelled, domain.CampaignStatusActive))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.attachMilestones(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// getOne loads a campaign with its milestones.
+func (r *walletCampaignRepository) getOne(ctx context.Context, query string, arg string) (*domain.WalletCampaign, error) {
+	campaign, err := scanWalletCampaign(r.db.Pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.attachMilestones(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (r *walletCampaignRepository) GetByWalletID(ctx context.Context, walletID string) ([]*domain.WalletCampaign, error) {
+	return r.list(ctx, `
+		SELECT `+walletCampaignColumns+`
+		FROM wallet_campaigns
+		WHERE wallet_id = $1
+		ORDER BY created_at DESC`,
+		walletID,
+	)
+}
+
+func (r *walletCampaignRepository) GetActive(ctx context.Context, now time.Time) ([]*domain.WalletCampaign, error) {
+	return r.list(ctx, `
+		SELECT `+walletCampaignColumns+`
+		FROM wallet_campaigns
+		WHERE status = $1 AND starts_at <= $2
+		ORDER BY ends_at`,
+		domain.CampaignStatusActive, now,
+	)
+}
+
+func (r *walletCampaignRepository) list(ctx context.Context, query string, args ...any) ([]*domain.WalletCampaign, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []*domain.WalletCampaign
+	for rows.Next() {
+		campaign, err := scanWalletCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachMilestones(ctx, campaigns...); err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// attachMilestones loads the milestones of the given campaigns, smallest
+// amount first.
+func (r *walletCampaignRepository) attachMilestones(ctx context.Context, campaigns ...*domain.WalletCampaign) error {
+	if len(campaigns) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domain.WalletCampaign, len(campaigns))
+	ids := make([]string, len(campaigns))
+	for i, campaign := range campaigns {
+		byID[campaign.ID] = campaign
+		ids[i] = campaign.ID
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, campaign_id, label, amount, reached_at
+		FROM wallet_campaign_milestones
+		WHERE campaign_id = ANY($1::uuid[])
+		ORDER BY amount`,
+		ids,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		milestone := &domain.CampaignMilestone{}
+		if err := rows.Scan(&milestone.ID, &milestone.CampaignID, &milestone.Label, &milestone.Amount, &milestone.ReachedAt); err != nil {
+			return err
+		}
+		campaign := byID[milestone.CampaignID]
+		campaign.Milestones = append(campaign.Milestones, milestone)
+	}
+
+	return rows.Err()
+}
+
+func (r *walletCampaignRepository) Update(ctx context.Context, campaign *domain.WalletCampaign) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE wallet_campaigns
+		SET title = $1, description = $2, goal = $3, ends_at = $4, updated_at = NOW()
+		WHERE id = $5 AND status = $6
+		RETURNING updated_at`,
+		campaign.Title,
+		campaign.Description,
+		campaign.Goal,
+		campaign.EndsAt,
+		campaign.ID,
+		domain.CampaignStatusActive,
+	).Scan(&campaign.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrCampaignNotActive
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *walletCampaignRepository) Cancel(ctx context.Context, campaign *domain.WalletCampaign) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE wallet_campaigns
+		SET status = $1, ended_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND status = $3
+		RETURNING ended_at, updated_at`,
+		domain.CampaignStatusCancelled,
+		campaign.ID,
+		domain.CampaignStatusActive,
+	).Scan(&campaign.EndedAt, &campaign.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrCampaignNotActive
+		}
+		return err
+	}
+
+	campaign.Status = domain.CampaignStatusCancelled
+	return nil
+}
+
+func (r *walletCampaignRepository) GetProgress(ctx context.Context, campaign *domain.WalletCampaign, until time.Time) (*domain.CampaignProgress, error) {
+	if until.After(campaign.EndsAt) {
+		until = campaign.EndsAt
+	}
+
+	types := make([]string, len(domain.CampaignContributionTypes))
+	for i, t := range domain.CampaignContributionTypes {
+		types[i] = string(t)
+	}
+
+	progress := &domain.CampaignProgress{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(net_amount), 0), COUNT(DISTINCT COALESCE(NULLIF(contributor_email, ''), id::text))
+		FROM transactions
+		WHERE wallet_id = $1 AND status = $2 AND type = ANY($3)
+		AND created_at >= $4 AND created_at < $5`,
+		campaign.WalletID,
+		domain.TransactionStatusCompleted,
+		types,
+		campaign.StartsAt,
+		until,
+	).Scan(&progress.Raised, &progress.ContributorCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+func (r *walletCampaignRepository) ReachMilestone(ctx context.Context, milestone *domain.CampaignMilestone, event *domain.CampaignEvent) (bool, error) {
+	return r.recordEvent(ctx, event, `
+		UPDATE wallet_campaign_milestones
+		SET reached_at = NOW()
+		WHERE id = $1 AND reached_at IS NULL
+		RETURNING reached_at`,
+		milestone.ID, &milestone.ReachedAt,
+	)
+}
+
+func (r *walletCampaignRepository) ReachGoal(ctx context.Context, campaign *domain.WalletCampaign, event *domain.CampaignEvent) (bool, error) {
+	return r.recordEvent(ctx, event, `
+		UPDATE wallet_campaigns
+		SET goal_reached_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND goal_reached_at IS NULL
+		RETURNING goal_reached_at`,
+		campaign.ID, &campaign.GoalReachedAt,
+	)
+}
+
+func (r *walletCampaignRepository) End(ctx context.Context, campaign *domain.WalletCampaign, event *domain.CampaignEvent) (bool, error) {
+	ended, err := r.recordEvent(ctx, event, `
+		UPDATE wallet_campaigns
+		SET status = 'ended', ended_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'active'
+		RETURNING ended_at`,
+		campaign.ID, &campaign.EndedAt,
+	)
+	if ended {
+		campaign.Status = domain.CampaignStatusEnded
+	}
+	return ended, err
+}
+
+// recordEvent runs claim, an UPDATE returning one timestamp into at, and
+// stores event in the same database transaction if the UPDATE matched a row.
+// Nothing is stored when another run already claimed the event, so each
+// event is recorded once.
+func (r *walletCampaignRepository) recordEvent(ctx context.Context, event *domain.CampaignEvent, claim string, id string, at **time.Time) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.QueryRow(ctx, claim, id).Scan(at); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO wallet_campaign_events (campaign_id, type, milestone_id, raised, contributor_count)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		event.CampaignID,
+		event.Type,
+		event.MilestoneID,
+		event.Raised,
+		event.ContributorCount,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *walletCampaignRepository) GetEvents(ctx context.Context, campaignID string) ([]*domain.CampaignEvent, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, campaign_id, type, milestone_id, raised, contributor_count, created_at
+		FROM wallet_campaign_events
+		WHERE campaign_id = $1
+		ORDER BY created_at DESC`,
+		campaignID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.CampaignEvent
+	for rows.Next() {
+		event := &domain.CampaignEvent{}
+		if err := rows.Scan(
+			&event.ID,
+			&event.CampaignID,
+			&event.Type,
+			&event.MilestoneID,
+			&event.Raised,
+			&event.ContributorCount,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
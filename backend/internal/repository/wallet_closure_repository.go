@@ -0,0 +1,464 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type walletClosureRepository struct {
+	db *database.PostgresDB
+}
+
+func NewWalletClosureRepository(db *database.PostgresDB) WalletClosureRepository {
+	return &walletClosureRepository{db: db}
+}
+
+const walletClosureColumns = `c.id, c.wallet_id, c.requested_by, c.disposition, c.amount, c.target_wallet_id, COALESCE(t.wallet_name, ''), c.transaction_id, c.target_transaction_id, c.reason, c.status, c.created_at, c.completed_at`
+
+const walletClosureFrom = `FROM wallet_closures c LEFT JOIN wallets t ON t.id = c.target_wallet_id`
+
+func scanWalletClosure(row pgx.Row) (*domain.WalletClosure, error) {
+	closure := &domain.WalletClosure{}
+	err := row.Scan(
+		&closure.ID,
+		&closure.WalletID,
+		&closure.RequestedBy,
+		&closure.Disposition,
+		&closure.Amount,
+		&closure.TargetWalletID,
+		&closure.TargetWalletName,
+		&closure.TransactionID,
+		&closure.TargetTransactionID,
+		&closure.Reason,
+		&closure.Status,
+		&closure.CreatedAt,
+		&closure.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return closure, nil
+}
+
+const walletClosureRefundColumns = `r.id, r.closure_id, r.payment_id, p.reference, r.transaction_id, r.contributor_email, r.amount, r.status, r.gateway_refund_id, r.attempts, r.last_error, r.created_at, r.updated_at`
+
+func scanWalletClosureRefund(row pgx.Row) (*domain.WalletClosureRefund, error) {
+	refund := &domain.WalletClosureRefund{}
+	err := row.Scan(
+		&refund.ID,
+		&refund.ClosureID,
+		&refund.PaymentID,
+		&refund.PaymentReference,
+		&refund.TransactionID,
+		&refund.ContributorEmail,
+		&refund.Amount,
+		&refund.Status,
+		&refund.GatewayRefundID,
+		&refund.Attempts,
+		&refund.LastError,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+type lockedWallet struct {
+	status  domain.WalletStatus
+	balance decimal.Decimal
+	name    string
+}
+
+// lockClosureWallets locks the closing wallet and any target wallet in id
+// order, so two wallets closing into each other cannot deadlock.
+func lockClosureWallets(ctx context.Context, tx pgx.Tx, ids ...string) (map[string]*lockedWallet, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, status, balance, wallet_name
+		FROM wallets
+		WHERE id = ANY($1::uuid[])
+		ORDER BY id
+		FOR UPDATE`,
+		ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wallets := make(map[string]*lockedWallet)
+	for rows.Next() {
+		var id string
+		wallet := &lockedWallet{}
+		if err := rows.Scan(&id, &wallet.status, &wallet.balance, &wallet.name); err != nil {
+			return nil, err
+		}
+		wallets[id] = wallet
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+func (r *walletClosureRepository) Close(ctx context.Context, wallet *domain.Wallet, closure *domain.WalletClosure, change *domain.WalletStatusChange) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ids := []string{closure.WalletID}
+	if closure.TargetWalletID != nil {
+		ids = append(ids, *closure.TargetWalletID)
+	}
+	locked, err := lockClosureWallets(ctx, tx, ids...)
+	if err != nil {
+		return err
+	}
+
+	source, ok := locked[closure.WalletID]
+	if !ok {
+		return domain.ErrWalletNotFound
+	}
+	if source.status != change.FromStatus {
+		return domain.ErrInvalidWalletTransition
+	}
+	if !source.balance.Equal(closure.Amount) {
+		return domain.ErrWalletBalanceChanged
+	}
+	if closure.TargetWalletID != nil {
+		target, ok := locked[*closure.TargetWalletID]
+		if !ok || !target.status.AcceptsContributions() {
+			return domain.ErrInvalidTransferTarget
+		}
+	}
+
+	if closure.Amount.IsPositive() {
+		if err := r.disposeOfBalance(ctx, tx, closure, source.name); err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO wallet_closures (wallet_id, requested_by, disposition, amount, target_wallet_id, transaction_id, target_transaction_id, reason, status, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CASE WHEN $9 = 'completed' THEN NOW() END)
+		RETURNING id, created_at, completed_at`,
+		closure.WalletID,
+		closure.RequestedBy,
+		closure.Disposition,
+		closure.Amount,
+		closure.TargetWalletID,
+		closure.TransactionID,
+		closure.TargetTransactionID,
+		closure.Reason,
+		closure.Status,
+	).Scan(&closure.ID, &closure.CreatedAt, &closure.CompletedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, refund := range closure.Refunds {
+		refund.ClosureID = closure.ID
+		err := tx.QueryRow(ctx, `
+			INSERT INTO wallet_closure_refunds (closure_id, payment_id, transaction_id, contributor_email, amount, status)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at, updated_at`,
+			refund.ClosureID,
+			refund.PaymentID,
+			refund.TransactionID,
+			refund.ContributorEmail,
+			refund.Amount,
+			refund.Status,
+		).Scan(&refund.ID, &refund.CreatedAt, &refund.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := updateWalletStatus(ctx, tx, wallet, change, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	wallet.Balance = decimal.Zero
+	return nil
+}
+
+// disposeOfBalance records the transactions that take the closure's amount
+// out of the wallet, credits any target wallet and zeroes the balance.
+// Refund transactions stay pending until Paystack accepts the refund.
+func (r *walletClosureRepository) disposeOfBalance(ctx context.Context, tx pgx.Tx, closure *domain.WalletClosure, walletName string) error {
+	switch closure.Disposition {
+	case domain.ClosureDispositionTransfer:
+		out := &domain.Transaction{
+			WalletID:  closure.WalletID,
+			Type:      domain.TransactionTypeTransferOut,
+			Amount:    closure.Amount,
+			NetAmount: closure.Amount,
+			Status:    domain.TransactionStatusCompleted,
+		}
+		if err := insertTransaction(ctx, tx, out); err != nil {
+			return err
+		}
+		in := &domain.Transaction{
+			WalletID:        *closure.TargetWalletID,
+			Type:            domain.TransactionTypeTransferIn,
+			Amount:          closure.Amount,
+			NetAmount:       closure.Amount,
+			Status:          domain.TransactionStatusCompleted,
+			ContributorName: walletName,
+		}
+		if err := insertTransaction(ctx, tx, in); err != nil {
+			return err
+		}
+		closure.TransactionID = &out.ID
+		closure.TargetTransactionID = &in.ID
+
+		_, err := tx.Exec(ctx, `
+			UPDATE wallets
+			SET balance = balance + $1::decimal, updated_at = NOW()
+			WHERE id = $2`,
+			closure.Amount.String(),
+			*closure.TargetWalletID,
+		)
+		if err != nil {
+			return err
+		}
+
+	case domain.ClosureDispositionDonate:
+		donation := &domain.Transaction{
+			WalletID:  closure.WalletID,
+			Type:      domain.TransactionTypeDonation,
+			Amount:    closure.Amount,
+			NetAmount: closure.Amount,
+			Status:    domain.TransactionStatusCompleted,
+		}
+		if err := insertTransaction(ctx, tx, donation); err != nil {
+			return err
+		}
+		closure.TransactionID = &donation.ID
+
+	case domain.ClosureDispositionRefund:
+		for _, refund := range closure.Refunds {
+			transaction := &domain.Transaction{
+				WalletID:         closure.WalletID,
+				Type:             domain.TransactionTypeRefund,
+				Amount:           refund.Amount,
+				NetAmount:        refund.Amount,
+				Status:           domain.TransactionStatusPending,
+				ContributorEmail: refund.ContributorEmail,
+			}
+			if err := insertTransaction(ctx, tx, transaction); err != nil {
+				return err
+			}
+			refund.TransactionID = transaction.ID
+		}
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE wallets
+		SET balance = balance - $1::decimal, updated_at = NOW()
+		WHERE id = $2`,
+		closure.Amount.String(),
+		closure.WalletID,
+	)
+	return err
+}
+
+func (r *walletClosureRepository) GetByID(ctx context.Context, id string) (*domain.WalletClosure, error) {
+	return r.getOne(ctx, `SELECT `+walletClosureColumns+` `+walletClosureFrom+` WHERE c.id = $1`, id)
+}
+
+func (r *walletClosureRepository) GetByWalletID(ctx context.Context, walletID string) (*domain.WalletClosure, error) {
+	return r.getOne(ctx, `SELECT `+walletClosureColumns+` `+walletClosureFrom+` WHERE c.wallet_id = $1`, walletID)
+}
+
+// getOne loads a closure with its refunds.
+func (r *walletClosureRepository) getOne(ctx context.Context, query string, arg string) (*domain.WalletClosure, error) {
+	closure, err := scanWalletClosure(r.db.Pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWalletClosureNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+walletClosureRefundColumns+`
+		FROM wallet_closure_refunds r
+		JOIN payments p ON p.id = r.payment_id
+		WHERE r.closure_id = $1
+		ORDER BY r.amount DESC, r.created_at`,
+		closure.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		refund, err := scanWalletClosureRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		closure.Refunds = append(closure.Refunds, refund)
+	}
+
+	return closure, nil
+}
+
+func (r *walletClosureRepository) List(ctx context.Context, filter WalletClosureFilter) ([]*domain.WalletClosure, int, error) {
+	where := `
+		WHERE ($1 = '' OR c.disposition = $1)
+		AND ($2 = '' OR c.status = $2)
+		AND (NOT $3 OR EXISTS (
+			SELECT 1 FROM wallet_closure_refunds fr WHERE fr.closure_id = c.id AND fr.status = $4
+		))`
+
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) `+walletClosureFrom+where,
+		string(filter.Disposition), string(filter.Status), filter.FailedRefunds, domain.ClosureRefundStatusFailed,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := `
+		SELECT ` + walletClosureColumns + `
+		` + walletClosureFrom + where + `
+		ORDER BY c.created_at DESC
+		LIMIT $5 OFFSET $6`
+
+	rows, err := r.db.Pool.Query(ctx, query,
+		string(filter.Disposition), string(filter.Status), filter.FailedRefunds, domain.ClosureRefundStatusFailed,
+		filter.PageSize, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var closures []*domain.WalletClosure
+	for rows.Next() {
+		closure, err := scanWalletClosure(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		closures = append(closures, closure)
+	}
+
+	return closures, total, nil
+}
+
+func (r *walletClosureRepository) SumDonations(ctx context.Context) (decimal.Decimal, int, error) {
+	var total decimal.Decimal
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0), COUNT(*)
+		FROM wallet_closures
+		WHERE disposition = $1 AND amount > 0`,
+		domain.ClosureDispositionDonate,
+	).Scan(&total, &count)
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+
+	return total, count, nil
+}
+
+func (r *walletClosureRepository) CompleteRefund(ctx context.Context, refund *domain.WalletClosureRefund) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Only an outstanding refund can complete, so a refund sent twice
+	// concurrently is only recorded once.
+	err = tx.QueryRow(ctx, `
+		UPDATE wallet_closure_refunds
+		SET status = $1, gateway_refund_id = $2, attempts = attempts + 1, last_error = '', updated_at = NOW()
+		WHERE id = $3 AND status IN ($4, $5)
+		RETURNING attempts, updated_at`,
+		domain.ClosureRefundStatusRefunded,
+		refund.GatewayRefundID,
+		refund.ID,
+		domain.ClosureRefundStatusPending,
+		domain.ClosureRefundStatusFailed,
+	).Scan(&refund.Attempts, &refund.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNoOutstandingRefunds
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE transactions
+		SET status = $1, paystack_reference = $2, updated_at = NOW()
+		WHERE id = $3`,
+		domain.TransactionStatusCompleted,
+		refund.GatewayRefundID,
+		refund.TransactionID,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE wallet_closures
+		SET status = $1, completed_at = NOW()
+		WHERE id = $2 AND status = $3
+		AND NOT EXISTS (SELECT 1 FROM wallet_closure_refunds WHERE closure_id = $2 AND status <> $4)`,
+		domain.WalletClosureStatusCompleted,
+		refund.ClosureID,
+		domain.WalletClosureStatusRefunding,
+		domain.ClosureRefundStatusRefunded,
+	); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	refund.Status = domain.ClosureRefundStatusRefunded
+	refund.LastError = ""
+	return nil
+}
+
+func (r *walletClosureRepository) FailRefund(ctx context.Context, refund *domain.WalletClosureRefund) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE wallet_closure_refunds
+		SET status = $1, attempts = attempts + 1, last_error = $2, updated_at = NOW()
+		WHERE id = $3 AND status IN ($4, $5)
+		RETURNING attempts, updated_at`,
+		domain.ClosureRefundStatusFailed,
+		refund.LastError,
+		refund.ID,
+		domain.ClosureRefundStatusPending,
+		domain.ClosureRefundStatusFailed,
+	).Scan(&refund.Attempts, &refund.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNoOutstandingRefunds
+		}
+		return err
+	}
+
+	refund.Status = domain.ClosureRefundStatusFailed
+	return nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type walletContributionRepository struct {
+	db *database.PostgresDB
+}
+
+func NewWalletContributionRepository(db *database.PostgresDB) WalletContributionRepository {
+	return &walletContributionRepository{db: db}
+}
+
+// Contributor emails are never selected, so they cannot reach the wall.
+const contributionColumns = `id, wallet_id, COALESCE(contributor_name, ''), contributor_anonymous, contributor_hides_amount, net_amount, COALESCE(contributor_message, ''), message_hidden_at, created_at`
+
+func scanContribution(row pgx.Row) (*domain.Contribution, error) {
+	contribution := &domain.Contribution{}
+	err := row.Scan(
+		&contribution.TransactionID,
+		&contribution.WalletID,
+		&contribution.Name,
+		&contribution.Anonymous,
+		&contribution.HideAmount,
+		&contribution.Amount,
+		&contribution.Message,
+		&contribution.MessageHiddenAt,
+		&contribution.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return contribution, nil
+}
+
+func contributionTypes() []string {
+	types := make([]string, len(domain.ContributionWallTypes))
+	for i, t := range domain.ContributionWallTypes {
+		types[i] = string(t)
+	}
+	return types
+}
+
+func (r *walletContributionRepository) List(ctx context.Context, filter ContributionFilter) ([]*domain.Contribution, int, error) {
+	where := `
+		FROM transactions
+		WHERE wallet_id = $1 AND status = $2 AND type = ANY($3)`
+	types := contributionTypes()
+
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) `+where, filter.WalletID, domain.TransactionStatusCompleted, types).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := `
+		SELECT ` + contributionColumns + where + `
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Pool.Query(ctx, query, filter.WalletID, domain.TransactionStatusCompleted, types, filter.PageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var contributions []*domain.Contribution
+	for rows.Next() {
+		contribution, err := scanContribution(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	return contributions, total, nil
+}
+
+func (r *walletContributionRepository) GetByID(ctx context.Context, transactionID string) (*domain.Contribution, error) {
+	query := `
+		SELECT ` + contributionColumns + `
+		FROM transactions
+		WHERE id = $1 AND status = $2 AND type = ANY($3)`
+
+	contribution, err := scanContribution(r.db.Pool.QueryRow(ctx, query, transactionID, domain.TransactionStatusCompleted, contributionTypes()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrContributionNotFound
+		}
+		return nil, err
+	}
+
+	return contribution, nil
+}
+
+func (r *walletContributionRepository) SetMessageHidden(ctx context.Context, contribution *domain.Contribution, hidden bool, userID string) error {
+	query := `
+		UPDATE transactions
+		SET message_hidden_at = CASE WHEN $2 THEN NOW() END,
+			message_hidden_by = CASE WHEN $2 THEN $3::uuid END,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING message_hidden_at`
+
+	err := r.db.Pool.QueryRow(ctx, query, contribution.TransactionID, hidden, userID).Scan(&contribution.MessageHiddenAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrContributionNotFound
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+)
+
+type walletLookupRepository struct {
+	db *database.PostgresDB
+}
+
+func NewWalletLookupRepository(db *database.PostgresDB) WalletLookupRepository {
+	return &walletLookupRepository{db: db}
+}
+
+func (r *walletLookupRepository) Create(ctx context.Context, lookup *domain.WalletLookup) error {
+	query := `
+		INSERT INTO wallet_lookups (pharmacy_id, pharmacy_user_id, code, wallet_id, found, amount_checked)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		lookup.PharmacyID,
+		lookup.PharmacyUserID,
+		lookup.Code,
+		lookup.WalletID,
+		lookup.Found,
+		lookup.AmountChecked,
+	).Scan(&lookup.ID, &lookup.CreatedAt)
+}
+
+func (r *walletLookupRepository) CountFailed(ctx context.Context, pharmacyID string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM wallet_lookups
+		WHERE pharmacy_id = $1 AND NOT found AND created_at >= $2`
+
+	var count int
+	err := r.db.Pool.QueryRow(ctx, query, pharmacyID, since).Scan(&count)
+	return count, err
+}
+
+func (r *walletLookupRepository) CountAmountChecks(ctx context.Context, pharmacyID, walletID string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM wallet_lookups
+		WHERE pharmacy_id = $1 AND wallet_id = $2 AND amount_checked AND created_at >= $3`
+
+	var count int
+	err := r.db.Pool.QueryRow(ctx, query, pharmacyID, walletID, since).Scan(&count)
+	return count, err
+}
+
+func (r *walletLookupRepository) ListByPharmacy(ctx context.Context, pharmacyID string, page, pageSize int) ([]*domain.WalletLookup, int, error) {
+	var total int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM wallet_lookups WHERE pharmacy_id = $1`, pharmacyID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	query := `
+		SELECT id, pharmacy_id, pharmacy_user_id, code, wallet_id, found, amount_checked, created_at
+		FROM wallet_lookups
+		WHERE pharmacy_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Pool.Query(ctx, query, pharmacyID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var lookups []*domain.WalletLookup
+	for rows.Next() {
+		lookup := &domain.WalletLookup{}
+		if err := rows.Scan(
+			&lookup.ID,
+			&lookup.PharmacyID,
+			&lookup.PharmacyUserID,
+			&lookup.Code,
+			&lookup.WalletID,
+			&lookup.Found,
+			&lookup.AmountChecked,
+			&lookup.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		lookups = append(lookups, lookup)
+	}
+
+	return lookups, total, nil
+}
@@ -20,8 +20,8 @@ func NewWalletRepository(db *database.PostgresDB) WalletRepository {
 
 func (r *walletRepository) Create(ctx context.Context, wallet *domain.Wallet) error {
 	query := `
-		INSERT INTO wallets (creator_id, beneficiary_id, wallet_name, description, photo_url, balance, funding_goal, shareable_code, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO wallets (creator_id, beneficiary_id, wallet_name, description, photo_url, photo_upload_id, balance, funding_goal, shareable_code, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id, created_at, updated_at`
 
 	err := r.db.Pool.QueryRow(ctx, query,
@@ -30,6 +30,7 @@ func (r *walletRepository) Create(ctx context.Context, wallet *domain.Wallet) er
 		wallet.WalletName,
 		wallet.Description,
 		wallet.PhotoURL,
+		wallet.PhotoUploadID,
 		wallet.Balance,
 		wallet.FundingGoal,
 		wallet.ShareableCode,
@@ -39,34 +40,36 @@ func (r *walletRepository) Create(ctx context.Context, wallet *domain.Wallet) er
 	return err
 }
 
-func (r *walletRepository) GetByID(ctx context.Context, id string) (*domain.Wallet, error) {
-	query := `
-		SELECT id, creator_id, beneficiary_id, wallet_name, description, photo_url, balance, funding_goal, shareable_code, status, created_at, updated_at
-		FROM wallets
-		WHERE id = $1`
+const walletColumns = `w.id, w.creator_id, w.beneficiary_id, w.wallet_name, w.description, w.photo_url, w.photo_upload_id, w.balance, w.funding_goal, w.shareable_code, w.status, w.status_reason, w.status_changed_at, w.frozen_from, w.deleted_at, w.created_at, w.updated_at`
 
+// usableWalletStatuses are the statuses in which a wallet can be found by its
+// shareable code: it can still be contributed to or spent.
+const usableWalletStatuses = `('active', 'paused', 'closing')`
+
+func scanWallet(row pgx.Row) (*domain.Wallet, error) {
 	wallet := &domain.Wallet{}
 	var balance, fundingGoal decimal.NullDecimal
 
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+	err := row.Scan(
 		&wallet.ID,
 		&wallet.CreatorID,
 		&wallet.BeneficiaryID,
 		&wallet.WalletName,
 		&wallet.Description,
 		&wallet.PhotoURL,
+		&wallet.PhotoUploadID,
 		&balance,
 		&fundingGoal,
 		&wallet.ShareableCode,
 		&wallet.Status,
+		&wallet.StatusReason,
+		&wallet.StatusChangedAt,
+		&wallet.FrozenFrom,
+		&wallet.DeletedAt,
 		&wallet.CreatedAt,
 		&wallet.UpdatedAt,
 	)
-
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, domain.ErrWalletNotFound
-		}
 		return nil, err
 	}
 
@@ -80,30 +83,32 @@ func (r *walletRepository) GetByID(ctx context.Context, id string) (*domain.Wall
 	return wallet, nil
 }
 
+// GetByID also returns deleted wallets, so their history can still be shown.
+func (r *walletRepository) GetByID(ctx context.Context, id string) (*domain.Wallet, error) {
+	return r.getOne(ctx, `SELECT `+walletColumns+` FROM wallets w WHERE w.id = $1`, id)
+}
+
 func (r *walletRepository) GetByShareableCode(ctx context.Context, code string) (*domain.Wallet, error) {
 	query := `
-		SELECT id, creator_id, beneficiary_id, wallet_name, description, photo_url, balance, funding_goal, shareable_code, status, created_at, updated_at
-		FROM wallets
-		WHERE shareable_code = $1 AND status = 'active'`
+		SELECT ` + walletColumns + `
+		FROM wallets w
+		WHERE w.shareable_code = $1 AND w.status IN ` + usableWalletStatuses
 
-	wallet := &domain.Wallet{}
-	var balance, fundingGoal decimal.NullDecimal
+	return r.getOne(ctx, query, code)
+}
 
-	err := r.db.Pool.QueryRow(ctx, query, code).Scan(
-		&wallet.ID,
-		&wallet.CreatorID,
-		&wallet.BeneficiaryID,
-		&wallet.WalletName,
-		&wallet.Description,
-		&wallet.PhotoURL,
-		&balance,
-		&fundingGoal,
-		&wallet.ShareableCode,
-		&wallet.Status,
-		&wallet.CreatedAt,
-		&wallet.UpdatedAt,
-	)
+func (r *walletRepository) GetByRetiredCode(ctx context.Context, code string) (*domain.Wallet, error) {
+	query := `
+		SELECT ` + walletColumns + `
+		FROM wallet_retired_codes rc
+		JOIN wallets w ON w.id = rc.wallet_id
+		WHERE rc.code = $1 AND w.status IN ` + usableWalletStatuses
+
+	return r.getOne(ctx, query, code)
+}
 
+func (r *walletRepository) getOne(ctx context.Context, query string, arg string) (*domain.Wallet, error) {
+	wallet, err := scanWallet(r.db.Pool.QueryRow(ctx, query, arg))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrWalletNotFound
@@ -111,22 +116,15 @@ func (r *walletRepository) GetByShareableCode(ctx context.Context, code string)
 		return nil, err
 	}
 
-	if balance.Valid {
-		wallet.Balance = balance.Decimal
-	}
-	if fundingGoal.Valid {
-		wallet.FundingGoal = fundingGoal.Decimal
-	}
-
 	return wallet, nil
 }
 
 func (r *walletRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Wallet, error) {
 	query := `
-		SELECT id, creator_id, beneficiary_id, wallet_name, description, photo_url, balance, funding_goal, shareable_code, status, created_at, updated_at
-		FROM wallets
-		WHERE creator_id = $1 OR beneficiary_id = $1
-		ORDER BY created_at DESC`
+		SELECT ` + walletColumns + `
+		FROM wallets w
+		WHERE (w.creator_id = $1 OR w.beneficiary_id = $1) AND w.deleted_at IS NULL
+		ORDER BY w.created_at DESC`
 
 	rows, err := r.db.Pool.Query(ctx, query, userID)
 	if err != nil {
@@ -136,44 +134,22 @@ func (r *walletRepository) GetByUserID(ctx context.Context, userID string) ([]*d
 
 	var wallets []*domain.Wallet
 	for rows.Next() {
-		wallet := &domain.Wallet{}
-		var balance, fundingGoal decimal.NullDecimal
-
-		err := rows.Scan(
-			&wallet.ID,
-			&wallet.CreatorID,
-			&wallet.BeneficiaryID,
-			&wallet.WalletName,
-			&wallet.Description,
-			&wallet.PhotoURL,
-			&balance,
-			&fundingGoal,
-			&wallet.ShareableCode,
-			&wallet.Status,
-			&wallet.CreatedAt,
-			&wallet.UpdatedAt,
-		)
+		wallet, err := scanWallet(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		if balance.Valid {
-			wallet.Balance = balance.Decimal
-		}
-		if fundingGoal.Valid {
-			wallet.FundingGoal = fundingGoal.Decimal
-		}
-
 		wallets = append(wallets, wallet)
 	}
 
 	return wallets, nil
 }
 
+// Update saves the wallet's details. Its status only changes through
+// ChangeStatus.
 func (r *walletRepository) Update(ctx context.Context, wallet *domain.Wallet) error {
 	query := `
 		UPDATE wallets
-		SET wallet_name = $1, description = $2, photo_url = $3, funding_goal = $4, status = $5, updated_at = NOW()
+		SET wallet_name = $1, description = $2, photo_url = $3, photo_upload_id = $4, funding_goal = $5, updated_at = NOW()
 		WHERE id = $6
 		RETURNING updated_at`
 
@@ -181,8 +157,8 @@ func (r *walletRepository) Update(ctx context.Context, wallet *domain.Wallet) er
 		wallet.WalletName,
 		wallet.Description,
 		wallet.PhotoURL,
+		wallet.PhotoUploadID,
 		wallet.FundingGoal,
-		wallet.Status,
 		wallet.ID,
 	).Scan(&wallet.UpdatedAt)
 
@@ -196,21 +172,122 @@ func (r *walletRepository) Update(ctx context.Context, wallet *domain.Wallet) er
 	return nil
 }
 
-func (r *walletRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM wallets WHERE id = $1`
+func (r *walletRepository) ChangeStatus(ctx context.Context, wallet *domain.Wallet, change *domain.WalletStatusChange) error {
+	return r.changeStatus(ctx, wallet, change, false)
+}
+
+func (r *walletRepository) SoftDelete(ctx context.Context, wallet *domain.Wallet, change *domain.WalletStatusChange) error {
+	return r.changeStatus(ctx, wallet, change, true)
+}
 
-	result, err := r.db.Pool.Exec(ctx, query, id)
+// changeStatus moves the wallet from change.FromStatus to change.ToStatus and
+// records the change. It fails with ErrInvalidWalletTransition if the wallet's
+// status changed in the meantime, and with ErrWalletHasBalance when closing a
+// wallet that still holds funds.
+func (r *walletRepository) changeStatus(ctx context.Context, wallet *domain.Wallet, change *domain.WalletStatusChange, softDelete bool) error {
+	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	if result.RowsAffected() == 0 {
-		return domain.ErrWalletNotFound
+	var status domain.WalletStatus
+	var balance decimal.Decimal
+	err = tx.QueryRow(ctx, `SELECT status, balance FROM wallets WHERE id = $1 FOR UPDATE`, wallet.ID).Scan(&status, &balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrWalletNotFound
+		}
+		return err
+	}
+	if status != change.FromStatus {
+		return domain.ErrInvalidWalletTransition
+	}
+	if change.ToStatus == domain.WalletStatusClosed && !balance.IsZero() {
+		return domain.ErrWalletHasBalance
+	}
+
+	if err := updateWalletStatus(ctx, tx, wallet, change, softDelete); err != nil {
+		return err
 	}
 
+	return tx.Commit(ctx)
+}
+
+// updateWalletStatus saves change.ToStatus on the wallet's row and records the
+// change within tx. The caller has locked the row and checked the transition.
+func updateWalletStatus(ctx context.Context, tx pgx.Tx, wallet *domain.Wallet, change *domain.WalletStatusChange, softDelete bool) error {
+	err := tx.QueryRow(ctx, `
+		UPDATE wallets
+		SET status = $1, status_reason = $2, status_changed_at = NOW(), frozen_from = $3,
+			deleted_at = CASE WHEN $4 THEN NOW() ELSE deleted_at END, updated_at = NOW()
+		WHERE id = $5
+		RETURNING status_changed_at, deleted_at, updated_at`,
+		change.ToStatus,
+		change.Reason,
+		wallet.FrozenFrom,
+		softDelete,
+		wallet.ID,
+	).Scan(&wallet.StatusChangedAt, &wallet.DeletedAt, &wallet.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	change.WalletID = wallet.ID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO wallet_status_changes (wallet_id, from_status, to_status, reason, actor_type, actor_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		change.WalletID,
+		change.FromStatus,
+		change.ToStatus,
+		change.Reason,
+		change.ActorType,
+		change.ActorID,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	wallet.Status = change.ToStatus
+	wallet.StatusReason = change.Reason
 	return nil
 }
 
+func (r *walletRepository) GetStatusHistory(ctx context.Context, walletID string) ([]*domain.WalletStatusChange, error) {
+	query := `
+		SELECT id, wallet_id, from_status, to_status, reason, actor_type, actor_id, created_at
+		FROM wallet_status_changes
+		WHERE wallet_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.Pool.Query(ctx, query, walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*domain.WalletStatusChange
+	for rows.Next() {
+		change := &domain.WalletStatusChange{}
+		if err := rows.Scan(
+			&change.ID,
+			&change.WalletID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Reason,
+			&change.ActorType,
+			&change.ActorID,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
 func (r *walletRepository) UpdateBalance(ctx context.Context, id string, amount string) error {
 	query := `
 		UPDATE wallets
@@ -230,7 +307,9 @@ func (r *walletRepository) UpdateBalance(ctx context.Context, id string, amount
 }
 
 func (r *walletRepository) ShareableCodeExists(ctx context.Context, code string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM wallets WHERE shareable_code = $1)`
+	query := `
+		SELECT EXISTS(SELECT 1 FROM wallets WHERE shareable_code = $1)
+			OR EXISTS(SELECT 1 FROM wallet_retired_codes WHERE code = $1)`
 
 	var exists bool
 	err := r.db.Pool.QueryRow(ctx, query, code).Scan(&exists)
@@ -240,3 +319,55 @@ func (r *walletRepository) ShareableCodeExists(ctx context.Context, code string)
 
 	return exists, nil
 }
+
+func (r *walletRepository) RotateShareableCode(ctx context.Context, wallet *domain.Wallet, newCode, userID string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO wallet_retired_codes (code, wallet_id, retired_by)
+		VALUES ($1, $2, $3)`,
+		wallet.ShareableCode, wallet.ID, userID,
+	); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE wallets
+		SET shareable_code = $1, updated_at = NOW()
+		WHERE id = $2 AND shareable_code = $3
+		RETURNING updated_at`,
+		newCode, wallet.ID, wallet.ShareableCode,
+	).Scan(&wallet.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrWalletNotFound
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	wallet.ShareableCode = newCode
+	return nil
+}
+
+// lockWalletStatus locks the wallet's row for the rest of tx, so its status
+// cannot change while money moves, and returns the status.
+func lockWalletStatus(ctx context.Context, tx pgx.Tx, walletID string) (domain.WalletStatus, error) {
+	var status domain.WalletStatus
+	err := tx.QueryRow(ctx, `SELECT status FROM wallets WHERE id = $1 FOR UPDATE`, walletID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrWalletNotFound
+		}
+		return "", err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type walletShareLinkRepository struct {
+	db *database.PostgresDB
+}
+
+func NewWalletShareLinkRepository(db *database.PostgresDB) WalletShareLinkRepository {
+	return &walletShareLinkRepository{db: db}
+}
+
+const walletShareLinkColumns = `id, wallet_id, created_by, token, label, expires_at, max_contributions, view_count, contribution_count, contribution_total, revoked_at, created_at`
+
+func scanWalletShareLink(row pgx.Row) (*domain.WalletShareLink, error) {
+	link := &domain.WalletShareLink{}
+	err := row.Scan(
+		&link.ID,
+		&link.WalletID,
+		&link.CreatedBy,
+		&link.Token,
+		&link.Label,
+		&link.ExpiresAt,
+		&link.MaxContributions,
+		&link.ViewCount,
+		&link.ContributionCount,
+		&link.ContributionTotal,
+		&link.RevokedAt,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (r *walletShareLinkRepository) Create(ctx context.Context, link *domain.WalletShareLink) error {
+	query := `
+		INSERT INTO wallet_share_links (wallet_id, created_by, token, label, expires_at, max_contributions)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		link.WalletID,
+		link.CreatedBy,
+		link.Token,
+		link.Label,
+		link.ExpiresAt,
+		link.MaxContributions,
+	).Scan(&link.ID, &link.CreatedAt)
+}
+
+func (r *walletShareLinkRepository) GetByID(ctx context.Context, id string) (*domain.WalletShareLink, error) {
+	return r.getOne(ctx, `SELECT `+walletShareLinkColumns+` FROM wallet_share_links WHERE id = $1`, id)
+}
+
+func (r *walletShareLinkRepository) GetByToken(ctx context.Context, token string) (*domain.WalletShareLink, error) {
+	return r.getOne(ctx, `SELECT `+walletShareLinkColumns+` FROM wallet_share_links WHERE token = $1`, token)
+}
+
+func (r *walletShareLinkRepository) getOne(ctx context.Context, query string, arg string) (*domain.WalletShareLink, error) {
+	link, err := scanWalletShareLink(r.db.Pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (r *walletShareLinkRepository) GetByWalletID(ctx context.Context, walletID string) ([]*domain.WalletShareLink, error) {
+	query := `
+		SELECT ` + walletShareLinkColumns + `
+		FROM wallet_share_links
+		WHERE wallet_id = $1
+		ORDER BY revoked_at IS NOT NULL, created_at DESC`
+
+	rows, err := r.db.Pool.Query(ctx, query, walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*domain.WalletShareLink
+	for rows.Next() {
+		link, err := scanWalletShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+func (r *walletShareLinkRepository) Revoke(ctx context.Context, link *domain.WalletShareLink) error {
+	query := `
+		UPDATE wallet_share_links
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING revoked_at`
+
+	err := r.db.Pool.QueryRow(ctx, query, link.ID).Scan(&link.RevokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrShareLinkNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *walletShareLinkRepository) RecordView(ctx context.Context, id string) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE wallet_share_links SET view_count = view_count + 1 WHERE id = $1`, id)
+	return err
+}
+
+func (r *walletShareLinkRepository) RecordContribution(ctx context.Context, id string, amount decimal.Decimal) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE wallet_share_links
+		SET contribution_count = contribution_count + 1, contribution_total = contribution_total + $2
+		WHERE id = $1`,
+		id, amount,
+	)
+	return err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/pkg/database"
+)
+
+type walletSpendingRuleRepository struct {
+	db *database.PostgresDB
+}
+
+func NewWalletSpendingRuleRepository(db *database.PostgresDB) WalletSpendingRuleRepository {
+	return &walletSpendingRuleRepository{db: db}
+}
+
+func (r *walletSpendingRuleRepository) Create(ctx context.Context, rule *domain.WalletSpendingRule) error {
+	query := `
+		INSERT INTO wallet_spending_rules (wallet_id, pharmacy_id, organisation_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		rule.WalletID,
+		rule.PharmacyID,
+		rule.OrganisationID,
+	).Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrSpendingRuleExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *walletSpendingRuleRepository) GetByWalletID(ctx context.Context, walletID string) ([]*domain.WalletSpendingRule, error) {
+	query := `
+		SELECT r.id, r.wallet_id, r.pharmacy_id, r.organisation_id, COALESCE(p.name, o.name, ''), r.created_at
+		FROM wallet_spending_rules r
+		LEFT JOIN pharmacies p ON p.id = r.pharmacy_id
+		LEFT JOIN organisations o ON o.id = r.organisation_id
+		WHERE r.wallet_id = $1
+		ORDER BY r.created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.WalletSpendingRule
+	for rows.Next() {
+		rule := &domain.WalletSpendingRule{}
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.WalletID,
+			&rule.PharmacyID,
+			&rule.OrganisationID,
+			&rule.TargetName,
+			&rule.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *walletSpendingRuleRepository) Delete(ctx context.Context, walletID, ruleID string) error {
+	result, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM wallet_spending_rules WHERE id = $1 AND wallet_id = $2`,
+		ruleID, walletID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrSpendingRuleNotFound
+	}
+	return nil
+}
+
+func (r *walletSpendingRuleRepository) IsAllowed(ctx context.Context, walletID, pharmacyID string) (bool, error) {
+	query := `
+		SELECT NOT EXISTS (SELECT 1 FROM wallet_spending_rules WHERE wallet_id = $1)
+			OR EXISTS (
+				SELECT 1
+				FROM wallet_spending_rules r
+				JOIN pharmacies p ON p.id = $2
+				WHERE r.wallet_id = $1
+				AND (r.pharmacy_id = p.id OR r.organisation_id = p.organisation_id)
+			)`
+
+	var allowed bool
+	err := r.db.Pool.QueryRow(ctx, query, walletID, pharmacyID).Scan(&allowed)
+	return allowed, err
+}
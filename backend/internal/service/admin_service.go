@@ -5,15 +5,14 @@ import (
 
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/internal/repository"
-	"github.com/carewallet/backend/internal/utils"
+	"github.com/shopspring/decimal"
 )
 
 type AdminService interface {
 	GetPharmacies(ctx context.Context, filter PharmacyFilter) ([]*domain.Pharmacy, int, error)
 	GetPharmacy(ctx context.Context, id string) (*domain.Pharmacy, error)
-	CreatePharmacy(ctx context.Context, req CreatePharmacyRequest) (*domain.Pharmacy, error)
+	CreatePharmacy(ctx context.Context, adminID string, req CreatePharmacyRequest) (*domain.Pharmacy, error)
 	UpdatePharmacy(ctx context.Context, id string, req UpdatePharmacyRequest) (*domain.Pharmacy, error)
-	ApprovePharmacy(ctx context.Context, id string) (*domain.Pharmacy, error)
 	SuspendPharmacy(ctx context.Context, id string) (*domain.Pharmacy, error)
 	ReactivatePharmacy(ctx context.Context, id string) (*domain.Pharmacy, error)
 	DeletePharmacy(ctx context.Context, id string) error
@@ -27,24 +26,29 @@ type PharmacyFilter struct {
 	Limit  int
 }
 
+// CreatePharmacyRequest also sets up the pharmacy's first manager login from
+// ManagerUsername and ManagerName; the username defaults to "manager". The
+// manager chooses a password from a link emailed to the pharmacy.
 type CreatePharmacyRequest struct {
-	Name               string `json:"name"`
-	ShortCode          string `json:"short_code"`
-	RegistrationNumber string `json:"registration_number"`
-	Address            string `json:"address"`
-	Phone              string `json:"phone"`
-	Email              string `json:"email"`
-	Password           string `json:"password"`
+	Name               string   `json:"name"`
+	ShortCode          string   `json:"short_code"`
+	RegistrationNumber string   `json:"registration_number"`
+	Address            string   `json:"address"`
+	Phone              string   `json:"phone"`
+	Email              string   `json:"email"`
+	ManagerUsername    string   `json:"manager_username,omitempty"`
+	ManagerName        string   `json:"manager_name,omitempty"`
+	DailyCashInLimit   *float64 `json:"daily_cash_in_limit,omitempty"`
 }
 
 type UpdatePharmacyRequest struct {
-	Name               string `json:"name"`
-	ShortCode          string `json:"short_code"`
-	RegistrationNumber string `json:"registration_number"`
-	Address            string `json:"address"`
-	Phone              string `json:"phone"`
-	Email              string `json:"email"`
-	Password           string `json:"password,omitempty"`
+	Name               string   `json:"name"`
+	ShortCode          string   `json:"short_code"`
+	RegistrationNumber string   `json:"registration_number"`
+	Address            string   `json:"address"`
+	Phone              string   `json:"phone"`
+	Email              string   `json:"email"`
+	DailyCashInLimit   *float64 `json:"daily_cash_in_limit,omitempty"`
 }
 
 type DashboardStats struct {
@@ -58,12 +62,21 @@ type DashboardStats struct {
 type adminService struct {
 	pharmacyRepo    repository.PharmacyRepository
 	transactionRepo repository.TransactionRepository
+	staffService    PharmacyStaffService
+	auditService    AuditService
 }
 
-func NewAdminService(pharmacyRepo repository.PharmacyRepository, transactionRepo repository.TransactionRepository) AdminService {
+func NewAdminService(
+	pharmacyRepo repository.PharmacyRepository,
+	transactionRepo repository.TransactionRepository,
+	staffService PharmacyStaffService,
+	auditService AuditService,
+) AdminService {
 	return &adminService{
 		pharmacyRepo:    pharmacyRepo,
 		transactionRepo: transactionRepo,
+		staffService:    staffService,
+		auditService:    auditService,
 	}
 }
 
@@ -91,11 +104,9 @@ func (s *adminService) GetPharmacy(ctx context.Context, id string) (*domain.Phar
 	return s.pharmacyRepo.GetByID(ctx, id)
 }
 
-func (s *adminService) CreatePharmacy(ctx context.Context, req CreatePharmacyRequest) (*domain.Pharmacy, error) {
-	// Hash password
-	passwordHash, err := utils.HashPassword(req.Password)
-	if err != nil {
-		return nil, err
+func (s *adminService) CreatePharmacy(ctx context.Context, adminID string, req CreatePharmacyRequest) (*domain.Pharmacy, error) {
+	if req.Email == "" {
+		return nil, domain.ErrPharmacyEmailRequired
 	}
 
 	pharmacy := &domain.Pharmacy{
@@ -105,14 +116,28 @@ func (s *adminService) CreatePharmacy(ctx context.Context, req CreatePharmacyReq
 		Address:            req.Address,
 		Phone:              req.Phone,
 		Email:              req.Email,
-		PasswordHash:       passwordHash,
 		Status:             domain.PharmacyStatusActive,
 	}
+	if req.DailyCashInLimit != nil {
+		limit := decimal.NewFromFloat(*req.DailyCashInLimit)
+		pharmacy.DailyCashInLimit = &limit
+	}
 
 	if err := s.pharmacyRepo.Create(ctx, pharmacy); err != nil {
 		return nil, err
 	}
 
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "pharmacy.created", "pharmacy", pharmacy.ID, map[string]interface{}{
+		"name":       pharmacy.Name,
+		"short_code": pharmacy.ShortCode,
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.staffService.InviteManager(ctx, domain.AuditActorAdmin, adminID, pharmacy, req.ManagerUsername, req.ManagerName); err != nil {
+		return nil, err
+	}
+
 	return pharmacy, nil
 }
 
@@ -140,12 +165,9 @@ func (s *adminService) UpdatePharmacy(ctx context.Context, id string, req Update
 	if req.Email != "" {
 		pharmacy.Email = req.Email
 	}
-	if req.Password != "" {
-		passwordHash, err := utils.HashPassword(req.Password)
-		if err != nil {
-			return nil, err
-		}
-		pharmacy.PasswordHash = passwordHash
+	if req.DailyCashInLimit != nil {
+		limit := decimal.NewFromFloat(*req.DailyCashInLimit)
+		pharmacy.DailyCashInLimit = &limit
 	}
 
 	if err := s.pharmacyRepo.Update(ctx, pharmacy); err != nil {
@@ -155,24 +177,14 @@ func (s *adminService) UpdatePharmacy(ctx context.Context, id string, req Update
 	return pharmacy, nil
 }
 
-func (s *adminService) ApprovePharmacy(ctx context.Context, id string) (*domain.Pharmacy, error) {
+func (s *adminService) SuspendPharmacy(ctx context.Context, id string) (*domain.Pharmacy, error) {
 	pharmacy, err := s.pharmacyRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	pharmacy.Status = domain.PharmacyStatusActive
-	if err := s.pharmacyRepo.Update(ctx, pharmacy); err != nil {
-		return nil, err
-	}
-
-	return pharmacy, nil
-}
-
-func (s *adminService) SuspendPharmacy(ctx context.Context, id string) (*domain.Pharmacy, error) {
-	pharmacy, err := s.pharmacyRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
+	if pharmacy.Status != domain.PharmacyStatusActive {
+		return nil, domain.ErrInvalidPharmacyTransition
 	}
 
 	pharmacy.Status = domain.PharmacyStatusInactive
@@ -189,6 +201,10 @@ func (s *adminService) ReactivatePharmacy(ctx context.Context, id string) (*doma
 		return nil, err
 	}
 
+	if pharmacy.Status != domain.PharmacyStatusInactive {
+		return nil, domain.ErrInvalidPharmacyTransition
+	}
+
 	pharmacy.Status = domain.PharmacyStatusActive
 	if err := s.pharmacyRepo.Update(ctx, pharmacy); err != nil {
 		return nil, err
@@ -217,8 +233,8 @@ func (s *adminService) GetDashboardStats(ctx context.Context) (*DashboardStats,
 		switch p.Status {
 		case domain.PharmacyStatusActive:
 			stats.ActivePharmacies++
-		case domain.PharmacyStatusInactive:
-			// Pending count - in production this would be a separate status
+		case domain.PharmacyStatusPending:
+			stats.PendingPharmacies++
 		}
 	}
 
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+type AuditService interface {
+	Record(ctx context.Context, actorType domain.AuditActorType, actorID, action, entityType, entityID string, metadata map[string]interface{}) error
+	List(ctx context.Context, entityType, entityID string, page, pageSize int) ([]*domain.AuditLog, int, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+func NewAuditService(auditRepo repository.AuditLogRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+func (s *auditService) Record(ctx context.Context, actorType domain.AuditActorType, actorID, action, entityType, entityID string, metadata map[string]interface{}) error {
+	entry := &domain.AuditLog{
+		ActorType:  actorType,
+		Action:     action,
+		EntityType: entityType,
+		Metadata:   metadata,
+	}
+	if actorID != "" {
+		entry.ActorID = &actorID
+	}
+	if entityID != "" {
+		entry.EntityID = &entityID
+	}
+
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *auditService) List(ctx context.Context, entityType, entityID string, page, pageSize int) ([]*domain.AuditLog, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 50
+	}
+
+	return s.auditRepo.List(ctx, entityType, entityID, page, pageSize)
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/bankverify"
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+// BankAccountService manages the bank accounts settlements are paid into.
+// Pharmacies submit changes, the holder name is verified with the bank, and
+// an admin approves the change before it takes effect after a cooling-off
+// period.
+type BankAccountService interface {
+	Submit(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.BankAccountRequest) (*dto.BankAccountResponse, error)
+	ListForPharmacy(ctx context.Context, pharmacyID string) ([]*dto.BankAccountResponse, error)
+	List(ctx context.Context, status string) ([]*dto.BankAccountResponse, error)
+	Verify(ctx context.Context, adminID, accountID string) (*dto.BankAccountResponse, error)
+	Approve(ctx context.Context, adminID, accountID string) (*dto.BankAccountResponse, error)
+	Reject(ctx context.Context, adminID, accountID, reason string) (*dto.BankAccountResponse, error)
+	GetEffective(ctx context.Context, pharmacyID string) (*domain.PharmacyBankAccount, error)
+}
+
+type bankAccountService struct {
+	bankAccountRepo repository.BankAccountRepository
+	pharmacyRepo    repository.PharmacyRepository
+	verifier        bankverify.Verifier
+	auditService    AuditService
+	config          *config.Config
+}
+
+func NewBankAccountService(
+	bankAccountRepo repository.BankAccountRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	verifier bankverify.Verifier,
+	auditService AuditService,
+	cfg *config.Config,
+) BankAccountService {
+	return &bankAccountService{
+		bankAccountRepo: bankAccountRepo,
+		pharmacyRepo:    pharmacyRepo,
+		verifier:        verifier,
+		auditService:    auditService,
+		config:          cfg,
+	}
+}
+
+func (s *bankAccountService) Submit(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.BankAccountRequest) (*dto.BankAccountResponse, error) {
+	if _, err := s.pharmacyRepo.GetByID(ctx, pharmacyID); err != nil {
+		return nil, err
+	}
+
+	account := &domain.PharmacyBankAccount{
+		PharmacyID:         pharmacyID,
+		BankName:           strings.TrimSpace(req.BankName),
+		BankCode:           strings.TrimSpace(req.BankCode),
+		BranchCode:         strings.TrimSpace(req.BranchCode),
+		AccountNumber:      strings.TrimSpace(req.AccountNumber),
+		AccountHolder:      strings.TrimSpace(req.AccountHolder),
+		Status:             domain.BankAccountStatusPending,
+		VerificationStatus: domain.BankVerificationUnverified,
+	}
+
+	if account.BankName == "" || account.AccountHolder == "" {
+		return nil, domain.ErrInvalidBankAccount
+	}
+
+	// Only the latest change request is reviewed.
+	if err := s.bankAccountRepo.CancelPending(ctx, pharmacyID); err != nil {
+		return nil, err
+	}
+
+	s.verify(ctx, account)
+
+	if err := s.bankAccountRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "bank_account.submitted", "bank_account", account.ID, map[string]interface{}{
+		"pharmacy_id":         pharmacyID,
+		"bank_name":           account.BankName,
+		"account_number":      account.MaskedAccountNumber(),
+		"verification_status": string(account.VerificationStatus),
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(account, nil), nil
+}
+
+func (s *bankAccountService) ListForPharmacy(ctx context.Context, pharmacyID string) ([]*dto.BankAccountResponse, error) {
+	accounts, err := s.bankAccountRepo.GetByPharmacyID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.GetEffective(ctx, pharmacyID)
+	if err != nil && !errors.Is(err, domain.ErrNoActiveBankAccount) {
+		return nil, err
+	}
+
+	responses := make([]*dto.BankAccountResponse, len(accounts))
+	for i, account := range accounts {
+		responses[i] = s.toResponse(account, active)
+	}
+
+	return responses, nil
+}
+
+func (s *bankAccountService) List(ctx context.Context, status string) ([]*dto.BankAccountResponse, error) {
+	accounts, err := s.bankAccountRepo.GetByStatus(ctx, domain.BankAccountStatus(status))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.BankAccountResponse, len(accounts))
+	for i, account := range accounts {
+		responses[i] = s.toResponse(account, nil)
+	}
+
+	return responses, nil
+}
+
+func (s *bankAccountService) Verify(ctx context.Context, adminID, accountID string) (*dto.BankAccountResponse, error) {
+	account, err := s.bankAccountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Status != domain.BankAccountStatusPending {
+		return nil, domain.ErrBankAccountNotPending
+	}
+
+	s.verify(ctx, account)
+
+	if err := s.bankAccountRepo.UpdateVerification(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "bank_account.verified", "bank_account", account.ID, map[string]interface{}{
+		"verification_status": string(account.VerificationStatus),
+		"verified_name":       account.VerifiedName,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(account, nil), nil
+}
+
+func (s *bankAccountService) Approve(ctx context.Context, adminID, accountID string) (*dto.BankAccountResponse, error) {
+	account, err := s.bankAccountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Status != domain.BankAccountStatusPending {
+		return nil, domain.ErrBankAccountNotPending
+	}
+
+	if account.VerificationStatus != domain.BankVerificationVerified {
+		return nil, domain.ErrBankAccountNotVerified
+	}
+
+	now := time.Now()
+	effectiveAt := now.Add(time.Duration(s.config.BankAccountCoolingOffHours) * time.Hour)
+	account.Status = domain.BankAccountStatusApproved
+	account.ReviewedBy = &adminID
+	account.ReviewedAt = &now
+	account.EffectiveAt = &effectiveAt
+
+	if err := s.bankAccountRepo.Review(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "bank_account.approved", "bank_account", account.ID, map[string]interface{}{
+		"pharmacy_id":  account.PharmacyID,
+		"effective_at": effectiveAt.Format(time.RFC3339),
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Bank account %s for pharmacy %s approved, effective from %s", account.ID, account.PharmacyID, effectiveAt.Format(time.RFC3339))
+
+	return s.toResponse(account, nil), nil
+}
+
+func (s *bankAccountService) Reject(ctx context.Context, adminID, accountID, reason string) (*dto.BankAccountResponse, error) {
+	account, err := s.bankAccountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Status != domain.BankAccountStatusPending {
+		return nil, domain.ErrBankAccountNotPending
+	}
+
+	now := time.Now()
+	account.Status = domain.BankAccountStatusRejected
+	account.ReviewedBy = &adminID
+	account.ReviewedAt = &now
+	account.RejectionReason = strings.TrimSpace(reason)
+
+	if err := s.bankAccountRepo.Review(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "bank_account.rejected", "bank_account", account.ID, map[string]interface{}{
+		"pharmacy_id": account.PharmacyID,
+		"reason":      account.RejectionReason,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(account, nil), nil
+}
+
+func (s *bankAccountService) GetEffective(ctx context.Context, pharmacyID string) (*domain.PharmacyBankAccount, error) {
+	return s.bankAccountRepo.GetEffective(ctx, pharmacyID)
+}
+
+// verify asks the verifier for the registered holder name and records whether
+// it matches the name the pharmacy supplied. Provider errors leave the account
+// marked as failed so an admin can retry.
+func (s *bankAccountService) verify(ctx context.Context, account *domain.PharmacyBankAccount) {
+	result, err := s.verifier.Verify(ctx, bankverify.AccountDetails{
+		BankCode:      account.BankCode,
+		BranchCode:    account.BranchCode,
+		AccountNumber: account.AccountNumber,
+		AccountHolder: account.AccountHolder,
+	})
+	if err != nil {
+		log.Printf("Failed to verify bank account for pharmacy %s: %v", account.PharmacyID, err)
+		account.VerificationStatus = domain.BankVerificationFailed
+		account.VerifiedName = ""
+		account.VerifiedAt = nil
+		return
+	}
+
+	now := time.Now()
+	account.VerifiedName = result.AccountName
+	account.VerifiedAt = &now
+	if bankverify.NamesMatch(account.AccountHolder, result.AccountName) {
+		account.VerificationStatus = domain.BankVerificationVerified
+	} else {
+		account.VerificationStatus = domain.BankVerificationMismatch
+	}
+}
+
+func (s *bankAccountService) toResponse(account *domain.PharmacyBankAccount, active *domain.PharmacyBankAccount) *dto.BankAccountResponse {
+	response := &dto.BankAccountResponse{
+		ID:                 account.ID,
+		PharmacyID:         account.PharmacyID,
+		BankName:           account.BankName,
+		BankCode:           account.BankCode,
+		BranchCode:         account.BranchCode,
+		AccountNumber:      account.MaskedAccountNumber(),
+		AccountHolder:      account.AccountHolder,
+		Status:             string(account.Status),
+		VerificationStatus: string(account.VerificationStatus),
+		VerifiedName:       account.VerifiedName,
+		RejectionReason:    account.RejectionReason,
+		IsActive:           active != nil && active.ID == account.ID,
+		CreatedAt:          account.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if account.EffectiveAt != nil {
+		effectiveAt := account.EffectiveAt.Format("2006-01-02T15:04:05Z07:00")
+		response.EffectiveAt = &effectiveAt
+	}
+
+	return response
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+// DisputeService lets wallet members contest pharmacy withdrawals. The
+// pharmacy is notified and may respond with evidence; an admin then upholds
+// the dispute, which reverses the withdrawal, or rejects it.
+type DisputeService interface {
+	Open(ctx context.Context, userID string, req dto.OpenDisputeRequest) (*domain.Dispute, error)
+	List(ctx context.Context, filter repository.DisputeFilter) ([]*domain.Dispute, int, error)
+	// Get returns a dispute. A non-empty userID restricts access to members
+	// of the disputed wallet and a non-empty pharmacyID to that pharmacy.
+	Get(ctx context.Context, disputeID, userID, pharmacyID string) (*domain.Dispute, error)
+	Respond(ctx context.Context, pharmacyID, pharmacyUserID, disputeID string, req dto.RespondDisputeRequest) (*domain.Dispute, error)
+	Uphold(ctx context.Context, adminID, disputeID, note string) (*domain.Dispute, error)
+	Reject(ctx context.Context, adminID, disputeID, reason string) (*domain.Dispute, error)
+}
+
+type disputeService struct {
+	disputeRepo     repository.DisputeRepository
+	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
+	userRepo        repository.UserRepository
+	pharmacyRepo    repository.PharmacyRepository
+	uploadService   UploadService
+	emailService    EmailService
+	auditService    AuditService
+	config          *config.Config
+}
+
+func NewDisputeService(
+	disputeRepo repository.DisputeRepository,
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	userRepo repository.UserRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	uploadService UploadService,
+	emailService EmailService,
+	auditService AuditService,
+	cfg *config.Config,
+) DisputeService {
+	return &disputeService{
+		disputeRepo:     disputeRepo,
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		userRepo:        userRepo,
+		pharmacyRepo:    pharmacyRepo,
+		uploadService:   uploadService,
+		emailService:    emailService,
+		auditService:    auditService,
+		config:          cfg,
+	}
+}
+
+func (s *disputeService) Open(ctx context.Context, userID string, req dto.OpenDisputeRequest) (*domain.Dispute, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.walletRepo.GetByID(ctx, transaction.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrTransactionNotFound
+	}
+
+	if transaction.Type != domain.TransactionTypeWithdrawal || transaction.PharmacyID == nil {
+		return nil, domain.ErrNotDisputable
+	}
+	if transaction.Status == domain.TransactionStatusReversed {
+		return nil, domain.ErrTransactionReversed
+	}
+	if transaction.Status != domain.TransactionStatusCompleted {
+		return nil, domain.ErrNotDisputable
+	}
+	if days := s.config.DisputeWindowDays; days > 0 && time.Since(transaction.CreatedAt) > time.Duration(days)*24*time.Hour {
+		return nil, domain.ErrDisputeWindowClosed
+	}
+
+	dispute := &domain.Dispute{
+		TransactionID: transaction.ID,
+		WalletID:      transaction.WalletID,
+		PharmacyID:    *transaction.PharmacyID,
+		OpenedBy:      userID,
+		Reason:        strings.TrimSpace(req.Reason),
+		Status:        domain.DisputeStatusOpen,
+	}
+
+	if err := s.disputeRepo.Create(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "dispute.opened", "dispute", dispute.ID, map[string]interface{}{
+		"transaction_id": transaction.ID,
+		"wallet_id":      transaction.WalletID,
+		"pharmacy_id":    dispute.PharmacyID,
+		"amount":         transaction.Amount.String(),
+		"reason":         dispute.Reason,
+	}); err != nil {
+		log.Printf("Failed to audit dispute %s: %v", dispute.ID, err)
+	}
+
+	s.notifyPharmacy(ctx, dispute.PharmacyID, "A CareWallet withdrawal has been disputed", fmt.Sprintf(
+		"Hello,\n\nA wallet member has disputed the withdrawal of R%s made at your pharmacy on %s (transaction %s):\n\n%s\n\nPlease respond with any supporting evidence from your CareWallet pharmacy portal.",
+		transaction.Amount.StringFixed(2), transaction.CreatedAt.In(s.config.Location()).Format("2 January 2006"), transaction.ID, dispute.Reason,
+	))
+
+	return dispute, nil
+}
+
+func (s *disputeService) List(ctx context.Context, filter repository.DisputeFilter) ([]*domain.Dispute, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	return s.disputeRepo.List(ctx, filter)
+}
+
+func (s *disputeService) Get(ctx context.Context, disputeID, userID, pharmacyID string) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacyID != "" && dispute.PharmacyID != pharmacyID {
+		return nil, domain.ErrDisputeNotFound
+	}
+
+	if userID != "" {
+		wallet, err := s.walletRepo.GetByID(ctx, dispute.WalletID)
+		if err != nil {
+			return nil, err
+		}
+		if !wallet.CanBeAccessedBy(userID) {
+			return nil, domain.ErrDisputeNotFound
+		}
+	}
+
+	return dispute, nil
+}
+
+func (s *disputeService) Respond(ctx context.Context, pharmacyID, pharmacyUserID, disputeID string, req dto.RespondDisputeRequest) (*domain.Dispute, error) {
+	dispute, err := s.Get(ctx, disputeID, "", pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispute.IsResolved() {
+		return nil, domain.ErrDisputeResolved
+	}
+
+	evidence := make([]string, 0, len(req.EvidenceUploadIDs))
+	for _, id := range req.EvidenceUploadIDs {
+		if _, err := s.uploadService.GetOwned(ctx, domain.UploadOwnerPharmacy, pharmacyID, id, domain.UploadPurposeDisputeEvidence); err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, id)
+	}
+
+	now := time.Now()
+	dispute.PharmacyResponse = strings.TrimSpace(req.Response)
+	dispute.EvidenceUploadIDs = evidence
+	dispute.RespondedBy = &pharmacyUserID
+	dispute.RespondedAt = &now
+
+	if err := s.disputeRepo.Respond(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "dispute.responded", "dispute", dispute.ID, map[string]interface{}{
+		"pharmacy_id":         pharmacyID,
+		"transaction_id":      dispute.TransactionID,
+		"evidence_upload_ids": evidence,
+	}); err != nil {
+		log.Printf("Failed to audit response to dispute %s: %v", dispute.ID, err)
+	}
+
+	return dispute, nil
+}
+
+func (s *disputeService) Uphold(ctx context.Context, adminID, disputeID, note string) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispute.IsResolved() {
+		return nil, domain.ErrDisputeResolved
+	}
+
+	original, err := s.transactionRepo.GetByID(ctx, dispute.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dispute.ResolvedBy = &adminID
+	dispute.ResolvedAt = &now
+	dispute.ResolutionNote = strings.TrimSpace(note)
+
+	// The reversal carries the original's fee and net amount so that, when
+	// the withdrawal has already been settled, the pharmacy's share is
+	// deducted from its next settlement.
+	reversal := &domain.Transaction{
+		WalletID:       original.WalletID,
+		Type:           domain.TransactionTypeReversal,
+		Amount:         original.Amount,
+		Fee:            original.Fee,
+		NetAmount:      original.NetAmount,
+		Status:         domain.TransactionStatusCompleted,
+		PharmacyID:     original.PharmacyID,
+		PharmacyName:   original.PharmacyName,
+		ReversalOf:     &original.ID,
+		ReversalReason: "Dispute upheld",
+	}
+	if dispute.ResolutionNote != "" {
+		reversal.ReversalReason += ": " + dispute.ResolutionNote
+	}
+
+	settled := original.SettlementID != nil
+	if err := s.disputeRepo.Uphold(ctx, dispute, original, reversal); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "dispute.upheld", "dispute", dispute.ID, map[string]interface{}{
+		"transaction_id": original.ID,
+		"reversal_id":    reversal.ID,
+		"pharmacy_id":    dispute.PharmacyID,
+		"amount":         original.Amount.String(),
+		"settled":        settled,
+		"note":           dispute.ResolutionNote,
+	}); err != nil {
+		log.Printf("Failed to audit upholding of dispute %s: %v", dispute.ID, err)
+	}
+
+	amount := original.Amount.StringFixed(2)
+	s.notifyOpener(ctx, dispute, "Your CareWallet dispute was upheld", fmt.Sprintf(
+		"Hello,\n\nWe upheld your dispute of the R%s withdrawal at %s. The amount has been returned to the wallet.",
+		amount, original.PharmacyName,
+	))
+
+	pharmacyBody := fmt.Sprintf("Hello,\n\nThe dispute of the R%s withdrawal made at your pharmacy on %s (transaction %s) was upheld and the withdrawal has been reversed.",
+		amount, original.CreatedAt.In(s.config.Location()).Format("2 January 2006"), original.ID)
+	if settled {
+		pharmacyBody += fmt.Sprintf(" As it was already settled, R%s will be deducted from your next settlement.", original.NetAmount.StringFixed(2))
+	}
+	s.notifyPharmacy(ctx, dispute.PharmacyID, "A disputed CareWallet withdrawal was reversed", pharmacyBody)
+
+	return dispute, nil
+}
+
+func (s *disputeService) Reject(ctx context.Context, adminID, disputeID, reason string) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispute.IsResolved() {
+		return nil, domain.ErrDisputeResolved
+	}
+
+	now := time.Now()
+	dispute.ResolvedBy = &adminID
+	dispute.ResolvedAt = &now
+	dispute.ResolutionNote = strings.TrimSpace(reason)
+
+	if err := s.disputeRepo.Reject(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "dispute.rejected", "dispute", dispute.ID, map[string]interface{}{
+		"transaction_id": dispute.TransactionID,
+		"pharmacy_id":    dispute.PharmacyID,
+		"reason":         dispute.ResolutionNote,
+	}); err != nil {
+		log.Printf("Failed to audit rejection of dispute %s: %v", dispute.ID, err)
+	}
+
+	s.notifyOpener(ctx, dispute, "Your CareWallet dispute", fmt.Sprintf(
+		"Hello,\n\nWe reviewed your dispute of transaction %s and could not uphold it:\n\n%s",
+		dispute.TransactionID, dispute.ResolutionNote,
+	))
+	s.notifyPharmacy(ctx, dispute.PharmacyID, "A CareWallet dispute was rejected", fmt.Sprintf(
+		"Hello,\n\nThe dispute of transaction %s at your pharmacy was rejected. No further action is needed.",
+		dispute.TransactionID,
+	))
+
+	return dispute, nil
+}
+
+// notifyPharmacy emails the pharmacy about a dispute. The dispute has already
+// been saved, so a failed email is only logged.
+func (s *disputeService) notifyPharmacy(ctx context.Context, pharmacyID, subject, body string) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		log.Printf("Failed to load pharmacy %s for dispute email: %v", pharmacyID, err)
+		return
+	}
+	if pharmacy.Email == "" {
+		return
+	}
+
+	if err := s.emailService.SendEmail(ctx, pharmacy.Email, subject, body); err != nil {
+		log.Printf("Failed to email pharmacy %s: %v", pharmacy.ID, err)
+	}
+}
+
+// notifyOpener emails the wallet member who opened the dispute.
+func (s *disputeService) notifyOpener(ctx context.Context, dispute *domain.Dispute, subject, body string) {
+	user, err := s.userRepo.GetByID(ctx, dispute.OpenedBy)
+	if err != nil {
+		log.Printf("Failed to load user %s for dispute email: %v", dispute.OpenedBy, err)
+		return
+	}
+
+	if err := s.emailService.SendEmail(ctx, user.Email, subject, body); err != nil {
+		log.Printf("Failed to email user %s: %v", user.ID, err)
+	}
+}
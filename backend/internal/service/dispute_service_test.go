@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+type fakeTransactionRepo struct {
+	repository.TransactionRepository
+	transactions map[string]*domain.Transaction
+	reversals    []*domain.Transaction
+}
+
+func (r *fakeTransactionRepo) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
+	transaction, ok := r.transactions[id]
+	if !ok {
+		return nil, domain.ErrTransactionNotFound
+	}
+	return transaction, nil
+}
+
+func (r *fakeTransactionRepo) Reverse(ctx context.Context, original, reversal *domain.Transaction) error {
+	if original.Status == domain.TransactionStatusReversed {
+		return domain.ErrTransactionReversed
+	}
+	original.Status = domain.TransactionStatusReversed
+	reversal.ID = "reversal-of-" + original.ID
+	r.reversals = append(r.reversals, reversal)
+	return nil
+}
+
+type fakeDisputeRepo struct {
+	repository.DisputeRepository
+	transactionRepo *fakeTransactionRepo
+	disputes        map[string]*domain.Dispute
+}
+
+func (r *fakeDisputeRepo) Create(ctx context.Context, dispute *domain.Dispute) error {
+	for _, existing := range r.disputes {
+		if existing.TransactionID == dispute.TransactionID {
+			return domain.ErrDisputeExists
+		}
+	}
+	dispute.ID = "d1"
+	r.disputes[dispute.ID] = dispute
+	return nil
+}
+
+func (r *fakeDisputeRepo) GetByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	dispute, ok := r.disputes[id]
+	if !ok {
+		return nil, domain.ErrDisputeNotFound
+	}
+	return dispute, nil
+}
+
+func (r *fakeDisputeRepo) Respond(ctx context.Context, dispute *domain.Dispute) error {
+	if dispute.IsResolved() {
+		return domain.ErrDisputeResolved
+	}
+	dispute.Status = domain.DisputeStatusResponded
+	return nil
+}
+
+func (r *fakeDisputeRepo) Uphold(ctx context.Context, dispute *domain.Dispute, original, reversal *domain.Transaction) error {
+	if err := r.transactionRepo.Reverse(ctx, original, reversal); err != nil {
+		return err
+	}
+	dispute.Status = domain.DisputeStatusUpheld
+	dispute.ReversalTransactionID = &reversal.ID
+	return nil
+}
+
+func (r *fakeDisputeRepo) Reject(ctx context.Context, dispute *domain.Dispute) error {
+	dispute.Status = domain.DisputeStatusRejected
+	return nil
+}
+
+type fakePharmacyRepo struct {
+	repository.PharmacyRepository
+}
+
+func (r *fakePharmacyRepo) GetByID(ctx context.Context, id string) (*domain.Pharmacy, error) {
+	return &domain.Pharmacy{ID: id, Email: id + "@example.com"}, nil
+}
+
+// newTestDisputeService returns a dispute service over one completed R100
+// withdrawal at pharmacy p1, made at createdAt from a wallet whose creator is
+// "creator".
+func newTestDisputeService(createdAt time.Time, change func(transaction *domain.Transaction)) (DisputeService, *fakeDisputeRepo, *fakeTransactionRepo, *fakeEmailService) {
+	pharmacyID := "p1"
+	transaction := &domain.Transaction{
+		ID:           "t1",
+		WalletID:     "w1",
+		Type:         domain.TransactionTypeWithdrawal,
+		Amount:       decimal.NewFromInt(100),
+		Fee:          decimal.NewFromInt(2),
+		NetAmount:    decimal.NewFromInt(98),
+		Status:       domain.TransactionStatusCompleted,
+		PharmacyID:   &pharmacyID,
+		PharmacyName: "Test Pharmacy",
+		CreatedAt:    createdAt,
+	}
+	change(transaction)
+
+	transactionRepo := &fakeTransactionRepo{transactions: map[string]*domain.Transaction{"t1": transaction}}
+	disputeRepo := &fakeDisputeRepo{transactionRepo: transactionRepo, disputes: make(map[string]*domain.Dispute)}
+	walletRepo := &fakeWalletRepo{wallet: &domain.Wallet{ID: "w1", CreatorID: "creator", Status: domain.WalletStatusActive}}
+	emailService := &fakeEmailService{}
+	cfg := &config.Config{Timezone: "UTC", DisputeWindowDays: 60}
+
+	disputeService := NewDisputeService(disputeRepo, transactionRepo, walletRepo, &fakeUserRepo{}, &fakePharmacyRepo{}, nil, emailService, fakeAuditService{}, cfg)
+	return disputeService, disputeRepo, transactionRepo, emailService
+}
+
+func TestDisputeOpen(t *testing.T) {
+	settlementID := "s1"
+	unchanged := func(transaction *domain.Transaction) {}
+
+	tests := []struct {
+		name      string
+		userID    string
+		createdAt time.Time
+		change    func(transaction *domain.Transaction)
+		wantErr   error
+	}{
+		{"recent withdrawal", "creator", time.Now().Add(-time.Hour), unchanged, nil},
+		{"settled withdrawal", "creator", time.Now().AddDate(0, 0, -30), func(transaction *domain.Transaction) { transaction.SettlementID = &settlementID }, nil},
+		{"last day of the window", "creator", time.Now().AddDate(0, 0, -60).Add(time.Minute), unchanged, nil},
+		{"window closed", "creator", time.Now().AddDate(0, 0, -60).Add(-time.Minute), unchanged, domain.ErrDisputeWindowClosed},
+		{"not a wallet member", "stranger", time.Now(), unchanged, domain.ErrTransactionNotFound},
+		{"deposit", "creator", time.Now(), func(transaction *domain.Transaction) { transaction.Type = domain.TransactionTypeDeposit }, domain.ErrNotDisputable},
+		{"withdrawal without a pharmacy", "creator", time.Now(), func(transaction *domain.Transaction) { transaction.PharmacyID = nil }, domain.ErrNotDisputable},
+		{"pending withdrawal", "creator", time.Now(), func(transaction *domain.Transaction) { transaction.Status = domain.TransactionStatusPending }, domain.ErrNotDisputable},
+		{"reversed withdrawal", "creator", time.Now(), func(transaction *domain.Transaction) { transaction.Status = domain.TransactionStatusReversed }, domain.ErrTransactionReversed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disputeService, repo, _, emailService := newTestDisputeService(tt.createdAt, tt.change)
+
+			dispute, err := disputeService.Open(context.Background(), tt.userID, dto.OpenDisputeRequest{TransactionID: "t1", Reason: " Not collected "})
+			if err != tt.wantErr {
+				t.Fatalf("Open() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.disputes) != 0 || len(emailService.sent) != 0 {
+					t.Fatalf("Open() stored %d disputes and sent %d emails, want none", len(repo.disputes), len(emailService.sent))
+				}
+				return
+			}
+			if dispute.Status != domain.DisputeStatusOpen || dispute.PharmacyID != "p1" || dispute.Reason != "Not collected" {
+				t.Fatalf("Open() = %s dispute at %s for %q, want open at p1 for %q", dispute.Status, dispute.PharmacyID, dispute.Reason, "Not collected")
+			}
+			if len(emailService.sent) != 1 {
+				t.Fatalf("Open() sent %d emails, want 1 to the pharmacy", len(emailService.sent))
+			}
+
+			if _, err := disputeService.Open(context.Background(), tt.userID, dto.OpenDisputeRequest{TransactionID: "t1", Reason: "Again"}); err != domain.ErrDisputeExists {
+				t.Fatalf("second Open() error = %v, want %v", err, domain.ErrDisputeExists)
+			}
+		})
+	}
+}
+
+func TestDisputeResolution(t *testing.T) {
+	tests := []struct {
+		name         string
+		uphold       bool
+		wantStatus   domain.DisputeStatus
+		wantReversal bool
+	}{
+		{"upheld", true, domain.DisputeStatusUpheld, true},
+		{"rejected", false, domain.DisputeStatusRejected, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			disputeService, _, transactionRepo, emailService := newTestDisputeService(time.Now().Add(-time.Hour), func(transaction *domain.Transaction) {})
+
+			dispute, err := disputeService.Open(ctx, "creator", dto.OpenDisputeRequest{TransactionID: "t1", Reason: "Not collected"})
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+
+			if _, err := disputeService.Respond(ctx, "p2", "pu1", dispute.ID, dto.RespondDisputeRequest{Response: "Collected"}); err != domain.ErrDisputeNotFound {
+				t.Fatalf("Respond() by another pharmacy error = %v, want %v", err, domain.ErrDisputeNotFound)
+			}
+			dispute, err = disputeService.Respond(ctx, "p1", "pu1", dispute.ID, dto.RespondDisputeRequest{Response: " Collected in person "})
+			if err != nil {
+				t.Fatalf("Respond() error = %v", err)
+			}
+			if dispute.Status != domain.DisputeStatusResponded || dispute.PharmacyResponse != "Collected in person" {
+				t.Fatalf("Respond() = %s with %q, want responded with %q", dispute.Status, dispute.PharmacyResponse, "Collected in person")
+			}
+
+			if tt.uphold {
+				dispute, err = disputeService.Uphold(ctx, "admin", dispute.ID, "No signature")
+			} else {
+				dispute, err = disputeService.Reject(ctx, "admin", dispute.ID, "Signature on file")
+			}
+			if err != nil {
+				t.Fatalf("resolving error = %v", err)
+			}
+			if dispute.Status != tt.wantStatus {
+				t.Fatalf("status = %s, want %s", dispute.Status, tt.wantStatus)
+			}
+
+			if tt.wantReversal {
+				if len(transactionRepo.reversals) != 1 {
+					t.Fatalf("recorded %d reversals, want 1", len(transactionRepo.reversals))
+				}
+				reversal := transactionRepo.reversals[0]
+				if !reversal.Amount.Equal(decimal.NewFromInt(100)) || !reversal.NetAmount.Equal(decimal.NewFromInt(98)) ||
+					*reversal.ReversalOf != "t1" || reversal.ReversalReason != "Dispute upheld: No signature" {
+					t.Fatalf("reversal = %s (net %s) of %s for %q, want 100 (net 98) of t1 for %q",
+						reversal.Amount, reversal.NetAmount, *reversal.ReversalOf, reversal.ReversalReason, "Dispute upheld: No signature")
+				}
+			} else if len(transactionRepo.reversals) != 0 {
+				t.Fatalf("recorded %d reversals, want none", len(transactionRepo.reversals))
+			}
+
+			// One email to the pharmacy on opening, then one each to the
+			// opener and the pharmacy on resolution.
+			if len(emailService.sent) != 3 {
+				t.Fatalf("sent %d emails, want 3", len(emailService.sent))
+			}
+
+			if _, err := disputeService.Uphold(ctx, "admin", dispute.ID, ""); err != domain.ErrDisputeResolved {
+				t.Fatalf("second Uphold() error = %v, want %v", err, domain.ErrDisputeResolved)
+			}
+			if _, err := disputeService.Reject(ctx, "admin", dispute.ID, ""); err != domain.ErrDisputeResolved {
+				t.Fatalf("second Reject() error = %v, want %v", err, domain.ErrDisputeResolved)
+			}
+			if _, err := disputeService.Respond(ctx, "p1", "pu1", dispute.ID, dto.RespondDisputeRequest{Response: "Late"}); err != domain.ErrDisputeResolved {
+				t.Fatalf("Respond() after resolution error = %v, want %v", err, domain.ErrDisputeResolved)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/fees"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// FeeQuoteService tells pharmacies and beneficiaries what a withdrawal will
+// cost before it is made, and holds that price for the withdrawal that
+// redeems the quote.
+type FeeQuoteService interface {
+	// Quote prices a withdrawal from a wallet the user can access without
+	// making it, including whether the balance covers it.
+	Quote(ctx context.Context, userID string, req dto.FeeQuoteRequest) (*dto.FeeQuoteResponse, error)
+	// QuoteForPharmacy prices a withdrawal at the pharmacy. The wallet code is
+	// resolved like a wallet lookup, so the quote is logged and counted
+	// against the pharmacy's amount check limit, and nothing about the
+	// wallet's balance is given away.
+	QuoteForPharmacy(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.FeeQuoteRequest) (*dto.FeeQuoteResponse, error)
+	// Check returns the quote for a withdrawal about to use it, failing with
+	// ErrFeeQuoteMismatch unless the withdrawal is for exactly the quoted
+	// wallet, pharmacy and amount and ErrFeeQuoteExpired if it can no longer
+	// be used. The quote is only redeemed when the withdrawal is recorded, so
+	// a withdrawal that fails leaves it for the next attempt.
+	Check(ctx context.Context, quoteID, walletID, pharmacyID string, amount decimal.Decimal) (*domain.FeeQuote, error)
+	Get(ctx context.Context, id string) (*domain.FeeQuote, error)
+}
+
+type feeQuoteService struct {
+	feeQuoteRepo     repository.FeeQuoteRepository
+	walletRepo       repository.WalletRepository
+	pharmacyRepo     repository.PharmacyRepository
+	spendingRuleRepo repository.WalletSpendingRuleRepository
+	lookupService    WalletLookupService
+	feeEngine        fees.Engine
+	config           *config.Config
+}
+
+func NewFeeQuoteService(
+	feeQuoteRepo repository.FeeQuoteRepository,
+	walletRepo repository.WalletRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	spendingRuleRepo repository.WalletSpendingRuleRepository,
+	lookupService WalletLookupService,
+	feeEngine fees.Engine,
+	cfg *config.Config,
+) FeeQuoteService {
+	return &feeQuoteService{
+		feeQuoteRepo:     feeQuoteRepo,
+		walletRepo:       walletRepo,
+		pharmacyRepo:     pharmacyRepo,
+		spendingRuleRepo: spendingRuleRepo,
+		lookupService:    lookupService,
+		feeEngine:        feeEngine,
+		config:           cfg,
+	}
+}
+
+func (s *feeQuoteService) Quote(ctx context.Context, userID string, req dto.FeeQuoteRequest) (*dto.FeeQuoteResponse, error) {
+	wallet, err := findWalletByCode(ctx, s.walletRepo, req.WalletCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	return s.quote(ctx, wallet, req, true)
+}
+
+func (s *feeQuoteService) QuoteForPharmacy(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.FeeQuoteRequest) (*dto.FeeQuoteResponse, error) {
+	wallet, err := s.lookupService.Find(ctx, pharmacyID, pharmacyUserID, req.WalletCode, true)
+	if err != nil {
+		return nil, err
+	}
+
+	req.PharmacyID = pharmacyID
+	return s.quote(ctx, wallet, req, false)
+}
+
+// quote prices the withdrawal and, if nothing stands in its way, holds the
+// price. The balance and whether it covers the amount are only reported to
+// callers entitled to see them.
+func (s *feeQuoteService) quote(ctx context.Context, wallet *domain.Wallet, req dto.FeeQuoteRequest, showBalance bool) (*dto.FeeQuoteResponse, error) {
+	if !wallet.Status.CanBeSpent() {
+		return nil, domain.ErrWalletNotSpendable
+	}
+
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, req.PharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.Status != domain.PharmacyStatusActive {
+		return nil, domain.ErrPharmacyInactive
+	}
+
+	amount := decimal.NewFromFloat(req.Amount).Round(2)
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	priced, err := s.feeEngine.Evaluate(ctx, withdrawalFeeRequest(pharmacy, amount))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.FeeQuoteResponse{
+		WalletName:   wallet.WalletName,
+		PharmacyName: pharmacy.Name,
+		Amount:       amount.InexactFloat64(),
+		Fee:          priced.Fee.InexactFloat64(),
+		NetAmount:    priced.Net.InexactFloat64(),
+		Violations:   []dto.FeeQuoteViolation{},
+	}
+
+	if showBalance {
+		balance := wallet.Balance.InexactFloat64()
+		response.AvailableBalance = &balance
+		if wallet.Balance.LessThan(amount) {
+			response.Violations = append(response.Violations, dto.FeeQuoteViolation{
+				Code:    "insufficient_balance",
+				Message: domain.ErrInsufficientBalance.Error(),
+			})
+		}
+	}
+
+	allowed, err := s.spendingRuleRepo.IsAllowed(ctx, wallet.ID, pharmacy.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		response.Violations = append(response.Violations, dto.FeeQuoteViolation{
+			Code:    "spending_not_allowed",
+			Message: domain.ErrSpendingNotAllowed.Error(),
+		})
+	}
+
+	if len(response.Violations) > 0 {
+		return response, nil
+	}
+
+	quote := &domain.FeeQuote{
+		WalletID:   wallet.ID,
+		PharmacyID: pharmacy.ID,
+		Amount:     amount,
+		Fee:        priced.Fee,
+		NetAmount:  priced.Net,
+		FeeRuleID:  priced.RuleID(),
+		ExpiresAt:  time.Now().Add(time.Duration(s.config.FeeQuoteValidityMinutes) * time.Minute),
+	}
+
+	if err := s.feeQuoteRepo.Create(ctx, quote); err != nil {
+		return nil, err
+	}
+
+	response.QuoteID = quote.ID
+	response.ExpiresAt = quote.ExpiresAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (s *feeQuoteService) Check(ctx context.Context, quoteID, walletID, pharmacyID string, amount decimal.Decimal) (*domain.FeeQuote, error) {
+	quote, err := s.feeQuoteRepo.GetByID(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.WalletID != walletID || quote.PharmacyID != pharmacyID || !quote.Amount.Equal(amount) {
+		return nil, domain.ErrFeeQuoteMismatch
+	}
+
+	if quote.RedeemedAt != nil || !time.Now().Before(quote.ExpiresAt) {
+		return nil, domain.ErrFeeQuoteExpired
+	}
+
+	return quote, nil
+}
+
+func (s *feeQuoteService) Get(ctx context.Context, id string) (*domain.FeeQuote, error) {
+	return s.feeQuoteRepo.GetByID(ctx, id)
+}
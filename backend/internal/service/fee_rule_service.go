@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// FeeRuleService lets admins manage the fee rules the fee engine prices
+// transactions with. Changing a rule adds a new version instead of editing
+// it, so past transactions keep the terms they were priced with.
+type FeeRuleService interface {
+	Create(ctx context.Context, adminID string, req dto.CreateFeeRuleRequest) (*domain.FeeRule, error)
+	List(ctx context.Context, filter repository.FeeRuleFilter) ([]*domain.FeeRule, error)
+	Get(ctx context.Context, id string) (*domain.FeeRule, error)
+	// GetVersions returns every version of the rule, newest first.
+	GetVersions(ctx context.Context, id string) ([]*domain.FeeRule, error)
+	Update(ctx context.Context, adminID, id string, req dto.UpdateFeeRuleRequest) (*domain.FeeRule, error)
+	Retire(ctx context.Context, adminID, id string) error
+}
+
+type feeRuleService struct {
+	feeRuleRepo      repository.FeeRuleRepository
+	pharmacyRepo     repository.PharmacyRepository
+	organisationRepo repository.OrganisationRepository
+	auditService     AuditService
+}
+
+func NewFeeRuleService(
+	feeRuleRepo repository.FeeRuleRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	organisationRepo repository.OrganisationRepository,
+	auditService AuditService,
+) FeeRuleService {
+	return &feeRuleService{
+		feeRuleRepo:      feeRuleRepo,
+		pharmacyRepo:     pharmacyRepo,
+		organisationRepo: organisationRepo,
+		auditService:     auditService,
+	}
+}
+
+func (s *feeRuleService) Create(ctx context.Context, adminID string, req dto.CreateFeeRuleRequest) (*domain.FeeRule, error) {
+	rule := &domain.FeeRule{
+		Code:            strings.TrimSpace(req.Code),
+		TransactionType: domain.TransactionType(req.TransactionType),
+		CreatedBy:       &adminID,
+	}
+	if rule.Code == "" {
+		return nil, domain.ErrInvalidFeeRule
+	}
+
+	if req.PharmacyID != "" && req.OrganisationID != "" {
+		return nil, domain.ErrInvalidFeeRule
+	}
+	if req.PharmacyID != "" {
+		pharmacy, err := s.pharmacyRepo.GetByID(ctx, req.PharmacyID)
+		if err != nil {
+			return nil, err
+		}
+		rule.PharmacyID = &pharmacy.ID
+	}
+	if req.OrganisationID != "" {
+		organisation, err := s.organisationRepo.GetByID(ctx, req.OrganisationID)
+		if err != nil {
+			return nil, err
+		}
+		rule.OrganisationID = &organisation.ID
+	}
+
+	// Deposits are not made at a pharmacy, so only platform rules apply.
+	if rule.TransactionType == domain.TransactionTypeDeposit && rule.Scope() != domain.FeeRuleScopePlatform {
+		return nil, domain.ErrInvalidFeeRule
+	}
+
+	if err := applyFeeRuleTerms(rule, req.FeeRuleTerms); err != nil {
+		return nil, err
+	}
+
+	if err := s.feeRuleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, adminID, "fee_rule.created", rule)
+
+	return rule, nil
+}
+
+func (s *feeRuleService) List(ctx context.Context, filter repository.FeeRuleFilter) ([]*domain.FeeRule, error) {
+	return s.feeRuleRepo.List(ctx, filter)
+}
+
+func (s *feeRuleService) Get(ctx context.Context, id string) (*domain.FeeRule, error) {
+	return s.feeRuleRepo.GetByID(ctx, id)
+}
+
+func (s *feeRuleService) GetVersions(ctx context.Context, id string) ([]*domain.FeeRule, error) {
+	rule, err := s.feeRuleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.feeRuleRepo.GetVersions(ctx, rule.Code)
+}
+
+func (s *feeRuleService) Update(ctx context.Context, adminID, id string, req dto.UpdateFeeRuleRequest) (*domain.FeeRule, error) {
+	current, err := s.feeRuleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !current.IsCurrent() {
+		return nil, domain.ErrFeeRuleSuperseded
+	}
+
+	next := &domain.FeeRule{
+		TransactionType: current.TransactionType,
+		PharmacyID:      current.PharmacyID,
+		OrganisationID:  current.OrganisationID,
+		CreatedBy:       &adminID,
+	}
+	if err := applyFeeRuleTerms(next, req.FeeRuleTerms); err != nil {
+		return nil, err
+	}
+
+	if err := s.feeRuleRepo.Supersede(ctx, current, next); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, adminID, "fee_rule.updated", next)
+
+	return next, nil
+}
+
+func (s *feeRuleService) Retire(ctx context.Context, adminID, id string) error {
+	rule, err := s.feeRuleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.feeRuleRepo.Retire(ctx, rule); err != nil {
+		return err
+	}
+
+	s.audit(ctx, adminID, "fee_rule.retired", rule)
+
+	return nil
+}
+
+func (s *feeRuleService) audit(ctx context.Context, adminID, action string, rule *domain.FeeRule) {
+	details := map[string]interface{}{
+		"code":             rule.Code,
+		"version":          rule.Version,
+		"transaction_type": rule.TransactionType,
+		"scope":            rule.Scope(),
+		"percentage":       rule.Percentage.String(),
+		"flat_amount":      rule.FlatAmount.String(),
+		"is_waiver":        rule.IsWaiver,
+	}
+	if rule.PharmacyID != nil {
+		details["pharmacy_id"] = *rule.PharmacyID
+	}
+	if rule.OrganisationID != nil {
+		details["organisation_id"] = *rule.OrganisationID
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, action, "fee_rule", rule.ID, details); err != nil {
+		log.Printf("Failed to audit fee rule %s: %v", rule.ID, err)
+	}
+}
+
+// applyFeeRuleTerms copies the request's terms onto the rule, failing with
+// ErrInvalidFeeRule if they don't make sense together.
+func applyFeeRuleTerms(rule *domain.FeeRule, terms dto.FeeRuleTerms) error {
+	rule.Name = strings.TrimSpace(terms.Name)
+	rule.Percentage = decimal.NewFromFloat(terms.Percentage)
+	rule.FlatAmount = decimal.NewFromFloat(terms.FlatAmount).Round(2)
+	rule.IsWaiver = terms.IsWaiver
+
+	if terms.MinFee != nil {
+		minFee := decimal.NewFromFloat(*terms.MinFee).Round(2)
+		rule.MinFee = &minFee
+	}
+	if terms.MaxFee != nil {
+		maxFee := decimal.NewFromFloat(*terms.MaxFee).Round(2)
+		rule.MaxFee = &maxFee
+	}
+	if rule.MinFee != nil && rule.MaxFee != nil && rule.MinFee.GreaterThan(*rule.MaxFee) {
+		return domain.ErrInvalidFeeRule
+	}
+
+	rule.ContributorPays = domain.ContributorPays(terms.ContributorPays)
+	if rule.ContributorPays == "" {
+		rule.ContributorPays = domain.ContributorPaysNever
+	}
+	if !rule.ContributorPays.IsValid() {
+		return domain.ErrInvalidFeeRule
+	}
+	// Withdrawal fees always come out of what the pharmacy is paid.
+	if rule.TransactionType != domain.TransactionTypeDeposit && rule.ContributorPays != domain.ContributorPaysNever {
+		return domain.ErrInvalidFeeRule
+	}
+
+	rule.StartsAt = time.Now()
+	if terms.StartsAt != nil {
+		rule.StartsAt = *terms.StartsAt
+	}
+	rule.EndsAt = terms.EndsAt
+	if rule.EndsAt != nil && !rule.EndsAt.After(rule.StartsAt) {
+		return domain.ErrInvalidFeeRule
+	}
+
+	if rule.Name == "" {
+		return domain.ErrInvalidFeeRule
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// ManualCreditService credits wallets outside the payment gateway. Every
+// credit is requested by an admin, carries a reason and an external
+// reference, and is optionally approved by a second admin before funds move.
+type ManualCreditService interface {
+	Request(ctx context.Context, adminID, walletID string, req dto.ManualCreditRequest) (*domain.ManualCredit, error)
+	Approve(ctx context.Context, adminID, creditID string) (*domain.ManualCredit, error)
+	Reject(ctx context.Context, adminID, creditID, reason string) (*domain.ManualCredit, error)
+	List(ctx context.Context, status string) ([]*domain.ManualCredit, error)
+}
+
+type manualCreditService struct {
+	manualCreditRepo repository.ManualCreditRepository
+	walletRepo       repository.WalletRepository
+	auditService     AuditService
+	config           *config.Config
+}
+
+func NewManualCreditService(
+	manualCreditRepo repository.ManualCreditRepository,
+	walletRepo repository.WalletRepository,
+	auditService AuditService,
+	cfg *config.Config,
+) ManualCreditService {
+	return &manualCreditService{
+		manualCreditRepo: manualCreditRepo,
+		walletRepo:       walletRepo,
+		auditService:     auditService,
+		config:           cfg,
+	}
+}
+
+func (s *manualCreditService) Request(ctx context.Context, adminID, walletID string, req dto.ManualCreditRequest) (*domain.ManualCredit, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.Status.CanBeCredited() {
+		return nil, domain.ErrWalletClosed
+	}
+
+	amount := decimal.NewFromFloat(req.Amount).Round(2)
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	credit := &domain.ManualCredit{
+		WalletID:          walletID,
+		Amount:            amount,
+		Reason:            strings.TrimSpace(req.Reason),
+		ExternalReference: strings.TrimSpace(req.ExternalReference),
+		Status:            domain.ManualCreditStatusPending,
+		RequestedBy:       adminID,
+	}
+
+	if err := s.manualCreditRepo.Create(ctx, credit); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "manual_credit.requested", "manual_credit", credit.ID, map[string]interface{}{
+		"wallet_id":          walletID,
+		"amount":             amount.String(),
+		"reason":             credit.Reason,
+		"external_reference": credit.ExternalReference,
+	}); err != nil {
+		return nil, err
+	}
+
+	if s.config.ManualCreditRequiresApproval {
+		return credit, nil
+	}
+
+	// Without a second-admin requirement the requesting admin approves their own credit.
+	return s.approve(ctx, adminID, credit)
+}
+
+func (s *manualCreditService) Approve(ctx context.Context, adminID, creditID string) (*domain.ManualCredit, error) {
+	credit, err := s.manualCreditRepo.GetByID(ctx, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !credit.IsPending() {
+		return nil, domain.ErrManualCreditNotPending
+	}
+
+	if s.config.ManualCreditRequiresApproval && credit.RequestedBy == adminID {
+		return nil, domain.ErrSelfApproval
+	}
+
+	return s.approve(ctx, adminID, credit)
+}
+
+func (s *manualCreditService) approve(ctx context.Context, adminID string, credit *domain.ManualCredit) (*domain.ManualCredit, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, credit.WalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.Status.CanBeCredited() {
+		return nil, domain.ErrWalletClosed
+	}
+
+	now := time.Now()
+	credit.ReviewedBy = &adminID
+	credit.ReviewedAt = &now
+
+	transaction := &domain.Transaction{
+		WalletID:           credit.WalletID,
+		Type:               domain.TransactionTypeManualCredit,
+		Amount:             credit.Amount,
+		Fee:                decimal.Zero,
+		NetAmount:          credit.Amount,
+		Status:             domain.TransactionStatusCompleted,
+		ContributorName:    "CareWallet",
+		ContributorMessage: credit.Reason,
+	}
+
+	if err := s.manualCreditRepo.Approve(ctx, credit, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "manual_credit.approved", "manual_credit", credit.ID, map[string]interface{}{
+		"wallet_id":      credit.WalletID,
+		"amount":         credit.Amount.String(),
+		"transaction_id": transaction.ID,
+	}); err != nil {
+		// The wallet has already been credited, so only log the failure.
+		log.Printf("Failed to audit approval of manual credit %s: %v", credit.ID, err)
+	}
+
+	return credit, nil
+}
+
+func (s *manualCreditService) Reject(ctx context.Context, adminID, creditID, reason string) (*domain.ManualCredit, error) {
+	credit, err := s.manualCreditRepo.GetByID(ctx, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !credit.IsPending() {
+		return nil, domain.ErrManualCreditNotPending
+	}
+
+	now := time.Now()
+	credit.ReviewedBy = &adminID
+	credit.ReviewedAt = &now
+	credit.RejectionReason = strings.TrimSpace(reason)
+
+	if err := s.manualCreditRepo.Reject(ctx, credit); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "manual_credit.rejected", "manual_credit", credit.ID, map[string]interface{}{
+		"wallet_id": credit.WalletID,
+		"reason":    credit.RejectionReason,
+	}); err != nil {
+		return nil, err
+	}
+
+	return credit, nil
+}
+
+func (s *manualCreditService) List(ctx context.Context, status string) ([]*domain.ManualCredit, error) {
+	return s.manualCreditRepo.GetByStatus(ctx, domain.ManualCreditStatus(status))
+}
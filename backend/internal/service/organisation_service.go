@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// OrganisationService manages pharmacy chains. Admins create organisations,
+// assign branches and add members; members can then view every branch's
+// transactions and settlements. Methods that take a userID restrict access to
+// the organisation's members; an empty userID is used for admins.
+type OrganisationService interface {
+	Create(ctx context.Context, adminID string, req dto.OrganisationRequest) (*domain.Organisation, error)
+	List(ctx context.Context) ([]*domain.Organisation, error)
+	ListForUser(ctx context.Context, userID string) ([]*domain.Organisation, error)
+	Get(ctx context.Context, userID, organisationID string) (*domain.Organisation, error)
+	Update(ctx context.Context, adminID, organisationID string, req dto.UpdateOrganisationRequest) (*domain.Organisation, error)
+
+	AddBranch(ctx context.Context, adminID, organisationID, pharmacyID string) (*dto.BranchResponse, error)
+	RemoveBranch(ctx context.Context, adminID, organisationID, pharmacyID string) error
+	GetBranches(ctx context.Context, userID, organisationID string) ([]*dto.BranchResponse, error)
+
+	AddMember(ctx context.Context, adminID, organisationID, email string) (*domain.OrganisationMember, error)
+	GetMembers(ctx context.Context, organisationID string) ([]*domain.OrganisationMember, error)
+	RemoveMember(ctx context.Context, adminID, organisationID, memberID string) error
+
+	// GetTransactions lists transactions across all branches, or at one
+	// branch when pharmacyID is set.
+	GetTransactions(ctx context.Context, userID, organisationID, pharmacyID string, page, pageSize int) (*dto.TransactionListResponse, error)
+	ListSettlements(ctx context.Context, userID, organisationID string, filter repository.SettlementFilter) ([]*domain.Settlement, int, error)
+	GetSettlementStatement(ctx context.Context, userID, organisationID, settlementID string) (*SettlementStatement, error)
+	// GetSettlementSummary aggregates the branches' settlements whose period
+	// ends after from and up to to.
+	GetSettlementSummary(ctx context.Context, userID, organisationID string, from, to time.Time) (*OrganisationSettlementSummary, error)
+}
+
+// OrganisationSettlementSummary is a chain-level view of settlements, with a
+// line per branch and the chain's totals.
+type OrganisationSettlementSummary struct {
+	Organisation     *domain.Organisation              `json:"organisation"`
+	From             time.Time                         `json:"from"`
+	To               time.Time                         `json:"to"`
+	Branches         []*domain.BranchSettlementSummary `json:"branches"`
+	SettlementCount  int                               `json:"settlement_count"`
+	GrossWithdrawals decimal.Decimal                   `json:"gross_withdrawals"`
+	WithdrawalFees   decimal.Decimal                   `json:"withdrawal_fees"`
+	CashInTotal      decimal.Decimal                   `json:"cash_in_total"`
+	NetPayable       decimal.Decimal                   `json:"net_payable"`
+	Paid             decimal.Decimal                   `json:"paid"`
+	Outstanding      decimal.Decimal                   `json:"outstanding"`
+}
+
+type organisationService struct {
+	organisationRepo  repository.OrganisationRepository
+	pharmacyRepo      repository.PharmacyRepository
+	userRepo          repository.UserRepository
+	transactionRepo   repository.TransactionRepository
+	settlementRepo    repository.SettlementRepository
+	settlementService SettlementService
+	auditService      AuditService
+}
+
+func NewOrganisationService(
+	organisationRepo repository.OrganisationRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	settlementRepo repository.SettlementRepository,
+	settlementService SettlementService,
+	auditService AuditService,
+) OrganisationService {
+	return &organisationService{
+		organisationRepo:  organisationRepo,
+		pharmacyRepo:      pharmacyRepo,
+		userRepo:          userRepo,
+		transactionRepo:   transactionRepo,
+		settlementRepo:    settlementRepo,
+		settlementService: settlementService,
+		auditService:      auditService,
+	}
+}
+
+func (s *organisationService) Create(ctx context.Context, adminID string, req dto.OrganisationRequest) (*domain.Organisation, error) {
+	organisation := &domain.Organisation{
+		Name:               strings.TrimSpace(req.Name),
+		RegistrationNumber: strings.TrimSpace(req.RegistrationNumber),
+		Email:              strings.TrimSpace(req.Email),
+		Phone:              strings.TrimSpace(req.Phone),
+		Status:             domain.OrganisationStatusActive,
+	}
+
+	if err := s.organisationRepo.Create(ctx, organisation); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "organisation.created", "organisation", organisation.ID, map[string]interface{}{
+		"name": organisation.Name,
+	}); err != nil {
+		return nil, err
+	}
+
+	return organisation, nil
+}
+
+func (s *organisationService) List(ctx context.Context) ([]*domain.Organisation, error) {
+	return s.organisationRepo.GetAll(ctx)
+}
+
+func (s *organisationService) ListForUser(ctx context.Context, userID string) ([]*domain.Organisation, error) {
+	return s.organisationRepo.GetByMemberUserID(ctx, userID)
+}
+
+func (s *organisationService) Get(ctx context.Context, userID, organisationID string) (*domain.Organisation, error) {
+	return s.authorize(ctx, userID, organisationID)
+}
+
+func (s *organisationService) Update(ctx context.Context, adminID, organisationID string, req dto.UpdateOrganisationRequest) (*domain.Organisation, error) {
+	organisation, err := s.organisationRepo.GetByID(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		organisation.Name = strings.TrimSpace(req.Name)
+	}
+	if req.RegistrationNumber != "" {
+		organisation.RegistrationNumber = strings.TrimSpace(req.RegistrationNumber)
+	}
+	if req.Email != "" {
+		organisation.Email = strings.TrimSpace(req.Email)
+	}
+	if req.Phone != "" {
+		organisation.Phone = strings.TrimSpace(req.Phone)
+	}
+	if req.Status != "" {
+		organisation.Status = domain.OrganisationStatus(req.Status)
+	}
+
+	if err := s.organisationRepo.Update(ctx, organisation); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "organisation.updated", "organisation", organisation.ID, map[string]interface{}{
+		"name":   organisation.Name,
+		"status": string(organisation.Status),
+	}); err != nil {
+		return nil, err
+	}
+
+	return organisation, nil
+}
+
+func (s *organisationService) AddBranch(ctx context.Context, adminID, organisationID, pharmacyID string) (*dto.BranchResponse, error) {
+	organisation, err := s.organisationRepo.GetByID(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous string
+	if pharmacy.OrganisationID != nil {
+		previous = *pharmacy.OrganisationID
+	}
+
+	pharmacy.OrganisationID = &organisation.ID
+	if err := s.pharmacyRepo.SetOrganisation(ctx, pharmacy); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "organisation.branch_added", "organisation", organisation.ID, map[string]interface{}{
+		"pharmacy_id":           pharmacy.ID,
+		"previous_organisation": previous,
+	}); err != nil {
+		return nil, err
+	}
+
+	return branchToResponse(pharmacy), nil
+}
+
+func (s *organisationService) RemoveBranch(ctx context.Context, adminID, organisationID, pharmacyID string) error {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return err
+	}
+
+	if pharmacy.OrganisationID == nil || *pharmacy.OrganisationID != organisationID {
+		return domain.ErrPharmacyNotFound
+	}
+
+	pharmacy.OrganisationID = nil
+	if err := s.pharmacyRepo.SetOrganisation(ctx, pharmacy); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "organisation.branch_removed", "organisation", organisationID, map[string]interface{}{
+		"pharmacy_id": pharmacy.ID,
+	})
+}
+
+func (s *organisationService) GetBranches(ctx context.Context, userID, organisationID string) ([]*dto.BranchResponse, error) {
+	if _, err := s.authorize(ctx, userID, organisationID); err != nil {
+		return nil, err
+	}
+
+	pharmacies, err := s.pharmacyRepo.GetByOrganisationID(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]*dto.BranchResponse, len(pharmacies))
+	for i, pharmacy := range pharmacies {
+		branches[i] = branchToResponse(pharmacy)
+	}
+
+	return branches, nil
+}
+
+func (s *organisationService) AddMember(ctx context.Context, adminID, organisationID, email string) (*domain.OrganisationMember, error) {
+	if _, err := s.organisationRepo.GetByID(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return nil, err
+	}
+
+	member := &domain.OrganisationMember{
+		OrganisationID: organisationID,
+		UserID:         user.ID,
+		Email:          user.Email,
+		FullName:       user.FullName,
+		AddedBy:        &adminID,
+	}
+
+	if err := s.organisationRepo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "organisation.member_added", "organisation", organisationID, map[string]interface{}{
+		"user_id": user.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (s *organisationService) GetMembers(ctx context.Context, organisationID string) ([]*domain.OrganisationMember, error) {
+	if _, err := s.organisationRepo.GetByID(ctx, organisationID); err != nil {
+		return nil, err
+	}
+
+	return s.organisationRepo.GetMembers(ctx, organisationID)
+}
+
+func (s *organisationService) RemoveMember(ctx context.Context, adminID, organisationID, memberID string) error {
+	if err := s.organisationRepo.RemoveMember(ctx, organisationID, memberID); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "organisation.member_removed", "organisation", organisationID, map[string]interface{}{
+		"member_id": memberID,
+	})
+}
+
+func (s *organisationService) GetTransactions(ctx context.Context, userID, organisationID, pharmacyID string, page, pageSize int) (*dto.TransactionListResponse, error) {
+	if _, err := s.authorize(ctx, userID, organisationID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	transactions, total, err := s.transactionRepo.GetByOrganisationID(ctx, organisationID, pharmacyID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		responses[i] = *transactionToResponse(tx)
+	}
+
+	return &dto.TransactionListResponse{
+		Transactions: responses,
+		Total:        total,
+		Page:         page,
+		PageSize:     pageSize,
+	}, nil
+}
+
+func (s *organisationService) ListSettlements(ctx context.Context, userID, organisationID string, filter repository.SettlementFilter) ([]*domain.Settlement, int, error) {
+	if _, err := s.authorize(ctx, userID, organisationID); err != nil {
+		return nil, 0, err
+	}
+
+	filter.OrganisationID = organisationID
+	return s.settlementService.List(ctx, filter)
+}
+
+func (s *organisationService) GetSettlementStatement(ctx context.Context, userID, organisationID, settlementID string) (*SettlementStatement, error) {
+	if _, err := s.authorize(ctx, userID, organisationID); err != nil {
+		return nil, err
+	}
+
+	settlement, err := s.settlementRepo.GetByID(ctx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, settlement.PharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.OrganisationID == nil || *pharmacy.OrganisationID != organisationID {
+		return nil, domain.ErrSettlementNotFound
+	}
+
+	return s.settlementService.GetStatement(ctx, settlement.ID, pharmacy.ID)
+}
+
+func (s *organisationService) GetSettlementSummary(ctx context.Context, userID, organisationID string, from, to time.Time) (*OrganisationSettlementSummary, error) {
+	organisation, err := s.authorize(ctx, userID, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := s.settlementRepo.SummariseByOrganisation(ctx, organisationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &OrganisationSettlementSummary{
+		Organisation:     organisation,
+		From:             from,
+		To:               to,
+		Branches:         branches,
+		GrossWithdrawals: decimal.Zero,
+		WithdrawalFees:   decimal.Zero,
+		CashInTotal:      decimal.Zero,
+		NetPayable:       decimal.Zero,
+		Paid:             decimal.Zero,
+		Outstanding:      decimal.Zero,
+	}
+	for _, branch := range branches {
+		summary.SettlementCount += branch.SettlementCount
+		summary.GrossWithdrawals = summary.GrossWithdrawals.Add(branch.GrossWithdrawals)
+		summary.WithdrawalFees = summary.WithdrawalFees.Add(branch.WithdrawalFees)
+		summary.CashInTotal = summary.CashInTotal.Add(branch.CashInTotal)
+		summary.NetPayable = summary.NetPayable.Add(branch.NetPayable)
+		summary.Paid = summary.Paid.Add(branch.Paid)
+		summary.Outstanding = summary.Outstanding.Add(branch.Outstanding)
+	}
+
+	return summary, nil
+}
+
+// authorize loads the organisation, failing with ErrOrganisationAccessDenied
+// unless userID is empty or belongs to one of its members.
+func (s *organisationService) authorize(ctx context.Context, userID, organisationID string) (*domain.Organisation, error) {
+	organisation, err := s.organisationRepo.GetByID(ctx, organisationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID == "" {
+		return organisation, nil
+	}
+
+	isMember, err := s.organisationRepo.IsMember(ctx, organisationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrOrganisationAccessDenied
+	}
+
+	return organisation, nil
+}
+
+func branchToResponse(pharmacy *domain.Pharmacy) *dto.BranchResponse {
+	return &dto.BranchResponse{
+		ID:        pharmacy.ID,
+		Name:      pharmacy.Name,
+		ShortCode: pharmacy.ShortCode,
+		Address:   pharmacy.Address,
+		Phone:     pharmacy.Phone,
+		Status:    string(pharmacy.Status),
+	}
+}
@@ -19,6 +19,7 @@ type OTPService interface {
 
 type EmailService interface {
 	SendOTP(ctx context.Context, email, code string, purpose domain.OTPPurpose) error
+	SendEmail(ctx context.Context, to, subject, body string) error
 }
 
 type otpService struct {
@@ -120,3 +121,8 @@ func (s *MockEmailService) SendOTP(ctx context.Context, email, code string, purp
 	log.Printf("[MockEmail] Sending OTP %s to %s for %s", code, email, purpose)
 	return nil
 }
+
+func (s *MockEmailService) SendEmail(ctx context.Context, to, subject, body string) error {
+	log.Printf("[MockEmail] Sending %q to %s:\n%s", subject, to, body)
+	return nil
+}
@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/fees"
 	"github.com/carewallet/backend/internal/paystack"
 	"github.com/carewallet/backend/internal/repository"
 	"github.com/google/uuid"
@@ -13,14 +17,33 @@ import (
 )
 
 type PaymentService interface {
-	InitializePayment(ctx context.Context, walletID, email string, amount float64, message string) (*PaymentInitResult, error)
+	// InitializePayment starts a contribution. coverFee asks for the deposit
+	// fee to be added to the contributor's charge when the fee rule allows it.
+	// shareLinkToken, when set, is the share link the contributor arrived
+	// through; the contribution is refused if the link is no longer usable.
+	// display is how the contributor appears on the contributor wall.
+	InitializePayment(ctx context.Context, walletID, email string, amount float64, message string, display ContributorDisplay, coverFee bool, shareLinkToken string) (*PaymentInitResult, error)
 	VerifyPayment(ctx context.Context, reference string) (*PaymentVerifyResult, error)
 }
 
+// ContributorDisplay is how a contributor chose to appear on the wallet's
+// contributor wall. Anonymous contributors are shown as "Anonymous" and
+// HideAmount leaves their amount off.
+type ContributorDisplay struct {
+	DisplayName string
+	Anonymous   bool
+	HideAmount  bool
+}
+
 type PaymentInitResult struct {
-	Reference        string `json:"reference"`
-	AccessCode       string `json:"access_code"`
-	AuthorizationURL string `json:"authorization_url"`
+	Reference          string  `json:"reference"`
+	AccessCode         string  `json:"access_code"`
+	AuthorizationURL   string  `json:"authorization_url"`
+	Amount             float64 `json:"amount"`
+	Fee                float64 `json:"fee"`
+	ChargedAmount      float64 `json:"charged_amount"`
+	CreditedAmount     float64 `json:"credited_amount"`
+	ContributorPaysFee bool    `json:"contributor_pays_fee"`
 }
 
 type PaymentVerifyResult struct {
@@ -30,51 +53,85 @@ type PaymentVerifyResult struct {
 }
 
 type paymentService struct {
-	paymentRepo     repository.PaymentRepository
-	walletRepo      repository.WalletRepository
-	transactionRepo repository.TransactionRepository
-	paystackClient  *paystack.Client
+	paymentRepo    repository.PaymentRepository
+	walletRepo     repository.WalletRepository
+	shareLinkRepo  repository.WalletShareLinkRepository
+	feeEngine      fees.Engine
+	paystackClient paystack.Gateway
 }
 
 func NewPaymentService(
 	paymentRepo repository.PaymentRepository,
 	walletRepo repository.WalletRepository,
-	transactionRepo repository.TransactionRepository,
-	paystackSecretKey string,
+	shareLinkRepo repository.WalletShareLinkRepository,
+	feeEngine fees.Engine,
+	paystackClient paystack.Gateway,
 ) PaymentService {
 	return &paymentService{
-		paymentRepo:     paymentRepo,
-		walletRepo:      walletRepo,
-		transactionRepo: transactionRepo,
-		paystackClient:  paystack.NewClient(paystackSecretKey),
+		paymentRepo:    paymentRepo,
+		walletRepo:     walletRepo,
+		shareLinkRepo:  shareLinkRepo,
+		feeEngine:      feeEngine,
+		paystackClient: paystackClient,
 	}
 }
 
-func (s *paymentService) InitializePayment(ctx context.Context, walletID, email string, amount float64, message string) (*PaymentInitResult, error) {
+func (s *paymentService) InitializePayment(ctx context.Context, walletID, email string, amount float64, message string, display ContributorDisplay, coverFee bool, shareLinkToken string) (*PaymentInitResult, error) {
 	// Verify wallet exists
 	wallet, err := s.walletRepo.GetByID(ctx, walletID)
 	if err != nil {
 		return nil, err
 	}
 
-	if wallet.Status != domain.WalletStatusActive {
-		return nil, domain.ErrWalletAccessDenied
+	if !wallet.Status.AcceptsContributions() {
+		return nil, domain.ErrWalletNotAcceptingContributions
+	}
+
+	var shareLinkID *string
+	if shareLinkToken != "" {
+		link, err := s.shareLinkRepo.GetByToken(ctx, shareLinkToken)
+		if err != nil {
+			return nil, err
+		}
+		if link.WalletID != wallet.ID {
+			return nil, domain.ErrShareLinkNotFound
+		}
+		if !link.IsUsable(time.Now()) {
+			return nil, domain.ErrShareLinkUnavailable
+		}
+		shareLinkID = &link.ID
 	}
 
 	// Generate unique reference
 	reference := fmt.Sprintf("CW_%s_%d", uuid.New().String()[:8], time.Now().Unix())
 
 	// Convert amount to decimal
-	amountDecimal := decimal.NewFromFloat(amount)
+	amountDecimal := decimal.NewFromFloat(amount).Round(2)
+
+	priced, err := s.feeEngine.Evaluate(ctx, fees.Request{
+		Type:               domain.TransactionTypeDeposit,
+		Amount:             amountDecimal,
+		ContributorPaysFee: coverFee,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Create payment record
 	payment := &domain.Payment{
-		WalletID:  walletID,
-		Reference: reference,
-		Amount:    amountDecimal,
-		Email:     email,
-		Message:   message,
-		Status:    domain.PaymentStatusPending,
+		WalletID:           walletID,
+		Reference:          reference,
+		Amount:             amountDecimal,
+		Fee:                priced.Fee,
+		FeeRuleID:          priced.RuleID(),
+		ContributorPaysFee: priced.ContributorPaysFee,
+		Email:              email,
+		Message:            strings.TrimSpace(message),
+		DisplayName:        strings.TrimSpace(display.DisplayName),
+		Anonymous:          display.Anonymous,
+		HideAmount:         display.HideAmount,
+		ShareLinkID:        shareLinkID,
+		Status:             domain.PaymentStatusPending,
 	}
 
 	if err := s.paymentRepo.Create(ctx, payment); err != nil {
@@ -82,7 +139,7 @@ func (s *paymentService) InitializePayment(ctx context.Context, walletID, email
 	}
 
 	// Initialize with Paystack
-	amountInCents := int64(amount * 100)
+	amountInCents := payment.ChargedAmount().Mul(decimal.NewFromInt(100)).IntPart()
 	paystackReq := &paystack.InitializeRequest{
 		Email:     email,
 		Amount:    amountInCents,
@@ -100,9 +157,14 @@ func (s *paymentService) InitializePayment(ctx context.Context, walletID, email
 	}
 
 	return &PaymentInitResult{
-		Reference:        reference,
-		AccessCode:       resp.Data.AccessCode,
-		AuthorizationURL: resp.Data.AuthorizationURL,
+		Reference:          reference,
+		AccessCode:         resp.Data.AccessCode,
+		AuthorizationURL:   resp.Data.AuthorizationURL,
+		Amount:             payment.Amount.InexactFloat64(),
+		Fee:                payment.Fee.InexactFloat64(),
+		ChargedAmount:      payment.ChargedAmount().InexactFloat64(),
+		CreditedAmount:     payment.CreditedAmount().InexactFloat64(),
+		ContributorPaysFee: payment.ContributorPaysFee,
 	}, nil
 }
 
@@ -113,9 +175,13 @@ func (s *paymentService) VerifyPayment(ctx context.Context, reference string) (*
 		return nil, err
 	}
 
-	if payment.Status == domain.PaymentStatusCompleted {
+	if payment.Status == domain.PaymentStatusCompleted || payment.Status == domain.PaymentStatusRefunded {
 		return nil, domain.ErrPaymentAlreadyVerified
 	}
+	// An earlier attempt to refund the payment did not go through.
+	if payment.Status == domain.PaymentStatusRefundDue {
+		return s.refund(ctx, payment)
+	}
 
 	// Verify with Paystack
 	resp, err := s.paystackClient.VerifyTransaction(reference)
@@ -134,44 +200,94 @@ func (s *paymentService) VerifyPayment(ctx context.Context, reference string) (*
 		}, domain.ErrPaymentFailed
 	}
 
-	// Update payment status
 	now := time.Now()
-	payment.Status = domain.PaymentStatusCompleted
 	payment.PaystackReference = fmt.Sprintf("%d", resp.Data.ID)
 	payment.VerifiedAt = &now
 
-	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+	transaction := &domain.Transaction{
+		WalletID:               payment.WalletID,
+		Type:                   domain.TransactionTypeDeposit,
+		Amount:                 payment.ChargedAmount(),
+		Fee:                    payment.Fee,
+		FeeRuleID:              payment.FeeRuleID,
+		NetAmount:              payment.CreditedAmount(),
+		Status:                 domain.TransactionStatusCompleted,
+		ContributorEmail:       payment.Email,
+		ContributorName:        payment.DisplayName,
+		ContributorMessage:     payment.Message,
+		ContributorAnonymous:   payment.Anonymous,
+		ContributorHidesAmount: payment.HideAmount,
+	}
+
+	// The contributor has already paid, so the wallet is credited even if it
+	// stopped taking contributions meanwhile, unless it has been closed.
+	if err := s.paymentRepo.Complete(ctx, payment, transaction); err != nil {
+		if errors.Is(err, domain.ErrWalletClosed) {
+			payment.Status = domain.PaymentStatusRefundDue
+			if err := s.paymentRepo.Update(ctx, payment); err != nil {
+				return nil, err
+			}
+			return s.refund(ctx, payment)
+		}
 		return nil, err
 	}
 
-	// Create deposit transaction
-	transaction := &domain.Transaction{
-		WalletID:           payment.WalletID,
-		Type:               domain.TransactionTypeDeposit,
-		Amount:             payment.Amount,
-		Status:             domain.TransactionStatusCompleted,
-		ContributorEmail:   payment.Email,
-		ContributorMessage: payment.Message,
+	if payment.ShareLinkID != nil {
+		if err := s.shareLinkRepo.RecordContribution(ctx, *payment.ShareLinkID, payment.Amount); err != nil {
+			log.Printf("Failed to record contribution %s against share link %s: %v", payment.Reference, *payment.ShareLinkID, err)
+		}
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return nil, err
+	return &PaymentVerifyResult{
+		Status:        "success",
+		Amount:        amountFloat,
+		TransactionID: transaction.ID,
+	}, nil
+}
+
+// refund sends a payment whose wallet was closed back to the contributor. A
+// refund Paystack already holds for the payment is not sent again, so a lost
+// response cannot refund the contributor twice. Refunds Paystack will not
+// take leave the payment refund_due, to be tried again.
+func (s *paymentService) refund(ctx context.Context, payment *domain.Payment) (*PaymentVerifyResult, error) {
+	result := &PaymentVerifyResult{
+		Status: string(domain.PaymentStatusRefundDue),
+		Amount: payment.Amount.InexactFloat64(),
 	}
 
-	// Update wallet balance
-	wallet, err := s.walletRepo.GetByID(ctx, payment.WalletID)
+	existing, err := s.paystackClient.ListRefunds(payment.PaystackReference)
 	if err != nil {
 		return nil, err
 	}
+	refunded := false
+	for _, refund := range existing.Data {
+		if refund.Status != paystack.RefundStatusFailed {
+			refunded = true
+		}
+	}
 
-	wallet.Balance = wallet.Balance.Add(payment.Amount)
-	if err := s.walletRepo.Update(ctx, wallet); err != nil {
+	if !refunded {
+		resp, err := s.paystackClient.CreateRefund(&paystack.RefundRequest{
+			Transaction:  payment.PaystackReference,
+			Amount:       payment.ChargedAmount().Shift(2).IntPart(),
+			Currency:     "ZAR",
+			MerchantNote: fmt.Sprintf("CareWallet wallet %s closed before payment %s arrived", payment.WalletID, payment.Reference),
+		})
+		if err != nil {
+			log.Printf("Failed to refund payment %s to closed wallet %s: %v", payment.Reference, payment.WalletID, err)
+			return result, domain.ErrPaymentRefundDue
+		}
+		if resp.Data.Status == paystack.RefundStatusFailed {
+			log.Printf("Paystack refused the refund of payment %s to closed wallet %s", payment.Reference, payment.WalletID)
+			return result, domain.ErrPaymentRefundDue
+		}
+	}
+
+	payment.Status = domain.PaymentStatusRefunded
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
 		return nil, err
 	}
 
-	return &PaymentVerifyResult{
-		Status:        "success",
-		Amount:        amountFloat,
-		TransactionID: transaction.ID,
-	}, nil
+	result.Status = string(domain.PaymentStatusRefunded)
+	return result, domain.ErrPaymentRefundDue
 }
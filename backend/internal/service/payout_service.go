@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/paystack"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+// PayoutService pays approved settlements into the pharmacy's active bank
+// account with Paystack transfers. Transfers are confirmed either in the
+// initiate response or later by webhook; failed transfers are retried until
+// the configured number of attempts is used up. A transfer is only treated as
+// failed once Paystack confirms it, so a lost response never leads to the
+// pharmacy being paid twice.
+type PayoutService interface {
+	Approve(ctx context.Context, adminID, settlementID string) (*domain.Settlement, error)
+	Retry(ctx context.Context, adminID, settlementID string) (*domain.Settlement, error)
+	// RetryFailed asks Paystack about payouts whose outcome is overdue,
+	// whether or not Paystack accepted the transfer, and retries failed ones.
+	RetryFailed(ctx context.Context) (int, error)
+	HandleWebhook(ctx context.Context, body []byte, signature string) error
+}
+
+type payoutService struct {
+	settlementRepo  repository.SettlementRepository
+	bankAccountRepo repository.BankAccountRepository
+	gateway         paystack.Gateway
+	auditService    AuditService
+	config          *config.Config
+}
+
+func NewPayoutService(
+	settlementRepo repository.SettlementRepository,
+	bankAccountRepo repository.BankAccountRepository,
+	gateway paystack.Gateway,
+	auditService AuditService,
+	cfg *config.Config,
+) PayoutService {
+	return &payoutService{
+		settlementRepo:  settlementRepo,
+		bankAccountRepo: bankAccountRepo,
+		gateway:         gateway,
+		auditService:    auditService,
+		config:          cfg,
+	}
+}
+
+func (s *payoutService) Approve(ctx context.Context, adminID, settlementID string) (*domain.Settlement, error) {
+	settlement, err := s.settlementRepo.GetByID(ctx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settlement.Status != domain.SettlementStatusUnpaid {
+		return nil, domain.ErrSettlementNotPayable
+	}
+
+	// Settlements where the pharmacy owes CareWallet are collected and marked
+	// paid by hand instead.
+	if !settlement.NetPayable.IsPositive() {
+		return nil, domain.ErrNothingToPay
+	}
+
+	if _, err := s.bankAccountRepo.GetEffective(ctx, settlement.PharmacyID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	settlement.ApprovedBy = &adminID
+	settlement.ApprovedAt = &now
+
+	if err := s.settlementRepo.Approve(ctx, settlement); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "settlement.approved", "settlement", settlement.ID, map[string]interface{}{
+		"pharmacy_id": settlement.PharmacyID,
+		"net_payable": settlement.NetPayable.String(),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.payout(ctx, settlement); err != nil {
+		// The settlement stays approved or failed and is picked up by the retry job.
+		log.Printf("Payout of settlement %s failed: %v", settlement.ID, err)
+	}
+
+	return settlement, nil
+}
+
+func (s *payoutService) Retry(ctx context.Context, adminID, settlementID string) (*domain.Settlement, error) {
+	settlement, err := s.settlementRepo.GetByID(ctx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settlement.Status == domain.SettlementStatusProcessing {
+		return nil, domain.ErrPayoutInProgress
+	}
+	if !settlement.IsPayable() {
+		return nil, domain.ErrSettlementNotPayable
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "settlement.payout_retried", "settlement", settlement.ID, map[string]interface{}{
+		"payout_attempts": settlement.PayoutAttempts,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.payout(ctx, settlement); err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+// unconfirmedPayoutAge is how long a transfer may stay processing before
+// Paystack is asked about it, leaving time for the initiate call that started
+// it to finish and for its webhook to arrive.
+const unconfirmedPayoutAge = 10 * time.Minute
+
+func (s *payoutService) RetryFailed(ctx context.Context) (int, error) {
+	unconfirmed, err := s.settlementRepo.GetUnconfirmed(ctx, time.Now().Add(-unconfirmedPayoutAge))
+	if err != nil {
+		return 0, err
+	}
+	for _, settlement := range unconfirmed {
+		if err := s.confirmTransfer(ctx, settlement, "transfer was never confirmed"); err != nil {
+			log.Printf("Could not confirm payout of settlement %s: %v", settlement.ID, err)
+		}
+	}
+
+	settlements, err := s.settlementRepo.GetRetryable(ctx, s.config.PayoutMaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	paid := 0
+	for _, settlement := range settlements {
+		if err := s.payout(ctx, settlement); err != nil {
+			log.Printf("Payout retry of settlement %s failed: %v", settlement.ID, err)
+			continue
+		}
+		if settlement.IsPaid() {
+			paid++
+		}
+	}
+
+	return paid, nil
+}
+
+// payout starts a new transfer attempt for the settlement. A transfer that
+// Paystack accepts but has not finished stays processing until the webhook
+// arrives.
+func (s *payoutService) payout(ctx context.Context, settlement *domain.Settlement) error {
+	account, err := s.bankAccountRepo.GetEffective(ctx, settlement.PharmacyID)
+	if err != nil {
+		return err
+	}
+
+	if account.RecipientCode == "" {
+		recipient, err := s.gateway.CreateTransferRecipient(&paystack.TransferRecipientRequest{
+			Type:          "basa",
+			Name:          account.AccountHolder,
+			AccountNumber: account.AccountNumber,
+			BankCode:      account.BankCode,
+			Currency:      "ZAR",
+		})
+		if err != nil {
+			return err
+		}
+
+		account.RecipientCode = recipient.Data.RecipientCode
+		if err := s.bankAccountRepo.SetRecipientCode(ctx, account); err != nil {
+			return err
+		}
+	}
+
+	if err := s.settlementRepo.StartPayout(ctx, settlement); err != nil {
+		return err
+	}
+
+	resp, err := s.gateway.InitiateTransfer(&paystack.TransferRequest{
+		Source:    "balance",
+		Amount:    settlement.NetPayable.Shift(2).IntPart(),
+		Recipient: account.RecipientCode,
+		Reference: settlement.TransferReference,
+		Reason:    fmt.Sprintf("CareWallet settlement %s", settlement.PeriodEnd.In(s.config.Location()).Format("2006-01-02")),
+		Currency:  "ZAR",
+	})
+	if err != nil {
+		// Paystack may have accepted the transfer even though its answer was
+		// lost, so ask before failing the attempt.
+		if confirmErr := s.confirmTransfer(ctx, settlement, err.Error()); confirmErr != nil {
+			log.Printf("Could not confirm payout of settlement %s: %v", settlement.ID, confirmErr)
+		}
+		return err
+	}
+
+	return s.applyTransfer(ctx, settlement, &resp.Data)
+}
+
+// confirmTransfer asks Paystack what became of the settlement's transfer
+// attempt. The attempt is only failed, with failure as its error, when
+// Paystack has no transfer with its reference; while Paystack cannot be
+// reached, or has a transfer that is still pending, the settlement stays
+// processing and is checked again later.
+func (s *payoutService) confirmTransfer(ctx context.Context, settlement *domain.Settlement, failure string) error {
+	resp, err := s.gateway.VerifyTransfer(settlement.TransferReference)
+	if err != nil {
+		return err
+	}
+
+	if resp.Status {
+		return s.applyTransfer(ctx, settlement, &resp.Data)
+	}
+	if settlement.TransferCode != "" {
+		// Paystack accepted this transfer, so it not being found is not a
+		// failure to act on.
+		return fmt.Errorf("paystack has no transfer %s with reference %s", settlement.TransferCode, settlement.TransferReference)
+	}
+
+	settlement.LastPayoutError = failure
+	if err := s.settlementRepo.FailPayout(ctx, settlement); err != nil {
+		return err
+	}
+	s.audit(ctx, "settlement.payout_failed", settlement, map[string]interface{}{
+		"pharmacy_id":     settlement.PharmacyID,
+		"error":           settlement.LastPayoutError,
+		"payout_attempts": settlement.PayoutAttempts,
+	})
+	return nil
+}
+
+// applyTransfer records a transfer's status against the settlement it pays.
+func (s *payoutService) applyTransfer(ctx context.Context, settlement *domain.Settlement, transfer *paystack.TransferData) error {
+	settlement.TransferCode = transfer.TransferCode
+
+	switch transfer.Status {
+	case paystack.TransferStatusSuccess:
+		now := time.Now()
+		settlement.PaidAt = &now
+		if err := s.settlementRepo.CompletePayout(ctx, settlement); err != nil {
+			return err
+		}
+		s.audit(ctx, "settlement.paid", settlement, map[string]interface{}{
+			"pharmacy_id":   settlement.PharmacyID,
+			"net_payable":   settlement.NetPayable.String(),
+			"transfer_code": settlement.TransferCode,
+		})
+
+	case paystack.TransferStatusFailed, paystack.TransferStatusReversed, "abandoned", "rejected":
+		settlement.LastPayoutError = transfer.Status
+		if transfer.Reason != "" {
+			settlement.LastPayoutError += ": " + transfer.Reason
+		}
+		if err := s.settlementRepo.FailPayout(ctx, settlement); err != nil {
+			return err
+		}
+		s.audit(ctx, "settlement.payout_failed", settlement, map[string]interface{}{
+			"pharmacy_id":     settlement.PharmacyID,
+			"transfer_code":   settlement.TransferCode,
+			"error":           settlement.LastPayoutError,
+			"payout_attempts": settlement.PayoutAttempts,
+		})
+
+	default:
+		if err := s.settlementRepo.SetTransferCode(ctx, settlement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *payoutService) audit(ctx context.Context, action string, settlement *domain.Settlement, metadata map[string]interface{}) {
+	// The transfer has already happened, so only log audit failures.
+	if err := s.auditService.Record(ctx, domain.AuditActorSystem, "", action, "settlement", settlement.ID, metadata); err != nil {
+		log.Printf("Failed to audit %s for settlement %s: %v", action, settlement.ID, err)
+	}
+}
+
+func (s *payoutService) HandleWebhook(ctx context.Context, body []byte, signature string) error {
+	if !s.gateway.VerifyWebhookSignature(body, signature) {
+		return domain.ErrUnauthorized
+	}
+
+	var event paystack.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return err
+	}
+
+	var status string
+	switch event.Event {
+	case "transfer.success":
+		status = paystack.TransferStatusSuccess
+	case "transfer.failed":
+		status = paystack.TransferStatusFailed
+	case "transfer.reversed":
+		status = paystack.TransferStatusReversed
+	default:
+		// Other events are acknowledged and ignored.
+		return nil
+	}
+
+	var transfer paystack.TransferData
+	if err := json.Unmarshal(event.Data, &transfer); err != nil {
+		return err
+	}
+	transfer.Status = status
+
+	settlement, err := s.settlementRepo.GetByTransferReference(ctx, transfer.Reference)
+	if err != nil {
+		if errors.Is(err, domain.ErrSettlementNotFound) {
+			log.Printf("Ignoring %s for unknown transfer reference %s", event.Event, transfer.Reference)
+			return nil
+		}
+		return err
+	}
+
+	if err := s.applyTransfer(ctx, settlement, &transfer); err != nil {
+		if errors.Is(err, domain.ErrSettlementNotPayable) {
+			// Duplicate or out-of-order delivery for a transfer already recorded.
+			log.Printf("Ignoring %s for settlement %s in status %s", event.Event, settlement.ID, settlement.Status)
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RunPayoutRetries retries failed settlement payouts once per interval until
+// ctx is cancelled.
+func RunPayoutRetries(ctx context.Context, payoutService PayoutService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		paid, err := payoutService.RetryFailed(ctx)
+		if err != nil {
+			log.Printf("Payout retry run failed: %v", err)
+		} else if paid > 0 {
+			log.Printf("Paid out %d settlements on retry", paid)
+		}
+	}
+}
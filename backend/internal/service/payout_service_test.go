@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/paystack"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// fakeSettlementRepo keeps settlements in memory with the status rules of
+// the Postgres repository. Methods the payout service does not use panic
+// through the embedded nil interface.
+type fakeSettlementRepo struct {
+	repository.SettlementRepository
+	settlements map[string]*domain.Settlement
+}
+
+func (r *fakeSettlementRepo) stored(settlement *domain.Settlement) *domain.Settlement {
+	return r.settlements[settlement.ID]
+}
+
+func (r *fakeSettlementRepo) save(settlement *domain.Settlement) {
+	settlement.UpdatedAt = time.Now()
+	copied := *settlement
+	r.settlements[settlement.ID] = &copied
+}
+
+func (r *fakeSettlementRepo) GetByID(ctx context.Context, id string) (*domain.Settlement, error) {
+	settlement, ok := r.settlements[id]
+	if !ok {
+		return nil, domain.ErrSettlementNotFound
+	}
+	copied := *settlement
+	return &copied, nil
+}
+
+func (r *fakeSettlementRepo) GetByTransferReference(ctx context.Context, reference string) (*domain.Settlement, error) {
+	for _, settlement := range r.settlements {
+		if settlement.TransferReference == reference {
+			copied := *settlement
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrSettlementNotFound
+}
+
+func (r *fakeSettlementRepo) GetRetryable(ctx context.Context, maxAttempts int) ([]*domain.Settlement, error) {
+	var settlements []*domain.Settlement
+	for _, settlement := range r.settlements {
+		if settlement.Status == domain.SettlementStatusApproved ||
+			(settlement.Status == domain.SettlementStatusFailed && settlement.PayoutAttempts < maxAttempts) {
+			copied := *settlement
+			settlements = append(settlements, &copied)
+		}
+	}
+	return settlements, nil
+}
+
+func (r *fakeSettlementRepo) GetUnconfirmed(ctx context.Context, before time.Time) ([]*domain.Settlement, error) {
+	var settlements []*domain.Settlement
+	for _, settlement := range r.settlements {
+		if settlement.Status == domain.SettlementStatusProcessing && settlement.UpdatedAt.Before(before) {
+			copied := *settlement
+			settlements = append(settlements, &copied)
+		}
+	}
+	return settlements, nil
+}
+
+func (r *fakeSettlementRepo) Approve(ctx context.Context, settlement *domain.Settlement) error {
+	if r.stored(settlement).Status != domain.SettlementStatusUnpaid {
+		return domain.ErrSettlementNotPayable
+	}
+	settlement.Status = domain.SettlementStatusApproved
+	r.save(settlement)
+	return nil
+}
+
+func (r *fakeSettlementRepo) StartPayout(ctx context.Context, settlement *domain.Settlement) error {
+	current := r.stored(settlement)
+	if current.Status != domain.SettlementStatusApproved && current.Status != domain.SettlementStatusFailed {
+		return domain.ErrSettlementNotPayable
+	}
+	settlement.Status = domain.SettlementStatusProcessing
+	settlement.PayoutAttempts = current.PayoutAttempts + 1
+	settlement.TransferReference = fmt.Sprintf("stl_%s_%d", settlement.ID, settlement.PayoutAttempts)
+	settlement.TransferCode = ""
+	settlement.LastPayoutError = ""
+	r.save(settlement)
+	return nil
+}
+
+func (r *fakeSettlementRepo) SetTransferCode(ctx context.Context, settlement *domain.Settlement) error {
+	if r.stored(settlement).TransferReference != settlement.TransferReference {
+		return domain.ErrSettlementNotFound
+	}
+	r.save(settlement)
+	return nil
+}
+
+func (r *fakeSettlementRepo) CompletePayout(ctx context.Context, settlement *domain.Settlement) error {
+	current := r.stored(settlement)
+	if current.TransferReference != settlement.TransferReference || current.Status != domain.SettlementStatusProcessing {
+		return domain.ErrSettlementNotPayable
+	}
+	settlement.Status = domain.SettlementStatusPaid
+	settlement.PayoutReference = settlement.TransferCode
+	r.save(settlement)
+	return nil
+}
+
+func (r *fakeSettlementRepo) FailPayout(ctx context.Context, settlement *domain.Settlement) error {
+	current := r.stored(settlement)
+	if current.TransferReference != settlement.TransferReference ||
+		(current.Status != domain.SettlementStatusProcessing && current.Status != domain.SettlementStatusPaid) {
+		return domain.ErrSettlementNotPayable
+	}
+	settlement.Status = domain.SettlementStatusFailed
+	settlement.PayoutReference = ""
+	settlement.PaidAt = nil
+	r.save(settlement)
+	return nil
+}
+
+type fakeBankAccountRepo struct {
+	repository.BankAccountRepository
+	account *domain.PharmacyBankAccount
+}
+
+func (r *fakeBankAccountRepo) GetEffective(ctx context.Context, pharmacyID string) (*domain.PharmacyBankAccount, error) {
+	return r.account, nil
+}
+
+func (r *fakeBankAccountRepo) SetRecipientCode(ctx context.Context, account *domain.PharmacyBankAccount) error {
+	return nil
+}
+
+type fakeAuditService struct{}
+
+func (fakeAuditService) Record(ctx context.Context, actorType domain.AuditActorType, actorID, action, entityType, entityID string, metadata map[string]interface{}) error {
+	return nil
+}
+
+func (fakeAuditService) List(ctx context.Context, entityType, entityID string, page, pageSize int) ([]*domain.AuditLog, int, error) {
+	return nil, 0, nil
+}
+
+// newTestPayoutService returns a payout service over one unpaid settlement
+// of R100 to a bank account with the given number.
+func newTestPayoutService(accountNumber string) (PayoutService, *fakeSettlementRepo, *paystack.FakeGateway) {
+	settlementRepo := &fakeSettlementRepo{settlements: map[string]*domain.Settlement{
+		"s1": {
+			ID:         "s1",
+			PharmacyID: "p1",
+			PeriodEnd:  time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			NetPayable: decimal.NewFromInt(100),
+			Status:     domain.SettlementStatusUnpaid,
+			UpdatedAt:  time.Now(),
+		},
+	}}
+	bankAccountRepo := &fakeBankAccountRepo{account: &domain.PharmacyBankAccount{
+		ID:            "b1",
+		PharmacyID:    "p1",
+		AccountNumber: accountNumber,
+		AccountHolder: "Test Pharmacy",
+		BankCode:      "632005",
+	}}
+	gateway := paystack.NewFakeGateway()
+	cfg := &config.Config{Timezone: "UTC", PayoutMaxAttempts: 3}
+
+	return NewPayoutService(settlementRepo, bankAccountRepo, gateway, fakeAuditService{}, cfg), settlementRepo, gateway
+}
+
+// makeStale backdates the settlement as if its transfer had been left
+// processing for longer than unconfirmedPayoutAge.
+func makeStale(repo *fakeSettlementRepo, id string) {
+	repo.settlements[id].UpdatedAt = time.Now().Add(-2 * unconfirmedPayoutAge)
+}
+
+func transferWebhook(event, reference string) []byte {
+	return []byte(fmt.Sprintf(`{"event":%q,"data":{"reference":%q,"transfer_code":"TRF_webhook"}}`, event, reference))
+}
+
+func TestPayoutApprove(t *testing.T) {
+	tests := []struct {
+		name          string
+		accountNumber string
+		outcome       string
+		wantStatus    domain.SettlementStatus
+	}{
+		{"transfer succeeds", "1234567890", paystack.TransferStatusSuccess, domain.SettlementStatusPaid},
+		{"transfer fails", "1234567890", paystack.TransferStatusFailed, domain.SettlementStatusFailed},
+		{"account cannot be credited", "9990000000", paystack.TransferStatusSuccess, domain.SettlementStatusFailed},
+		{"transfer pending", "1234567890", "pending", domain.SettlementStatusProcessing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payoutService, repo, gateway := newTestPayoutService(tt.accountNumber)
+			gateway.SetTransferOutcome(tt.outcome)
+
+			settlement, err := payoutService.Approve(context.Background(), "admin", "s1")
+			if err != nil {
+				t.Fatalf("Approve() error = %v", err)
+			}
+			if settlement.Status != tt.wantStatus {
+				t.Fatalf("Approve() status = %q, want %q", settlement.Status, tt.wantStatus)
+			}
+			if stored := repo.settlements["s1"]; stored.Status != tt.wantStatus || stored.PayoutAttempts != 1 {
+				t.Fatalf("stored status = %q after %d attempts, want %q after 1", stored.Status, stored.PayoutAttempts, tt.wantStatus)
+			}
+			if repo.settlements["s1"].TransferCode == "" {
+				t.Fatalf("stored transfer code is empty")
+			}
+
+			if _, err := payoutService.Approve(context.Background(), "admin", "s1"); err != domain.ErrSettlementNotPayable {
+				t.Fatalf("second Approve() error = %v, want %v", err, domain.ErrSettlementNotPayable)
+			}
+		})
+	}
+}
+
+func TestPayoutWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		event      string
+		wantStatus domain.SettlementStatus
+		// wantAfterRetry is the status once RetryFailed has run with
+		// transfers succeeding.
+		wantAfterRetry domain.SettlementStatus
+		wantAttempts   int
+	}{
+		{"success", "transfer.success", domain.SettlementStatusPaid, domain.SettlementStatusPaid, 1},
+		{"failure then retry", "transfer.failed", domain.SettlementStatusFailed, domain.SettlementStatusPaid, 2},
+		{"reversal then retry", "transfer.reversed", domain.SettlementStatusFailed, domain.SettlementStatusPaid, 2},
+		{"unrelated event", "charge.success", domain.SettlementStatusProcessing, domain.SettlementStatusProcessing, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			payoutService, repo, gateway := newTestPayoutService("1234567890")
+			gateway.SetTransferOutcome("pending")
+
+			settlement, err := payoutService.Approve(ctx, "admin", "s1")
+			if err != nil {
+				t.Fatalf("Approve() error = %v", err)
+			}
+
+			if err := payoutService.HandleWebhook(ctx, transferWebhook(tt.event, settlement.TransferReference), ""); err != nil {
+				t.Fatalf("HandleWebhook() error = %v", err)
+			}
+			if got := repo.settlements["s1"].Status; got != tt.wantStatus {
+				t.Fatalf("status after %s = %q, want %q", tt.event, got, tt.wantStatus)
+			}
+
+			// A repeated delivery is acknowledged without changing anything.
+			if err := payoutService.HandleWebhook(ctx, transferWebhook(tt.event, settlement.TransferReference), ""); err != nil {
+				t.Fatalf("repeated HandleWebhook() error = %v", err)
+			}
+
+			gateway.SetTransferOutcome(paystack.TransferStatusSuccess)
+			if _, err := payoutService.RetryFailed(ctx); err != nil {
+				t.Fatalf("RetryFailed() error = %v", err)
+			}
+			stored := repo.settlements["s1"]
+			if stored.Status != tt.wantAfterRetry || stored.PayoutAttempts != tt.wantAttempts {
+				t.Fatalf("after retry status = %q after %d attempts, want %q after %d",
+					stored.Status, stored.PayoutAttempts, tt.wantAfterRetry, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestPayoutRetryFailedConfirmsStaleTransfers(t *testing.T) {
+	tests := []struct {
+		name string
+		// gatewayStatus is what Paystack reports for the transfer by the time
+		// it is checked; empty when Paystack has no record of it.
+		gatewayStatus string
+		// lostTransferCode drops the transfer code, as when the initiate
+		// response never arrived.
+		lostTransferCode bool
+		wantStatus       domain.SettlementStatus
+		wantAttempts     int
+	}{
+		{"accepted transfer succeeded", paystack.TransferStatusSuccess, false, domain.SettlementStatusPaid, 1},
+		{"accepted transfer failed", paystack.TransferStatusFailed, false, domain.SettlementStatusPaid, 2},
+		{"accepted transfer still pending", "pending", false, domain.SettlementStatusProcessing, 1},
+		{"accepted transfer unknown to Paystack", "", false, domain.SettlementStatusProcessing, 1},
+		{"unaccepted transfer unknown to Paystack", "", true, domain.SettlementStatusPaid, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			payoutService, repo, gateway := newTestPayoutService("1234567890")
+			gateway.SetTransferOutcome("pending")
+
+			settlement, err := payoutService.Approve(ctx, "admin", "s1")
+			if err != nil {
+				t.Fatalf("Approve() error = %v", err)
+			}
+
+			if tt.gatewayStatus == "" {
+				// Point the settlement at a reference Paystack never saw.
+				repo.settlements["s1"].TransferReference = settlement.TransferReference + "_lost"
+			} else {
+				gateway.SetTransferStatus(settlement.TransferReference, tt.gatewayStatus)
+			}
+			if tt.lostTransferCode {
+				repo.settlements["s1"].TransferCode = ""
+			}
+
+			// Nothing is checked before the transfer is overdue.
+			if _, err := payoutService.RetryFailed(ctx); err != nil {
+				t.Fatalf("RetryFailed() error = %v", err)
+			}
+			if got := repo.settlements["s1"].Status; got != domain.SettlementStatusProcessing {
+				t.Fatalf("status before transfer is overdue = %q, want %q", got, domain.SettlementStatusProcessing)
+			}
+
+			makeStale(repo, "s1")
+			gateway.SetTransferOutcome(paystack.TransferStatusSuccess)
+			if _, err := payoutService.RetryFailed(ctx); err != nil {
+				t.Fatalf("RetryFailed() error = %v", err)
+			}
+
+			stored := repo.settlements["s1"]
+			if stored.Status != tt.wantStatus || stored.PayoutAttempts != tt.wantAttempts {
+				t.Fatalf("status = %q after %d attempts, want %q after %d",
+					stored.Status, stored.PayoutAttempts, tt.wantStatus, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/utils"
+)
+
+// PharmacyAPIKeyService issues and checks the API keys a pharmacy's
+// dispensing software uses instead of a staff login.
+type PharmacyAPIKeyService interface {
+	Create(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+	List(ctx context.Context, pharmacyID string) ([]dto.APIKeyResponse, error)
+	Revoke(ctx context.Context, pharmacyID, pharmacyUserID, keyID string) error
+	// Authenticate returns the key and the staff member it acts for, failing
+	// with ErrAPIKeyInvalid if the key is unknown or revoked or that staff
+	// member has been disabled.
+	Authenticate(ctx context.Context, rawKey, ip string) (*domain.PharmacyAPIKey, *domain.PharmacyUser, error)
+}
+
+type pharmacyAPIKeyService struct {
+	apiKeyRepo   repository.PharmacyAPIKeyRepository
+	staffService PharmacyStaffService
+	auditService AuditService
+}
+
+func NewPharmacyAPIKeyService(
+	apiKeyRepo repository.PharmacyAPIKeyRepository,
+	staffService PharmacyStaffService,
+	auditService AuditService,
+) PharmacyAPIKeyService {
+	return &pharmacyAPIKeyService{
+		apiKeyRepo:   apiKeyRepo,
+		staffService: staffService,
+		auditService: auditService,
+	}
+}
+
+func (s *pharmacyAPIKeyService) Create(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	var scopes []domain.APIKeyScope
+	seen := make(map[domain.APIKeyScope]bool)
+	for _, s := range req.Scopes {
+		scope := domain.APIKeyScope(strings.TrimSpace(s))
+		if !scope.IsValid() {
+			return nil, domain.ErrInvalidAPIKeyScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	rawKey, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &domain.PharmacyAPIKey{
+		PharmacyID: pharmacyID,
+		CreatedBy:  pharmacyUserID,
+		Name:       strings.TrimSpace(req.Name),
+		KeyPrefix:  prefix,
+		KeyHash:    utils.HashToken(rawKey),
+		Scopes:     scopes,
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "api_key.created", "pharmacy_api_key", key.ID, map[string]interface{}{
+		"pharmacy_id": pharmacyID,
+		"key_prefix":  key.KeyPrefix,
+		"scopes":      req.Scopes,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreateAPIKeyResponse{
+		APIKeyResponse: apiKeyToResponse(key),
+		Key:            rawKey,
+	}, nil
+}
+
+func (s *pharmacyAPIKeyService) List(ctx context.Context, pharmacyID string) ([]dto.APIKeyResponse, error) {
+	keys, err := s.apiKeyRepo.GetByPharmacyID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = apiKeyToResponse(key)
+	}
+
+	return responses, nil
+}
+
+func (s *pharmacyAPIKeyService) Revoke(ctx context.Context, pharmacyID, pharmacyUserID, keyID string) error {
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	if key.PharmacyID != pharmacyID || key.IsRevoked() {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	key.RevokedBy = &pharmacyUserID
+	if err := s.apiKeyRepo.Revoke(ctx, key); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "api_key.revoked", "pharmacy_api_key", key.ID, map[string]interface{}{
+		"pharmacy_id": pharmacyID,
+		"key_prefix":  key.KeyPrefix,
+	})
+}
+
+func (s *pharmacyAPIKeyService) Authenticate(ctx context.Context, rawKey, ip string) (*domain.PharmacyAPIKey, *domain.PharmacyUser, error) {
+	key, err := s.apiKeyRepo.GetByHash(ctx, utils.HashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, nil, domain.ErrAPIKeyInvalid
+		}
+		return nil, nil, err
+	}
+
+	if key.IsRevoked() {
+		return nil, nil, domain.ErrAPIKeyInvalid
+	}
+
+	staff, err := s.staffService.GetActive(ctx, key.PharmacyID, key.CreatedBy)
+	if err != nil {
+		if errors.Is(err, domain.ErrPharmacyUserNotFound) {
+			return nil, nil, domain.ErrAPIKeyInvalid
+		}
+		return nil, nil, err
+	}
+
+	// A failed write should not turn away a valid request.
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, ip); err != nil {
+		log.Printf("Failed to record use of API key %s: %v", key.KeyPrefix, err)
+	}
+
+	return key, staff, nil
+}
+
+func apiKeyToResponse(key *domain.PharmacyAPIKey) dto.APIKeyResponse {
+	response := dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		KeyPrefix:  key.KeyPrefix,
+		Scopes:     make([]string, len(key.Scopes)),
+		CreatedBy:  key.CreatedBy,
+		LastUsedIP: key.LastUsedIP,
+		CreatedAt:  key.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	for i, scope := range key.Scopes {
+		response.Scopes[i] = string(scope)
+	}
+	if key.LastUsedAt != nil {
+		lastUsed := key.LastUsedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.LastUsedAt = &lastUsed
+	}
+	if key.RevokedAt != nil {
+		revoked := key.RevokedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.RevokedAt = &revoked
+	}
+
+	return response
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"log"
 
 	"github.com/carewallet/backend/internal/config"
 	"github.com/carewallet/backend/internal/domain"
@@ -11,29 +12,32 @@ import (
 )
 
 type PharmacyAuthService interface {
-	Login(ctx context.Context, shortCode, password string) (*dto.PharmacyAuthResponse, error)
+	Login(ctx context.Context, shortCode, username, password string) (*dto.PharmacyAuthResponse, error)
 	GetCurrentPharmacy(ctx context.Context, pharmacyID string) (*domain.Pharmacy, error)
 }
 
 type pharmacyAuthService struct {
-	pharmacyRepo repository.PharmacyRepository
-	jwtManager   *utils.JWTManager
-	config       *config.Config
+	pharmacyRepo     repository.PharmacyRepository
+	pharmacyUserRepo repository.PharmacyUserRepository
+	jwtManager       *utils.JWTManager
+	config           *config.Config
 }
 
 func NewPharmacyAuthService(
 	pharmacyRepo repository.PharmacyRepository,
+	pharmacyUserRepo repository.PharmacyUserRepository,
 	jwtManager *utils.JWTManager,
 	cfg *config.Config,
 ) PharmacyAuthService {
 	return &pharmacyAuthService{
-		pharmacyRepo: pharmacyRepo,
-		jwtManager:   jwtManager,
-		config:       cfg,
+		pharmacyRepo:     pharmacyRepo,
+		pharmacyUserRepo: pharmacyUserRepo,
+		jwtManager:       jwtManager,
+		config:           cfg,
 	}
 }
 
-func (s *pharmacyAuthService) Login(ctx context.Context, shortCode, password string) (*dto.PharmacyAuthResponse, error) {
+func (s *pharmacyAuthService) Login(ctx context.Context, shortCode, username, password string) (*dto.PharmacyAuthResponse, error) {
 	pharmacy, err := s.pharmacyRepo.GetByShortCode(ctx, shortCode)
 	if err != nil {
 		if err == domain.ErrPharmacyNotFound {
@@ -46,19 +50,35 @@ func (s *pharmacyAuthService) Login(ctx context.Context, shortCode, password str
 		return nil, domain.ErrPharmacyInactive
 	}
 
-	if !utils.CheckPassword(password, pharmacy.PasswordHash) {
+	user, err := s.pharmacyUserRepo.GetByUsername(ctx, pharmacy.ID, username)
+	if err != nil {
+		if err == domain.ErrPharmacyUserNotFound {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if !user.IsActive() || !utils.CheckPassword(password, user.PasswordHash) {
 		return nil, domain.ErrInvalidCredentials
 	}
 
-	// Generate JWT with pharmacy ID
-	token, _, err := s.jwtManager.Generate(pharmacy.ID, pharmacy.Email)
+	// The token subject stays the pharmacy; the staff member rides along in the claims.
+	token, _, err := s.jwtManager.Generate(pharmacy.ID, pharmacy.Email,
+		utils.WithRole(string(domain.UserRolePharmacy)),
+		utils.WithPharmacyUser(user.ID, string(user.Role)),
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.pharmacyUserRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+		log.Printf("Failed to record login for pharmacy user %s: %v", user.ID, err)
+	}
+
 	return &dto.PharmacyAuthResponse{
 		Token:    token,
 		Pharmacy: pharmacyToResponse(pharmacy),
+		User:     pharmacyUserToResponse(user),
 	}, nil
 }
 
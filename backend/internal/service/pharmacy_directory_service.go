@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+const (
+	defaultSearchRadiusKm = 5
+	maxSearchRadiusKm     = 50
+	defaultSearchLimit    = 20
+	maxSearchLimit        = 50
+)
+
+// PharmacyDirectoryService runs the public pharmacy finder and manages the
+// location, services and opening hours it shows for each pharmacy.
+type PharmacyDirectoryService interface {
+	Search(ctx context.Context, req dto.PharmacySearchRequest) ([]*domain.NearbyPharmacy, error)
+	GetListing(ctx context.Context, pharmacyID string) (*dto.PharmacyListingResponse, error)
+	UpdateListing(ctx context.Context, actorType domain.AuditActorType, actorID, pharmacyID string, req dto.PharmacyListingRequest) (*dto.PharmacyListingResponse, error)
+}
+
+type pharmacyDirectoryService struct {
+	pharmacyRepo repository.PharmacyRepository
+	auditService AuditService
+	config       *config.Config
+}
+
+func NewPharmacyDirectoryService(pharmacyRepo repository.PharmacyRepository, auditService AuditService, cfg *config.Config) PharmacyDirectoryService {
+	return &pharmacyDirectoryService{
+		pharmacyRepo: pharmacyRepo,
+		auditService: auditService,
+		config:       cfg,
+	}
+}
+
+func (s *pharmacyDirectoryService) Search(ctx context.Context, req dto.PharmacySearchRequest) ([]*domain.NearbyPharmacy, error) {
+	if !validLocation(req.Latitude, req.Longitude) {
+		return nil, domain.ErrInvalidLocation
+	}
+
+	radius := req.RadiusKm
+	if radius <= 0 {
+		radius = defaultSearchRadiusKm
+	}
+	if radius > maxSearchRadiusKm {
+		radius = maxSearchRadiusKm
+	}
+
+	limit := req.Limit
+	if limit < 1 || limit > maxSearchLimit {
+		limit = defaultSearchLimit
+	}
+
+	pharmacies, err := s.pharmacyRepo.FindNearby(ctx, repository.NearbyPharmacyFilter{
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		RadiusKm:  radius,
+		Service:   normaliseService(req.Service),
+		OpenNow:   req.OpenNow,
+		Timezone:  s.config.Location().String(),
+		Limit:     limit,
+	})
+	if err != nil || len(pharmacies) == 0 {
+		return pharmacies, err
+	}
+
+	ids := make([]string, len(pharmacies))
+	byID := make(map[string]*domain.NearbyPharmacy, len(pharmacies))
+	for i, p := range pharmacies {
+		ids[i] = p.ID
+		p.OpeningHours = []*domain.PharmacyOpeningHours{}
+		byID[p.ID] = p
+	}
+
+	hours, err := s.pharmacyRepo.GetOpeningHours(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hours {
+		if p, ok := byID[h.PharmacyID]; ok {
+			p.OpeningHours = append(p.OpeningHours, h)
+		}
+	}
+
+	return pharmacies, nil
+}
+
+func (s *pharmacyDirectoryService) GetListing(ctx context.Context, pharmacyID string) (*dto.PharmacyListingResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := s.pharmacyRepo.GetOpeningHours(ctx, pharmacy.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return listingToResponse(pharmacy, hours), nil
+}
+
+func (s *pharmacyDirectoryService) UpdateListing(ctx context.Context, actorType domain.AuditActorType, actorID, pharmacyID string, req dto.PharmacyListingRequest) (*dto.PharmacyListingResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, domain.ErrInvalidLocation
+	}
+	if req.Latitude != nil && !validLocation(*req.Latitude, *req.Longitude) {
+		return nil, domain.ErrInvalidLocation
+	}
+
+	hours := make([]*domain.PharmacyOpeningHours, len(req.OpeningHours))
+	for i, h := range req.OpeningHours {
+		opens, ok := parseClock(h.OpensAt)
+		if !ok {
+			return nil, domain.ErrInvalidOpeningHours
+		}
+		closes, ok := parseClock(h.ClosesAt)
+		if !ok || closes <= opens || h.DayOfWeek < 0 || h.DayOfWeek > 6 {
+			return nil, domain.ErrInvalidOpeningHours
+		}
+		hours[i] = &domain.PharmacyOpeningHours{
+			DayOfWeek: time.Weekday(h.DayOfWeek),
+			OpensAt:   formatClock(opens),
+			ClosesAt:  formatClock(closes),
+		}
+	}
+	sort.SliceStable(hours, func(i, j int) bool {
+		if hours[i].DayOfWeek != hours[j].DayOfWeek {
+			return hours[i].DayOfWeek < hours[j].DayOfWeek
+		}
+		return hours[i].OpensAt < hours[j].OpensAt
+	})
+
+	services := []string{}
+	seen := make(map[string]bool)
+	for _, service := range req.Services {
+		service = normaliseService(service)
+		if service == "" || seen[service] {
+			continue
+		}
+		seen[service] = true
+		services = append(services, service)
+	}
+
+	pharmacy.Latitude = req.Latitude
+	pharmacy.Longitude = req.Longitude
+	pharmacy.Services = services
+
+	if err := s.pharmacyRepo.UpdateListing(ctx, pharmacy, hours); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, actorType, actorID, "pharmacy.listing_updated", "pharmacy", pharmacy.ID, map[string]interface{}{
+		"has_location":  pharmacy.Latitude != nil,
+		"services":      services,
+		"opening_hours": len(hours),
+	}); err != nil {
+		return nil, err
+	}
+
+	return listingToResponse(pharmacy, hours), nil
+}
+
+func listingToResponse(pharmacy *domain.Pharmacy, hours []*domain.PharmacyOpeningHours) *dto.PharmacyListingResponse {
+	response := &dto.PharmacyListingResponse{
+		PharmacyID:   pharmacy.ID,
+		Latitude:     pharmacy.Latitude,
+		Longitude:    pharmacy.Longitude,
+		Services:     pharmacy.Services,
+		OpeningHours: make([]dto.OpeningHoursRequest, len(hours)),
+	}
+	if response.Services == nil {
+		response.Services = []string{}
+	}
+	for i, h := range hours {
+		response.OpeningHours[i] = dto.OpeningHoursRequest{
+			DayOfWeek: int(h.DayOfWeek),
+			OpensAt:   h.OpensAt,
+			ClosesAt:  h.ClosesAt,
+		}
+	}
+	return response
+}
+
+func validLocation(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// normaliseService makes service names such as "Vaccinations " and
+// "vaccinations" match.
+func normaliseService(service string) string {
+	return strings.ToLower(strings.TrimSpace(service))
+}
+
+// parseClock parses an HH:MM time of day into minutes after midnight,
+// allowing 24:00 for the end of the day.
+func parseClock(value string) (int, bool) {
+	var hour, minute int
+	if len(value) != 5 || value[2] != ':' {
+		return 0, false
+	}
+	if _, err := fmt.Sscanf(value, "%02d:%02d", &hour, &minute); err != nil {
+		return 0, false
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
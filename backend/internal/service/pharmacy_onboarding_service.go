@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/utils"
+)
+
+// PharmacyOnboardingService handles pharmacies that register themselves.
+// Applications wait in a review queue where an admin approves them, asks for
+// changes or rejects them. On approval the pharmacy's first manager is emailed
+// a link to choose a password, so admins never handle pharmacy passwords.
+type PharmacyOnboardingService interface {
+	Register(ctx context.Context, req dto.PharmacyRegistrationRequest) (*dto.PharmacyApplicationResponse, error)
+	// GetApplication and Resubmit are used by the applicant, who proves
+	// ownership with the token returned at registration.
+	GetApplication(ctx context.Context, id, token string) (*dto.PharmacyApplicationResponse, error)
+	Resubmit(ctx context.Context, id, token string, req dto.PharmacyRegistrationRequest) (*dto.PharmacyApplicationResponse, error)
+	List(ctx context.Context, status string) ([]*dto.PharmacyApplicationResponse, error)
+	Get(ctx context.Context, id string) (*dto.PharmacyApplicationResponse, error)
+	Approve(ctx context.Context, adminID, id string, req dto.ApprovePharmacyRequest) (*dto.PharmacyApplicationResponse, error)
+	RequestChanges(ctx context.Context, adminID, id, reason string) (*dto.PharmacyApplicationResponse, error)
+	Reject(ctx context.Context, adminID, id, reason string) (*dto.PharmacyApplicationResponse, error)
+}
+
+type pharmacyOnboardingService struct {
+	pharmacyRepo  repository.PharmacyRepository
+	staffService  PharmacyStaffService
+	uploadService UploadService
+	emailService  EmailService
+	auditService  AuditService
+	config        *config.Config
+}
+
+func NewPharmacyOnboardingService(
+	pharmacyRepo repository.PharmacyRepository,
+	staffService PharmacyStaffService,
+	uploadService UploadService,
+	emailService EmailService,
+	auditService AuditService,
+	cfg *config.Config,
+) PharmacyOnboardingService {
+	return &pharmacyOnboardingService{
+		pharmacyRepo:  pharmacyRepo,
+		staffService:  staffService,
+		uploadService: uploadService,
+		emailService:  emailService,
+		auditService:  auditService,
+		config:        cfg,
+	}
+}
+
+func (s *pharmacyOnboardingService) Register(ctx context.Context, req dto.PharmacyRegistrationRequest) (*dto.PharmacyApplicationResponse, error) {
+	documents, err := s.toDocuments(ctx, "", req.Documents)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pharmacy := &domain.Pharmacy{
+		Status:               domain.PharmacyStatusPending,
+		ApplicationTokenHash: utils.HashToken(token),
+		SubmittedAt:          &now,
+	}
+	applyRegistration(pharmacy, req)
+
+	if err := s.pharmacyRepo.CreateApplication(ctx, pharmacy, documents); err != nil {
+		return nil, err
+	}
+
+	if err := s.claimUploads(ctx, pharmacy.ID, documents); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacy.ID, "pharmacy.registered", "pharmacy", pharmacy.ID, map[string]interface{}{
+		"name":                pharmacy.Name,
+		"registration_number": pharmacy.RegistrationNumber,
+		"documents":           len(documents),
+	}); err != nil {
+		return nil, err
+	}
+
+	link := fmt.Sprintf("%s/pharmacy/register/%s?token=%s", s.config.FrontendURL, pharmacy.ID, token)
+	s.notify(ctx, pharmacy, "We received your CareWallet pharmacy application", fmt.Sprintf(
+		"Hello %s,\n\nThanks for applying to accept CareWallet at %s. We will review your application and let you know the outcome.\n\nYou can check on your application here: %s",
+		pharmacy.ContactName, pharmacy.Name, link,
+	))
+
+	response := s.applicationToResponse(pharmacy, documents)
+	response.ApplicationToken = token
+	return response, nil
+}
+
+func (s *pharmacyOnboardingService) GetApplication(ctx context.Context, id, token string) (*dto.PharmacyApplicationResponse, error) {
+	pharmacy, err := s.getForApplicant(ctx, id, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, pharmacy)
+}
+
+func (s *pharmacyOnboardingService) Resubmit(ctx context.Context, id, token string, req dto.PharmacyRegistrationRequest) (*dto.PharmacyApplicationResponse, error) {
+	pharmacy, err := s.getForApplicant(ctx, id, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.Status != domain.PharmacyStatusChangesRequested {
+		return nil, domain.ErrApplicationNotEditable
+	}
+
+	documents, err := s.toDocuments(ctx, pharmacy.ID, req.Documents)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	applyRegistration(pharmacy, req)
+	pharmacy.Status = domain.PharmacyStatusPending
+	pharmacy.SubmittedAt = &now
+
+	if err := s.pharmacyRepo.UpdateApplication(ctx, pharmacy, documents); err != nil {
+		return nil, err
+	}
+
+	if err := s.claimUploads(ctx, pharmacy.ID, documents); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacy.ID, "pharmacy.resubmitted", "pharmacy", pharmacy.ID, map[string]interface{}{
+		"documents": len(documents),
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.applicationToResponse(pharmacy, documents), nil
+}
+
+func (s *pharmacyOnboardingService) List(ctx context.Context, status string) ([]*dto.PharmacyApplicationResponse, error) {
+	pharmacies, err := s.pharmacyRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.PharmacyApplicationResponse, 0)
+	for _, pharmacy := range pharmacies {
+		if pharmacy.SubmittedAt == nil || string(pharmacy.Status) != status {
+			continue
+		}
+		response, err := s.toResponse(ctx, pharmacy)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func (s *pharmacyOnboardingService) Get(ctx context.Context, id string) (*dto.PharmacyApplicationResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, pharmacy)
+}
+
+func (s *pharmacyOnboardingService) Approve(ctx context.Context, adminID, id string, req dto.ApprovePharmacyRequest) (*dto.PharmacyApplicationResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.Status != domain.PharmacyStatusPending {
+		return nil, domain.ErrApplicationNotReviewable
+	}
+	if pharmacy.Email == "" {
+		return nil, domain.ErrPharmacyEmailRequired
+	}
+
+	// The manager is invited while the application is still pending, so a
+	// failed invite leaves it to be approved again rather than live with no
+	// one able to sign in.
+	if err := s.inviteManager(ctx, adminID, pharmacy, req.ManagerUsername); err != nil {
+		return nil, err
+	}
+
+	s.review(pharmacy, adminID, domain.PharmacyStatusActive, req.Notes)
+	// The applicant signs in as staff from now on.
+	pharmacy.ApplicationTokenHash = ""
+
+	if err := s.pharmacyRepo.Update(ctx, pharmacy); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "pharmacy.approved", "pharmacy", pharmacy.ID, map[string]interface{}{
+		"notes": pharmacy.ReviewNotes,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, pharmacy)
+}
+
+// inviteManager invites the pharmacy's first manager. A manager created by an
+// earlier approval that failed part way is sent a fresh setup link instead.
+func (s *pharmacyOnboardingService) inviteManager(ctx context.Context, adminID string, pharmacy *domain.Pharmacy, username string) error {
+	staff, err := s.staffService.List(ctx, pharmacy.ID)
+	if err != nil {
+		return err
+	}
+	for _, user := range staff {
+		if user.Role == string(domain.PharmacyUserRoleManager) {
+			return s.staffService.SendSetupLink(ctx, domain.AuditActorAdmin, adminID, pharmacy.ID, user.ID)
+		}
+	}
+
+	_, err = s.staffService.InviteManager(ctx, domain.AuditActorAdmin, adminID, pharmacy, username, pharmacy.ContactName)
+	return err
+}
+
+func (s *pharmacyOnboardingService) RequestChanges(ctx context.Context, adminID, id, reason string) (*dto.PharmacyApplicationResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.Status != domain.PharmacyStatusPending {
+		return nil, domain.ErrApplicationNotReviewable
+	}
+
+	s.review(pharmacy, adminID, domain.PharmacyStatusChangesRequested, reason)
+
+	if err := s.pharmacyRepo.Update(ctx, pharmacy); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "pharmacy.changes_requested", "pharmacy", pharmacy.ID, map[string]interface{}{
+		"reason": pharmacy.ReviewNotes,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, pharmacy, "Your CareWallet pharmacy application needs changes", fmt.Sprintf(
+		"Hello %s,\n\nWe reviewed the application for %s and need a few changes before we can approve it:\n\n%s\n\nPlease update your application using the link we sent when you applied.",
+		pharmacy.ContactName, pharmacy.Name, pharmacy.ReviewNotes,
+	))
+
+	return s.toResponse(ctx, pharmacy)
+}
+
+func (s *pharmacyOnboardingService) Reject(ctx context.Context, adminID, id, reason string) (*dto.PharmacyApplicationResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pharmacy.IsUnderReview() {
+		return nil, domain.ErrApplicationNotReviewable
+	}
+
+	s.review(pharmacy, adminID, domain.PharmacyStatusRejected, reason)
+
+	if err := s.pharmacyRepo.Update(ctx, pharmacy); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "pharmacy.rejected", "pharmacy", pharmacy.ID, map[string]interface{}{
+		"reason": pharmacy.ReviewNotes,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, pharmacy, "Your CareWallet pharmacy application", fmt.Sprintf(
+		"Hello %s,\n\nUnfortunately we could not approve the application for %s:\n\n%s",
+		pharmacy.ContactName, pharmacy.Name, pharmacy.ReviewNotes,
+	))
+
+	return s.toResponse(ctx, pharmacy)
+}
+
+func (s *pharmacyOnboardingService) getForApplicant(ctx context.Context, id, token string) (*domain.Pharmacy, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrPharmacyNotFound {
+			return nil, domain.ErrApplicationNotFound
+		}
+		return nil, err
+	}
+
+	if pharmacy.ApplicationTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(pharmacy.ApplicationTokenHash), []byte(utils.HashToken(token))) != 1 {
+		return nil, domain.ErrApplicationNotFound
+	}
+
+	return pharmacy, nil
+}
+
+func (s *pharmacyOnboardingService) review(pharmacy *domain.Pharmacy, adminID string, status domain.PharmacyStatus, notes string) {
+	now := time.Now()
+	pharmacy.Status = status
+	pharmacy.ReviewNotes = strings.TrimSpace(notes)
+	pharmacy.ReviewedBy = &adminID
+	pharmacy.ReviewedAt = &now
+}
+
+// notify emails the applicant. The review has already been saved, so a failed
+// email is only logged.
+func (s *pharmacyOnboardingService) notify(ctx context.Context, pharmacy *domain.Pharmacy, subject, body string) {
+	if err := s.emailService.SendEmail(ctx, pharmacy.Email, subject, body); err != nil {
+		log.Printf("Failed to email pharmacy %s: %v", pharmacy.ID, err)
+	}
+}
+
+func (s *pharmacyOnboardingService) toResponse(ctx context.Context, pharmacy *domain.Pharmacy) (*dto.PharmacyApplicationResponse, error) {
+	documents, err := s.pharmacyRepo.GetDocuments(ctx, pharmacy.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applicationToResponse(pharmacy, documents), nil
+}
+
+// toDocuments validates the submitted documents. Uploaded documents must be
+// the applicant's own unclaimed uploads or already belong to the pharmacy.
+func (s *pharmacyOnboardingService) toDocuments(ctx context.Context, pharmacyID string, reqs []dto.PharmacyDocumentRequest) ([]*domain.PharmacyDocument, error) {
+	documents := make([]*domain.PharmacyDocument, len(reqs))
+	for i, req := range reqs {
+		documentType := domain.PharmacyDocumentType(req.DocumentType)
+		if !documentType.IsValid() {
+			return nil, domain.ErrInvalidDocumentType
+		}
+
+		document := &domain.PharmacyDocument{
+			DocumentType: documentType,
+			FileName:     strings.TrimSpace(req.FileName),
+			URL:          strings.TrimSpace(req.URL),
+		}
+
+		if req.UploadID != "" {
+			upload, err := s.uploadService.GetOwned(ctx, domain.UploadOwnerApplicant, "", req.UploadID, domain.UploadPurposePharmacyDocument)
+			if errors.Is(err, domain.ErrUploadNotFound) && pharmacyID != "" {
+				upload, err = s.uploadService.GetOwned(ctx, domain.UploadOwnerPharmacy, pharmacyID, req.UploadID, domain.UploadPurposePharmacyDocument)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			document.UploadID = &upload.ID
+			document.URL = ""
+			if document.FileName == "" {
+				document.FileName = upload.FileName
+			}
+		}
+
+		if document.FileName == "" {
+			document.FileName = string(documentType)
+		}
+		documents[i] = document
+	}
+	return documents, nil
+}
+
+// claimUploads hands the applicant's uploads to the pharmacy so nobody else
+// can attach them to another application.
+func (s *pharmacyOnboardingService) claimUploads(ctx context.Context, pharmacyID string, documents []*domain.PharmacyDocument) error {
+	for _, document := range documents {
+		if document.UploadID == nil {
+			continue
+		}
+		if err := s.uploadService.Claim(ctx, *document.UploadID, domain.UploadOwnerPharmacy, pharmacyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyRegistration(pharmacy *domain.Pharmacy, req dto.PharmacyRegistrationRequest) {
+	pharmacy.Name = strings.TrimSpace(req.Name)
+	pharmacy.ShortCode = strings.ToUpper(strings.TrimSpace(req.ShortCode))
+	pharmacy.RegistrationNumber = strings.TrimSpace(req.RegistrationNumber)
+	pharmacy.Address = strings.TrimSpace(req.Address)
+	pharmacy.Phone = strings.TrimSpace(req.Phone)
+	pharmacy.Email = strings.TrimSpace(req.Email)
+	pharmacy.ContactName = strings.TrimSpace(req.ContactName)
+}
+
+func (s *pharmacyOnboardingService) applicationToResponse(p *domain.Pharmacy, documents []*domain.PharmacyDocument) *dto.PharmacyApplicationResponse {
+	response := &dto.PharmacyApplicationResponse{
+		ID:                 p.ID,
+		Name:               p.Name,
+		ShortCode:          p.ShortCode,
+		RegistrationNumber: p.RegistrationNumber,
+		Address:            p.Address,
+		Phone:              p.Phone,
+		Email:              p.Email,
+		ContactName:        p.ContactName,
+		Status:             string(p.Status),
+		ReviewNotes:        p.ReviewNotes,
+		Documents:          make([]dto.PharmacyDocumentResponse, len(documents)),
+	}
+
+	if p.SubmittedAt != nil {
+		submittedAt := p.SubmittedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.SubmittedAt = &submittedAt
+	}
+	if p.ReviewedAt != nil {
+		reviewedAt := p.ReviewedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.ReviewedAt = &reviewedAt
+	}
+
+	for i, d := range documents {
+		response.Documents[i] = dto.PharmacyDocumentResponse{
+			ID:           d.ID,
+			DocumentType: string(d.DocumentType),
+			FileName:     d.FileName,
+			URL:          d.URL,
+			UploadID:     d.UploadID,
+		}
+		if d.UploadID != nil {
+			response.Documents[i].URL = s.uploadService.SignedURL(*d.UploadID)
+		}
+	}
+
+	return response
+}
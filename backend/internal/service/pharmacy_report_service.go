@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+// PharmacyReportService gives pharmacies their own transaction history and
+// the end-of-day figures they reconcile their tills against.
+type PharmacyReportService interface {
+	ListTransactions(ctx context.Context, filter repository.PharmacyTransactionFilter) (*dto.TransactionListResponse, error)
+	// EndOfDay reports the business day (in the configured timezone) that
+	// contains day, with a line per staff member.
+	EndOfDay(ctx context.Context, pharmacyID string, day time.Time) (*EndOfDayReport, error)
+}
+
+type EndOfDayReport struct {
+	PharmacyName      string                   `json:"pharmacy_name"`
+	PharmacyShortCode string                   `json:"pharmacy_short_code"`
+	Date              string                   `json:"date"`
+	From              time.Time                `json:"from"`
+	To                time.Time                `json:"to"`
+	Cashiers          []*domain.CashierSummary `json:"cashiers"`
+	Totals            domain.CashierSummary    `json:"totals"`
+}
+
+type pharmacyReportService struct {
+	transactionRepo repository.TransactionRepository
+	pharmacyRepo    repository.PharmacyRepository
+	config          *config.Config
+}
+
+func NewPharmacyReportService(
+	transactionRepo repository.TransactionRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	cfg *config.Config,
+) PharmacyReportService {
+	return &pharmacyReportService{
+		transactionRepo: transactionRepo,
+		pharmacyRepo:    pharmacyRepo,
+		config:          cfg,
+	}
+}
+
+func (s *pharmacyReportService) ListTransactions(ctx context.Context, filter repository.PharmacyTransactionFilter) (*dto.TransactionListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	transactions, total, err := s.transactionRepo.GetByPharmacyID(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		responses[i] = *transactionToResponse(tx)
+	}
+
+	return &dto.TransactionListResponse{
+		Transactions: responses,
+		Total:        total,
+		Page:         filter.Page,
+		PageSize:     filter.PageSize,
+	}, nil
+}
+
+func (s *pharmacyReportService) EndOfDay(ctx context.Context, pharmacyID string, day time.Time) (*EndOfDayReport, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := s.config.StartOfDay(day)
+	to := from.AddDate(0, 0, 1)
+
+	cashiers, err := s.transactionRepo.SummariseByCashier(ctx, pharmacyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &EndOfDayReport{
+		PharmacyName:      pharmacy.Name,
+		PharmacyShortCode: pharmacy.ShortCode,
+		Date:              from.Format("2006-01-02"),
+		From:              from,
+		To:                to,
+		Cashiers:          cashiers,
+	}
+	for _, cashier := range cashiers {
+		report.Totals.Add(cashier)
+	}
+
+	return report, nil
+}
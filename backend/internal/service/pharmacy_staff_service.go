@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/utils"
+)
+
+// PharmacyStaffService manages the staff logins of a pharmacy. Staff are
+// disabled rather than deleted so their past transactions stay attributable.
+type PharmacyStaffService interface {
+	List(ctx context.Context, pharmacyID string) ([]dto.PharmacyUserResponse, error)
+	// Create adds a staff member on behalf of a pharmacy manager or, when a
+	// pharmacy has lost access to every manager login, an admin.
+	Create(ctx context.Context, actorType domain.AuditActorType, actorID, pharmacyID string, req dto.CreatePharmacyUserRequest) (*dto.PharmacyUserResponse, error)
+	Update(ctx context.Context, actorID, pharmacyID, userID string, req dto.UpdatePharmacyUserRequest) (*dto.PharmacyUserResponse, error)
+	Disable(ctx context.Context, actorID, pharmacyID, userID string) error
+	// GetActive returns the staff member if they belong to the pharmacy and
+	// have not been disabled.
+	GetActive(ctx context.Context, pharmacyID, userID string) (*domain.PharmacyUser, error)
+	// InviteManager creates a manager login without a usable password and
+	// emails the pharmacy a link to choose one.
+	InviteManager(ctx context.Context, actorType domain.AuditActorType, actorID string, pharmacy *domain.Pharmacy, username, fullName string) (*dto.PharmacyUserResponse, error)
+	// SendSetupLink emails the pharmacy a fresh set-password link for a staff
+	// member, invalidating any earlier link.
+	SendSetupLink(ctx context.Context, actorType domain.AuditActorType, actorID, pharmacyID, userID string) error
+	SetPassword(ctx context.Context, token, password string) error
+}
+
+type pharmacyStaffService struct {
+	pharmacyUserRepo repository.PharmacyUserRepository
+	pharmacyRepo     repository.PharmacyRepository
+	setupTokenRepo   repository.PasswordSetupTokenRepository
+	emailService     EmailService
+	auditService     AuditService
+	config           *config.Config
+}
+
+func NewPharmacyStaffService(
+	pharmacyUserRepo repository.PharmacyUserRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	setupTokenRepo repository.PasswordSetupTokenRepository,
+	emailService EmailService,
+	auditService AuditService,
+	cfg *config.Config,
+) PharmacyStaffService {
+	return &pharmacyStaffService{
+		pharmacyUserRepo: pharmacyUserRepo,
+		pharmacyRepo:     pharmacyRepo,
+		setupTokenRepo:   setupTokenRepo,
+		emailService:     emailService,
+		auditService:     auditService,
+		config:           cfg,
+	}
+}
+
+func (s *pharmacyStaffService) List(ctx context.Context, pharmacyID string) ([]dto.PharmacyUserResponse, error) {
+	users, err := s.pharmacyUserRepo.GetByPharmacyID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PharmacyUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = pharmacyUserToResponse(user)
+	}
+
+	return responses, nil
+}
+
+func (s *pharmacyStaffService) Create(ctx context.Context, actorType domain.AuditActorType, actorID, pharmacyID string, req dto.CreatePharmacyUserRequest) (*dto.PharmacyUserResponse, error) {
+	role := domain.PharmacyUserRole(req.Role)
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidPharmacyRole
+	}
+
+	passwordHash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.PharmacyUser{
+		PharmacyID:   pharmacyID,
+		Username:     strings.TrimSpace(req.Username),
+		FullName:     strings.TrimSpace(req.FullName),
+		PasswordHash: passwordHash,
+		Role:         role,
+		Status:       domain.PharmacyUserStatusActive,
+	}
+
+	if err := s.pharmacyUserRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, actorType, actorID, "pharmacy_user.created", "pharmacy_user", user.ID, map[string]interface{}{
+		"pharmacy_id": pharmacyID,
+		"username":    user.Username,
+		"role":        string(user.Role),
+	}); err != nil {
+		return nil, err
+	}
+
+	response := pharmacyUserToResponse(user)
+	return &response, nil
+}
+
+func (s *pharmacyStaffService) Update(ctx context.Context, actorID, pharmacyID, userID string, req dto.UpdatePharmacyUserRequest) (*dto.PharmacyUserResponse, error) {
+	user, err := s.getForPharmacy(ctx, pharmacyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wasActiveManager := user.Role == domain.PharmacyUserRoleManager && user.IsActive()
+
+	if req.FullName != "" {
+		user.FullName = strings.TrimSpace(req.FullName)
+	}
+	if req.Role != "" {
+		role := domain.PharmacyUserRole(req.Role)
+		if !role.IsValid() {
+			return nil, domain.ErrInvalidPharmacyRole
+		}
+		user.Role = role
+	}
+	if req.Status != "" {
+		user.Status = domain.PharmacyUserStatus(req.Status)
+	}
+	if req.Password != "" {
+		passwordHash, err := utils.HashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = passwordHash
+	}
+
+	if wasActiveManager && (user.Role != domain.PharmacyUserRoleManager || !user.IsActive()) {
+		if err := s.ensureAnotherManager(ctx, pharmacyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.pharmacyUserRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, actorID, "pharmacy_user.updated", "pharmacy_user", user.ID, map[string]interface{}{
+		"pharmacy_id":      pharmacyID,
+		"role":             string(user.Role),
+		"status":           string(user.Status),
+		"password_changed": req.Password != "",
+	}); err != nil {
+		return nil, err
+	}
+
+	response := pharmacyUserToResponse(user)
+	return &response, nil
+}
+
+func (s *pharmacyStaffService) Disable(ctx context.Context, actorID, pharmacyID, userID string) error {
+	user, err := s.getForPharmacy(ctx, pharmacyID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsActive() {
+		return nil
+	}
+
+	if user.Role == domain.PharmacyUserRoleManager {
+		if err := s.ensureAnotherManager(ctx, pharmacyID); err != nil {
+			return err
+		}
+	}
+
+	user.Status = domain.PharmacyUserStatusDisabled
+	if err := s.pharmacyUserRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, domain.AuditActorPharmacy, actorID, "pharmacy_user.disabled", "pharmacy_user", user.ID, map[string]interface{}{
+		"pharmacy_id": pharmacyID,
+		"username":    user.Username,
+	})
+}
+
+func (s *pharmacyStaffService) GetActive(ctx context.Context, pharmacyID, userID string) (*domain.PharmacyUser, error) {
+	user, err := s.getForPharmacy(ctx, pharmacyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, domain.ErrPharmacyUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *pharmacyStaffService) InviteManager(ctx context.Context, actorType domain.AuditActorType, actorID string, pharmacy *domain.Pharmacy, username, fullName string) (*dto.PharmacyUserResponse, error) {
+	if pharmacy.Email == "" {
+		return nil, domain.ErrPharmacyEmailRequired
+	}
+
+	// Nobody knows this password; the manager sets their own from the emailed link.
+	placeholder, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := utils.HashPassword(placeholder)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.PharmacyUser{
+		PharmacyID:   pharmacy.ID,
+		Username:     strings.TrimSpace(username),
+		FullName:     strings.TrimSpace(fullName),
+		PasswordHash: passwordHash,
+		Role:         domain.PharmacyUserRoleManager,
+		Status:       domain.PharmacyUserStatusActive,
+	}
+	if user.Username == "" {
+		user.Username = "manager"
+	}
+	if user.FullName == "" {
+		user.FullName = pharmacy.Name
+	}
+
+	if err := s.pharmacyUserRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, actorType, actorID, "pharmacy_user.invited", "pharmacy_user", user.ID, map[string]interface{}{
+		"pharmacy_id": pharmacy.ID,
+		"username":    user.Username,
+		"role":        string(user.Role),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendSetupLink(ctx, pharmacy, user); err != nil {
+		return nil, err
+	}
+
+	response := pharmacyUserToResponse(user)
+	return &response, nil
+}
+
+func (s *pharmacyStaffService) SendSetupLink(ctx context.Context, actorType domain.AuditActorType, actorID, pharmacyID, userID string) error {
+	user, err := s.GetActive(ctx, pharmacyID, userID)
+	if err != nil {
+		return err
+	}
+
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return err
+	}
+	if pharmacy.Email == "" {
+		return domain.ErrPharmacyEmailRequired
+	}
+
+	if err := s.sendSetupLink(ctx, pharmacy, user); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, actorType, actorID, "pharmacy_user.setup_link_sent", "pharmacy_user", user.ID, map[string]interface{}{
+		"pharmacy_id": pharmacyID,
+		"username":    user.Username,
+	})
+}
+
+func (s *pharmacyStaffService) SetPassword(ctx context.Context, token, password string) error {
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	setupToken, err := s.setupTokenRepo.Consume(ctx, utils.HashToken(token), passwordHash)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.pharmacyUserRepo.GetByID(ctx, setupToken.PharmacyUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, domain.AuditActorPharmacy, user.ID, "pharmacy_user.password_set", "pharmacy_user", user.ID, map[string]interface{}{
+		"pharmacy_id": user.PharmacyID,
+	})
+}
+
+// sendSetupLink issues a new one-time token and emails the link to the
+// pharmacy's address. Staff have no email of their own, so the pharmacy
+// passes the link on.
+func (s *pharmacyStaffService) sendSetupLink(ctx context.Context, pharmacy *domain.Pharmacy, user *domain.PharmacyUser) error {
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(time.Duration(s.config.PasswordSetupTokenHours) * time.Hour)
+	if err := s.setupTokenRepo.Create(ctx, &domain.PasswordSetupToken{
+		PharmacyUserID: user.ID,
+		TokenHash:      utils.HashToken(token),
+		ExpiresAt:      expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/pharmacy/set-password?token=%s", s.config.FrontendURL, token)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nA CareWallet pharmacy portal login has been set up for %s.\n\nPharmacy code: %s\nUsername: %s\n\nChoose your password here: %s\n\nThis link can be used once and expires on %s.",
+		user.FullName, pharmacy.Name, pharmacy.ShortCode, user.Username, link, expiresAt.In(s.config.Location()).Format("2 January 2006 15:04"),
+	)
+
+	return s.emailService.SendEmail(ctx, pharmacy.Email, "Set your CareWallet pharmacy password", body)
+}
+
+func (s *pharmacyStaffService) getForPharmacy(ctx context.Context, pharmacyID, userID string) (*domain.PharmacyUser, error) {
+	user, err := s.pharmacyUserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PharmacyID != pharmacyID {
+		return nil, domain.ErrPharmacyUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *pharmacyStaffService) ensureAnotherManager(ctx context.Context, pharmacyID string) error {
+	count, err := s.pharmacyUserRepo.CountActiveManagers(ctx, pharmacyID)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return domain.ErrLastManager
+	}
+	return nil
+}
+
+func pharmacyUserToResponse(u *domain.PharmacyUser) dto.PharmacyUserResponse {
+	response := dto.PharmacyUserResponse{
+		ID:       u.ID,
+		Username: u.Username,
+		FullName: u.FullName,
+		Role:     string(u.Role),
+		Status:   string(u.Status),
+	}
+
+	if u.LastLoginAt != nil {
+		lastLogin := u.LastLoginAt.Format("2006-01-02T15:04:05Z07:00")
+		response.LastLoginAt = &lastLogin
+	}
+
+	return response
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/fees"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// PharmacyWithdrawalService handles withdrawals made at the pharmacy counter.
+// Staff start a withdrawal, the beneficiary receives an OTP, and the
+// withdrawal is completed once staff enter the OTP the beneficiary reads out.
+// The balance is only checked on completion, after the beneficiary has
+// consented, so starting withdrawals cannot be used to find it out.
+type PharmacyWithdrawalService interface {
+	Initiate(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.WithdrawalInitRequest) (*dto.WithdrawalInitResponse, error)
+	Complete(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.WithdrawalCompleteRequest) (*dto.TransactionResponse, error)
+	// Void reverses a completed withdrawal keyed in by mistake, returning the
+	// reversal. It is only allowed on the business day the withdrawal was
+	// made, within the configured window and before it is settled.
+	Void(ctx context.Context, pharmacyID, pharmacyUserID, transactionID, reason string) (*dto.TransactionResponse, error)
+}
+
+type pharmacyWithdrawalService struct {
+	withdrawalRepo   repository.PharmacyWithdrawalRepository
+	transactionRepo  repository.TransactionRepository
+	spendingRuleRepo repository.WalletSpendingRuleRepository
+	userRepo         repository.UserRepository
+	pharmacyRepo     repository.PharmacyRepository
+	lookupService    WalletLookupService
+	feeEngine        fees.Engine
+	feeQuoteService  FeeQuoteService
+	otpService       OTPService
+	auditService     AuditService
+	config           *config.Config
+}
+
+func NewPharmacyWithdrawalService(
+	withdrawalRepo repository.PharmacyWithdrawalRepository,
+	transactionRepo repository.TransactionRepository,
+	spendingRuleRepo repository.WalletSpendingRuleRepository,
+	userRepo repository.UserRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	lookupService WalletLookupService,
+	feeEngine fees.Engine,
+	feeQuoteService FeeQuoteService,
+	otpService OTPService,
+	auditService AuditService,
+	cfg *config.Config,
+) PharmacyWithdrawalService {
+	return &pharmacyWithdrawalService{
+		withdrawalRepo:   withdrawalRepo,
+		transactionRepo:  transactionRepo,
+		spendingRuleRepo: spendingRuleRepo,
+		userRepo:         userRepo,
+		pharmacyRepo:     pharmacyRepo,
+		lookupService:    lookupService,
+		feeEngine:        feeEngine,
+		feeQuoteService:  feeQuoteService,
+		otpService:       otpService,
+		auditService:     auditService,
+		config:           cfg,
+	}
+}
+
+func (s *pharmacyWithdrawalService) Initiate(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.WithdrawalInitRequest) (*dto.WithdrawalInitResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.Status != domain.PharmacyStatusActive {
+		return nil, domain.ErrPharmacyInactive
+	}
+
+	wallet, err := s.lookupService.Find(ctx, pharmacyID, pharmacyUserID, req.WalletCode, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.Status.CanBeSpent() {
+		return nil, domain.ErrWalletNotSpendable
+	}
+
+	if err := checkSpendingRules(ctx, s.spendingRuleRepo, wallet.ID, pharmacyID); err != nil {
+		return nil, err
+	}
+
+	amount := decimal.NewFromFloat(req.Amount).Round(2)
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	// The OTP goes to the beneficiary, or to the wallet creator if there is none.
+	ownerID := wallet.CreatorID
+	if wallet.BeneficiaryID != nil {
+		ownerID = *wallet.BeneficiaryID
+	}
+	owner, err := s.userRepo.GetByID(ctx, ownerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrNoBeneficiaryEmail
+		}
+		return nil, err
+	}
+	if owner.Email == "" {
+		return nil, domain.ErrNoBeneficiaryEmail
+	}
+
+	var quoteID *string
+	if req.QuoteID != "" {
+		quote, err := s.feeQuoteService.Check(ctx, req.QuoteID, wallet.ID, pharmacyID, amount)
+		if err != nil {
+			return nil, err
+		}
+		quoteID = &quote.ID
+	}
+
+	withdrawal := &domain.PharmacyWithdrawal{
+		PharmacyID:     pharmacyID,
+		PharmacyUserID: pharmacyUserID,
+		WalletID:       wallet.ID,
+		Amount:         amount,
+		FeeQuoteID:     quoteID,
+		OTPEmail:       owner.Email,
+		Status:         domain.PharmacyWithdrawalStatusPending,
+		ExpiresAt:      time.Now().Add(time.Duration(s.config.OTPExpirationMinutes) * time.Minute),
+	}
+
+	if err := s.withdrawalRepo.Create(ctx, withdrawal); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.otpService.Send(ctx, dto.SendOTPRequest{
+		Email:   owner.Email,
+		Purpose: string(domain.OTPPurposeWithdrawal),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dto.WithdrawalInitResponse{
+		WithdrawalID:    withdrawal.ID,
+		WalletName:      wallet.WalletName,
+		BeneficiaryName: maskName(owner.FullName),
+		Amount:          amount.InexactFloat64(),
+		OTPSentTo:       maskEmail(owner.Email),
+	}, nil
+}
+
+func (s *pharmacyWithdrawalService) Complete(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.WithdrawalCompleteRequest) (*dto.TransactionResponse, error) {
+	withdrawal, err := s.withdrawalRepo.GetByID(ctx, req.WithdrawalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if withdrawal.PharmacyID != pharmacyID {
+		return nil, domain.ErrWithdrawalNotFound
+	}
+
+	if withdrawal.Status != domain.PharmacyWithdrawalStatusPending {
+		return nil, domain.ErrWithdrawalNotPending
+	}
+
+	if withdrawal.IsExpired() {
+		return nil, domain.ErrWithdrawalExpired
+	}
+
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.Status != domain.PharmacyStatusActive {
+		return nil, domain.ErrPharmacyInactive
+	}
+
+	// Check the line items before the OTP is used up.
+	lineItems, err := lineItemsFromRequest(req.LineItems, withdrawal.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// The wallet owner may have restricted where it is spent since the
+	// withdrawal was started.
+	if err := checkSpendingRules(ctx, s.spendingRuleRepo, withdrawal.WalletID, pharmacyID); err != nil {
+		return nil, err
+	}
+
+	otpResp, err := s.otpService.Verify(ctx, dto.VerifyOTPRequest{
+		Email:   withdrawal.OTPEmail,
+		Code:    req.OTPCode,
+		Purpose: string(domain.OTPPurposeWithdrawal),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !otpResp.Valid {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	var quote *domain.FeeQuote
+	if withdrawal.FeeQuoteID != nil {
+		quote, err = s.feeQuoteService.Get(ctx, *withdrawal.FeeQuoteID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transaction := &domain.Transaction{
+		WalletID:       withdrawal.WalletID,
+		Type:           domain.TransactionTypeWithdrawal,
+		Amount:         withdrawal.Amount,
+		Status:         domain.TransactionStatusCompleted,
+		PharmacyID:     &pharmacy.ID,
+		PharmacyName:   pharmacy.Name,
+		PharmacyUserID: &pharmacyUserID,
+		LineItems:      lineItems,
+	}
+
+	if err := applyWithdrawalFee(ctx, s.feeEngine, pharmacy, quote, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := s.withdrawalRepo.Complete(ctx, withdrawal, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "withdrawal.completed", "transaction", transaction.ID, map[string]interface{}{
+		"pharmacy_id":   pharmacyID,
+		"wallet_id":     withdrawal.WalletID,
+		"amount":        withdrawal.Amount.String(),
+		"withdrawal_id": withdrawal.ID,
+		"initiated_by":  withdrawal.PharmacyUserID,
+		"line_items":    len(lineItems),
+	}); err != nil {
+		log.Printf("Failed to audit withdrawal %s: %v", transaction.ID, err)
+	}
+
+	return transactionToResponse(transaction), nil
+}
+
+// nappiCodePattern matches a 6 or 7 digit NAPPI product code with an
+// optional 3 digit pack suffix, e.g. 7083701-001.
+var nappiCodePattern = regexp.MustCompile(`^\d{6,7}(-?\d{3})?$`)
+
+// lineItemsFromRequest checks the dispensed items and that they add up to
+// the withdrawal amount.
+func lineItemsFromRequest(items []dto.LineItemRequest, amount decimal.Decimal) ([]domain.LineItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	lineItems := make([]domain.LineItem, len(items))
+	total := decimal.Zero
+	for i, item := range items {
+		lineItem := domain.LineItem{
+			ProductName: strings.TrimSpace(item.ProductName),
+			NAPPICode:   strings.TrimSpace(item.NAPPICode),
+			Quantity:    item.Quantity,
+			UnitPrice:   decimal.NewFromFloat(item.UnitPrice).Round(2),
+			Category:    domain.LineItemCategory(item.Category),
+		}
+
+		if lineItem.ProductName == "" || lineItem.Quantity <= 0 || lineItem.UnitPrice.IsNegative() || !lineItem.Category.IsValid() {
+			return nil, domain.ErrInvalidLineItem
+		}
+		if lineItem.NAPPICode != "" && !nappiCodePattern.MatchString(lineItem.NAPPICode) {
+			return nil, domain.ErrInvalidLineItem
+		}
+
+		lineItem.Total = lineItem.UnitPrice.Mul(decimal.NewFromInt(int64(lineItem.Quantity)))
+		total = total.Add(lineItem.Total)
+		lineItems[i] = lineItem
+	}
+
+	if !total.Equal(amount) {
+		return nil, domain.ErrLineItemsMismatch
+	}
+
+	return lineItems, nil
+}
+
+// withdrawalFeeRequest describes a withdrawal at the pharmacy for the fee
+// engine.
+func withdrawalFeeRequest(pharmacy *domain.Pharmacy, amount decimal.Decimal) fees.Request {
+	req := fees.Request{
+		Type:       domain.TransactionTypeWithdrawal,
+		Amount:     amount,
+		PharmacyID: pharmacy.ID,
+	}
+	if pharmacy.OrganisationID != nil {
+		req.OrganisationID = *pharmacy.OrganisationID
+	}
+	return req
+}
+
+func (s *pharmacyWithdrawalService) Void(ctx context.Context, pharmacyID, pharmacyUserID, transactionID, reason string) (*dto.TransactionResponse, error) {
+	original, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.Type != domain.TransactionTypeWithdrawal || original.PharmacyID == nil || *original.PharmacyID != pharmacyID {
+		return nil, domain.ErrTransactionNotFound
+	}
+
+	if original.Status == domain.TransactionStatusReversed {
+		return nil, domain.ErrTransactionReversed
+	}
+	if original.SettlementID != nil {
+		return nil, domain.ErrTransactionSettled
+	}
+
+	now := time.Now()
+	if !s.config.StartOfDay(now).Equal(s.config.StartOfDay(original.CreatedAt)) {
+		return nil, domain.ErrVoidWindowClosed
+	}
+	if window := s.config.WithdrawalVoidWindowMinutes; window > 0 && now.Sub(original.CreatedAt) > time.Duration(window)*time.Minute {
+		return nil, domain.ErrVoidWindowClosed
+	}
+
+	reversal := &domain.Transaction{
+		WalletID:       original.WalletID,
+		Type:           domain.TransactionTypeReversal,
+		Amount:         original.Amount,
+		Fee:            original.Fee,
+		NetAmount:      original.NetAmount,
+		Status:         domain.TransactionStatusCompleted,
+		PharmacyID:     original.PharmacyID,
+		PharmacyName:   original.PharmacyName,
+		PharmacyUserID: &pharmacyUserID,
+		ReversalOf:     &original.ID,
+		ReversalReason: strings.TrimSpace(reason),
+	}
+
+	if err := s.transactionRepo.Reverse(ctx, original, reversal); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "withdrawal.voided", "transaction", original.ID, map[string]interface{}{
+		"pharmacy_id":  pharmacyID,
+		"wallet_id":    original.WalletID,
+		"amount":       original.Amount.String(),
+		"reversal_id":  reversal.ID,
+		"reason":       reversal.ReversalReason,
+		"completed_by": original.PharmacyUserID,
+	}); err != nil {
+		log.Printf("Failed to audit void of withdrawal %s: %v", original.ID, err)
+	}
+
+	return transactionToResponse(reversal), nil
+}
+
+// applyWithdrawalFee sets the transaction's fee from the redeemed quote, or
+// from the fee engine when there is none.
+func applyWithdrawalFee(ctx context.Context, feeEngine fees.Engine, pharmacy *domain.Pharmacy, quote *domain.FeeQuote, transaction *domain.Transaction) error {
+	if quote != nil {
+		transaction.Fee = quote.Fee
+		transaction.NetAmount = quote.NetAmount
+		transaction.FeeRuleID = quote.FeeRuleID
+		transaction.FeeQuoteID = &quote.ID
+		return nil
+	}
+
+	priced, err := feeEngine.Evaluate(ctx, withdrawalFeeRequest(pharmacy, transaction.Amount))
+	if err != nil {
+		return err
+	}
+
+	transaction.Fee = priced.Fee
+	transaction.NetAmount = priced.Net
+	transaction.FeeRuleID = priced.RuleID()
+	return nil
+}
+
+// checkSpendingRules fails with ErrSpendingNotAllowed unless the wallet's
+// spending rules allow it to be spent at the pharmacy.
+func checkSpendingRules(ctx context.Context, spendingRuleRepo repository.WalletSpendingRuleRepository, walletID, pharmacyID string) error {
+	allowed, err := spendingRuleRepo.IsAllowed(ctx, walletID, pharmacyID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrSpendingNotAllowed
+	}
+	return nil
+}
+
+func maskEmail(email string) string {
+	if len(email) < 5 {
+		return "***"
+	}
+	atIndex := -1
+	for i, c := range email {
+		if c == '@' {
+			atIndex = i
+			break
+		}
+	}
+	if atIndex < 2 {
+		return "***" + email[atIndex:]
+	}
+	return email[:2] + "***" + email[atIndex:]
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestPharmacyWithdrawalVoid(t *testing.T) {
+	cfg := &config.Config{Timezone: "Africa/Johannesburg"}
+	now := time.Now()
+	startOfDay := cfg.StartOfDay(now)
+	// earlierToday is a minute ago, unless the business day began since.
+	earlierToday := now.Add(-time.Minute)
+	if earlierToday.Before(startOfDay) {
+		earlierToday = startOfDay
+	}
+	settlementID := "s1"
+
+	tests := []struct {
+		name       string
+		pharmacyID string
+		createdAt  time.Time
+		windowMins int
+		change     func(transaction *domain.Transaction)
+		wantErr    error
+		wantVoided bool
+	}{
+		{"earlier today", "p1", earlierToday, 0, nil, nil, true},
+		{"inside the window", "p1", earlierToday, 30, nil, nil, true},
+		{"outside the window", "p1", now.Add(-31 * time.Minute), 30, nil, domain.ErrVoidWindowClosed, false},
+		{"previous business day", "p1", startOfDay.Add(-time.Minute), 0, nil, domain.ErrVoidWindowClosed, false},
+		{"another pharmacy", "p2", earlierToday, 0, nil, domain.ErrTransactionNotFound, false},
+		{"deposit", "p1", earlierToday, 0, func(transaction *domain.Transaction) { transaction.Type = domain.TransactionTypeCashDeposit }, domain.ErrTransactionNotFound, false},
+		{"already settled", "p1", earlierToday, 0, func(transaction *domain.Transaction) { transaction.SettlementID = &settlementID }, domain.ErrTransactionSettled, false},
+		{"already reversed", "p1", earlierToday, 0, func(transaction *domain.Transaction) { transaction.Status = domain.TransactionStatusReversed }, domain.ErrTransactionReversed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pharmacyID := "p1"
+			completedBy := "pu1"
+			transaction := &domain.Transaction{
+				ID:             "t1",
+				WalletID:       "w1",
+				Type:           domain.TransactionTypeWithdrawal,
+				Amount:         decimal.NewFromInt(100),
+				Fee:            decimal.NewFromInt(2),
+				NetAmount:      decimal.NewFromInt(98),
+				Status:         domain.TransactionStatusCompleted,
+				PharmacyID:     &pharmacyID,
+				PharmacyUserID: &completedBy,
+				CreatedAt:      tt.createdAt,
+			}
+			if tt.change != nil {
+				tt.change(transaction)
+			}
+			transactionRepo := &fakeTransactionRepo{transactions: map[string]*domain.Transaction{"t1": transaction}}
+
+			testCfg := *cfg
+			testCfg.WithdrawalVoidWindowMinutes = tt.windowMins
+			withdrawalService := NewPharmacyWithdrawalService(nil, transactionRepo, nil, nil, nil, nil, nil, nil, nil, fakeAuditService{}, &testCfg)
+
+			reversal, err := withdrawalService.Void(context.Background(), tt.pharmacyID, "manager", "t1", " Wrong wallet ")
+			if err != tt.wantErr {
+				t.Fatalf("Void() error = %v, want %v", err, tt.wantErr)
+			}
+			if !tt.wantVoided {
+				if len(transactionRepo.reversals) != 0 {
+					t.Fatalf("Void() recorded %d reversals, want none", len(transactionRepo.reversals))
+				}
+				return
+			}
+
+			if len(transactionRepo.reversals) != 1 {
+				t.Fatalf("Void() recorded %d reversals, want 1", len(transactionRepo.reversals))
+			}
+			recorded := transactionRepo.reversals[0]
+			if recorded.Type != domain.TransactionTypeReversal || !recorded.Amount.Equal(transaction.Amount) ||
+				!recorded.Fee.Equal(transaction.Fee) || *recorded.ReversalOf != "t1" ||
+				*recorded.PharmacyUserID != "manager" || recorded.ReversalReason != "Wrong wallet" {
+				t.Fatalf("Void() recorded %+v, want a reversal of t1 by manager for %q", recorded, "Wrong wallet")
+			}
+			if reversal == nil || reversal.ID != recorded.ID {
+				t.Fatalf("Void() returned %+v, want reversal %s", reversal, recorded.ID)
+			}
+
+			if _, err := withdrawalService.Void(context.Background(), tt.pharmacyID, "manager", "t1", ""); err != domain.ErrTransactionReversed {
+				t.Fatalf("second Void() error = %v, want %v", err, domain.ErrTransactionReversed)
+			}
+		})
+	}
+}
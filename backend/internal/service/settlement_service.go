@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+type SettlementService interface {
+	GenerateBatches(ctx context.Context, cutoff time.Time) ([]*domain.Settlement, error)
+	List(ctx context.Context, filter repository.SettlementFilter) ([]*domain.Settlement, int, error)
+	// GetStatement returns a settlement with its line items. A non-empty
+	// pharmacyID restricts access to that pharmacy's own settlements.
+	GetStatement(ctx context.Context, settlementID, pharmacyID string) (*SettlementStatement, error)
+	MarkPaid(ctx context.Context, adminID, settlementID, payoutReference string) (*domain.Settlement, error)
+}
+
+type SettlementStatement struct {
+	Settlement        *domain.Settlement `json:"settlement"`
+	PharmacyName      string             `json:"pharmacy_name"`
+	PharmacyShortCode string             `json:"pharmacy_short_code"`
+	// PayoutAccount is the masked bank account in effect for the pharmacy.
+	PayoutAccount string                    `json:"payout_account,omitempty"`
+	Lines         []SettlementStatementLine `json:"lines"`
+}
+
+type SettlementStatementLine struct {
+	TransactionID   string  `json:"transaction_id"`
+	Type            string  `json:"type"`
+	CreatedAt       string  `json:"created_at"`
+	ContributorName string  `json:"contributor_name,omitempty"`
+	Amount          float64 `json:"amount"`
+	Fee             float64 `json:"fee"`
+	NetAmount       float64 `json:"net_amount"`
+	// Payable is the line's signed effect on the settlement's net payable.
+	Payable float64 `json:"payable"`
+}
+
+type settlementService struct {
+	settlementRepo  repository.SettlementRepository
+	transactionRepo repository.TransactionRepository
+	pharmacyRepo    repository.PharmacyRepository
+	bankAccountRepo repository.BankAccountRepository
+	auditService    AuditService
+	config          *config.Config
+}
+
+func NewSettlementService(
+	settlementRepo repository.SettlementRepository,
+	transactionRepo repository.TransactionRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	bankAccountRepo repository.BankAccountRepository,
+	auditService AuditService,
+	cfg *config.Config,
+) SettlementService {
+	return &settlementService{
+		settlementRepo:  settlementRepo,
+		transactionRepo: transactionRepo,
+		pharmacyRepo:    pharmacyRepo,
+		bankAccountRepo: bankAccountRepo,
+		auditService:    auditService,
+		config:          cfg,
+	}
+}
+
+func (s *settlementService) GenerateBatches(ctx context.Context, cutoff time.Time) ([]*domain.Settlement, error) {
+	pharmacyIDs, err := s.settlementRepo.GetPharmacyIDsWithUnsettled(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	var settlements []*domain.Settlement
+	for _, pharmacyID := range pharmacyIDs {
+		settlement, err := s.settlementRepo.CreateBatch(ctx, pharmacyID, cutoff)
+		if err != nil {
+			// One pharmacy failing should not hold up everyone else's settlement.
+			log.Printf("Failed to create settlement for pharmacy %s: %v", pharmacyID, err)
+			continue
+		}
+		if settlement == nil {
+			continue
+		}
+
+		if err := s.auditService.Record(ctx, domain.AuditActorSystem, "", "settlement.created", "settlement", settlement.ID, map[string]interface{}{
+			"pharmacy_id": pharmacyID,
+			"period_end":  cutoff.Format(time.RFC3339),
+			"net_payable": settlement.NetPayable.String(),
+		}); err != nil {
+			log.Printf("Failed to audit settlement %s: %v", settlement.ID, err)
+		}
+
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, nil
+}
+
+func (s *settlementService) List(ctx context.Context, filter repository.SettlementFilter) ([]*domain.Settlement, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	return s.settlementRepo.List(ctx, filter)
+}
+
+func (s *settlementService) GetStatement(ctx context.Context, settlementID, pharmacyID string) (*SettlementStatement, error) {
+	settlement, err := s.settlementRepo.GetByID(ctx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacyID != "" && settlement.PharmacyID != pharmacyID {
+		return nil, domain.ErrSettlementNotFound
+	}
+
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, settlement.PharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactionRepo.GetBySettlementID(ctx, settlement.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]SettlementStatementLine, len(transactions))
+	for i, tx := range transactions {
+		payable := tx.NetAmount
+		switch tx.Type {
+		case domain.TransactionTypeCashDeposit:
+			payable = tx.Amount.Neg()
+		case domain.TransactionTypeReversal:
+			payable = tx.NetAmount.Neg()
+		}
+
+		lines[i] = SettlementStatementLine{
+			TransactionID:   tx.ID,
+			Type:            string(tx.Type),
+			CreatedAt:       tx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ContributorName: tx.ContributorName,
+			Amount:          tx.Amount.InexactFloat64(),
+			Fee:             tx.Fee.InexactFloat64(),
+			NetAmount:       tx.NetAmount.InexactFloat64(),
+			Payable:         payable.InexactFloat64(),
+		}
+	}
+
+	statement := &SettlementStatement{
+		Settlement:        settlement,
+		PharmacyName:      pharmacy.Name,
+		PharmacyShortCode: pharmacy.ShortCode,
+		Lines:             lines,
+	}
+
+	account, err := s.bankAccountRepo.GetEffective(ctx, settlement.PharmacyID)
+	if err != nil && !errors.Is(err, domain.ErrNoActiveBankAccount) {
+		return nil, err
+	}
+	if account != nil {
+		statement.PayoutAccount = account.BankName + " " + account.MaskedAccountNumber()
+	}
+
+	return statement, nil
+}
+
+func (s *settlementService) MarkPaid(ctx context.Context, adminID, settlementID, payoutReference string) (*domain.Settlement, error) {
+	settlement, err := s.settlementRepo.GetByID(ctx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settlement.IsPaid() {
+		return nil, domain.ErrSettlementAlreadyPaid
+	}
+
+	if !settlement.CanBeMarkedPaid() {
+		return nil, domain.ErrPayoutInProgress
+	}
+
+	now := time.Now()
+	settlement.PayoutReference = strings.TrimSpace(payoutReference)
+	settlement.PaidBy = &adminID
+	settlement.PaidAt = &now
+
+	if err := s.settlementRepo.MarkPaid(ctx, settlement); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "settlement.paid", "settlement", settlement.ID, map[string]interface{}{
+		"pharmacy_id":      settlement.PharmacyID,
+		"net_payable":      settlement.NetPayable.String(),
+		"payout_reference": settlement.PayoutReference,
+	}); err != nil {
+		log.Printf("Failed to audit payment of settlement %s: %v", settlement.ID, err)
+	}
+
+	return settlement, nil
+}
+
+// RunSettlementScheduler settles everything up to the start of the current
+// business day once per interval until ctx is cancelled.
+func RunSettlementScheduler(ctx context.Context, settlementService SettlementService, cfg *config.Config, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := cfg.StartOfDay(time.Now())
+		settlements, err := settlementService.GenerateBatches(ctx, cutoff)
+		if err != nil {
+			log.Printf("Settlement run failed: %v", err)
+		} else if len(settlements) > 0 {
+			total := decimal.Zero
+			for _, s := range settlements {
+				total = total.Add(s.NetPayable)
+			}
+			log.Printf("Created %d settlement batches (net payable %s)", len(settlements), total.StringFixed(2))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,86 +2,60 @@ package service
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/carewallet/backend/internal/config"
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/fees"
 	"github.com/carewallet/backend/internal/repository"
 	"github.com/shopspring/decimal"
 )
 
 type TransactionService interface {
-	Deposit(ctx context.Context, walletID string, req dto.DepositRequest) (*dto.TransactionResponse, error)
 	Withdraw(ctx context.Context, userID string, req dto.WithdrawalRequest) (*dto.TransactionResponse, error)
 	GetWalletTransactions(ctx context.Context, userID, walletID string, page, pageSize int) (*dto.TransactionListResponse, error)
+	CashIn(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.CashInRequest) (*dto.TransactionResponse, error)
+	GetCashInSummary(ctx context.Context, pharmacyID string) (*dto.CashInSummaryResponse, error)
 }
 
 type transactionService struct {
-	transactionRepo repository.TransactionRepository
-	walletRepo      repository.WalletRepository
-	pharmacyRepo    repository.PharmacyRepository
-	otpService      OTPService
-	config          *config.Config
+	transactionRepo  repository.TransactionRepository
+	walletRepo       repository.WalletRepository
+	spendingRuleRepo repository.WalletSpendingRuleRepository
+	pharmacyRepo     repository.PharmacyRepository
+	feeEngine        fees.Engine
+	feeQuoteService  FeeQuoteService
+	otpService       OTPService
+	auditService     AuditService
+	config           *config.Config
 }
 
 func NewTransactionService(
 	transactionRepo repository.TransactionRepository,
 	walletRepo repository.WalletRepository,
+	spendingRuleRepo repository.WalletSpendingRuleRepository,
 	pharmacyRepo repository.PharmacyRepository,
+	feeEngine fees.Engine,
+	feeQuoteService FeeQuoteService,
 	otpService OTPService,
+	auditService AuditService,
 	cfg *config.Config,
 ) TransactionService {
 	return &transactionService{
-		transactionRepo: transactionRepo,
-		walletRepo:      walletRepo,
-		pharmacyRepo:    pharmacyRepo,
-		otpService:      otpService,
-		config:          cfg,
+		transactionRepo:  transactionRepo,
+		walletRepo:       walletRepo,
+		spendingRuleRepo: spendingRuleRepo,
+		pharmacyRepo:     pharmacyRepo,
+		feeEngine:        feeEngine,
+		feeQuoteService:  feeQuoteService,
+		otpService:       otpService,
+		auditService:     auditService,
+		config:           cfg,
 	}
 }
 
-func (s *transactionService) Deposit(ctx context.Context, walletID string, req dto.DepositRequest) (*dto.TransactionResponse, error) {
-	// Verify wallet exists and is active
-	wallet, err := s.walletRepo.GetByID(ctx, walletID)
-	if err != nil {
-		return nil, err
-	}
-
-	if wallet.Status != domain.WalletStatusActive {
-		return nil, domain.ErrWalletNotFound
-	}
-
-	amount := decimal.NewFromFloat(req.Amount)
-	if amount.LessThanOrEqual(decimal.Zero) {
-		return nil, domain.ErrInvalidAmount
-	}
-
-	// Create transaction (no fee on deposits)
-	transaction := &domain.Transaction{
-		WalletID:           walletID,
-		Type:               domain.TransactionTypeDeposit,
-		Amount:             amount,
-		Fee:                decimal.Zero,
-		NetAmount:          amount,
-		Status:             domain.TransactionStatusCompleted,
-		ContributorEmail:   req.ContributorEmail,
-		ContributorName:    req.ContributorName,
-		ContributorMessage: req.ContributorMessage,
-		PaystackReference:  req.PaystackReference,
-	}
-
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return nil, err
-	}
-
-	// Update wallet balance
-	if err := s.walletRepo.UpdateBalance(ctx, walletID, amount.String()); err != nil {
-		return nil, err
-	}
-
-	return transactionToResponse(transaction), nil
-}
-
 func (s *transactionService) Withdraw(ctx context.Context, userID string, req dto.WithdrawalRequest) (*dto.TransactionResponse, error) {
 	// Verify wallet exists and user has access
 	wallet, err := s.walletRepo.GetByID(ctx, req.WalletID)
@@ -93,17 +67,16 @@ func (s *transactionService) Withdraw(ctx context.Context, userID string, req dt
 		return nil, domain.ErrWalletAccessDenied
 	}
 
+	if !wallet.Status.CanBeSpent() {
+		return nil, domain.ErrWalletNotSpendable
+	}
+
 	// Get user for OTP verification
-	amount := decimal.NewFromFloat(req.Amount)
+	amount := decimal.NewFromFloat(req.Amount).Round(2)
 	if amount.LessThanOrEqual(decimal.Zero) {
 		return nil, domain.ErrInvalidAmount
 	}
 
-	// Calculate fee
-	feePercentage := decimal.NewFromFloat(s.config.PlatformFeePercentage)
-	fee := amount.Mul(feePercentage).Round(2)
-	netAmount := amount.Sub(fee)
-
 	// Check balance
 	if wallet.Balance.LessThan(amount) {
 		return nil, domain.ErrInsufficientBalance
@@ -119,26 +92,35 @@ func (s *transactionService) Withdraw(ctx context.Context, userID string, req dt
 		return nil, domain.ErrPharmacyInactive
 	}
 
+	if err := checkSpendingRules(ctx, s.spendingRuleRepo, wallet.ID, pharmacy.ID); err != nil {
+		return nil, err
+	}
+
+	var quote *domain.FeeQuote
+	if req.QuoteID != "" {
+		quote, err = s.feeQuoteService.Check(ctx, req.QuoteID, wallet.ID, pharmacy.ID, amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create transaction
 	pharmacyID := pharmacy.ID
 	transaction := &domain.Transaction{
 		WalletID:     req.WalletID,
 		Type:         domain.TransactionTypeWithdrawal,
 		Amount:       amount,
-		Fee:          fee,
-		NetAmount:    netAmount,
 		Status:       domain.TransactionStatusCompleted,
 		PharmacyID:   &pharmacyID,
 		PharmacyName: pharmacy.Name,
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+	// Calculate fee
+	if err := applyWithdrawalFee(ctx, s.feeEngine, pharmacy, quote, transaction); err != nil {
 		return nil, err
 	}
 
-	// Update wallet balance (subtract amount)
-	negativeAmount := amount.Neg()
-	if err := s.walletRepo.UpdateBalance(ctx, req.WalletID, negativeAmount.String()); err != nil {
+	if err := s.transactionRepo.CreateWithdrawal(ctx, transaction); err != nil {
 		return nil, err
 	}
 
@@ -181,21 +163,138 @@ func (s *transactionService) GetWalletTransactions(ctx context.Context, userID,
 	}, nil
 }
 
+func (s *transactionService) CashIn(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.CashInRequest) (*dto.TransactionResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pharmacy.Status != domain.PharmacyStatusActive {
+		return nil, domain.ErrPharmacyInactive
+	}
+
+	wallet, err := findWalletByCode(ctx, s.walletRepo, req.WalletCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.Status.AcceptsContributions() {
+		return nil, domain.ErrWalletNotAcceptingContributions
+	}
+
+	amount := decimal.NewFromFloat(req.Amount).Round(2)
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	// Cash deposits carry no fee; the pharmacy owes the full amount to the platform.
+	transaction := &domain.Transaction{
+		WalletID:               wallet.ID,
+		Type:                   domain.TransactionTypeCashDeposit,
+		Amount:                 amount,
+		Fee:                    decimal.Zero,
+		NetAmount:              amount,
+		Status:                 domain.TransactionStatusCompleted,
+		ContributorName:        req.ContributorName,
+		ContributorMessage:     req.ContributorMessage,
+		ContributorAnonymous:   req.Anonymous,
+		ContributorHidesAmount: req.HideAmount,
+		PharmacyID:             &pharmacy.ID,
+		PharmacyName:           pharmacy.Name,
+		PharmacyUserID:         &pharmacyUserID,
+	}
+
+	dayStart := s.config.StartOfDay(time.Now())
+	if err := s.transactionRepo.CreateCashIn(ctx, transaction, dayStart, s.dailyCashInLimit(pharmacy)); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "cash_in.created", "transaction", transaction.ID, map[string]interface{}{
+		"pharmacy_id":      pharmacy.ID,
+		"wallet_id":        wallet.ID,
+		"amount":           amount.String(),
+		"contributor_name": req.ContributorName,
+	}); err != nil {
+		log.Printf("Failed to audit cash-in %s: %v", transaction.ID, err)
+	}
+
+	return transactionToResponse(transaction), nil
+}
+
+func (s *transactionService) GetCashInSummary(ctx context.Context, pharmacyID string) (*dto.CashInSummaryResponse, error) {
+	pharmacy, err := s.pharmacyRepo.GetByID(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	todayTotal, todayCount, err := s.transactionRepo.SumCashIns(ctx, pharmacyID, s.config.StartOfDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	owed, err := s.transactionRepo.SumUnsettledCashIns(ctx, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := s.dailyCashInLimit(pharmacy)
+	remaining := limit.Sub(todayTotal)
+	if remaining.IsNegative() {
+		remaining = decimal.Zero
+	}
+
+	return &dto.CashInSummaryResponse{
+		DailyLimit:     limit.InexactFloat64(),
+		TodayTotal:     todayTotal.InexactFloat64(),
+		TodayCount:     todayCount,
+		RemainingToday: remaining.InexactFloat64(),
+		AmountOwed:     owed.InexactFloat64(),
+	}, nil
+}
+
+func (s *transactionService) dailyCashInLimit(pharmacy *domain.Pharmacy) decimal.Decimal {
+	if pharmacy.DailyCashInLimit != nil {
+		return *pharmacy.DailyCashInLimit
+	}
+	return decimal.NewFromFloat(s.config.CashInDailyLimit)
+}
+
 func transactionToResponse(tx *domain.Transaction) *dto.TransactionResponse {
+	var lineItems []dto.LineItemResponse
+	for _, item := range tx.LineItems {
+		lineItems = append(lineItems, dto.LineItemResponse{
+			ProductName: item.ProductName,
+			NAPPICode:   item.NAPPICode,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice.InexactFloat64(),
+			Total:       item.Total.InexactFloat64(),
+			Category:    string(item.Category),
+		})
+	}
+
 	return &dto.TransactionResponse{
-		ID:                 tx.ID,
-		WalletID:           tx.WalletID,
-		Type:               string(tx.Type),
-		Amount:             tx.Amount.InexactFloat64(),
-		Fee:                tx.Fee.InexactFloat64(),
-		NetAmount:          tx.NetAmount.InexactFloat64(),
-		Status:             string(tx.Status),
-		ContributorEmail:   tx.ContributorEmail,
-		ContributorName:    tx.ContributorName,
-		ContributorMessage: tx.ContributorMessage,
-		PharmacyID:         tx.PharmacyID,
-		PharmacyName:       tx.PharmacyName,
-		PaystackReference:  tx.PaystackReference,
-		CreatedAt:          tx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:                     tx.ID,
+		WalletID:               tx.WalletID,
+		Type:                   string(tx.Type),
+		Amount:                 tx.Amount.InexactFloat64(),
+		Fee:                    tx.Fee.InexactFloat64(),
+		FeeRuleID:              tx.FeeRuleID,
+		FeeQuoteID:             tx.FeeQuoteID,
+		NetAmount:              tx.NetAmount.InexactFloat64(),
+		Status:                 string(tx.Status),
+		ContributorEmail:       tx.ContributorEmail,
+		ContributorName:        tx.ContributorName,
+		ContributorMessage:     tx.ContributorMessage,
+		ContributorAnonymous:   tx.ContributorAnonymous,
+		ContributorHidesAmount: tx.ContributorHidesAmount,
+		PharmacyID:             tx.PharmacyID,
+		PharmacyName:           tx.PharmacyName,
+		PharmacyUserID:         tx.PharmacyUserID,
+		PaystackReference:      tx.PaystackReference,
+		SettlementID:           tx.SettlementID,
+		ReversalOf:             tx.ReversalOf,
+		ReversalReason:         tx.ReversalReason,
+		LineItems:              lineItems,
+		CreatedAt:              tx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/storage"
+	"github.com/google/uuid"
+)
+
+// UploadService stores files in blob storage and hands out signed,
+// time-limited download links. Only the owner of a file (or an admin) can
+// get a link to it.
+type UploadService interface {
+	// Upload stores a file for the owner. The content type is sniffed from
+	// the file itself; what the client claims is ignored.
+	Upload(ctx context.Context, ownerType domain.UploadOwnerType, ownerID, purpose, fileName string, r io.Reader) (*dto.UploadResponse, error)
+	Get(ctx context.Context, ownerType domain.UploadOwnerType, ownerID, id string) (*dto.UploadResponse, error)
+	GetForAdmin(ctx context.Context, id string) (*dto.UploadResponse, error)
+	// GetOwned returns an upload for use elsewhere, e.g. as a wallet photo,
+	// failing with ErrUploadNotFound unless the owner and purpose match.
+	GetOwned(ctx context.Context, ownerType domain.UploadOwnerType, ownerID, id string, purpose domain.UploadPurpose) (*domain.Upload, error)
+	Claim(ctx context.Context, id string, ownerType domain.UploadOwnerType, ownerID string) error
+	// Open returns the file for a signed download link.
+	Open(ctx context.Context, id, expires, signature string) (*domain.Upload, io.ReadCloser, error)
+	SignedURL(id string) string
+	// RemoveUnclaimed deletes applicant uploads that no registration claimed
+	// within UnclaimedUploadTTLHours, returning how many were removed.
+	RemoveUnclaimed(ctx context.Context) (int, error)
+}
+
+type uploadService struct {
+	uploadRepo repository.UploadRepository
+	store      storage.BlobStore
+	signer     *storage.URLSigner
+	config     *config.Config
+}
+
+func NewUploadService(
+	uploadRepo repository.UploadRepository,
+	store storage.BlobStore,
+	signer *storage.URLSigner,
+	cfg *config.Config,
+) UploadService {
+	return &uploadService{
+		uploadRepo: uploadRepo,
+		store:      store,
+		signer:     signer,
+		config:     cfg,
+	}
+}
+
+func (s *uploadService) Upload(ctx context.Context, ownerType domain.UploadOwnerType, ownerID, purpose, fileName string, r io.Reader) (*dto.UploadResponse, error) {
+	uploadPurpose := domain.UploadPurpose(purpose)
+	if !ownerType.CanUpload(uploadPurpose) {
+		return nil, domain.ErrInvalidUploadPurpose
+	}
+
+	// Read one byte past the limit so oversized files are detected without
+	// trusting the declared size.
+	data, err := io.ReadAll(io.LimitReader(r, s.config.UploadMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.config.UploadMaxBytes {
+		return nil, domain.ErrUploadTooLarge
+	}
+	if len(data) == 0 {
+		return nil, domain.ErrUnsupportedFileType
+	}
+
+	contentType, err := sniffContentType(data, uploadPurpose)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &domain.Upload{
+		OwnerType:   ownerType,
+		Purpose:     uploadPurpose,
+		StorageKey:  fmt.Sprintf("%s/%s/%s", uploadPurpose, time.Now().UTC().Format("2006/01"), uuid.NewString()),
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+	}
+	if ownerID != "" {
+		upload.OwnerID = &ownerID
+	}
+
+	if err := s.store.Put(ctx, upload.StorageKey, upload.ContentType, data); err != nil {
+		return nil, err
+	}
+
+	if err := s.uploadRepo.Create(ctx, upload); err != nil {
+		if deleteErr := s.store.Delete(ctx, upload.StorageKey); deleteErr != nil {
+			log.Printf("Failed to remove orphaned blob %s: %v", upload.StorageKey, deleteErr)
+		}
+		return nil, err
+	}
+
+	return s.toResponse(upload), nil
+}
+
+func (s *uploadService) Get(ctx context.Context, ownerType domain.UploadOwnerType, ownerID, id string) (*dto.UploadResponse, error) {
+	upload, err := s.uploadRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !upload.IsOwnedBy(ownerType, ownerID) {
+		return nil, domain.ErrUploadNotFound
+	}
+
+	return s.toResponse(upload), nil
+}
+
+func (s *uploadService) GetForAdmin(ctx context.Context, id string) (*dto.UploadResponse, error) {
+	upload, err := s.uploadRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(upload), nil
+}
+
+func (s *uploadService) GetOwned(ctx context.Context, ownerType domain.UploadOwnerType, ownerID, id string, purpose domain.UploadPurpose) (*domain.Upload, error) {
+	upload, err := s.uploadRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !upload.IsOwnedBy(ownerType, ownerID) || upload.Purpose != purpose {
+		return nil, domain.ErrUploadNotFound
+	}
+
+	return upload, nil
+}
+
+func (s *uploadService) Claim(ctx context.Context, id string, ownerType domain.UploadOwnerType, ownerID string) error {
+	return s.uploadRepo.SetOwner(ctx, id, ownerType, ownerID)
+}
+
+func (s *uploadService) Open(ctx context.Context, id, expires, signature string) (*domain.Upload, io.ReadCloser, error) {
+	if err := s.signer.Verify(id, expires, signature); err != nil {
+		return nil, nil, err
+	}
+
+	upload, err := s.uploadRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := s.store.Get(ctx, upload.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, domain.ErrUploadNotFound
+		}
+		return nil, nil, err
+	}
+
+	return upload, body, nil
+}
+
+func (s *uploadService) SignedURL(id string) string {
+	url, _ := s.signer.SignedURL(id)
+	return url
+}
+
+func (s *uploadService) RemoveUnclaimed(ctx context.Context) (int, error) {
+	before := time.Now().Add(-time.Duration(s.config.UnclaimedUploadTTLHours) * time.Hour)
+	uploads, err := s.uploadRepo.GetUnclaimed(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, upload := range uploads {
+		if err := s.store.Delete(ctx, upload.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Failed to remove blob %s of unclaimed upload %s: %v", upload.StorageKey, upload.ID, err)
+			continue
+		}
+		if err := s.uploadRepo.Delete(ctx, upload.ID); err != nil {
+			log.Printf("Failed to remove unclaimed upload %s: %v", upload.ID, err)
+			continue
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func (s *uploadService) toResponse(upload *domain.Upload) *dto.UploadResponse {
+	url, expiresAt := s.signer.SignedURL(upload.ID)
+
+	return &dto.UploadResponse{
+		ID:           upload.ID,
+		Purpose:      string(upload.Purpose),
+		FileName:     upload.FileName,
+		ContentType:  upload.ContentType,
+		SizeBytes:    upload.SizeBytes,
+		URL:          url,
+		URLExpiresAt: expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:    upload.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// sniffContentType detects the file type from its first bytes and checks it
+// is allowed for the purpose.
+func sniffContentType(data []byte, purpose domain.UploadPurpose) (string, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "", domain.ErrUnsupportedFileType
+	}
+
+	for _, allowed := range purpose.AllowedContentTypes() {
+		if contentType == allowed {
+			return contentType, nil
+		}
+	}
+
+	return "", domain.ErrUnsupportedFileType
+}
+
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+// RunUploadCleanup removes unclaimed applicant uploads every interval until
+// ctx is cancelled.
+func RunUploadCleanup(ctx context.Context, uploadService UploadService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		removed, err := uploadService.RemoveUnclaimed(ctx)
+		if err != nil {
+			log.Printf("Upload cleanup failed: %v", err)
+		} else if removed > 0 {
+			log.Printf("Removed %d unclaimed uploads", removed)
+		}
+	}
+}
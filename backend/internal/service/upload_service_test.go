@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/carewallet/backend/internal/storage"
+)
+
+type fakeUploadRepo struct {
+	repository.UploadRepository
+	uploads []*domain.Upload
+}
+
+func (r *fakeUploadRepo) Create(ctx context.Context, upload *domain.Upload) error {
+	upload.ID = "u1"
+	upload.CreatedAt = time.Now()
+	r.uploads = append(r.uploads, upload)
+	return nil
+}
+
+var (
+	testJPEG = append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, make([]byte, 60)...)
+	testPNG  = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 60)...)
+	testPDF  = []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<<>>\nendobj\n")
+	testHTML = []byte("<!DOCTYPE html><html><body>not an image</body></html>")
+	testExe  = append([]byte("MZ\x90\x00"), make([]byte, 60)...)
+)
+
+func TestSniffContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		purpose domain.UploadPurpose
+		want    string
+	}{
+		{"jpeg wallet photo", testJPEG, domain.UploadPurposeWalletPhoto, "image/jpeg"},
+		{"png wallet photo", testPNG, domain.UploadPurposeWalletPhoto, "image/png"},
+		{"pdf wallet photo", testPDF, domain.UploadPurposeWalletPhoto, ""},
+		{"pdf prescription", testPDF, domain.UploadPurposePrescription, "application/pdf"},
+		{"jpeg dispute evidence", testJPEG, domain.UploadPurposeDisputeEvidence, "image/jpeg"},
+		{"html pharmacy document", testHTML, domain.UploadPurposePharmacyDocument, ""},
+		{"executable id document", testExe, domain.UploadPurposeIDDocument, ""},
+		{"unknown purpose", testJPEG, "avatar", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sniffContentType(tt.data, tt.purpose)
+			if tt.want == "" {
+				if err != domain.ErrUnsupportedFileType {
+					t.Fatalf("sniffContentType() = %q, %v, want %v", got, err, domain.ErrUnsupportedFileType)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("sniffContentType() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpload(t *testing.T) {
+	const maxBytes = 64
+
+	tests := []struct {
+		name     string
+		owner    domain.UploadOwnerType
+		purpose  string
+		fileName string
+		data     []byte
+		wantErr  error
+		wantType string
+		wantName string
+	}{
+		{"photo at the size limit", domain.UploadOwnerUser, "wallet_photo", "me.jpg", testJPEG[:maxBytes], nil, "image/jpeg", "me.jpg"},
+		{"photo one byte over the limit", domain.UploadOwnerUser, "wallet_photo", "me.jpg", append(testJPEG[:maxBytes:maxBytes], 0), domain.ErrUploadTooLarge, "", ""},
+		{"empty file", domain.UploadOwnerUser, "wallet_photo", "me.jpg", nil, domain.ErrUnsupportedFileType, "", ""},
+		{"claimed type ignored", domain.UploadOwnerUser, "wallet_photo", "photo.jpg", testHTML, domain.ErrUnsupportedFileType, "", ""},
+		{"purpose the owner may not use", domain.UploadOwnerUser, "pharmacy_document", "licence.pdf", testPDF, domain.ErrInvalidUploadPurpose, "", ""},
+		{"applicant document", domain.UploadOwnerApplicant, "pharmacy_document", `C:\scans\licence.pdf`, testPDF, nil, "application/pdf", "licence.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := storage.NewLocalStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("NewLocalStore() error = %v", err)
+			}
+			repo := &fakeUploadRepo{}
+			signer := storage.NewURLSigner("secret", "http://localhost/files", time.Hour)
+			uploadService := NewUploadService(repo, store, signer, &config.Config{UploadMaxBytes: maxBytes})
+
+			got, err := uploadService.Upload(context.Background(), tt.owner, "", tt.purpose, tt.fileName, bytes.NewReader(tt.data))
+			if err != tt.wantErr {
+				t.Fatalf("Upload() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.uploads) != 0 {
+					t.Fatalf("Upload() recorded %d uploads, want none", len(repo.uploads))
+				}
+				return
+			}
+			if got.ContentType != tt.wantType || got.FileName != tt.wantName || got.SizeBytes != int64(len(tt.data)) {
+				t.Fatalf("Upload() = %s %q of %d bytes, want %s %q of %d bytes",
+					got.ContentType, got.FileName, got.SizeBytes, tt.wantType, tt.wantName, len(tt.data))
+			}
+		})
+	}
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// WalletCampaignService runs fundraising campaigns for wallets. Only the
+// wallet's creator can start, change or cancel a campaign; its members can
+// follow it. CheckCampaigns records milestones and goals as they are reached
+// and ends campaigns whose deadline has passed, emailing the wallet's creator
+// each time.
+type WalletCampaignService interface {
+	Create(ctx context.Context, userID, walletID string, req dto.CreateCampaignRequest) (*dto.CampaignResponse, error)
+	List(ctx context.Context, userID, walletID string) ([]dto.CampaignResponse, error)
+	Get(ctx context.Context, userID, walletID, campaignID string) (*dto.CampaignResponse, error)
+	Update(ctx context.Context, userID, walletID, campaignID string, req dto.UpdateCampaignRequest) (*dto.CampaignResponse, error)
+	Cancel(ctx context.Context, userID, walletID, campaignID string) (*dto.CampaignResponse, error)
+	GetEvents(ctx context.Context, userID, walletID, campaignID string) ([]*domain.CampaignEvent, error)
+	// GetPublic returns the campaign shown on the wallet's public page.
+	GetPublic(ctx context.Context, walletID string) (*dto.CampaignResponse, error)
+	// CheckCampaigns records the events due on every running campaign and
+	// returns how many it recorded.
+	CheckCampaigns(ctx context.Context) (int, error)
+}
+
+type walletCampaignService struct {
+	campaignRepo repository.WalletCampaignRepository
+	walletRepo   repository.WalletRepository
+	userRepo     repository.UserRepository
+	emailService EmailService
+	auditService AuditService
+	config       *config.Config
+}
+
+func NewWalletCampaignService(
+	campaignRepo repository.WalletCampaignRepository,
+	walletRepo repository.WalletRepository,
+	userRepo repository.UserRepository,
+	emailService EmailService,
+	auditService AuditService,
+	cfg *config.Config,
+) WalletCampaignService {
+	return &walletCampaignService{
+		campaignRepo: campaignRepo,
+		walletRepo:   walletRepo,
+		userRepo:     userRepo,
+		emailService: emailService,
+		auditService: auditService,
+		config:       cfg,
+	}
+}
+
+func (s *walletCampaignService) Create(ctx context.Context, userID, walletID string, req dto.CreateCampaignRequest) (*dto.CampaignResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.CreatorID != userID {
+		return nil, domain.ErrWalletAccessDenied
+	}
+	if !wallet.Status.AcceptsContributions() {
+		return nil, domain.ErrWalletNotAcceptingContributions
+	}
+
+	now := time.Now()
+	campaign := &domain.WalletCampaign{
+		WalletID:    wallet.ID,
+		CreatedBy:   userID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		Goal:        decimal.NewFromFloat(req.Goal).Round(2),
+		StartsAt:    now,
+		EndsAt:      req.EndsAt,
+		Status:      domain.CampaignStatusActive,
+	}
+	if req.StartsAt != nil {
+		campaign.StartsAt = *req.StartsAt
+	}
+	if campaign.Title == "" || !campaign.Goal.IsPositive() || !campaign.EndsAt.After(now) || !campaign.EndsAt.After(campaign.StartsAt) {
+		return nil, domain.ErrInvalidCampaign
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range req.Milestones {
+		milestone := &domain.CampaignMilestone{
+			Label:  strings.TrimSpace(m.Label),
+			Amount: decimal.NewFromFloat(m.Amount).Round(2),
+		}
+		if milestone.Label == "" || !milestone.Amount.IsPositive() || milestone.Amount.GreaterThan(campaign.Goal) || seen[milestone.Amount.String()] {
+			return nil, domain.ErrInvalidMilestone
+		}
+		seen[milestone.Amount.String()] = true
+		campaign.Milestones = append(campaign.Milestones, milestone)
+	}
+
+	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "campaign.created", "wallet_campaign", campaign.ID, map[string]interface{}{
+		"wallet_id":  wallet.ID,
+		"goal":       campaign.Goal.String(),
+		"starts_at":  campaign.StartsAt.Format(time.RFC3339),
+		"ends_at":    campaign.EndsAt.Format(time.RFC3339),
+		"milestones": len(campaign.Milestones),
+	}); err != nil {
+		log.Printf("Failed to audit creation of campaign %s: %v", campaign.ID, err)
+	}
+
+	return s.toResponse(ctx, campaign)
+}
+
+func (s *walletCampaignService) List(ctx context.Context, userID, walletID string) ([]dto.CampaignResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	campaigns, err := s.campaignRepo.GetByWalletID(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.CampaignResponse, len(campaigns))
+	for i, campaign := range campaigns {
+		response, err := s.toResponse(ctx, campaign)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+
+	return responses, nil
+}
+
+func (s *walletCampaignService) Get(ctx context.Context, userID, walletID, campaignID string) (*dto.CampaignResponse, error) {
+	campaign, err := s.getCampaign(ctx, userID, walletID, campaignID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, campaign)
+}
+
+func (s *walletCampaignService) Update(ctx context.Context, userID, walletID, campaignID string, req dto.UpdateCampaignRequest) (*dto.CampaignResponse, error) {
+	campaign, err := s.getCampaign(ctx, userID, walletID, campaignID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status != domain.CampaignStatusActive {
+		return nil, domain.ErrCampaignNotActive
+	}
+
+	if req.Title != nil {
+		campaign.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		campaign.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Goal != nil {
+		campaign.Goal = decimal.NewFromFloat(*req.Goal).Round(2)
+	}
+	if req.EndsAt != nil {
+		campaign.EndsAt = *req.EndsAt
+	}
+
+	if campaign.Title == "" || !campaign.Goal.IsPositive() || !campaign.EndsAt.After(time.Now()) || !campaign.EndsAt.After(campaign.StartsAt) {
+		return nil, domain.ErrInvalidCampaign
+	}
+	for _, milestone := range campaign.Milestones {
+		if milestone.Amount.GreaterThan(campaign.Goal) {
+			return nil, domain.ErrInvalidMilestone
+		}
+	}
+
+	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "campaign.updated", "wallet_campaign", campaign.ID, map[string]interface{}{
+		"goal":    campaign.Goal.String(),
+		"ends_at": campaign.EndsAt.Format(time.RFC3339),
+	}); err != nil {
+		log.Printf("Failed to audit update of campaign %s: %v", campaign.ID, err)
+	}
+
+	return s.toResponse(ctx, campaign)
+}
+
+func (s *walletCampaignService) Cancel(ctx context.Context, userID, walletID, campaignID string) (*dto.CampaignResponse, error) {
+	campaign, err := s.getCampaign(ctx, userID, walletID, campaignID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.Cancel(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "campaign.cancelled", "wallet_campaign", campaign.ID, map[string]interface{}{
+		"wallet_id": campaign.WalletID,
+	}); err != nil {
+		log.Printf("Failed to audit cancellation of campaign %s: %v", campaign.ID, err)
+	}
+
+	return s.toResponse(ctx, campaign)
+}
+
+func (s *walletCampaignService) GetEvents(ctx context.Context, userID, walletID, campaignID string) ([]*domain.CampaignEvent, error) {
+	campaign, err := s.getCampaign(ctx, userID, walletID, campaignID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.campaignRepo.GetEvents(ctx, campaign.ID)
+}
+
+func (s *walletCampaignService) GetPublic(ctx context.Context, walletID string) (*dto.CampaignResponse, error) {
+	campaign, err := s.campaignRepo.GetCurrentByWalletID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, campaign)
+}
+
+// getCampaign loads a campaign of the wallet for one of its members, or for
+// its creator only when ownerOnly is set.
+func (s *walletCampaignService) getCampaign(ctx context.Context, userID, walletID, campaignID string, ownerOnly bool) (*domain.WalletCampaign, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerOnly && wallet.CreatorID != userID || !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.WalletID != wallet.ID {
+		return nil, domain.ErrCampaignNotFound
+	}
+
+	return campaign, nil
+}
+
+// getWallet returns the wallet unless it has been deleted.
+func (s *walletCampaignService) getWallet(ctx context.Context, walletID string) (*domain.Wallet, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsDeleted() {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	return wallet, nil
+}
+
+func (s *walletCampaignService) CheckCampaigns(ctx context.Context) (int, error) {
+	now := time.Now()
+	campaigns, err := s.campaignRepo.GetActive(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, campaign := range campaigns {
+		n, err := s.checkCampaign(ctx, campaign, now)
+		recorded += n
+		if err != nil {
+			log.Printf("Failed to check campaign %s: %v", campaign.ID, err)
+		}
+	}
+
+	return recorded, nil
+}
+
+// checkCampaign records the milestones and goal the campaign has reached and
+// ends it once its deadline has passed. Each event is only recorded once, so
+// overlapping runs do not repeat them.
+func (s *walletCampaignService) checkCampaign(ctx context.Context, campaign *domain.WalletCampaign, now time.Time) (int, error) {
+	progress, err := s.campaignRepo.GetProgress(ctx, campaign, now)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, milestone := range campaign.Milestones {
+		if milestone.ReachedAt != nil || progress.Raised.LessThan(milestone.Amount) {
+			continue
+		}
+		event := newCampaignEvent(campaign, domain.CampaignEventMilestoneReached, progress)
+		event.MilestoneID = &milestone.ID
+		reached, err := s.campaignRepo.ReachMilestone(ctx, milestone, event)
+		if err != nil {
+			return recorded, err
+		}
+		if reached {
+			recorded++
+			s.notify(ctx, campaign, event, fmt.Sprintf("Milestone reached: %s", milestone.Label), fmt.Sprintf(
+				"Hello,\n\nYour campaign \"%s\" has reached its milestone \"%s\" of R%s. It has raised R%s from %d contributors so far.",
+				campaign.Title, milestone.Label, milestone.Amount.StringFixed(2), progress.Raised.StringFixed(2), progress.ContributorCount,
+			))
+		}
+	}
+
+	if campaign.GoalReachedAt == nil && progress.Raised.GreaterThanOrEqual(campaign.Goal) {
+		event := newCampaignEvent(campaign, domain.CampaignEventGoalReached, progress)
+		reached, err := s.campaignRepo.ReachGoal(ctx, campaign, event)
+		if err != nil {
+			return recorded, err
+		}
+		if reached {
+			recorded++
+			s.notify(ctx, campaign, event, "Your campaign reached its goal", fmt.Sprintf(
+				"Hello,\n\nYour campaign \"%s\" has reached its goal of R%s, raising R%s from %d contributors.",
+				campaign.Title, campaign.Goal.StringFixed(2), progress.Raised.StringFixed(2), progress.ContributorCount,
+			))
+		}
+	}
+
+	if campaign.IsPastDeadline(now) {
+		event := newCampaignEvent(campaign, domain.CampaignEventDeadlinePassed, progress)
+		ended, err := s.campaignRepo.End(ctx, campaign, event)
+		if err != nil {
+			return recorded, err
+		}
+		if ended {
+			recorded++
+			s.notify(ctx, campaign, event, "Your campaign has ended", fmt.Sprintf(
+				"Hello,\n\nYour campaign \"%s\" ended on %s. It raised R%s of its R%s goal (%s%%) from %d contributors.",
+				campaign.Title, campaign.EndsAt.In(s.config.Location()).Format("2 January 2006"),
+				progress.Raised.StringFixed(2), campaign.Goal.StringFixed(2), progress.Percent(campaign.Goal).String(), progress.ContributorCount,
+			))
+		}
+	}
+
+	return recorded, nil
+}
+
+func newCampaignEvent(campaign *domain.WalletCampaign, eventType domain.CampaignEventType, progress *domain.CampaignProgress) *domain.CampaignEvent {
+	return &domain.CampaignEvent{
+		CampaignID:       campaign.ID,
+		Type:             eventType,
+		Raised:           progress.Raised,
+		ContributorCount: progress.ContributorCount,
+	}
+}
+
+// notify audits a campaign event and emails the wallet's creator about it.
+// The event has already been recorded, so failures are only logged.
+func (s *walletCampaignService) notify(ctx context.Context, campaign *domain.WalletCampaign, event *domain.CampaignEvent, subject, body string) {
+	metadata := map[string]interface{}{
+		"wallet_id":         campaign.WalletID,
+		"raised":            event.Raised.String(),
+		"contributor_count": event.ContributorCount,
+	}
+	if event.MilestoneID != nil {
+		metadata["milestone_id"] = *event.MilestoneID
+	}
+	if err := s.auditService.Record(ctx, domain.AuditActorSystem, "", "campaign."+string(event.Type), "wallet_campaign", campaign.ID, metadata); err != nil {
+		log.Printf("Failed to audit %s for campaign %s: %v", event.Type, campaign.ID, err)
+	}
+
+	wallet, err := s.walletRepo.GetByID(ctx, campaign.WalletID)
+	if err != nil {
+		log.Printf("Failed to load wallet %s for campaign email: %v", campaign.WalletID, err)
+		return
+	}
+	user, err := s.userRepo.GetByID(ctx, wallet.CreatorID)
+	if err != nil {
+		log.Printf("Failed to load user %s for campaign email: %v", wallet.CreatorID, err)
+		return
+	}
+
+	if err := s.emailService.SendEmail(ctx, user.Email, subject, body); err != nil {
+		log.Printf("Failed to email user %s about campaign %s: %v", user.ID, campaign.ID, err)
+	}
+}
+
+func (s *walletCampaignService) toResponse(ctx context.Context, campaign *domain.WalletCampaign) (*dto.CampaignResponse, error) {
+	now := time.Now()
+	until := now
+	if campaign.EndedAt != nil && campaign.EndedAt.Before(until) {
+		until = *campaign.EndedAt
+	}
+	progress, err := s.campaignRepo.GetProgress(ctx, campaign, until)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CampaignResponse{
+		ID:               campaign.ID,
+		Title:            campaign.Title,
+		Description:      campaign.Description,
+		Goal:             campaign.Goal.InexactFloat64(),
+		Raised:           progress.Raised.InexactFloat64(),
+		ProgressPercent:  progress.Percent(campaign.Goal).InexactFloat64(),
+		ContributorCount: progress.ContributorCount,
+		StartsAt:         campaign.StartsAt.Format("2006-01-02T15:04:05Z07:00"),
+		EndsAt:           campaign.EndsAt.Format("2006-01-02T15:04:05Z07:00"),
+		Status:           string(campaign.Status),
+		Milestones:       make([]dto.CampaignMilestoneResponse, len(campaign.Milestones)),
+	}
+	if campaign.Status == domain.CampaignStatusActive && now.Before(campaign.EndsAt) {
+		response.DaysRemaining = int(math.Ceil(campaign.EndsAt.Sub(now).Hours() / 24))
+	}
+	if campaign.GoalReachedAt != nil {
+		reached := campaign.GoalReachedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.GoalReachedAt = &reached
+	}
+	if campaign.EndedAt != nil {
+		ended := campaign.EndedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.EndedAt = &ended
+	}
+
+	for i, milestone := range campaign.Milestones {
+		response.Milestones[i] = dto.CampaignMilestoneResponse{
+			ID:     milestone.ID,
+			Label:  milestone.Label,
+			Amount: milestone.Amount.InexactFloat64(),
+			// The check job may not have run since the milestone was passed.
+			Reached: milestone.ReachedAt != nil || progress.Raised.GreaterThanOrEqual(milestone.Amount),
+		}
+		if milestone.ReachedAt != nil {
+			reached := milestone.ReachedAt.Format("2006-01-02T15:04:05Z07:00")
+			response.Milestones[i].ReachedAt = &reached
+		}
+	}
+
+	return response, nil
+}
+
+// RunCampaignChecks checks running campaigns once per interval until ctx is
+// cancelled.
+func RunCampaignChecks(ctx context.Context, campaignService WalletCampaignService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		recorded, err := campaignService.CheckCampaigns(ctx)
+		if err != nil {
+			log.Printf("Campaign check run failed: %v", err)
+		} else if recorded > 0 {
+			log.Printf("Recorded %d campaign events", recorded)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// fakeCampaignRepo holds one campaign whose progress is set by the test.
+type fakeCampaignRepo struct {
+	repository.WalletCampaignRepository
+	campaign *domain.WalletCampaign
+	raised   decimal.Decimal
+	events   []*domain.CampaignEvent
+}
+
+func (r *fakeCampaignRepo) Create(ctx context.Context, campaign *domain.WalletCampaign) error {
+	campaign.ID = "c1"
+	r.campaign = campaign
+	return nil
+}
+
+func (r *fakeCampaignRepo) GetActive(ctx context.Context, now time.Time) ([]*domain.WalletCampaign, error) {
+	if r.campaign.Status != domain.CampaignStatusActive || !r.campaign.HasStarted(now) {
+		return nil, nil
+	}
+	return []*domain.WalletCampaign{r.campaign}, nil
+}
+
+func (r *fakeCampaignRepo) GetProgress(ctx context.Context, campaign *domain.WalletCampaign, until time.Time) (*domain.CampaignProgress, error) {
+	return &domain.CampaignProgress{Raised: r.raised, ContributorCount: 3}, nil
+}
+
+func (r *fakeCampaignRepo) ReachMilestone(ctx context.Context, milestone *domain.CampaignMilestone, event *domain.CampaignEvent) (bool, error) {
+	if milestone.ReachedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	milestone.ReachedAt = &now
+	r.events = append(r.events, event)
+	return true, nil
+}
+
+func (r *fakeCampaignRepo) ReachGoal(ctx context.Context, campaign *domain.WalletCampaign, event *domain.CampaignEvent) (bool, error) {
+	if campaign.GoalReachedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	campaign.GoalReachedAt = &now
+	r.events = append(r.events, event)
+	return true, nil
+}
+
+func (r *fakeCampaignRepo) End(ctx context.Context, campaign *domain.WalletCampaign, event *domain.CampaignEvent) (bool, error) {
+	if campaign.Status != domain.CampaignStatusActive {
+		return false, nil
+	}
+	now := time.Now()
+	campaign.Status = domain.CampaignStatusEnded
+	campaign.EndedAt = &now
+	r.events = append(r.events, event)
+	return true, nil
+}
+
+type fakeWalletRepo struct {
+	repository.WalletRepository
+	wallet *domain.Wallet
+}
+
+func (r *fakeWalletRepo) GetByID(ctx context.Context, id string) (*domain.Wallet, error) {
+	if r.wallet == nil || r.wallet.ID != id {
+		return nil, domain.ErrWalletNotFound
+	}
+	return r.wallet, nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return &domain.User{ID: id, Email: id + "@example.com"}, nil
+}
+
+type fakeEmailService struct {
+	sent []string
+}
+
+func (s *fakeEmailService) SendOTP(ctx context.Context, email, code string, purpose domain.OTPPurpose) error {
+	return nil
+}
+
+func (s *fakeEmailService) SendEmail(ctx context.Context, to, subject, body string) error {
+	s.sent = append(s.sent, subject)
+	return nil
+}
+
+func newTestCampaignService(wallet *domain.Wallet) (WalletCampaignService, *fakeCampaignRepo, *fakeEmailService) {
+	campaignRepo := &fakeCampaignRepo{}
+	emailService := &fakeEmailService{}
+	cfg := &config.Config{Timezone: "UTC"}
+	campaignService := NewWalletCampaignService(campaignRepo, &fakeWalletRepo{wallet: wallet}, &fakeUserRepo{}, emailService, fakeAuditService{}, cfg)
+	return campaignService, campaignRepo, emailService
+}
+
+func TestWalletCampaignCreate(t *testing.T) {
+	now := time.Now()
+	nextMonth := now.AddDate(0, 1, 0)
+	lastWeek := now.AddDate(0, 0, -7)
+	twoMonths := now.AddDate(0, 2, 0)
+	valid := func() dto.CreateCampaignRequest {
+		return dto.CreateCampaignRequest{
+			Title:  "Insulin for winter",
+			Goal:   1000,
+			EndsAt: nextMonth,
+			Milestones: []dto.CampaignMilestoneRequest{
+				{Label: "First month", Amount: 250},
+				{Label: "Halfway", Amount: 500},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		status  domain.WalletStatus
+		change  func(req *dto.CreateCampaignRequest)
+		wantErr error
+	}{
+		{"valid", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) {}, nil},
+		{"milestone at the goal", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.Milestones[1].Amount = 1000 }, nil},
+		{"not the creator", "beneficiary", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) {}, domain.ErrWalletAccessDenied},
+		{"paused wallet", "creator", domain.WalletStatusPaused, func(req *dto.CreateCampaignRequest) {}, domain.ErrWalletNotAcceptingContributions},
+		{"blank title", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.Title = "  " }, domain.ErrInvalidCampaign},
+		{"goal rounds to zero", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.Goal = 0.001 }, domain.ErrInvalidCampaign},
+		{"end date passed", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.EndsAt = lastWeek }, domain.ErrInvalidCampaign},
+		{"ends before it starts", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.StartsAt = &twoMonths }, domain.ErrInvalidCampaign},
+		{"milestone above the goal", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.Milestones[1].Amount = 1000.01 }, domain.ErrInvalidMilestone},
+		{"milestone without a label", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.Milestones[0].Label = " " }, domain.ErrInvalidMilestone},
+		{"duplicate milestone amounts", "creator", domain.WalletStatusActive, func(req *dto.CreateCampaignRequest) { req.Milestones[1].Amount = 250 }, domain.ErrInvalidMilestone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beneficiaryID := "beneficiary"
+			wallet := &domain.Wallet{ID: "w1", CreatorID: "creator", BeneficiaryID: &beneficiaryID, Status: tt.status}
+			campaignService, repo, _ := newTestCampaignService(wallet)
+			req := valid()
+			tt.change(&req)
+
+			got, err := campaignService.Create(context.Background(), tt.userID, "w1", req)
+			if err != tt.wantErr {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if repo.campaign != nil {
+					t.Fatalf("Create() stored a campaign, want none")
+				}
+				return
+			}
+			if got.Status != string(domain.CampaignStatusActive) || len(got.Milestones) != len(req.Milestones) || got.DaysRemaining < 28 {
+				t.Fatalf("Create() = %s with %d milestones and %d days left, want active with %d milestones",
+					got.Status, len(got.Milestones), got.DaysRemaining, len(req.Milestones))
+			}
+		})
+	}
+}
+
+func TestWalletCampaignCheckCampaigns(t *testing.T) {
+	tests := []struct {
+		name         string
+		raised       string
+		pastDeadline bool
+		wantEvents   []domain.CampaignEventType
+	}{
+		{"nothing reached", "100", false, nil},
+		{"first milestone", "250", false, []domain.CampaignEventType{domain.CampaignEventMilestoneReached}},
+		{"both milestones", "600", false, []domain.CampaignEventType{domain.CampaignEventMilestoneReached, domain.CampaignEventMilestoneReached}},
+		{"goal reached", "1000", false, []domain.CampaignEventType{
+			domain.CampaignEventMilestoneReached, domain.CampaignEventMilestoneReached, domain.CampaignEventGoalReached,
+		}},
+		{"deadline passed short of the goal", "300", true, []domain.CampaignEventType{
+			domain.CampaignEventMilestoneReached, domain.CampaignEventDeadlinePassed,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			wallet := &domain.Wallet{ID: "w1", CreatorID: "creator", Status: domain.WalletStatusActive}
+			campaignService, repo, emailService := newTestCampaignService(wallet)
+
+			endsAt := time.Now().AddDate(0, 0, 7)
+			if tt.pastDeadline {
+				endsAt = time.Now().Add(-time.Minute)
+			}
+			repo.campaign = &domain.WalletCampaign{
+				ID:       "c1",
+				WalletID: "w1",
+				Title:    "Insulin for winter",
+				Goal:     decimal.NewFromInt(1000),
+				StartsAt: time.Now().AddDate(0, -1, 0),
+				EndsAt:   endsAt,
+				Status:   domain.CampaignStatusActive,
+				Milestones: []*domain.CampaignMilestone{
+					{ID: "m1", Label: "First month", Amount: decimal.NewFromInt(250)},
+					{ID: "m2", Label: "Halfway", Amount: decimal.NewFromInt(500)},
+				},
+			}
+			repo.raised = decimal.RequireFromString(tt.raised)
+
+			recorded, err := campaignService.CheckCampaigns(ctx)
+			if err != nil {
+				t.Fatalf("CheckCampaigns() error = %v", err)
+			}
+			if recorded != len(tt.wantEvents) || len(repo.events) != len(tt.wantEvents) || len(emailService.sent) != len(tt.wantEvents) {
+				t.Fatalf("CheckCampaigns() recorded %d events and sent %d emails, want %d of each",
+					len(repo.events), len(emailService.sent), len(tt.wantEvents))
+			}
+			for i, event := range repo.events {
+				if event.Type != tt.wantEvents[i] || !event.Raised.Equal(repo.raised) {
+					t.Fatalf("event %d = %s at %s, want %s at %s", i, event.Type, event.Raised, tt.wantEvents[i], repo.raised)
+				}
+			}
+
+			// A second run records nothing new.
+			recorded, err = campaignService.CheckCampaigns(ctx)
+			if err != nil || recorded != 0 {
+				t.Fatalf("second CheckCampaigns() = %d, %v, want 0, nil", recorded, err)
+			}
+		})
+	}
+}
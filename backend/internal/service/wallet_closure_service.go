@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/paystack"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// WalletClosureService closes wallets that still hold funds. The owner picks
+// what happens to the remaining balance: it moves to another wallet, goes
+// back to the contributors who paid online in proportion to what each gave,
+// or is donated to the platform fund. The balance is taken out of the wallet
+// and the wallet closed in one step; refunds are then sent through Paystack,
+// and any it refuses are left failed, listed for admins to follow up and
+// retry.
+type WalletClosureService interface {
+	Close(ctx context.Context, userID, walletID string, req dto.CloseWalletRequest) (*domain.WalletClosure, error)
+	GetByWalletID(ctx context.Context, userID, walletID string) (*domain.WalletClosure, error)
+	List(ctx context.Context, filter repository.WalletClosureFilter) ([]*domain.WalletClosure, int, error)
+	GetByID(ctx context.Context, closureID string) (*domain.WalletClosure, error)
+	RetryRefunds(ctx context.Context, adminID, closureID string) (*domain.WalletClosure, error)
+	GetPlatformFund(ctx context.Context) (*dto.PlatformFundResponse, error)
+}
+
+type walletClosureService struct {
+	closureRepo  repository.WalletClosureRepository
+	walletRepo   repository.WalletRepository
+	paymentRepo  repository.PaymentRepository
+	gateway      paystack.Gateway
+	auditService AuditService
+}
+
+func NewWalletClosureService(
+	closureRepo repository.WalletClosureRepository,
+	walletRepo repository.WalletRepository,
+	paymentRepo repository.PaymentRepository,
+	gateway paystack.Gateway,
+	auditService AuditService,
+) WalletClosureService {
+	return &walletClosureService{
+		closureRepo:  closureRepo,
+		walletRepo:   walletRepo,
+		paymentRepo:  paymentRepo,
+		gateway:      gateway,
+		auditService: auditService,
+	}
+}
+
+func (s *walletClosureService) Close(ctx context.Context, userID, walletID string, req dto.CloseWalletRequest) (*domain.WalletClosure, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsDeleted() {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	if wallet.CreatorID != userID {
+		return nil, domain.ErrWalletAccessDenied
+	}
+	if !wallet.OwnerCanChangeStatusTo(domain.WalletStatusClosed) {
+		return nil, domain.ErrInvalidWalletTransition
+	}
+
+	disposition := domain.ClosureDisposition(req.Disposition)
+	if !disposition.IsValid() {
+		return nil, domain.ErrInvalidClosureDisposition
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	closure := &domain.WalletClosure{
+		WalletID:    wallet.ID,
+		RequestedBy: userID,
+		Disposition: disposition,
+		Amount:      wallet.Balance,
+		Reason:      reason,
+		Status:      domain.WalletClosureStatusCompleted,
+	}
+
+	switch disposition {
+	case domain.ClosureDispositionTransfer:
+		target, err := findWalletByCode(ctx, s.walletRepo, req.TargetWalletCode)
+		if err != nil {
+			return nil, err
+		}
+		if target.ID == wallet.ID || !target.Status.AcceptsContributions() {
+			return nil, domain.ErrInvalidTransferTarget
+		}
+		closure.TargetWalletID = &target.ID
+		closure.TargetWalletName = target.WalletName
+
+	case domain.ClosureDispositionRefund:
+		refunds, err := s.planRefunds(ctx, wallet)
+		if err != nil {
+			return nil, err
+		}
+		closure.Refunds = refunds
+		if len(refunds) > 0 {
+			closure.Status = domain.WalletClosureStatusRefunding
+		}
+	}
+
+	if reason == "" {
+		reason = "Wallet closed"
+	}
+	change := &domain.WalletStatusChange{
+		FromStatus: wallet.Status,
+		ToStatus:   domain.WalletStatusClosed,
+		Reason:     reason,
+		ActorType:  domain.AuditActorUser,
+		ActorID:    &userID,
+	}
+	if err := s.closureRepo.Close(ctx, wallet, closure, change); err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"closure_id":  closure.ID,
+		"disposition": closure.Disposition,
+		"amount":      closure.Amount.String(),
+		"from_status": change.FromStatus,
+	}
+	if closure.TargetWalletID != nil {
+		metadata["target_wallet_id"] = *closure.TargetWalletID
+	}
+	if len(closure.Refunds) > 0 {
+		metadata["refund_count"] = len(closure.Refunds)
+	}
+	// The wallet is already closed, so only log audit failures.
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "wallet.closed", "wallet", wallet.ID, metadata); err != nil {
+		log.Printf("Failed to audit closure of wallet %s: %v", wallet.ID, err)
+	}
+
+	s.sendRefunds(ctx, closure)
+	return closure, nil
+}
+
+// planRefunds splits the wallet's balance between its online contributions in
+// proportion to what each put into the wallet. Funds that came in any other
+// way, such as cash at a pharmacy, cannot go back through Paystack, so the
+// balance must not be more than the online contributions.
+func (s *walletClosureService) planRefunds(ctx context.Context, wallet *domain.Wallet) ([]*domain.WalletClosureRefund, error) {
+	if !wallet.Balance.IsPositive() {
+		return nil, nil
+	}
+
+	payments, err := s.paymentRepo.GetCompletedByWalletID(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	contributed := make([]decimal.Decimal, len(payments))
+	total := decimal.Zero
+	for i, payment := range payments {
+		contributed[i] = payment.CreditedAmount()
+		total = total.Add(contributed[i])
+	}
+	if wallet.Balance.GreaterThan(total) {
+		return nil, domain.ErrRefundNotCovered
+	}
+
+	var refunds []*domain.WalletClosureRefund
+	for i, share := range domain.ProRataShares(wallet.Balance, contributed) {
+		if !share.IsPositive() {
+			continue
+		}
+		refunds = append(refunds, &domain.WalletClosureRefund{
+			PaymentID:        payments[i].ID,
+			PaymentReference: payments[i].Reference,
+			ContributorEmail: payments[i].Email,
+			Amount:           share,
+			Status:           domain.ClosureRefundStatusPending,
+		})
+	}
+
+	return refunds, nil
+}
+
+// sendRefunds asks Paystack to refund each outstanding share of the closure
+// and records the outcome. Refunds are independent, so one failing does not
+// stop the rest.
+func (s *walletClosureService) sendRefunds(ctx context.Context, closure *domain.WalletClosure) {
+	sent := 0
+	for _, refund := range closure.Refunds {
+		if !refund.IsOutstanding() {
+			continue
+		}
+
+		gatewayRefundID, err := s.sendRefund(ctx, closure, refund)
+		if err != nil {
+			refund.LastError = err.Error()
+			if failErr := s.closureRepo.FailRefund(ctx, refund); failErr != nil {
+				log.Printf("Failed to record refund failure %s of closure %s: %v", refund.ID, closure.ID, failErr)
+			}
+			s.audit(ctx, "wallet_closure.refund_failed", closure, map[string]interface{}{
+				"refund_id": refund.ID,
+				"amount":    refund.Amount.String(),
+				"error":     refund.LastError,
+				"attempts":  refund.Attempts,
+			})
+			continue
+		}
+
+		refund.GatewayRefundID = gatewayRefundID
+		if err := s.closureRepo.CompleteRefund(ctx, refund); err != nil {
+			log.Printf("Failed to record refund %s of closure %s: %v", refund.ID, closure.ID, err)
+			continue
+		}
+		sent++
+		s.audit(ctx, "wallet_closure.refunded", closure, map[string]interface{}{
+			"refund_id":         refund.ID,
+			"amount":            refund.Amount.String(),
+			"gateway_refund_id": refund.GatewayRefundID,
+		})
+	}
+
+	if sent > 0 && closure.Status == domain.WalletClosureStatusRefunding {
+		if refreshed, err := s.closureRepo.GetByID(ctx, closure.ID); err == nil {
+			closure.Status = refreshed.Status
+			closure.CompletedAt = refreshed.CompletedAt
+		}
+	}
+}
+
+// sendRefund refunds one share and returns Paystack's ID for the refund. An
+// earlier attempt whose response was lost may already have reached Paystack,
+// so the payment's refunds are checked first and an existing one is used
+// rather than paying the contributor twice. A closure refunds each payment
+// at most once, so any refund Paystack has not failed is this one.
+func (s *walletClosureService) sendRefund(ctx context.Context, closure *domain.WalletClosure, refund *domain.WalletClosureRefund) (string, error) {
+	payment, err := s.paymentRepo.GetByReference(ctx, refund.PaymentReference)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := s.gateway.ListRefunds(payment.PaystackReference)
+	if err != nil {
+		return "", fmt.Errorf("checking for an earlier refund: %w", err)
+	}
+	for _, data := range existing.Data {
+		if data.Status != paystack.RefundStatusFailed {
+			return fmt.Sprintf("%d", data.ID), nil
+		}
+	}
+
+	resp, err := s.gateway.CreateRefund(&paystack.RefundRequest{
+		Transaction:  payment.PaystackReference,
+		Amount:       refund.Amount.Shift(2).IntPart(),
+		Currency:     "ZAR",
+		MerchantNote: fmt.Sprintf("CareWallet wallet %s closed", closure.WalletID),
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Data.Status == paystack.RefundStatusFailed {
+		return "", fmt.Errorf("paystack refused the refund")
+	}
+
+	return fmt.Sprintf("%d", resp.Data.ID), nil
+}
+
+func (s *walletClosureService) audit(ctx context.Context, action string, closure *domain.WalletClosure, metadata map[string]interface{}) {
+	// The refund has already happened, so only log audit failures.
+	if err := s.auditService.Record(ctx, domain.AuditActorSystem, "", action, "wallet_closure", closure.ID, metadata); err != nil {
+		log.Printf("Failed to audit %s for closure %s: %v", action, closure.ID, err)
+	}
+}
+
+func (s *walletClosureService) GetByWalletID(ctx context.Context, userID, walletID string) (*domain.WalletClosure, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	return s.closureRepo.GetByWalletID(ctx, wallet.ID)
+}
+
+func (s *walletClosureService) List(ctx context.Context, filter repository.WalletClosureFilter) ([]*domain.WalletClosure, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	return s.closureRepo.List(ctx, filter)
+}
+
+func (s *walletClosureService) GetByID(ctx context.Context, closureID string) (*domain.WalletClosure, error) {
+	return s.closureRepo.GetByID(ctx, closureID)
+}
+
+func (s *walletClosureService) RetryRefunds(ctx context.Context, adminID, closureID string) (*domain.WalletClosure, error) {
+	closure, err := s.closureRepo.GetByID(ctx, closureID)
+	if err != nil {
+		return nil, err
+	}
+
+	outstanding := 0
+	for _, refund := range closure.Refunds {
+		if refund.IsOutstanding() {
+			outstanding++
+		}
+	}
+	if outstanding == 0 {
+		return nil, domain.ErrNoOutstandingRefunds
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorAdmin, adminID, "wallet_closure.refunds_retried", "wallet_closure", closure.ID, map[string]interface{}{
+		"outstanding": outstanding,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.sendRefunds(ctx, closure)
+	return closure, nil
+}
+
+func (s *walletClosureService) GetPlatformFund(ctx context.Context) (*dto.PlatformFundResponse, error) {
+	total, count, err := s.closureRepo.SumDonations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PlatformFundResponse{
+		Total:         total.InexactFloat64(),
+		DonationCount: count,
+	}, nil
+}
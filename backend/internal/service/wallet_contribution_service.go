@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+// WalletContributionService serves a wallet's contributor wall: who has given
+// and what they said, shown the way each contributor chose. Contributor
+// emails never leave the service. The wallet's creator can hide messages from
+// the wall and show them again.
+type WalletContributionService interface {
+	// ListByCode returns the wall for the public page reached by a shareable
+	// code, including codes the wallet has rotated away from.
+	ListByCode(ctx context.Context, code string, filter repository.ContributionFilter) ([]dto.ContributionResponse, int, error)
+	// ListByShareLink returns the wall for the public page reached by a share
+	// link, while the link is still usable.
+	ListByShareLink(ctx context.Context, token string, filter repository.ContributionFilter) ([]dto.ContributionResponse, int, error)
+	// ListForOwner returns the wall as the wallet's creator sees it, with
+	// hidden messages included and marked.
+	ListForOwner(ctx context.Context, userID, walletID string, filter repository.ContributionFilter) ([]dto.ContributionResponse, int, error)
+	HideMessage(ctx context.Context, userID, walletID, transactionID string) (*dto.ContributionResponse, error)
+	ShowMessage(ctx context.Context, userID, walletID, transactionID string) (*dto.ContributionResponse, error)
+}
+
+type walletContributionService struct {
+	contributionRepo repository.WalletContributionRepository
+	walletRepo       repository.WalletRepository
+	shareLinkRepo    repository.WalletShareLinkRepository
+	auditService     AuditService
+}
+
+func NewWalletContributionService(
+	contributionRepo repository.WalletContributionRepository,
+	walletRepo repository.WalletRepository,
+	shareLinkRepo repository.WalletShareLinkRepository,
+	auditService AuditService,
+) WalletContributionService {
+	return &walletContributionService{
+		contributionRepo: contributionRepo,
+		walletRepo:       walletRepo,
+		shareLinkRepo:    shareLinkRepo,
+		auditService:     auditService,
+	}
+}
+
+func (s *walletContributionService) ListByCode(ctx context.Context, code string, filter repository.ContributionFilter) ([]dto.ContributionResponse, int, error) {
+	wallet, _, err := findWalletByPublicCode(ctx, s.walletRepo, code)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.list(ctx, wallet.ID, filter, false)
+}
+
+func (s *walletContributionService) ListByShareLink(ctx context.Context, token string, filter repository.ContributionFilter) ([]dto.ContributionResponse, int, error) {
+	link, err := s.shareLinkRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !link.IsUsable(time.Now()) {
+		return nil, 0, domain.ErrShareLinkUnavailable
+	}
+
+	wallet, err := s.walletRepo.GetByID(ctx, link.WalletID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !wallet.Status.AcceptsContributions() {
+		return nil, 0, domain.ErrShareLinkUnavailable
+	}
+
+	return s.list(ctx, wallet.ID, filter, false)
+}
+
+func (s *walletContributionService) ListForOwner(ctx context.Context, userID, walletID string, filter repository.ContributionFilter) ([]dto.ContributionResponse, int, error) {
+	if _, err := s.getOwnedWallet(ctx, userID, walletID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.list(ctx, walletID, filter, true)
+}
+
+func (s *walletContributionService) list(ctx context.Context, walletID string, filter repository.ContributionFilter, forOwner bool) ([]dto.ContributionResponse, int, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+	filter.WalletID = walletID
+
+	contributions, total, err := s.contributionRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]dto.ContributionResponse, len(contributions))
+	for i, contribution := range contributions {
+		responses[i] = contributionToResponse(contribution, forOwner)
+	}
+
+	return responses, total, nil
+}
+
+func (s *walletContributionService) HideMessage(ctx context.Context, userID, walletID, transactionID string) (*dto.ContributionResponse, error) {
+	return s.setMessageHidden(ctx, userID, walletID, transactionID, true)
+}
+
+func (s *walletContributionService) ShowMessage(ctx context.Context, userID, walletID, transactionID string) (*dto.ContributionResponse, error) {
+	return s.setMessageHidden(ctx, userID, walletID, transactionID, false)
+}
+
+func (s *walletContributionService) setMessageHidden(ctx context.Context, userID, walletID, transactionID string, hidden bool) (*dto.ContributionResponse, error) {
+	wallet, err := s.getOwnedWallet(ctx, userID, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	contribution, err := s.contributionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if contribution.WalletID != wallet.ID {
+		return nil, domain.ErrContributionNotFound
+	}
+
+	if err := s.contributionRepo.SetMessageHidden(ctx, contribution, hidden, userID); err != nil {
+		return nil, err
+	}
+
+	action := "contribution.message_shown"
+	if hidden {
+		action = "contribution.message_hidden"
+	}
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, action, "transaction", contribution.TransactionID, map[string]interface{}{
+		"wallet_id": wallet.ID,
+	}); err != nil {
+		log.Printf("Failed to audit %s for transaction %s: %v", action, contribution.TransactionID, err)
+	}
+
+	response := contributionToResponse(contribution, true)
+	return &response, nil
+}
+
+// getOwnedWallet returns the wallet if userID created it; only the creator
+// moderates the wall.
+func (s *walletContributionService) getOwnedWallet(ctx context.Context, userID, walletID string) (*domain.Wallet, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsDeleted() {
+		return nil, domain.ErrWalletNotFound
+	}
+	if wallet.CreatorID != userID {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	return wallet, nil
+}
+
+func contributionToResponse(contribution *domain.Contribution, forOwner bool) dto.ContributionResponse {
+	response := dto.ContributionResponse{
+		ID:          contribution.TransactionID,
+		DisplayName: contribution.DisplayName(),
+		CreatedAt:   contribution.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if !contribution.HideAmount {
+		amount := contribution.Amount.InexactFloat64()
+		response.Amount = &amount
+	}
+	if contribution.MessageHiddenAt == nil || forOwner {
+		response.Message = contribution.Message
+		response.MessageHidden = contribution.MessageHiddenAt != nil
+	}
+
+	return response
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/qr"
+	"github.com/carewallet/backend/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+// WalletLookupService answers a pharmacy's questions about a wallet code
+// without giving away more than the counter needs: a masked name and whether
+// an amount is covered. The balance itself is only shown once the wallet
+// owner consents with an OTP. Every lookup is logged, and pharmacies that
+// look up too many unknown codes, or check too many amounts against one
+// wallet, are refused for a while.
+type WalletLookupService interface {
+	// Lookup describes the wallet with the code. A nil amount skips the
+	// coverage check; it fails with ErrAmountCheckLimit once the pharmacy
+	// has checked too many amounts against the wallet in the past hour.
+	Lookup(ctx context.Context, pharmacyID, pharmacyUserID, code string, amount *decimal.Decimal) (*dto.WalletLookupResponse, error)
+	// RequestBalanceConsent sends the wallet owner an OTP to share with the
+	// pharmacy if they agree to their balance being shown.
+	RequestBalanceConsent(ctx context.Context, pharmacyID, pharmacyUserID, code string) (*dto.BalanceConsentResponse, error)
+	DiscloseBalance(ctx context.Context, pharmacyID, pharmacyUserID, code, otpCode string) (*dto.WalletBalanceResponse, error)
+	// ScanVoucher checks a wallet voucher scanned from a QR code and looks up
+	// the wallet it carries. It fails with qr.ErrInvalidVoucher if the voucher
+	// was altered or has expired.
+	ScanVoucher(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.ScanVoucherRequest) (*dto.ScanVoucherResponse, error)
+	ListLookups(ctx context.Context, pharmacyID string, page, pageSize int) ([]*domain.WalletLookup, int, error)
+	// Find resolves a wallet code for another pharmacy flow, such as a fee
+	// quote or a withdrawal, under the same logging and limits as Lookup.
+	// amountChecked counts the call against the amount check limit.
+	Find(ctx context.Context, pharmacyID, pharmacyUserID, code string, amountChecked bool) (*domain.Wallet, error)
+}
+
+type walletLookupService struct {
+	lookupRepo       repository.WalletLookupRepository
+	walletRepo       repository.WalletRepository
+	spendingRuleRepo repository.WalletSpendingRuleRepository
+	userRepo         repository.UserRepository
+	otpService       OTPService
+	auditService     AuditService
+	voucherSigner    *qr.VoucherSigner
+	config           *config.Config
+}
+
+func NewWalletLookupService(
+	lookupRepo repository.WalletLookupRepository,
+	walletRepo repository.WalletRepository,
+	spendingRuleRepo repository.WalletSpendingRuleRepository,
+	userRepo repository.UserRepository,
+	otpService OTPService,
+	auditService AuditService,
+	voucherSigner *qr.VoucherSigner,
+	cfg *config.Config,
+) WalletLookupService {
+	return &walletLookupService{
+		lookupRepo:       lookupRepo,
+		walletRepo:       walletRepo,
+		spendingRuleRepo: spendingRuleRepo,
+		userRepo:         userRepo,
+		otpService:       otpService,
+		auditService:     auditService,
+		voucherSigner:    voucherSigner,
+		config:           cfg,
+	}
+}
+
+func (s *walletLookupService) Lookup(ctx context.Context, pharmacyID, pharmacyUserID, code string, amount *decimal.Decimal) (*dto.WalletLookupResponse, error) {
+	wallet, err := s.Find(ctx, pharmacyID, pharmacyUserID, code, amount != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	spendableHere, err := s.spendingRuleRepo.IsAllowed(ctx, wallet.ID, pharmacyID)
+	if err != nil {
+		return nil, err
+	}
+
+	beneficiaryName := "Unknown"
+	if owner, err := s.walletOwner(ctx, wallet); err == nil {
+		beneficiaryName = maskName(owner.FullName)
+	}
+
+	response := &dto.WalletLookupResponse{
+		WalletID:        wallet.ID,
+		WalletName:      wallet.WalletName,
+		BeneficiaryName: beneficiaryName,
+		SpendableHere:   spendableHere,
+	}
+	if amount != nil {
+		covered := wallet.Balance.GreaterThanOrEqual(*amount)
+		response.AmountCovered = &covered
+	}
+
+	return response, nil
+}
+
+func (s *walletLookupService) RequestBalanceConsent(ctx context.Context, pharmacyID, pharmacyUserID, code string) (*dto.BalanceConsentResponse, error) {
+	wallet, err := s.find(ctx, pharmacyID, pharmacyUserID, code, false)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.walletOwner(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.otpService.Send(ctx, dto.SendOTPRequest{
+		Email:   owner.Email,
+		Purpose: string(domain.OTPPurposeBalanceDisclosure),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dto.BalanceConsentResponse{OTPSentTo: maskEmail(owner.Email)}, nil
+}
+
+func (s *walletLookupService) DiscloseBalance(ctx context.Context, pharmacyID, pharmacyUserID, code, otpCode string) (*dto.WalletBalanceResponse, error) {
+	wallet, err := s.find(ctx, pharmacyID, pharmacyUserID, code, false)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.walletOwner(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	otpResp, err := s.otpService.Verify(ctx, dto.VerifyOTPRequest{
+		Email:   owner.Email,
+		Code:    otpCode,
+		Purpose: string(domain.OTPPurposeBalanceDisclosure),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !otpResp.Valid {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorPharmacy, pharmacyUserID, "wallet.balance_disclosed", "wallet", wallet.ID, map[string]interface{}{
+		"pharmacy_id": pharmacyID,
+	}); err != nil {
+		log.Printf("Failed to audit balance disclosure for wallet %s: %v", wallet.ID, err)
+	}
+
+	return &dto.WalletBalanceResponse{
+		WalletID: wallet.ID,
+		Balance:  wallet.Balance.InexactFloat64(),
+	}, nil
+}
+
+func (s *walletLookupService) ScanVoucher(ctx context.Context, pharmacyID, pharmacyUserID string, req dto.ScanVoucherRequest) (*dto.ScanVoucherResponse, error) {
+	code, expiresAt, err := s.voucherSigner.Verify(req.Voucher)
+	if err != nil {
+		return nil, err
+	}
+
+	var amount *decimal.Decimal
+	if req.Amount != nil {
+		parsed := decimal.NewFromFloat(*req.Amount)
+		amount = &parsed
+	}
+
+	lookup, err := s.Lookup(ctx, pharmacyID, pharmacyUserID, code, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ScanVoucherResponse{
+		WalletLookupResponse: *lookup,
+		WalletCode:           code,
+		VoucherExpiresAt:     expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+func (s *walletLookupService) ListLookups(ctx context.Context, pharmacyID string, page, pageSize int) ([]*domain.WalletLookup, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	return s.lookupRepo.ListByPharmacy(ctx, pharmacyID, page, pageSize)
+}
+
+func (s *walletLookupService) Find(ctx context.Context, pharmacyID, pharmacyUserID, code string, amountChecked bool) (*domain.Wallet, error) {
+	wallet, err := s.find(ctx, pharmacyID, pharmacyUserID, code, amountChecked)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit := s.config.WalletAmountCheckLimit; amountChecked && limit > 0 {
+		// The count includes this lookup, which find has already logged.
+		checks, err := s.lookupRepo.CountAmountChecks(ctx, pharmacyID, wallet.ID, time.Now().Add(-time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		if checks > limit {
+			return nil, domain.ErrAmountCheckLimit
+		}
+	}
+
+	return wallet, nil
+}
+
+// find resolves a wallet code for a pharmacy, logging the attempt and whether
+// it checks an amount. It fails with ErrLookupLimitReached once the pharmacy
+// has made too many failed lookups in the past hour.
+func (s *walletLookupService) find(ctx context.Context, pharmacyID, pharmacyUserID, code string, amountChecked bool) (*domain.Wallet, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" || len(code) > 20 {
+		return nil, domain.ErrInvalidWalletCode
+	}
+
+	if limit := s.config.WalletLookupFailureLimit; limit > 0 {
+		failed, err := s.lookupRepo.CountFailed(ctx, pharmacyID, time.Now().Add(-time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		if failed >= limit {
+			return nil, domain.ErrLookupLimitReached
+		}
+	}
+
+	wallet, findErr := findWalletByCode(ctx, s.walletRepo, code)
+	if findErr != nil && !errors.Is(findErr, domain.ErrWalletNotFound) && !errors.Is(findErr, domain.ErrInvalidWalletCode) {
+		return nil, findErr
+	}
+
+	lookup := &domain.WalletLookup{
+		PharmacyID:    pharmacyID,
+		Code:          code,
+		Found:         wallet != nil,
+		AmountChecked: wallet != nil && amountChecked,
+	}
+	if pharmacyUserID != "" {
+		lookup.PharmacyUserID = &pharmacyUserID
+	}
+	if wallet != nil {
+		lookup.WalletID = &wallet.ID
+	}
+	if err := s.lookupRepo.Create(ctx, lookup); err != nil {
+		return nil, err
+	}
+
+	if findErr != nil {
+		return nil, findErr
+	}
+	return wallet, nil
+}
+
+// walletOwner returns the beneficiary, or the wallet creator if there is none.
+func (s *walletLookupService) walletOwner(ctx context.Context, wallet *domain.Wallet) (*domain.User, error) {
+	ownerID := wallet.CreatorID
+	if wallet.BeneficiaryID != nil {
+		ownerID = *wallet.BeneficiaryID
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, ownerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrNoBeneficiaryEmail
+		}
+		return nil, err
+	}
+	if owner.Email == "" {
+		return nil, domain.ErrNoBeneficiaryEmail
+	}
+
+	return owner, nil
+}
+
+// maskName keeps the first letter of each part of a name, e.g. "Thandi
+// Nkosi" becomes "T*** N***".
+func maskName(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) == 0 {
+		return "Unknown"
+	}
+
+	for i, part := range parts {
+		first, _ := utf8.DecodeRuneInString(part)
+		parts[i] = string(first) + "***"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/carewallet/backend/internal/config"
+	"github.com/carewallet/backend/internal/domain"
+	"github.com/carewallet/backend/internal/dto"
+	"github.com/carewallet/backend/internal/qr"
+	"github.com/carewallet/backend/internal/repository"
+)
+
+// WalletQRService provides what a wallet's QR codes encode: the link to its
+// contribution page for contributors, and a signed, short-lived voucher for
+// pharmacies to scan instead of typing the wallet code.
+type WalletQRService interface {
+	ContributionLink(ctx context.Context, userID, walletID string) (string, error)
+	IssueVoucher(ctx context.Context, userID, walletID string) (*dto.WalletVoucherResponse, error)
+}
+
+type walletQRService struct {
+	walletRepo    repository.WalletRepository
+	voucherSigner *qr.VoucherSigner
+	config        *config.Config
+}
+
+func NewWalletQRService(walletRepo repository.WalletRepository, voucherSigner *qr.VoucherSigner, cfg *config.Config) WalletQRService {
+	return &walletQRService{
+		walletRepo:    walletRepo,
+		voucherSigner: voucherSigner,
+		config:        cfg,
+	}
+}
+
+func (s *walletQRService) ContributionLink(ctx context.Context, userID, walletID string) (string, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return "", err
+	}
+
+	if !wallet.CanBeAccessedBy(userID) {
+		return "", domain.ErrWalletAccessDenied
+	}
+
+	return fmt.Sprintf("%s/wallet/%s", s.config.FrontendURL, url.PathEscape(wallet.ShareableCode)), nil
+}
+
+func (s *walletQRService) IssueVoucher(ctx context.Context, userID, walletID string) (*dto.WalletVoucherResponse, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrWalletAccessDenied
+	}
+	if !wallet.Status.CanBeSpent() {
+		return nil, domain.ErrWalletNotSpendable
+	}
+
+	voucher, expiresAt := s.voucherSigner.Sign(wallet.ShareableCode)
+	return &dto.WalletVoucherResponse{
+		Voucher:   voucher,
+		ExpiresAt: expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
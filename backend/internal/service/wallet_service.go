@@ -2,7 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
+	"github.com/carewallet/backend/internal/config"
 	"github.com/carewallet/backend/internal/domain"
 	"github.com/carewallet/backend/internal/dto"
 	"github.com/carewallet/backend/internal/repository"
@@ -17,32 +23,73 @@ type WalletService interface {
 	GetUserWallets(ctx context.Context, userID string) ([]dto.WalletResponse, error)
 	Update(ctx context.Context, userID, walletID string, req dto.UpdateWalletRequest) (*dto.WalletResponse, error)
 	Delete(ctx context.Context, userID, walletID string) error
+	// Spending rules limit where a wallet can be spent. Only the wallet's
+	// creator can change them.
+	GetSpendingRules(ctx context.Context, userID, walletID string) ([]dto.SpendingRuleResponse, error)
+	AddSpendingRule(ctx context.Context, userID, walletID string, req dto.AddSpendingRuleRequest) (*dto.SpendingRuleResponse, error)
+	RemoveSpendingRule(ctx context.Context, userID, walletID, ruleID string) error
+	// ChangeStatus moves the wallet through its lifecycle. Only the wallet's
+	// creator can change its status, and only along the allowed transitions.
+	ChangeStatus(ctx context.Context, userID, walletID string, req dto.ChangeWalletStatusRequest) (*dto.WalletResponse, error)
+	GetStatusHistory(ctx context.Context, userID, walletID string) ([]*domain.WalletStatusChange, error)
+	// Freeze and Unfreeze are for admins. A frozen wallet cannot be spent or
+	// contributed to, and returns to its earlier status when unfrozen.
+	Freeze(ctx context.Context, adminID, walletID, reason string) (*dto.WalletResponse, error)
+	Unfreeze(ctx context.Context, adminID, walletID, reason string) (*dto.WalletResponse, error)
+	// RotateCode gives the wallet a new shareable code. The old code keeps
+	// leading to the public page, without revealing the new code, but no
+	// longer works at pharmacies. Only the wallet's creator can rotate the
+	// code or manage share links.
+	RotateCode(ctx context.Context, userID, walletID string) (*dto.WalletResponse, error)
+	GetShareLinks(ctx context.Context, userID, walletID string) ([]dto.ShareLinkResponse, error)
+	CreateShareLink(ctx context.Context, userID, walletID string, req dto.CreateShareLinkRequest) (*dto.ShareLinkResponse, error)
+	RevokeShareLink(ctx context.Context, userID, walletID, linkID string) error
+	// GetByShareLink returns the public page for a share link and counts the
+	// view. It fails with ErrShareLinkUnavailable once the link has stopped
+	// taking contributions.
+	GetByShareLink(ctx context.Context, token string) (*dto.PublicWalletResponse, error)
 }
 
 type walletService struct {
-	walletRepo repository.WalletRepository
+	walletRepo       repository.WalletRepository
+	spendingRuleRepo repository.WalletSpendingRuleRepository
+	shareLinkRepo    repository.WalletShareLinkRepository
+	pharmacyRepo     repository.PharmacyRepository
+	organisationRepo repository.OrganisationRepository
+	uploadService    UploadService
+	campaignService  WalletCampaignService
+	auditService     AuditService
+	config           *config.Config
 }
 
-func NewWalletService(walletRepo repository.WalletRepository) WalletService {
-	return &walletService{walletRepo: walletRepo}
+func NewWalletService(
+	walletRepo repository.WalletRepository,
+	spendingRuleRepo repository.WalletSpendingRuleRepository,
+	shareLinkRepo repository.WalletShareLinkRepository,
+	pharmacyRepo repository.PharmacyRepository,
+	organisationRepo repository.OrganisationRepository,
+	uploadService UploadService,
+	campaignService WalletCampaignService,
+	auditService AuditService,
+	cfg *config.Config,
+) WalletService {
+	return &walletService{
+		walletRepo:       walletRepo,
+		spendingRuleRepo: spendingRuleRepo,
+		shareLinkRepo:    shareLinkRepo,
+		pharmacyRepo:     pharmacyRepo,
+		organisationRepo: organisationRepo,
+		uploadService:    uploadService,
+		campaignService:  campaignService,
+		auditService:     auditService,
+		config:           cfg,
+	}
 }
 
 func (s *walletService) Create(ctx context.Context, userID string, req dto.CreateWalletRequest) (*dto.WalletResponse, error) {
-	// Generate unique shareable code
-	var shareableCode string
-	for {
-		code, err := utils.GenerateShareableCode()
-		if err != nil {
-			return nil, err
-		}
-		exists, err := s.walletRepo.ShareableCodeExists(ctx, code)
-		if err != nil {
-			return nil, err
-		}
-		if !exists {
-			shareableCode = code
-			break
-		}
+	shareableCode, err := s.newShareableCode(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	wallet := &domain.Wallet{
@@ -57,15 +104,21 @@ func (s *walletService) Create(ctx context.Context, userID string, req dto.Creat
 		Status:        domain.WalletStatusActive,
 	}
 
+	if req.PhotoUploadID != nil && *req.PhotoUploadID != "" {
+		if err := s.setPhotoUpload(ctx, userID, wallet, *req.PhotoUploadID); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.walletRepo.Create(ctx, wallet); err != nil {
 		return nil, err
 	}
 
-	return walletToResponse(wallet), nil
+	return s.toResponse(wallet), nil
 }
 
 func (s *walletService) GetByID(ctx context.Context, userID, walletID string) (*dto.WalletResponse, error) {
-	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	wallet, err := s.getWallet(ctx, walletID)
 	if err != nil {
 		return nil, err
 	}
@@ -74,24 +127,20 @@ func (s *walletService) GetByID(ctx context.Context, userID, walletID string) (*
 		return nil, domain.ErrWalletAccessDenied
 	}
 
-	return walletToResponse(wallet), nil
+	return s.toResponse(wallet), nil
 }
 
 func (s *walletService) GetByShareableCode(ctx context.Context, code string) (*dto.PublicWalletResponse, error) {
-	wallet, err := s.walletRepo.GetByShareableCode(ctx, code)
+	wallet, retiredCode, err := findWalletByPublicCode(ctx, s.walletRepo, code)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.PublicWalletResponse{
-		ID:            wallet.ID,
-		WalletName:    wallet.WalletName,
-		Description:   wallet.Description,
-		PhotoURL:      wallet.PhotoURL,
-		Balance:       wallet.Balance.InexactFloat64(),
-		FundingGoal:   wallet.FundingGoal.InexactFloat64(),
-		ShareableCode: wallet.ShareableCode,
-	}, nil
+	// A retired code may have leaked, which is why it was rotated, so it must
+	// not lead to the code pharmacies now accept.
+	response := s.toPublicResponse(ctx, wallet, retiredCode == "")
+	response.RedirectedFrom = retiredCode
+	return response, nil
 }
 
 func (s *walletService) GetUserWallets(ctx context.Context, userID string) ([]dto.WalletResponse, error) {
@@ -102,14 +151,14 @@ func (s *walletService) GetUserWallets(ctx context.Context, userID string) ([]dt
 
 	responses := make([]dto.WalletResponse, len(wallets))
 	for i, wallet := range wallets {
-		responses[i] = *walletToResponse(wallet)
+		responses[i] = *s.toResponse(wallet)
 	}
 
 	return responses, nil
 }
 
 func (s *walletService) Update(ctx context.Context, userID, walletID string, req dto.UpdateWalletRequest) (*dto.WalletResponse, error) {
-	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	wallet, err := s.getWallet(ctx, walletID)
 	if err != nil {
 		return nil, err
 	}
@@ -126,23 +175,36 @@ func (s *walletService) Update(ctx context.Context, userID, walletID string, req
 	}
 	if req.PhotoURL != nil {
 		wallet.PhotoURL = *req.PhotoURL
+		wallet.PhotoUploadID = nil
+	}
+	if req.PhotoUploadID != nil {
+		if *req.PhotoUploadID == "" {
+			wallet.PhotoUploadID = nil
+		} else if err := s.setPhotoUpload(ctx, userID, wallet, *req.PhotoUploadID); err != nil {
+			return nil, err
+		}
 	}
 	if req.FundingGoal != nil {
 		wallet.FundingGoal = decimal.NewFromFloat(*req.FundingGoal)
 	}
-	if req.Status != nil {
-		wallet.Status = domain.WalletStatus(*req.Status)
+
+	// The status changes first, so a refused change leaves the details as
+	// they were.
+	if req.Status != nil && domain.WalletStatus(*req.Status) != wallet.Status {
+		if err := s.changeStatusAsOwner(ctx, userID, wallet, domain.WalletStatus(*req.Status), ""); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.walletRepo.Update(ctx, wallet); err != nil {
 		return nil, err
 	}
 
-	return walletToResponse(wallet), nil
+	return s.toResponse(wallet), nil
 }
 
 func (s *walletService) Delete(ctx context.Context, userID, walletID string) error {
-	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	wallet, err := s.getWallet(ctx, walletID)
 	if err != nil {
 		return err
 	}
@@ -154,12 +216,492 @@ func (s *walletService) Delete(ctx context.Context, userID, walletID string) err
 	if !wallet.CanBeDeleted() {
 		return domain.ErrWalletHasBalance
 	}
+	if wallet.Status != domain.WalletStatusClosed && !wallet.OwnerCanChangeStatusTo(domain.WalletStatusClosed) {
+		return domain.ErrInvalidWalletTransition
+	}
+
+	// Deleting closes the wallet and hides it; its transactions are kept.
+	change := &domain.WalletStatusChange{
+		FromStatus: wallet.Status,
+		ToStatus:   domain.WalletStatusClosed,
+		Reason:     "Wallet deleted",
+		ActorType:  domain.AuditActorUser,
+		ActorID:    &userID,
+	}
+	if err := s.walletRepo.SoftDelete(ctx, wallet, change); err != nil {
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "wallet.deleted", "wallet", wallet.ID, map[string]interface{}{
+		"from_status": change.FromStatus,
+	}); err != nil {
+		log.Printf("Failed to audit deletion of wallet %s: %v", wallet.ID, err)
+	}
+
+	return nil
+}
+
+func (s *walletService) ChangeStatus(ctx context.Context, userID, walletID string, req dto.ChangeWalletStatusRequest) (*dto.WalletResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.changeStatusAsOwner(ctx, userID, wallet, domain.WalletStatus(req.Status), strings.TrimSpace(req.Reason)); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(wallet), nil
+}
+
+func (s *walletService) GetStatusHistory(ctx context.Context, userID, walletID string) ([]*domain.WalletStatusChange, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	return s.walletRepo.GetStatusHistory(ctx, wallet.ID)
+}
+
+func (s *walletService) Freeze(ctx context.Context, adminID, walletID, reason string) (*dto.WalletResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.CanBeFrozen() {
+		return nil, domain.ErrInvalidWalletTransition
+	}
+
+	frozenFrom := wallet.Status
+	wallet.FrozenFrom = &frozenFrom
+	if err := s.changeStatus(ctx, wallet, domain.WalletStatusFrozen, reason, domain.AuditActorAdmin, adminID); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(wallet), nil
+}
+
+func (s *walletService) Unfreeze(ctx context.Context, adminID, walletID, reason string) (*dto.WalletResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.Status != domain.WalletStatusFrozen {
+		return nil, domain.ErrInvalidWalletTransition
+	}
+
+	to := domain.WalletStatusActive
+	if wallet.FrozenFrom != nil {
+		to = *wallet.FrozenFrom
+	}
+	wallet.FrozenFrom = nil
+	if err := s.changeStatus(ctx, wallet, to, reason, domain.AuditActorAdmin, adminID); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(wallet), nil
+}
+
+// changeStatusAsOwner makes a status change the wallet's creator asked for.
+func (s *walletService) changeStatusAsOwner(ctx context.Context, userID string, wallet *domain.Wallet, to domain.WalletStatus, reason string) error {
+	if wallet.CreatorID != userID {
+		return domain.ErrWalletAccessDenied
+	}
+	if !to.IsValid() || to == domain.WalletStatusFrozen {
+		return domain.ErrInvalidWalletStatus
+	}
+	if !wallet.OwnerCanChangeStatusTo(to) {
+		return domain.ErrInvalidWalletTransition
+	}
+
+	return s.changeStatus(ctx, wallet, to, reason, domain.AuditActorUser, userID)
+}
+
+func (s *walletService) changeStatus(ctx context.Context, wallet *domain.Wallet, to domain.WalletStatus, reason string, actorType domain.AuditActorType, actorID string) error {
+	change := &domain.WalletStatusChange{
+		FromStatus: wallet.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		ActorType:  actorType,
+		ActorID:    &actorID,
+	}
+	if err := s.walletRepo.ChangeStatus(ctx, wallet, change); err != nil {
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, actorType, actorID, "wallet.status_changed", "wallet", wallet.ID, map[string]interface{}{
+		"from_status": change.FromStatus,
+		"to_status":   change.ToStatus,
+		"reason":      change.Reason,
+	}); err != nil {
+		log.Printf("Failed to audit status change of wallet %s: %v", wallet.ID, err)
+	}
+
+	return nil
+}
+
+// getWallet returns the wallet unless it has been deleted.
+func (s *walletService) getWallet(ctx context.Context, walletID string) (*domain.Wallet, error) {
+	wallet, err := s.walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.IsDeleted() {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	return wallet, nil
+}
+
+func (s *walletService) GetSpendingRules(ctx context.Context, userID, walletID string) ([]dto.SpendingRuleResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.CanBeAccessedBy(userID) {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	rules, err := s.spendingRuleRepo.GetByWalletID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SpendingRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = *spendingRuleToResponse(rule)
+	}
+
+	return responses, nil
+}
+
+func (s *walletService) AddSpendingRule(ctx context.Context, userID, walletID string, req dto.AddSpendingRuleRequest) (*dto.SpendingRuleResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.CreatorID != userID {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	rule := &domain.WalletSpendingRule{WalletID: wallet.ID}
+	switch {
+	case req.PharmacyID != "" && req.OrganisationID == "":
+		pharmacy, err := s.pharmacyRepo.GetByID(ctx, req.PharmacyID)
+		if err != nil {
+			return nil, err
+		}
+		rule.PharmacyID = &pharmacy.ID
+		rule.TargetName = pharmacy.Name
+	case req.OrganisationID != "" && req.PharmacyID == "":
+		organisation, err := s.organisationRepo.GetByID(ctx, req.OrganisationID)
+		if err != nil {
+			return nil, err
+		}
+		rule.OrganisationID = &organisation.ID
+		rule.TargetName = organisation.Name
+	default:
+		return nil, domain.ErrInvalidSpendingRule
+	}
+
+	if err := s.spendingRuleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return spendingRuleToResponse(rule), nil
+}
+
+func (s *walletService) RemoveSpendingRule(ctx context.Context, userID, walletID, ruleID string) error {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return err
+	}
+
+	if wallet.CreatorID != userID {
+		return domain.ErrWalletAccessDenied
+	}
+
+	return s.spendingRuleRepo.Delete(ctx, wallet.ID, ruleID)
+}
+
+func (s *walletService) RotateCode(ctx context.Context, userID, walletID string) (*dto.WalletResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.CreatorID != userID {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	oldCode := wallet.ShareableCode
+	newCode, err := s.newShareableCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.walletRepo.RotateShareableCode(ctx, wallet, newCode, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "wallet.code_rotated", "wallet", wallet.ID, map[string]interface{}{
+		"old_code": oldCode,
+		"new_code": newCode,
+	}); err != nil {
+		log.Printf("Failed to audit code rotation for wallet %s: %v", wallet.ID, err)
+	}
+
+	return s.toResponse(wallet), nil
+}
+
+func (s *walletService) GetShareLinks(ctx context.Context, userID, walletID string) ([]dto.ShareLinkResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.CreatorID != userID {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	links, err := s.shareLinkRepo.GetByWalletID(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	responses := make([]dto.ShareLinkResponse, len(links))
+	for i, link := range links {
+		responses[i] = s.shareLinkToResponse(link, now)
+	}
+
+	return responses, nil
+}
+
+func (s *walletService) CreateShareLink(ctx context.Context, userID, walletID string, req dto.CreateShareLinkRequest) (*dto.ShareLinkResponse, error) {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.CreatorID != userID {
+		return nil, domain.ErrWalletAccessDenied
+	}
+
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, domain.ErrInvalidShareLink
+	}
+	if req.MaxContributions != nil && *req.MaxContributions < 1 {
+		return nil, domain.ErrInvalidShareLink
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &domain.WalletShareLink{
+		WalletID:         wallet.ID,
+		CreatedBy:        userID,
+		Token:            token,
+		Label:            strings.TrimSpace(req.Label),
+		ExpiresAt:        req.ExpiresAt,
+		MaxContributions: req.MaxContributions,
+	}
+
+	if err := s.shareLinkRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "wallet.share_link_created", "wallet_share_link", link.ID, map[string]interface{}{
+		"wallet_id":         wallet.ID,
+		"expires_at":        link.ExpiresAt,
+		"max_contributions": link.MaxContributions,
+	}); err != nil {
+		log.Printf("Failed to audit share link %s: %v", link.ID, err)
+	}
+
+	response := s.shareLinkToResponse(link, now)
+	return &response, nil
+}
+
+func (s *walletService) RevokeShareLink(ctx context.Context, userID, walletID, linkID string) error {
+	wallet, err := s.getWallet(ctx, walletID)
+	if err != nil {
+		return err
+	}
+
+	if wallet.CreatorID != userID {
+		return domain.ErrWalletAccessDenied
+	}
+
+	link, err := s.shareLinkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return err
+	}
+
+	if link.WalletID != wallet.ID || link.IsRevoked() {
+		return domain.ErrShareLinkNotFound
+	}
+
+	if err := s.shareLinkRepo.Revoke(ctx, link); err != nil {
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditActorUser, userID, "wallet.share_link_revoked", "wallet_share_link", link.ID, map[string]interface{}{
+		"wallet_id": wallet.ID,
+	}); err != nil {
+		log.Printf("Failed to audit share link %s: %v", link.ID, err)
+	}
+
+	return nil
+}
+
+func (s *walletService) GetByShareLink(ctx context.Context, token string) (*dto.PublicWalletResponse, error) {
+	link, err := s.shareLinkRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !link.IsUsable(time.Now()) {
+		return nil, domain.ErrShareLinkUnavailable
+	}
+
+	wallet, err := s.walletRepo.GetByID(ctx, link.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if !wallet.Status.AcceptsContributions() {
+		return nil, domain.ErrShareLinkUnavailable
+	}
+
+	// A failed count should not stop anyone contributing.
+	if err := s.shareLinkRepo.RecordView(ctx, link.ID); err != nil {
+		log.Printf("Failed to record view of share link %s: %v", link.ID, err)
+	}
 
-	return s.walletRepo.Delete(ctx, walletID)
+	return s.toPublicResponse(ctx, wallet, false), nil
+}
+
+func (s *walletService) shareLinkToResponse(link *domain.WalletShareLink, now time.Time) dto.ShareLinkResponse {
+	response := dto.ShareLinkResponse{
+		ID:                link.ID,
+		Label:             link.Label,
+		URL:               fmt.Sprintf("%s/wallet/share/%s", s.config.FrontendURL, link.Token),
+		Token:             link.Token,
+		MaxContributions:  link.MaxContributions,
+		Usable:            link.IsUsable(now),
+		ViewCount:         link.ViewCount,
+		ContributionCount: link.ContributionCount,
+		ContributionTotal: link.ContributionTotal.InexactFloat64(),
+		CreatedAt:         link.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if link.ExpiresAt != nil {
+		expires := link.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+		response.ExpiresAt = &expires
+	}
+	if link.RevokedAt != nil {
+		revoked := link.RevokedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.RevokedAt = &revoked
+	}
+
+	return response
+}
+
+// newShareableCode returns a code no wallet has ever used.
+func (s *walletService) newShareableCode(ctx context.Context) (string, error) {
+	for {
+		code, err := utils.GenerateShareableCode()
+		if err != nil {
+			return "", err
+		}
+		exists, err := s.walletRepo.ShareableCodeExists(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return code, nil
+		}
+	}
+}
+
+func spendingRuleToResponse(rule *domain.WalletSpendingRule) *dto.SpendingRuleResponse {
+	return &dto.SpendingRuleResponse{
+		ID:             rule.ID,
+		PharmacyID:     rule.PharmacyID,
+		OrganisationID: rule.OrganisationID,
+		TargetName:     rule.TargetName,
+		CreatedAt:      rule.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// setPhotoUpload uses an image the user uploaded as the wallet photo.
+func (s *walletService) setPhotoUpload(ctx context.Context, userID string, wallet *domain.Wallet, uploadID string) error {
+	upload, err := s.uploadService.GetOwned(ctx, domain.UploadOwnerUser, userID, uploadID, domain.UploadPurposeWalletPhoto)
+	if err != nil {
+		return err
+	}
+
+	wallet.PhotoUploadID = &upload.ID
+	wallet.PhotoURL = ""
+	return nil
+}
+
+// photoURL returns a fresh signed link for an uploaded photo, or the
+// external photo URL.
+func (s *walletService) photoURL(wallet *domain.Wallet) string {
+	if wallet.PhotoUploadID != nil {
+		return s.uploadService.SignedURL(*wallet.PhotoUploadID)
+	}
+	return wallet.PhotoURL
+}
+
+// toPublicResponse describes the wallet to contributors. withCode is false
+// for pages reached through a share link, which must not reveal the code
+// pharmacies accept.
+func (s *walletService) toPublicResponse(ctx context.Context, wallet *domain.Wallet, withCode bool) *dto.PublicWalletResponse {
+	response := &dto.PublicWalletResponse{
+		ID:          wallet.ID,
+		WalletName:  wallet.WalletName,
+		Description: wallet.Description,
+		PhotoURL:    s.photoURL(wallet),
+		Balance:     wallet.Balance.InexactFloat64(),
+		FundingGoal: wallet.FundingGoal.InexactFloat64(),
+		Status:      string(wallet.Status),
+	}
+	if withCode {
+		response.ShareableCode = wallet.ShareableCode
+		response.DisplayCode = utils.FormatShareableCode(wallet.ShareableCode)
+	}
+
+	// The page still works without the campaign.
+	campaign, err := s.campaignService.GetPublic(ctx, wallet.ID)
+	if err == nil {
+		response.Campaign = campaign
+	} else if !errors.Is(err, domain.ErrCampaignNotFound) {
+		log.Printf("Failed to load campaign for wallet %s: %v", wallet.ID, err)
+	}
+	return response
+}
+
+func (s *walletService) toResponse(wallet *domain.Wallet) *dto.WalletResponse {
+	response := walletToResponse(wallet)
+	response.PhotoURL = s.photoURL(wallet)
+	return response
 }
 
 func walletToResponse(wallet *domain.Wallet) *dto.WalletResponse {
-	return &dto.WalletResponse{
+	response := &dto.WalletResponse{
 		ID:            wallet.ID,
 		CreatorID:     wallet.CreatorID,
 		BeneficiaryID: wallet.BeneficiaryID,
@@ -169,8 +711,63 @@ func walletToResponse(wallet *domain.Wallet) *dto.WalletResponse {
 		Balance:       wallet.Balance.InexactFloat64(),
 		FundingGoal:   wallet.FundingGoal.InexactFloat64(),
 		ShareableCode: wallet.ShareableCode,
+		DisplayCode:   utils.FormatShareableCode(wallet.ShareableCode),
 		Status:        string(wallet.Status),
+		StatusReason:  wallet.StatusReason,
 		CreatedAt:     wallet.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:     wallet.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
+	if wallet.StatusChangedAt != nil {
+		changed := wallet.StatusChangedAt.Format("2006-01-02T15:04:05Z07:00")
+		response.StatusChangedAt = &changed
+	}
+	return response
+}
+
+// findWalletByCode finds the wallet a shareable code refers to, as a person
+// typed or read it out. It relies on WalletRepository.GetByShareableCode to
+// find only usable (active, paused or closing) wallets; callers needing a
+// stricter status, e.g. one that accepts contributions, check it themselves.
+// It fails with ErrInvalidWalletCode when the input cannot be a code, e.g.
+// because its check character is wrong.
+func findWalletByCode(ctx context.Context, walletRepo repository.WalletRepository, input string) (*domain.Wallet, error) {
+	candidates := utils.ShareableCodeCandidates(input)
+	if len(candidates) == 0 {
+		return nil, domain.ErrInvalidWalletCode
+	}
+
+	for _, code := range candidates {
+		wallet, err := walletRepo.GetByShareableCode(ctx, code)
+		if err == nil {
+			return wallet, nil
+		}
+		if !errors.Is(err, domain.ErrWalletNotFound) {
+			return nil, err
+		}
+	}
+	return nil, domain.ErrWalletNotFound
+}
+
+// findWalletByPublicCode finds the wallet whose public page the code leads
+// to. Contributors may still have a link with a code the wallet has since
+// rotated away from; that code is returned alongside the wallet.
+func findWalletByPublicCode(ctx context.Context, walletRepo repository.WalletRepository, input string) (*domain.Wallet, string, error) {
+	wallet, err := findWalletByCode(ctx, walletRepo, input)
+	if err == nil {
+		return wallet, "", nil
+	}
+	if !errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, "", err
+	}
+
+	for _, candidate := range utils.ShareableCodeCandidates(input) {
+		wallet, retiredErr := walletRepo.GetByRetiredCode(ctx, candidate)
+		if retiredErr == nil {
+			return wallet, candidate, nil
+		}
+		if !errors.Is(retiredErr, domain.ErrWalletNotFound) {
+			return nil, "", retiredErr
+		}
+	}
+	return nil, "", err
 }
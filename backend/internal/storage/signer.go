@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidSignature = errors.New("download link is invalid or has expired")
+
+// URLSigner creates time-limited download links served by the API. The link
+// carries its expiry and an HMAC over the file ID and expiry, so anyone holding
+// it can download the file until it expires without signing in.
+type URLSigner struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+func NewURLSigner(secret, baseURL string, ttl time.Duration) *URLSigner {
+	return &URLSigner{
+		secret:  []byte(secret),
+		baseURL: baseURL,
+		ttl:     ttl,
+	}
+}
+
+// SignedURL returns a download link for the file that expires after the
+// signer's TTL, along with the expiry time.
+func (s *URLSigner) SignedURL(fileID string) (string, time.Time) {
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.signature(fileID, expires))
+
+	return fmt.Sprintf("%s/api/v1/files/%s?%s", s.baseURL, url.PathEscape(fileID), query.Encode()), expiresAt
+}
+
+// Verify checks a signature and expiry taken from a download link.
+func (s *URLSigner) Verify(fileID, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+
+	expected := s.signature(fileID, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (s *URLSigner) signature(fileID, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(fileID + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,18 @@
+// Package storage keeps uploaded files in a pluggable blob store. Files are
+// stored under opaque keys; metadata such as the owner lives in the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores and retrieves file contents by key.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -2,25 +2,66 @@ package utils
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"math/big"
 )
 
 const (
-	shareableCodeLength = 8
-	shareableCodeChars  = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Excluded I, O, 0, 1 for clarity
+	// Shareable codes are 9 random characters and a check character, shown
+	// in two groups of five. Codes issued before the check character was
+	// added are 8 random characters and stay valid.
+	shareableCodeLength       = 9
+	legacyShareableCodeLength = 8
+	shareableCodeChars        = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Excluded I, O, 0, 1 for clarity
 
 	otpCodeLength = 6
 	otpCodeChars  = "0123456789"
+
+	secureTokenBytes = 32
+
+	apiKeyMarker      = "cwk_"
+	apiKeyPrefixBytes = 4
 )
 
 func GenerateShareableCode() (string, error) {
-	return generateRandomString(shareableCodeLength, shareableCodeChars)
+	payload, err := generateRandomString(shareableCodeLength, shareableCodeChars)
+	if err != nil {
+		return "", err
+	}
+	return payload + string(shareableCheckChar(payload)), nil
 }
 
 func GenerateOTPCode() (string, error) {
 	return generateRandomString(otpCodeLength, otpCodeChars)
 }
 
+// GenerateSecureToken returns a random hex token for emailed one-time links.
+func GenerateSecureToken() (string, error) {
+	b := make([]byte, secureTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GenerateAPIKey returns a new pharmacy API key and its prefix. The prefix is
+// part of the key and is stored in clear so a key can be recognised in
+// listings without keeping the key itself.
+func GenerateAPIKey() (key, prefix string, err error) {
+	b := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix = apiKeyMarker + hex.EncodeToString(b)
+
+	secret, err := GenerateSecureToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return prefix + "_" + secret, prefix, nil
+}
+
 func generateRandomString(length int, charset string) (string, error) {
 	result := make([]byte, length)
 	charsetLen := big.NewInt(int64(len(charset)))
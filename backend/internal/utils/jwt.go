@@ -11,9 +11,33 @@ import (
 type JWTClaims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
+	// PharmacyUserID and PharmacyRole identify the staff member behind a
+	// pharmacy portal token.
+	PharmacyUserID string `json:"pharmacy_user_id,omitempty"`
+	PharmacyRole   string `json:"pharmacy_role,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenOption customises the claims of a generated token.
+type TokenOption func(*JWTClaims)
+
+// WithRole marks the token as belonging to a principal of the given role,
+// e.g. a pharmacy rather than a regular user.
+func WithRole(role string) TokenOption {
+	return func(c *JWTClaims) {
+		c.Role = role
+	}
+}
+
+// WithPharmacyUser records the pharmacy staff member a token was issued to.
+func WithPharmacyUser(pharmacyUserID, role string) TokenOption {
+	return func(c *JWTClaims) {
+		c.PharmacyUserID = pharmacyUserID
+		c.PharmacyRole = role
+	}
+}
+
 type JWTManager struct {
 	secret     []byte
 	expiration time.Duration
@@ -26,7 +50,7 @@ func NewJWTManager(secret string, expiration time.Duration) *JWTManager {
 	}
 }
 
-func (m *JWTManager) Generate(userID, email string) (string, string, error) {
+func (m *JWTManager) Generate(userID, email string, opts ...TokenOption) (string, string, error) {
 	jti := uuid.New().String()
 
 	claims := JWTClaims{
@@ -40,6 +64,9 @@ func (m *JWTManager) Generate(userID, email string) (string, string, error) {
 			Issuer:    "carewallet",
 		},
 	}
+	for _, opt := range opts {
+		opt(&claims)
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(m.secret)
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"strings"
+)
+
+// shareableConfusables maps characters left out of the code alphabet to the
+// ones they are most likely mistaken for when a code is read or typed.
+var shareableConfusables = map[rune]string{
+	'O': "DQ",
+	'0': "DQ",
+	'I': "LJ",
+	'1': "LJ",
+}
+
+// maxShareableCandidates bounds how many readings of a code with confusable
+// characters are considered.
+const maxShareableCandidates = 16
+
+// ShareableCodeCandidates returns the stored codes that user input could
+// refer to. Case, spaces and dashes are ignored and confusable characters
+// are expanded to every character they may stand for. For current codes only
+// readings with a valid check character are returned, so the result is
+// usually a single code; it is empty when the input cannot be a valid code.
+func ShareableCodeCandidates(input string) []string {
+	var cleaned []rune
+	for _, r := range strings.ToUpper(input) {
+		switch r {
+		case ' ', '-', '.', '_':
+			continue
+		}
+		cleaned = append(cleaned, r)
+	}
+
+	if len(cleaned) != shareableCodeLength+1 && len(cleaned) != legacyShareableCodeLength {
+		return nil
+	}
+
+	candidates := []string{""}
+	for _, r := range cleaned {
+		options := string(r)
+		if alternatives, ok := shareableConfusables[r]; ok {
+			options = alternatives
+		} else if !strings.ContainsRune(shareableCodeChars, r) {
+			return nil
+		}
+
+		if len(candidates)*len(options) > maxShareableCandidates {
+			return nil
+		}
+		next := make([]string, 0, len(candidates)*len(options))
+		for _, prefix := range candidates {
+			for _, option := range options {
+				next = append(next, prefix+string(option))
+			}
+		}
+		candidates = next
+	}
+
+	if len(cleaned) == legacyShareableCodeLength {
+		return candidates
+	}
+
+	valid := candidates[:0]
+	for _, candidate := range candidates {
+		if validShareableCheckChar(candidate) {
+			valid = append(valid, candidate)
+		}
+	}
+	return valid
+}
+
+// FormatShareableCode groups a stored code for display, e.g. "ABCDE-FGH2K"
+// or "ABCD-EFGH" for a legacy code.
+func FormatShareableCode(code string) string {
+	switch len(code) {
+	case shareableCodeLength + 1:
+		return code[:5] + "-" + code[5:]
+	case legacyShareableCodeLength:
+		return code[:4] + "-" + code[4:]
+	}
+	return code
+}
+
+// shareableCheckChar computes the Luhn mod N check character over the code
+// alphabet. It catches every single-character mistake and most swaps of
+// adjacent characters.
+func shareableCheckChar(payload string) byte {
+	n := len(shareableCodeChars)
+	factor := 2
+	sum := 0
+	for i := len(payload) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(shareableCodeChars, payload[i])
+		addend = addend/n + addend%n
+		sum += addend
+		factor = 3 - factor
+	}
+	return shareableCodeChars[(n-sum%n)%n]
+}
+
+func validShareableCheckChar(code string) bool {
+	n := len(shareableCodeChars)
+	factor := 1
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(shareableCodeChars, code[i])
+		addend = addend/n + addend%n
+		sum += addend
+		factor = 3 - factor
+	}
+	return sum%n == 0
+}
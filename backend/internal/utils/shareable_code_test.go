@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShareableCheckChar(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    string
+	}{
+		{"ABCDEFGHJ", "ABCDEFGHJJ"},
+		{"HD7LKM3PX", "HD7LKM3PXC"},
+		{"Q2WJRT9DZ", "Q2WJRT9DZ5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			code := tt.payload + string(shareableCheckChar(tt.payload))
+			if code != tt.want {
+				t.Fatalf("code = %q, want %q", code, tt.want)
+			}
+			if !validShareableCheckChar(code) {
+				t.Fatalf("validShareableCheckChar(%q) = false, want true", code)
+			}
+
+			// Every single-character mistake must be caught.
+			for i := range code {
+				for j := 0; j < len(shareableCodeChars); j++ {
+					if shareableCodeChars[j] == code[i] {
+						continue
+					}
+					typo := code[:i] + string(shareableCodeChars[j]) + code[i+1:]
+					if validShareableCheckChar(typo) {
+						t.Errorf("validShareableCheckChar(%q) = true, want false", typo)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestShareableCodeCandidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"exact code", "HD7LKM3PXC", []string{"HD7LKM3PXC"}},
+		{"lower case with dash", "hd7lk-m3pxc", []string{"HD7LKM3PXC"}},
+		{"letter O read for D", "HO7LKM3PXC", []string{"HD7LKM3PXC"}},
+		{"letter I read for L", "HD7IKM3PXC", []string{"HD7LKM3PXC"}},
+		{"digits 0 and 1 with spacing", "h07-1km3p-xc", []string{"HD7LKM3PXC"}},
+		{"wrong check character", "HD7LKM3PXD", nil},
+		{"adjacent characters swapped", "HD7KLM3PXC", nil},
+		{"character outside the alphabet", "HD7LKM3P*C", nil},
+		{"too short", "HD7LKM3PX", nil},
+		{"too long", "HD7LKM3PXC2", nil},
+		{"too many confusable readings", "00000000O0", nil},
+		{"legacy code", "ABCD-EFGH", []string{"ABCDEFGH"}},
+		{"legacy code with confusable", "ab0d efgh", []string{"ABDDEFGH", "ABQDEFGH"}},
+		{"legacy length with invalid character", "ABCDEFG*", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShareableCodeCandidates(tt.input)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ShareableCodeCandidates(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}